@@ -18,6 +18,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/dotandev/hintents/internal/diagnostic"
 	"github.com/dotandev/hintents/internal/simulator"
 )
 
@@ -45,12 +46,12 @@ func (sa *SecurityAnalyzer) Analyze(resp *simulator.SimulationResponse) []Securi
 		return sa.violations
 	}
 
-	sa.checkUnauthorizedStateModifications(resp.CategorizedEvents)
+	sa.checkUnauthorizedStateModifications(resp.Events)
 
 	return sa.violations
 }
 
-func (sa *SecurityAnalyzer) checkUnauthorizedStateModifications(events []simulator.CategorizedEvent) {
+func (sa *SecurityAnalyzer) checkUnauthorizedStateModifications(events []diagnostic.Event) {
 	type StateModification struct {
 		index      int
 		contractID string
@@ -60,15 +61,12 @@ func (sa *SecurityAnalyzer) checkUnauthorizedStateModifications(events []simulat
 	stateWrites := make([]StateModification, 0)
 
 	for i, event := range events {
-		contractID := ""
-		if event.ContractID != nil {
-			contractID = *event.ContractID
-		}
+		contractID := event.ContractID
 
-		switch event.EventType {
-		case "require_auth":
+		switch {
+		case isAuthCheck(event):
 			authChecks[contractID] = append(authChecks[contractID], i)
-		case "storage_write":
+		case isStorageWrite(event):
 			stateWrites = append(stateWrites, StateModification{
 				index:      i,
 				contractID: contractID,
@@ -101,7 +99,7 @@ func (sa *SecurityAnalyzer) hasAuthBeforeWrite(authIndices []int, writeIndex int
 	return false
 }
 
-func (sa *SecurityAnalyzer) isSACPattern(events []simulator.CategorizedEvent, writeIndex int) bool {
+func (sa *SecurityAnalyzer) isSACPattern(events []diagnostic.Event, writeIndex int) bool {
 	if writeIndex >= len(events) {
 		return false
 	}
@@ -109,7 +107,8 @@ func (sa *SecurityAnalyzer) isSACPattern(events []simulator.CategorizedEvent, wr
 	event := events[writeIndex]
 
 	for _, topic := range event.Topics {
-		topicLower := strings.ToLower(topic)
+		text, _ := diagnostic.Text(topic)
+		topicLower := strings.ToLower(text)
 		if strings.Contains(topicLower, "balance") ||
 			strings.Contains(topicLower, "allowance") ||
 			strings.Contains(topicLower, "admin") ||
@@ -118,7 +117,8 @@ func (sa *SecurityAnalyzer) isSACPattern(events []simulator.CategorizedEvent, wr
 		}
 	}
 
-	dataLower := strings.ToLower(event.Data)
+	data, _ := diagnostic.Text(event.Data)
+	dataLower := strings.ToLower(data)
 	if strings.Contains(dataLower, "stellar_asset") ||
 		strings.Contains(dataLower, "sac_") {
 		return true
@@ -126,3 +126,18 @@ func (sa *SecurityAnalyzer) isSACPattern(events []simulator.CategorizedEvent, wr
 
 	return false
 }
+
+// isAuthCheck reports whether event records an authorization check: a
+// require_auth event, or the host calling an account contract's __check_auth
+func isAuthCheck(event diagnostic.Event) bool {
+	if event.Kind == diagnostic.KindFnCall && event.Function() == "__check_auth" {
+		return true
+	}
+	name := event.Name()
+	return name == "require_auth" || name == "auth"
+}
+
+// isStorageWrite reports whether event records a write to contract storage
+func isStorageWrite(event diagnostic.Event) bool {
+	return event.Name() == "storage_write"
+}
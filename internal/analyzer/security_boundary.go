@@ -15,16 +15,10 @@
 package analyzer
 
 import (
-	"encoding/json"
 	"strings"
-)
 
-type Event struct {
-	Type      string `json:"type"`
-	Contract  string `json:"contract,omitempty"`
-	Address   string `json:"address,omitempty"`
-	EventType string `json:"event_type,omitempty"`
-}
+	"github.com/dotandev/hintents/internal/diagnostic"
+)
 
 type Violation struct {
 	Type        string                 `json:"type"`
@@ -45,42 +39,38 @@ func NewSecurityBoundaryChecker() *SecurityBoundaryChecker {
 	return &SecurityBoundaryChecker{}
 }
 
-func (c *SecurityBoundaryChecker) Analyze(events []string) ([]Violation, error) {
+func (c *SecurityBoundaryChecker) Analyze(events []diagnostic.Event) ([]Violation, error) {
 	var violations []Violation
 
 	contractStates := make(map[string]*contractInvocationState)
 
-	for _, eventStr := range events {
-		var event Event
-		if err := json.Unmarshal([]byte(eventStr), &event); err != nil {
-			continue
-		}
-
-		if event.Contract == "" || event.Contract == "unknown" {
+	for _, event := range events {
+		contract := event.ContractID
+		if contract == "" || contract == "unknown" {
 			continue
 		}
 
-		if _, exists := contractStates[event.Contract]; !exists {
-			contractStates[event.Contract] = &contractInvocationState{
+		if _, exists := contractStates[contract]; !exists {
+			contractStates[contract] = &contractInvocationState{
 				AuthChecked: make(map[string]bool),
 			}
 		}
 
-		state := contractStates[event.Contract]
+		state := contractStates[contract]
 
-		switch event.Type {
-		case "auth":
-			state.AuthChecked[event.Address] = true
+		switch {
+		case isAuthCheck(event):
+			state.AuthChecked[authAddress(event)] = true
 			state.HasAuth = true
 
-		case "storage_write":
+		case isStorageWrite(event):
 			if !state.HasAuth {
-				if !isSACPattern(event) {
+				if !isSACContract(contract) {
 					violations = append(violations, Violation{
 						Type:        "unauthorized_state_modification",
 						Severity:    "high",
 						Description: "Storage write operation without prior require_auth check",
-						Contract:    event.Contract,
+						Contract:    contract,
 						Details: map[string]interface{}{
 							"operation": "storage_write",
 						},
@@ -93,9 +83,21 @@ func (c *SecurityBoundaryChecker) Analyze(events []string) ([]Violation, error)
 	return violations, nil
 }
 
-func isSACPattern(event Event) bool {
-	contract := event.Contract
+// authAddress returns the address an auth event was checked for, the topic
+// following its name
+func authAddress(event diagnostic.Event) string {
+	if len(event.Topics) < 2 {
+		return ""
+	}
+	if address, ok := event.Topics[1].GetAddress(); ok {
+		if s, err := address.String(); err == nil {
+			return s
+		}
+	}
+	return ""
+}
 
+func isSACContract(contract string) bool {
 	if contract == "" || contract == "unknown" {
 		return false
 	}
@@ -15,17 +15,29 @@
 package analyzer
 
 import (
-	"fmt"
 	"testing"
 
+	"github.com/dotandev/hintents/internal/diagnostic"
+	"github.com/stellar/go/xdr"
 	"github.com/stretchr/testify/assert"
 )
 
+// authEvent is an auth event of contractID for the account address
+func authEvent(t *testing.T, contractID, address string) diagnostic.Event {
+	t.Helper()
+	account, err := xdr.AddressToAccountId(address)
+	assert.NoError(t, err)
+	addr := xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeAccount, AccountId: &account}
+	return namedEvent(contractID, "auth", xdr.ScVal{Type: xdr.ScValTypeScvAddress, Address: &addr})
+}
+
+const testAccount = "GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H"
+
 func TestSecurityBoundaryChecker_NoViolations(t *testing.T) {
-	events := []string{
-		`{"type":"contract_call","contract":"CABC123"}`,
-		`{"type":"auth","address":"GABC123","contract":"CABC123"}`,
-		`{"type":"storage_write","contract":"CABC123"}`,
+	events := []diagnostic.Event{
+		namedEvent("CABC123", "contract_call"),
+		authEvent(t, "CABC123", testAccount),
+		namedEvent("CABC123", "storage_write"),
 	}
 
 	checker := NewSecurityBoundaryChecker()
@@ -36,9 +48,9 @@ func TestSecurityBoundaryChecker_NoViolations(t *testing.T) {
 }
 
 func TestSecurityBoundaryChecker_UnauthorizedStateModification(t *testing.T) {
-	events := []string{
-		`{"type":"contract_call","contract":"CABC123"}`,
-		`{"type":"storage_write","contract":"CABC123"}`,
+	events := []diagnostic.Event{
+		namedEvent("CABC123", "contract_call"),
+		namedEvent("CABC123", "storage_write"),
 	}
 
 	checker := NewSecurityBoundaryChecker()
@@ -64,8 +76,8 @@ func TestSecurityBoundaryChecker_SACPattern_NoFalsePositive(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			events := []string{
-				fmt.Sprintf(`{"type":"storage_write","contract":"%s"}`, tc.contract),
+			events := []diagnostic.Event{
+				namedEvent(tc.contract, "storage_write"),
 			}
 
 			checker := NewSecurityBoundaryChecker()
@@ -78,12 +90,12 @@ func TestSecurityBoundaryChecker_SACPattern_NoFalsePositive(t *testing.T) {
 }
 
 func TestSecurityBoundaryChecker_MultipleContracts(t *testing.T) {
-	events := []string{
-		`{"type":"contract_call","contract":"C1"}`,
-		`{"type":"auth","address":"A1","contract":"C1"}`,
-		`{"type":"storage_write","contract":"C1"}`,
-		`{"type":"contract_call","contract":"C2"}`,
-		`{"type":"storage_write","contract":"C2"}`,
+	events := []diagnostic.Event{
+		namedEvent("C1", "contract_call"),
+		authEvent(t, "C1", testAccount),
+		namedEvent("C1", "storage_write"),
+		namedEvent("C2", "contract_call"),
+		namedEvent("C2", "storage_write"),
 	}
 
 	checker := NewSecurityBoundaryChecker()
@@ -95,10 +107,10 @@ func TestSecurityBoundaryChecker_MultipleContracts(t *testing.T) {
 }
 
 func TestSecurityBoundaryChecker_AuthAfterWrite_StillViolation(t *testing.T) {
-	events := []string{
-		`{"type":"contract_call","contract":"CABC"}`,
-		`{"type":"storage_write","contract":"CABC"}`,
-		`{"type":"auth","address":"GABC","contract":"CABC"}`,
+	events := []diagnostic.Event{
+		namedEvent("CABC", "contract_call"),
+		namedEvent("CABC", "storage_write"),
+		authEvent(t, "CABC", testAccount),
 	}
 
 	checker := NewSecurityBoundaryChecker()
@@ -108,11 +120,11 @@ func TestSecurityBoundaryChecker_AuthAfterWrite_StillViolation(t *testing.T) {
 	assert.Len(t, violations, 1)
 }
 
-func TestSecurityBoundaryChecker_InvalidJSON_Skipped(t *testing.T) {
-	events := []string{
-		`invalid json`,
-		`{"type":"contract_call","contract":"CABC"}`,
-		`{"type":"storage_write","contract":"CABC"}`,
+func TestSecurityBoundaryChecker_UnnamedEvents_Skipped(t *testing.T) {
+	events := []diagnostic.Event{
+		diagnostic.New(diagnostic.TypeContract, "CABC", nil, xdr.ScVal{}),
+		namedEvent("CABC", "contract_call"),
+		namedEvent("CABC", "storage_write"),
 	}
 
 	checker := NewSecurityBoundaryChecker()
@@ -123,7 +135,7 @@ func TestSecurityBoundaryChecker_InvalidJSON_Skipped(t *testing.T) {
 }
 
 func TestSecurityBoundaryChecker_EmptyEvents(t *testing.T) {
-	events := []string{}
+	events := []diagnostic.Event{}
 
 	checker := NewSecurityBoundaryChecker()
 	violations, err := checker.Analyze(events)
@@ -133,9 +145,9 @@ func TestSecurityBoundaryChecker_EmptyEvents(t *testing.T) {
 }
 
 func TestSecurityBoundaryChecker_UnknownContract_Skipped(t *testing.T) {
-	events := []string{
-		`{"type":"storage_write","contract":"unknown"}`,
-		`{"type":"storage_write","contract":""}`,
+	events := []diagnostic.Event{
+		namedEvent("unknown", "storage_write"),
+		namedEvent("", "storage_write"),
 	}
 
 	checker := NewSecurityBoundaryChecker()
@@ -17,29 +17,62 @@ package analyzer
 import (
 	"testing"
 
+	"github.com/dotandev/hintents/internal/diagnostic"
 	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/stellar/go/xdr"
 	"github.com/stretchr/testify/assert"
 )
 
+func symbol(s string) xdr.ScVal {
+	sym := xdr.ScSymbol(s)
+	return xdr.ScVal{Type: xdr.ScValTypeScvSymbol, Sym: &sym}
+}
+
+func str(s string) xdr.ScVal {
+	str := xdr.ScString(s)
+	return xdr.ScVal{Type: xdr.ScValTypeScvString, Str: &str}
+}
+
+// namedEvent is a contract event whose first topic is name
+func namedEvent(contractID, name string, topics ...xdr.ScVal) diagnostic.Event {
+	return diagnostic.New(diagnostic.TypeContract, contractID,
+		append([]xdr.ScVal{symbol(name)}, topics...), xdr.ScVal{})
+}
+
+// writeEvent is a storage_write event of key carrying data
+func writeEvent(contractID, key, data string) diagnostic.Event {
+	event := namedEvent(contractID, "storage_write", symbol(key))
+	event.Data = str(data)
+	return event
+}
+
 func TestSecurityAnalyzer_NoViolations(t *testing.T) {
 	analyzer := NewSecurityAnalyzer()
 
 	contractID := "contract123"
 	resp := &simulator.SimulationResponse{
 		Status: "success",
-		CategorizedEvents: []simulator.CategorizedEvent{
-			{
-				EventType:  "require_auth",
-				ContractID: &contractID,
-				Topics:     []string{"require_auth"},
-				Data:       "auth_data",
-			},
-			{
-				EventType:  "storage_write",
-				ContractID: &contractID,
-				Topics:     []string{"write"},
-				Data:       "write_data",
-			},
+		Events: []diagnostic.Event{
+			namedEvent(contractID, "require_auth"),
+			writeEvent(contractID, "write", "write_data"),
+		},
+	}
+
+	violations := analyzer.Analyze(resp)
+	assert.Empty(t, violations)
+}
+
+func TestSecurityAnalyzer_CheckAuthCall(t *testing.T) {
+	analyzer := NewSecurityAnalyzer()
+
+	contractID := "contract123"
+	checkAuth := diagnostic.New(diagnostic.TypeDiagnostic, contractID,
+		[]xdr.ScVal{symbol("fn_call"), symbol("account"), symbol("__check_auth")}, xdr.ScVal{})
+	resp := &simulator.SimulationResponse{
+		Status: "success",
+		Events: []diagnostic.Event{
+			checkAuth,
+			writeEvent(contractID, "write", "write_data"),
 		},
 	}
 
@@ -53,13 +86,8 @@ func TestSecurityAnalyzer_UnauthorizedStateModification(t *testing.T) {
 	contractID := "contract123"
 	resp := &simulator.SimulationResponse{
 		Status: "success",
-		CategorizedEvents: []simulator.CategorizedEvent{
-			{
-				EventType:  "storage_write",
-				ContractID: &contractID,
-				Topics:     []string{"write"},
-				Data:       "unauthorized_write",
-			},
+		Events: []diagnostic.Event{
+			writeEvent(contractID, "write", "unauthorized_write"),
 		},
 	}
 
@@ -72,51 +100,23 @@ func TestSecurityAnalyzer_UnauthorizedStateModification(t *testing.T) {
 func TestSecurityAnalyzer_SACPattern_NoFalsePositive(t *testing.T) {
 	tests := []struct {
 		name   string
-		events []simulator.CategorizedEvent
+		events []diagnostic.Event
 	}{
 		{
-			name: "SAC balance update",
-			events: []simulator.CategorizedEvent{
-				{
-					EventType:  "storage_write",
-					ContractID: strPtr("sac_contract"),
-					Topics:     []string{"Balance"},
-					Data:       "balance_data",
-				},
-			},
+			name:   "SAC balance update",
+			events: []diagnostic.Event{writeEvent("sac_contract", "Balance", "balance_data")},
 		},
 		{
-			name: "SAC allowance update",
-			events: []simulator.CategorizedEvent{
-				{
-					EventType:  "storage_write",
-					ContractID: strPtr("sac_contract"),
-					Topics:     []string{"Allowance"},
-					Data:       "allowance_data",
-				},
-			},
+			name:   "SAC allowance update",
+			events: []diagnostic.Event{writeEvent("sac_contract", "Allowance", "allowance_data")},
 		},
 		{
-			name: "SAC admin operation",
-			events: []simulator.CategorizedEvent{
-				{
-					EventType:  "storage_write",
-					ContractID: strPtr("sac_contract"),
-					Topics:     []string{"Admin"},
-					Data:       "admin_data",
-				},
-			},
+			name:   "SAC admin operation",
+			events: []diagnostic.Event{writeEvent("sac_contract", "Admin", "admin_data")},
 		},
 		{
-			name: "Stellar asset contract",
-			events: []simulator.CategorizedEvent{
-				{
-					EventType:  "storage_write",
-					ContractID: strPtr("sac_contract"),
-					Topics:     []string{"write"},
-					Data:       "stellar_asset_data",
-				},
-			},
+			name:   "Stellar asset contract",
+			events: []diagnostic.Event{writeEvent("sac_contract", "write", "stellar_asset_data")},
 		},
 	}
 
@@ -124,8 +124,8 @@ func TestSecurityAnalyzer_SACPattern_NoFalsePositive(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			analyzer := NewSecurityAnalyzer()
 			resp := &simulator.SimulationResponse{
-				Status:            "success",
-				CategorizedEvents: tt.events,
+				Status: "success",
+				Events: tt.events,
 			}
 
 			violations := analyzer.Analyze(resp)
@@ -142,25 +142,10 @@ func TestSecurityAnalyzer_MultipleContracts(t *testing.T) {
 
 	resp := &simulator.SimulationResponse{
 		Status: "success",
-		CategorizedEvents: []simulator.CategorizedEvent{
-			{
-				EventType:  "require_auth",
-				ContractID: &contract1,
-				Topics:     []string{"require_auth"},
-				Data:       "auth1",
-			},
-			{
-				EventType:  "storage_write",
-				ContractID: &contract1,
-				Topics:     []string{"write"},
-				Data:       "write1",
-			},
-			{
-				EventType:  "storage_write",
-				ContractID: &contract2,
-				Topics:     []string{"write"},
-				Data:       "write2",
-			},
+		Events: []diagnostic.Event{
+			namedEvent(contract1, "require_auth"),
+			writeEvent(contract1, "write", "write1"),
+			writeEvent(contract2, "write", "write2"),
 		},
 	}
 
@@ -175,26 +160,12 @@ func TestSecurityAnalyzer_AuthAfterWrite_StillViolation(t *testing.T) {
 	contractID := "contract123"
 	resp := &simulator.SimulationResponse{
 		Status: "success",
-		CategorizedEvents: []simulator.CategorizedEvent{
-			{
-				EventType:  "storage_write",
-				ContractID: &contractID,
-				Topics:     []string{"write"},
-				Data:       "write_data",
-			},
-			{
-				EventType:  "require_auth",
-				ContractID: &contractID,
-				Topics:     []string{"require_auth"},
-				Data:       "auth_data",
-			},
+		Events: []diagnostic.Event{
+			writeEvent(contractID, "write", "write_data"),
+			namedEvent(contractID, "require_auth"),
 		},
 	}
 
 	violations := analyzer.Analyze(resp)
 	assert.Len(t, violations, 1)
 }
-
-func strPtr(s string) *string {
-	return &s
-}
@@ -18,6 +18,7 @@ import (
 	"fmt"
 
 	"github.com/dotandev/hintents/internal/authtrace"
+	"github.com/dotandev/hintents/internal/config"
 	"github.com/dotandev/hintents/internal/logger"
 	"github.com/dotandev/hintents/internal/rpc"
 	"github.com/spf13/cobra"
@@ -42,19 +43,15 @@ Examples:
   erst auth-debug --json <tx-hash>`,
 	Args: cobra.ExactArgs(1),
 	PreRunE: func(cmd *cobra.Command, args []string) error {
-		switch rpc.Network(authNetworkFlag) {
-		case rpc.Testnet, rpc.Mainnet, rpc.Futurenet:
-		default:
-			return fmt.Errorf("invalid network: %s", authNetworkFlag)
-		}
-		return nil
+		_, err := config.ResolveNetwork(authNetworkFlag)
+		return err
 	},
 	RunE: func(cmd *cobra.Command, args []string) error {
 		txHash := args[0]
 
-		client := rpc.NewClient(rpc.Network(authNetworkFlag))
-		if authRPCURLFlag != "" {
-			client = rpc.NewClientWithURL(authRPCURLFlag, rpc.Network(authNetworkFlag))
+		client, err := config.NewNetworkClient(authNetworkFlag, authRPCURLFlag, "")
+		if err != nil {
+			return err
 		}
 
 		logger.Logger.Info("Fetching transaction for auth analysis", "tx_hash", txHash)
@@ -64,16 +61,15 @@ Examples:
 			return fmt.Errorf("failed to fetch transaction: %w", err)
 		}
 
-		fmt.Printf("Transaction Envelope: %d bytes
-", len(resp.EnvelopeXdr))
+		fmt.Printf("Transaction Envelope: %d bytes\n", len(resp.EnvelopeXdr))
 
-		config := authtrace.AuthTraceConfig{
+		traceConfig := authtrace.AuthTraceConfig{
 			TraceCustomContracts: true,
 			CaptureSigDetails:    true,
 			MaxEventDepth:        1000,
 		}
 
-		tracker := authtrace.NewTracker(config)
+		tracker := authtrace.NewTracker(traceConfig)
 		trace := tracker.GenerateTrace()
 		reporter := authtrace.NewDetailedReporter(trace)
 
@@ -96,26 +92,22 @@ Examples:
 
 func printDetailedAnalysis(reporter *authtrace.DetailedReporter) {
 	metrics := reporter.SummaryMetrics()
-	fmt.Println("
---- SUMMARY METRICS ---")
+	fmt.Println("\n--- SUMMARY METRICS ---")
 	for key, value := range metrics {
-		fmt.Printf("%s: %v
-", key, value)
+		fmt.Printf("%s: %v\n", key, value)
 	}
 
 	missingKeys := reporter.IdentifyMissingKeys()
 	if len(missingKeys) > 0 {
-		fmt.Println("
---- MISSING SIGNATURES ---")
+		fmt.Println("\n--- MISSING SIGNATURES ---")
 		for _, signer := range missingKeys {
-			fmt.Printf("  - %s (required weight: %d)
-", signer.SignerKey, signer.Weight)
+			fmt.Printf("  - %s (required weight: %d)\n", signer.SignerKey, signer.Weight)
 		}
 	}
 }
 
 func init() {
-	authDebugCmd.Flags().StringVarP(&authNetworkFlag, "network", "n", string(rpc.Mainnet), "Stellar network (testnet, mainnet, futurenet)")
+	authDebugCmd.Flags().StringVarP(&authNetworkFlag, "network", "n", string(rpc.Mainnet), "Stellar network (testnet, mainnet, futurenet or a saved custom network)")
 	authDebugCmd.Flags().StringVar(&authRPCURLFlag, "rpc-url", "", "Custom Horizon RPC URL")
 	authDebugCmd.Flags().BoolVar(&authDetailedFlag, "detailed", false, "Show detailed analysis and missing signatures")
 	authDebugCmd.Flags().BoolVar(&authJSONOutputFlag, "json", false, "Output as JSON")
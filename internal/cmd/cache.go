@@ -20,6 +20,7 @@ import (
 	"path/filepath"
 
 	"github.com/dotandev/hintents/internal/cache"
+	"github.com/dotandev/hintents/internal/rpc"
 	"github.com/spf13/cobra"
 )
 
@@ -36,6 +37,24 @@ func getCacheDir() string {
 	return filepath.Join(homeDir, ".erst", "cache")
 }
 
+// configureLedgerCache attaches the on-disk ledger entry cache to client
+// according to the --cache-mode and --offline flags.
+func configureLedgerCache(client *rpc.Client, network string) error {
+	mode, err := rpc.ParseCacheMode(cacheModeFlag)
+	if err != nil {
+		return err
+	}
+	if offlineFlag {
+		mode = rpc.CacheModeOffline
+	}
+	if mode == rpc.CacheModeOff {
+		return nil
+	}
+
+	client.LedgerCache = rpc.NewLedgerCache(getCacheDir(), network, mode)
+	return nil
+}
+
 var cacheCmd = &cobra.Command{
 	Use:   "cache",
 	Short: "Manage transaction and simulation cache",
@@ -84,19 +103,13 @@ var cacheStatusCmd = &cobra.Command{
 			return fmt.Errorf("Error: failed to list cache files: %w", err)
 		}
 
-		fmt.Printf("Cache directory: %s
-", cacheDir)
-		fmt.Printf("Cache size: %s
-", formatBytes(size))
-		fmt.Printf("Files cached: %d
-", len(files))
-		fmt.Printf("Maximum size: %s
-", formatBytes(cache.DefaultConfig().MaxSizeBytes))
+		fmt.Printf("Cache directory: %s\n", cacheDir)
+		fmt.Printf("Cache size: %s\n", formatBytes(size))
+		fmt.Printf("Files cached: %d\n", len(files))
+		fmt.Printf("Maximum size: %s\n", formatBytes(cache.DefaultConfig().MaxSizeBytes))
 
 		if size > cache.DefaultConfig().MaxSizeBytes {
-			fmt.Printf("
-⚠️  Cache size exceeds maximum limit. Run 'erst cache clean' to free space.
-")
+			fmt.Printf("\n⚠️  Cache size exceeds maximum limit. Run 'erst cache clean' to free space.\n")
 		}
 
 		return nil
@@ -160,8 +173,7 @@ var cacheClearCmd = &cobra.Command{
 
 		// Get confirmation unless force flag is set
 		if !cacheForceFlag {
-			fmt.Printf("This will delete ALL cached files in %s
-", cacheDir)
+			fmt.Printf("This will delete ALL cached files in %s\n", cacheDir)
 			fmt.Print("Are you sure? (yes/no): ")
 			var response string
 			if _, err := fmt.Scanln(&response); err != nil {
@@ -21,8 +21,10 @@ import (
 	"os/signal"
 	"syscall"
 
+	"github.com/dotandev/hintents/internal/config"
 	"github.com/dotandev/hintents/internal/daemon"
 	"github.com/dotandev/hintents/internal/rpc"
+	"github.com/dotandev/hintents/internal/simulator"
 	"github.com/dotandev/hintents/internal/telemetry"
 	"github.com/spf13/cobra"
 )
@@ -34,6 +36,8 @@ var (
 	daemonAuthToken string
 	daemonTracing   bool
 	daemonOTLPURL   string
+	daemonSimLimits = simulator.DefaultLimits
+	daemonWorkers   int
 )
 
 var daemonCmd = &cobra.Command{
@@ -67,18 +71,18 @@ Example:
 		}
 
 		// Validate network
-		switch rpc.Network(daemonNetwork) {
-		case rpc.Testnet, rpc.Mainnet, rpc.Futurenet:
-		default:
-			return fmt.Errorf("invalid network: %s. Must be one of: testnet, mainnet, futurenet", daemonNetwork)
+		if _, err := config.ResolveNetwork(daemonNetwork); err != nil {
+			return err
 		}
 
 		// Create server
 		server, err := daemon.NewServer(daemon.Config{
-			Port:      daemonPort,
-			Network:   daemonNetwork,
-			RPCURL:    daemonRPCURL,
-			AuthToken: daemonAuthToken,
+			Port:             daemonPort,
+			Network:          daemonNetwork,
+			RPCURL:           daemonRPCURL,
+			AuthToken:        daemonAuthToken,
+			SimulatorLimits:  daemonSimLimits,
+			SimulatorWorkers: daemonWorkers,
 		})
 		if err != nil {
 			return fmt.Errorf("failed to create server: %w", err)
@@ -93,18 +97,14 @@ Example:
 		signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
 		go func() {
 			<-sigChan
-			fmt.Println("
-Received interrupt signal, shutting down...")
+			fmt.Println("\nReceived interrupt signal, shutting down...")
 			cancel()
 		}()
 
-		fmt.Printf("Starting ERST daemon on port %s
-", daemonPort)
-		fmt.Printf("Network: %s
-", daemonNetwork)
+		fmt.Printf("Starting ERST daemon on port %s\n", daemonPort)
+		fmt.Printf("Network: %s\n", daemonNetwork)
 		if daemonRPCURL != "" {
-			fmt.Printf("RPC URL: %s
-", daemonRPCURL)
+			fmt.Printf("RPC URL: %s\n", daemonRPCURL)
 		}
 		if daemonAuthToken != "" {
 			fmt.Println("Authentication: enabled")
@@ -117,11 +117,16 @@ Received interrupt signal, shutting down...")
 
 func init() {
 	daemonCmd.Flags().StringVarP(&daemonPort, "port", "p", "8080", "Port to listen on")
-	daemonCmd.Flags().StringVarP(&daemonNetwork, "network", "n", string(rpc.Mainnet), "Stellar network to use (testnet, mainnet, futurenet)")
+	daemonCmd.Flags().StringVarP(&daemonNetwork, "network", "n", string(rpc.Mainnet), "Stellar network to use (testnet, mainnet, futurenet or a saved custom network)")
 	daemonCmd.Flags().StringVar(&daemonRPCURL, "rpc-url", "", "Custom Horizon RPC URL to use")
 	daemonCmd.Flags().StringVar(&daemonAuthToken, "auth-token", "", "Authentication token for API access")
 	daemonCmd.Flags().BoolVar(&daemonTracing, "tracing", false, "Enable OpenTelemetry tracing")
 	daemonCmd.Flags().StringVar(&daemonOTLPURL, "otlp-url", "http://localhost:4318", "OTLP exporter URL")
+	daemonCmd.Flags().DurationVar(&daemonSimLimits.Timeout, "sim-timeout", simulator.DefaultLimits.Timeout, "Wall-clock limit per simulation (0 disables)")
+	daemonCmd.Flags().Int64Var(&daemonSimLimits.MaxStdoutBytes, "sim-max-output", simulator.DefaultLimits.MaxStdoutBytes, "Maximum simulator response size in bytes (0 disables)")
+	daemonCmd.Flags().Uint64Var(&daemonSimLimits.MaxMemoryBytes, "sim-max-memory", 0, "Simulator address space limit in bytes, Linux only (0 disables)")
+	daemonCmd.Flags().DurationVar(&daemonSimLimits.MaxCPUTime, "sim-max-cpu", 0, "Simulator CPU time limit, Linux only (0 disables)")
+	daemonCmd.Flags().IntVar(&daemonWorkers, "sim-workers", 4, "Number of long-lived simulator processes (0 starts one per simulation)")
 
 	rootCmd.AddCommand(daemonCmd)
 }
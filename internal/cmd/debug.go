@@ -15,36 +15,30 @@
 package cmd
 
 import (
+	"context"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
 	"sync"
 	"time"
 
-	"github.com/dotandev/hintents/internal/errors"
+	"github.com/dotandev/hintents/internal/config"
+	"github.com/dotandev/hintents/internal/contractspec"
+	"github.com/dotandev/hintents/internal/decoder"
 	"github.com/dotandev/hintents/internal/localization"
+	"github.com/dotandev/hintents/internal/logger"
 	"github.com/dotandev/hintents/internal/rpc"
-	"github.com/dotandev/hintents/internal/simulator"
-	"github.com/dotandev/hintents/internal/telemetry"
-	"github.com/spf13/cobra"
-	"go.opentelemetry.io/otel/attribute"
-)
-
-var (
-	networkFlag     string
-	rpcURLFlag      string
-	rpcTokenFlag    string
-	tracingEnabled  bool
-	otlpExporterURL string
-	generateTrace   bool
-	traceOutputFile string
 	"github.com/dotandev/hintents/internal/security"
 	"github.com/dotandev/hintents/internal/session"
 	"github.com/dotandev/hintents/internal/simulator"
 	"github.com/dotandev/hintents/internal/snapshot"
 	"github.com/dotandev/hintents/internal/telemetry"
 	"github.com/dotandev/hintents/internal/tokenflow"
+	"github.com/dotandev/hintents/internal/trace"
+	"github.com/dotandev/hintents/internal/wasmdebug"
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 	"github.com/stellar/go/xdr"
@@ -54,6 +48,7 @@ var (
 var (
 	networkFlag        string
 	rpcURLFlag         string
+	rpcTokenFlag       string
 	tracingEnabled     bool
 	otlpExporterURL    string
 	generateTrace      bool
@@ -62,7 +57,20 @@ var (
 	compareNetworkFlag string
 	verbose            bool
 	wasmPath           string
+	wasmDebugPath      string
+	contractSpecPath   string
 	args               []string
+	offlineFlag        bool
+	cacheModeFlag      string
+	simTimeoutFlag     time.Duration
+
+	// contractSpecs decodes the calls and errors of traces with the specs of
+	// their contracts
+	contractSpecs *contractspec.Contracts
+	// replayEntries and replayWrites are the ledger entries the replayed
+	// transaction started from and the ones it wrote, the host state of traces
+	replayEntries map[string]string
+	replayWrites  map[string]string
 )
 
 // DebugCommand holds dependencies for the debug command
@@ -88,32 +96,26 @@ Example:
 		Args: cobra.ExactArgs(1),
 		PreRunE: func(cmd *cobra.Command, args []string) error {
 			// Validate network flag
-			switch rpc.Network(networkFlag) {
-			case rpc.Testnet, rpc.Mainnet, rpc.Futurenet:
-				return nil
-			default:
-				return fmt.Errorf("invalid network: %s. Must be one of: testnet, mainnet, futurenet", networkFlag)
-			}
+			_, err := config.ResolveNetwork(networkFlag)
+			return err
 		},
 		RunE: d.runDebug,
 	}
-	
+
 	// Set up flags
-	cmd.Flags().StringVarP(&networkFlag, "network", "n", string(rpc.Mainnet), "Stellar network to use (testnet, mainnet, futurenet)")
+	cmd.Flags().StringVarP(&networkFlag, "network", "n", string(rpc.Mainnet), "Stellar network to use (testnet, mainnet, futurenet or a saved custom network)")
 	cmd.Flags().StringVar(&rpcURLFlag, "rpc-url", "", "Custom Horizon RPC URL to use")
 	cmd.Flags().StringVar(&rpcTokenFlag, "rpc-token", "", "RPC authentication token (can also use ERST_RPC_TOKEN env var)")
-	
+
 	return cmd
 }
 
 func (d *DebugCommand) runDebug(cmd *cobra.Command, args []string) error {
 	txHash := args[0]
 
-	var client *rpc.Client
-	if rpcURLFlag != "" {
-		client = rpc.NewClientWithURL(rpcURLFlag, rpc.Network(networkFlag), rpcTokenFlag)
-	} else {
-		client = rpc.NewClient(rpc.Network(networkFlag), rpcTokenFlag)
+	client, err := config.NewNetworkClient(networkFlag, rpcURLFlag, rpcTokenFlag)
+	if err != nil {
+		return err
 	}
 
 	fmt.Printf("Debugging transaction: %s\n", txHash)
@@ -129,14 +131,12 @@ func (d *DebugCommand) runDebug(cmd *cobra.Command, args []string) error {
 	}
 
 	fmt.Printf("Transaction fetched successfully. Envelope size: %d bytes\n", len(resp.EnvelopeXdr))
-	
+	printTransactionResult(resp)
+
 	// TODO: Use d.Runner for simulation when ready
-	// simReq := &simulator.SimulationRequest{
-	//     EnvelopeXdr: resp.EnvelopeXdr,
-	//     ResultMetaXdr: resp.ResultMetaXdr,
-	// }
+	// simReq, err := buildReplayRequest(resp, nil, TimestampFlag)
 	// simResp, err := d.Runner.Run(simReq)
-	
+
 	return nil
 }
 
@@ -155,7 +155,21 @@ through the local simulator, and displays detailed execution traces including:
 The simulation results are stored in a session that can be saved for later analysis.
 
 Local WASM Replay Mode:
-  Use --wasm flag to test contracts locally without network data.`,
+  Use --wasm flag to test contracts locally without network data.
+
+Source Mapping:
+  Use --wasm-debug with a build of the contract that kept its DWARF debug info
+  to map the code offsets the simulator reports, such as where a contract
+  trapped, to Rust functions and source lines. The build must be the code the
+  simulator ran, so offsets line up.
+
+Contract Interfaces:
+  Call arguments and contract errors are decoded with the contractspecv0
+  interface of each contract, fetched with its WASM code and cached per WASM
+  hash, so traces show "amount: 100" and "InsufficientAllowance" instead of
+  positional values and "Error(Contract, #7)". In local replay the interface
+  is read from --wasm. Use --contract-spec to give the interface of contracts
+  whose code cannot be fetched.`,
 	Example: `  # Debug a transaction on mainnet
   erst debug 5c0a1234567890abcdef1234567890abcdef1234567890abcdef1234567890ab
 
@@ -166,7 +180,13 @@ Local WASM Replay Mode:
   erst debug --network mainnet --compare-network testnet abc123...def789
 
   # Local WASM replay (no network required)
-  erst debug --wasm ./contract.wasm --args "arg1" --args "arg2"`,
+  erst debug --wasm ./contract.wasm --args "arg1" --args "arg2"
+
+  # Map a trap to Rust source lines
+  erst debug --wasm ./contract.wasm --wasm-debug ./contract.wasm --args "arg1"
+
+  # Decode the calls of a contract whose code cannot be fetched
+  erst debug --contract-spec ./contract.wasm abc123...def789`,
 	Args: cobra.MaximumNArgs(1),
 	PreRunE: func(cmd *cobra.Command, args []string) error {
 		// Local WASM replay mode doesn't need transaction hash
@@ -183,28 +203,34 @@ Local WASM Replay Mode:
 		}
 
 		// Validate network flag
-		switch rpc.Network(networkFlag) {
-		case rpc.Testnet, rpc.Mainnet, rpc.Futurenet:
-			// valid
-		default:
-			return errors.WrapInvalidNetwork(networkFlag)
+		if _, err := config.ResolveNetwork(networkFlag); err != nil {
+			return err
+		}
+
+		if _, err := rpc.ParseCacheMode(cacheModeFlag); err != nil {
+			return err
 		}
 
 		// Validate compare network flag if present
 		if compareNetworkFlag != "" {
-			switch rpc.Network(compareNetworkFlag) {
-			case rpc.Testnet, rpc.Mainnet, rpc.Futurenet:
-				// valid
-			default:
-				return fmt.Errorf("invalid compare-network: %s. Must be one of: testnet, mainnet, futurenet", compareNetworkFlag)
+			if _, err := config.ResolveNetwork(compareNetworkFlag); err != nil {
+				return fmt.Errorf("invalid compare-network: %w", err)
 			}
 		}
 		return nil
 	},
 	RunE: func(cmd *cobra.Command, cmdArgs []string) error {
+		debugInfo, err := loadWasmDebug()
+		if err != nil {
+			return err
+		}
+
 		// Local WASM replay mode
 		if wasmPath != "" {
-			return runLocalWasmReplay()
+			if err := loadContractSpecs(cmd.Context(), nil); err != nil {
+				return err
+			}
+			return runLocalWasmReplay(cmd.Context(), debugInfo)
 		}
 
 		// Network transaction replay mode
@@ -233,41 +259,48 @@ Local WASM Replay Mode:
 		)
 		defer span.End()
 
-		client := rpc.NewClient(rpc.Network(networkFlag))
-		horizonURL := ""
+		client, err := config.NewNetworkClient(networkFlag, rpcURLFlag, rpcTokenFlag)
+		if err != nil {
+			return err
+		}
+		horizonURL := client.Config.HorizonURL
 		if rpcURLFlag != "" {
-			client = rpc.NewClientWithURL(rpcURLFlag, rpc.Network(networkFlag), rpcTokenFlag)
 			horizonURL = rpcURLFlag
-		} else {
-			switch rpc.Network(networkFlag) {
-			case rpc.Testnet:
-				horizonURL = rpc.TestnetHorizonURL
-			case rpc.Futurenet:
-				horizonURL = rpc.FuturenetHorizonURL
-			default:
-				horizonURL = rpc.MainnetHorizonURL
-			}
 		}
 
-		fmt.Printf("Fetching transaction: %s
-", txHash)
+		if err := configureLedgerCache(client, networkFlag); err != nil {
+			return err
+		}
+		if err := loadContractSpecs(ctx, client); err != nil {
+			return err
+		}
+
+		fmt.Printf("Fetching transaction: %s\n", txHash)
 		resp, err := client.GetTransaction(ctx, txHash)
 		if err != nil {
 			return fmt.Errorf(localization.Get("error.fetch_transaction"), err)
 		}
 
-		fmt.Printf("Transaction fetched successfully. Envelope size: %d bytes
-", len(resp.EnvelopeXdr))
+		fmt.Printf("Transaction fetched successfully. Envelope size: %d bytes\n", len(resp.EnvelopeXdr))
+		printTransactionResult(resp)
+		// Entries cached before this ledger may predate the transaction
+		client.LedgerSequence = resp.LedgerSequence
 
 		// Extract ledger keys for replay
-		keys, err := extractLedgerKeys(resp.ResultMetaXdr)
+		keys, err := extractLedgerKeys(resp.EnvelopeXdr, resp.ResultMetaXdr)
 		if err != nil {
 			return fmt.Errorf("failed to extract ledger keys: %w", err)
 		}
 
-		runner, err := simulator.NewRunner()
+		// Roll the fetched (current) state back to what the transaction saw
+		preState, err := snapshot.ReconstructTransaction(resp.EnvelopeXdr, resp.ResultMetaXdr)
 		if err != nil {
-			return fmt.Errorf("failed to initialize simulator: %w", err)
+			return fmt.Errorf("failed to reconstruct ledger state: %w", err)
+		}
+		printReconstruction(preState)
+		replayWrites, err = snapshot.Writes(resp.ResultMetaXdr)
+		if err != nil {
+			return fmt.Errorf("failed to read ledger writes: %w", err)
 		}
 
 		// Determine timestamps to simulate
@@ -280,13 +313,34 @@ Local WASM Replay Mode:
 			}
 		}
 
+		runner, err := simulator.NewRunner("", verbose)
+		if err != nil {
+			return fmt.Errorf("failed to initialize simulator: %w", err)
+		}
+		runner.Limits.Timeout = simTimeoutFlag
+
+		// Sweeps and comparisons run several simulations, keep the simulator
+		// processes alive between them instead of spawning one per run
+		var sim simulator.RunnerInterface = runner
+		if len(timestamps) > 1 || compareNetworkFlag != "" {
+			workers := 1
+			if compareNetworkFlag != "" {
+				workers = 2
+			}
+			pool, err := simulator.NewPool(runner, workers)
+			if err != nil {
+				logger.Logger.Warn("Simulator pool unavailable, starting one process per simulation", "error", err)
+			} else {
+				defer pool.Close()
+				sim = pool
+			}
+		}
+
 		var lastSimResp *simulator.SimulationResponse
 
 		for _, ts := range timestamps {
 			if len(timestamps) > 1 {
-				fmt.Printf("
---- Simulating at Timestamp: %d ---
-", ts)
+				fmt.Printf("\n--- Simulating at Timestamp: %d ---\n", ts)
 			}
 
 			var simResp *simulator.SimulationResponse
@@ -305,34 +359,19 @@ Local WASM Replay Mode:
 					if err != nil {
 						return fmt.Errorf("failed to fetch ledger entries: %w", err)
 					}
+					ledgerEntries = preState.Apply(ledgerEntries)
 				}
 
-				fmt.Printf("Running simulation on %s...
-", networkFlag)
-				simReq := &simulator.SimulationRequest{
-					EnvelopeXdr:   resp.EnvelopeXdr,
-					ResultMetaXdr: resp.ResultMetaXdr,
-					LedgerEntries: entries,
-				})
-			}()
-
-			go func() {
-				defer wg.Done()
-				
-				compareClient := rpc.NewClient(rpc.Network(compareNetworkFlag), "")
-				
-				// Fetch entries
-				compareEntries, err := compareClient.GetLedgerEntries(cmd.Context(), keys)
-				compareClient := rpc.NewClient(rpc.Network(compareNetworkFlag))
-				entries, err := compareClient.GetLedgerEntries(ctx, keys)
-					LedgerEntries: ledgerEntries,
-					Timestamp:     ts,
+				fmt.Printf("Running simulation on %s...\n", networkFlag)
+				simReq, err := buildReplayRequest(resp, ledgerEntries, ts)
+				if err != nil {
+					return err
 				}
-				simResp, err = runner.Run(simReq)
+				replayEntries = ledgerEntries
+				simResp, err = sim.RunContext(ctx, simReq)
 				if err != nil {
 					if len(timestamps) > 1 {
-						fmt.Printf("Simulation failed at timestamp %d: %v
-", ts, err)
+						fmt.Printf("Simulation failed at timestamp %d: %v\n", ts, err)
 						continue
 					}
 					return fmt.Errorf("simulation failed: %w", err)
@@ -343,6 +382,7 @@ Local WASM Replay Mode:
 				var wg sync.WaitGroup
 				var primaryResult, compareResult *simulator.SimulationResponse
 				var primaryErr, compareErr error
+				var primaryEntries map[string]string
 
 				wg.Add(2)
 				go func() {
@@ -352,28 +392,37 @@ Local WASM Replay Mode:
 						primaryErr = err
 						return
 					}
-					primaryResult, primaryErr = runner.Run(&simulator.SimulationRequest{
-						EnvelopeXdr:   resp.EnvelopeXdr,
-						ResultMetaXdr: resp.ResultMetaXdr,
-						LedgerEntries: entries,
-						Timestamp:     ts,
-					})
+					primaryEntries = preState.Apply(entries)
+					simReq, err := buildReplayRequest(resp, primaryEntries, ts)
+					if err != nil {
+						primaryErr = err
+						return
+					}
+					primaryResult, primaryErr = sim.RunContext(ctx, simReq)
 				}()
 
 				go func() {
 					defer wg.Done()
-					compareClient := rpc.NewClient(rpc.Network(compareNetworkFlag))
+					compareClient, err := config.NewNetworkClient(compareNetworkFlag, "", "")
+					if err != nil {
+						compareErr = err
+						return
+					}
+					if err := configureLedgerCache(compareClient, compareNetworkFlag); err != nil {
+						compareErr = err
+						return
+					}
 					entries, err := compareClient.GetLedgerEntries(ctx, keys)
 					if err != nil {
 						compareErr = err
 						return
 					}
-					compareResult, compareErr = runner.Run(&simulator.SimulationRequest{
-						EnvelopeXdr:   resp.EnvelopeXdr,
-						ResultMetaXdr: resp.ResultMetaXdr,
-						LedgerEntries: entries,
-						Timestamp:     ts,
-					})
+					simReq, err := buildReplayRequest(resp, entries, ts)
+					if err != nil {
+						compareErr = err
+						return
+					}
+					compareResult, compareErr = sim.RunContext(ctx, simReq)
 				}()
 
 				wg.Wait()
@@ -385,6 +434,7 @@ Local WASM Replay Mode:
 				}
 
 				simResp = primaryResult // Use primary for further analysis
+				replayEntries = primaryEntries
 				printSimulationResult(networkFlag, primaryResult)
 				printSimulationResult(compareNetworkFlag, compareResult)
 				diffResults(primaryResult, compareResult, networkFlag, compareNetworkFlag)
@@ -395,34 +445,47 @@ Local WASM Replay Mode:
 		if lastSimResp == nil {
 			return fmt.Errorf("no simulation results generated")
 		}
+		if pool, ok := sim.(*simulator.Pool); ok && verbose {
+			stats := pool.Stats()
+			fmt.Printf("Simulator pool: %d workers, %d requests, %d restarts, avg %s\n",
+				stats.Size, stats.Requests, stats.Restarts, stats.AvgLatency.Round(time.Millisecond))
+		}
+
+		if generateTrace || traceOutputFile != "" {
+			path, err := writeExecutionTrace(txHash, lastSimResp)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Execution trace written to %s\n", path)
+		}
+		if ProfileFlag {
+			if err := writeFlamegraph(txHash, lastSimResp); err != nil {
+				return err
+			}
+		}
+		if debugInfo != nil {
+			printSourceLocations(debugInfo, lastSimResp)
+		}
 
 		// Analysis: Security
-		fmt.Printf("
-=== Security Analysis ===
-")
+		fmt.Printf("\n=== Security Analysis ===\n")
 		secDetector := security.NewDetector()
 		findings := secDetector.Analyze(resp.EnvelopeXdr, resp.ResultMetaXdr, lastSimResp.Events, lastSimResp.Logs)
 		if len(findings) == 0 {
 			fmt.Println("✓ No security issues detected")
 		} else {
 			for i, f := range findings {
-				fmt.Printf("%d. [%s] %s: %s
-", i+1, f.Severity, f.Title, f.Description)
+				fmt.Printf("%d. [%s] %s: %s\n", i+1, f.Severity, f.Title, f.Description)
 			}
 		}
 
 		// Analysis: Token Flows
 		if report, err := tokenflow.BuildReport(resp.EnvelopeXdr, resp.ResultMetaXdr); err == nil && len(report.Agg) > 0 {
-			fmt.Printf("
-Token Flow Summary:
-")
+			fmt.Printf("\nToken Flow Summary:\n")
 			for _, line := range report.SummaryLines() {
-				fmt.Printf("  %s
-", line)
+				fmt.Printf("  %s\n", line)
 			}
-			fmt.Printf("
-Token Flow Chart (Mermaid):
-")
+			fmt.Printf("\nToken Flow Chart (Mermaid):\n")
 			fmt.Println(report.MermaidFlowchart())
 		}
 
@@ -434,17 +497,19 @@ Token Flow Chart (Mermaid):
 			HorizonURL:    horizonURL,
 			TxHash:        txHash,
 			EnvelopeXdr:   resp.EnvelopeXdr,
+			ResultXdr:     resp.ResultXdr,
 			ResultMetaXdr: resp.ResultMetaXdr,
 		}
+		if simResponseJSON, err := json.Marshal(lastSimResp); err == nil {
+			sessionData.SimResponseJSON = string(simResponseJSON)
+		}
 		SetCurrentSession(sessionData)
-		fmt.Printf("
-Session ready. Use 'erst session save' to persist.
-")
+		fmt.Printf("\nSession ready. Use 'erst session save' to persist.\n")
 		return nil
 	},
 }
 
-func runLocalWasmReplay() error {
+func runLocalWasmReplay(ctx context.Context, debugInfo *wasmdebug.DebugInfo) error {
 	color.Yellow("⚠️  WARNING: Using Mock State (not mainnet data)")
 	fmt.Println()
 
@@ -454,30 +519,31 @@ func runLocalWasmReplay() error {
 	}
 
 	color.Cyan("🔧 Local WASM Replay Mode")
-	fmt.Printf("WASM File: %s
-", wasmPath)
-	fmt.Printf("Arguments: %v
-", args)
+	fmt.Printf("WASM File: %s\n", wasmPath)
+	fmt.Printf("Arguments: %v\n", args)
 	fmt.Println()
 
 	// Create simulator runner
-	runner, err := simulator.NewRunner()
+	runner, err := simulator.NewRunner("", verbose)
 	if err != nil {
 		return fmt.Errorf("failed to initialize simulator: %w", err)
 	}
-
-	// Create simulation request with local WASM
-	req := &simulator.SimulationRequest{
-		EnvelopeXdr:   "",  // Empty for local replay
-		ResultMetaXdr: "",  // Empty for local replay
-		LedgerEntries: nil, // Mock state will be generated
-		WasmPath:      &wasmPath,
-		MockArgs:      &args,
+	runner.Limits.Timeout = simTimeoutFlag
+
+	// Create simulation request with local WASM; mock state will be generated
+	req, err := simulator.NewSimulationRequestBuilder().
+		WithWasmPath(wasmPath).
+		WithMockArgs(args).
+		WithTimestamp(TimestampFlag).
+		WithProfile(ProfileFlag).
+		Build()
+	if err != nil {
+		return fmt.Errorf("invalid simulation request: %w", err)
 	}
 
 	// Run simulation
 	color.Green("▶ Executing contract locally...")
-	resp, err := runner.Run(req)
+	resp, err := runner.RunContext(ctx, req)
 	if err != nil {
 		color.Red("✗ Execution failed: %v", err)
 		return err
@@ -491,8 +557,7 @@ func runLocalWasmReplay() error {
 	if len(resp.Logs) > 0 {
 		color.Cyan("📋 Logs:")
 		for _, log := range resp.Logs {
-			fmt.Printf("  %s
-", log)
+			fmt.Printf("  %s\n", log)
 		}
 		fmt.Println()
 	}
@@ -500,12 +565,20 @@ func runLocalWasmReplay() error {
 	if len(resp.Events) > 0 {
 		color.Cyan("📡 Events:")
 		for _, event := range resp.Events {
-			fmt.Printf("  %s
-", event)
+			fmt.Printf("  %s\n", event)
 		}
 		fmt.Println()
 	}
 
+	if ProfileFlag {
+		if err := writeFlamegraph("local", resp); err != nil {
+			return err
+		}
+	}
+	if debugInfo != nil {
+		printSourceLocations(debugInfo, resp)
+	}
+
 	if verbose {
 		color.Cyan("🔍 Full Response:")
 		jsonBytes, _ := json.MarshalIndent(resp, "", "  ")
@@ -515,7 +588,27 @@ func runLocalWasmReplay() error {
 	return nil
 }
 
-func extractLedgerKeys(metaXdr string) ([]string, error) {
+// buildReplayRequest assembles the simulation request replaying a fetched
+// transaction against entries, in the ledger the transaction executed in
+func buildReplayRequest(resp *rpc.TransactionResponse, entries map[string]string, timestamp int64) (*simulator.SimulationRequest, error) {
+	req, err := simulator.NewSimulationRequestBuilder().
+		WithEnvelopeXDR(resp.EnvelopeXdr).
+		WithResultMetaXDR(resp.ResultMetaXdr).
+		WithLedgerEntries(entries).
+		WithTimestamp(timestamp).
+		WithLedgerSequence(resp.LedgerSequence).
+		WithProfile(ProfileFlag).
+		Build()
+	if err != nil {
+		return nil, fmt.Errorf("invalid simulation request: %w", err)
+	}
+	return req, nil
+}
+
+// extractLedgerKeys returns the keys a transaction touched: those its meta
+// changes and those of its Soroban footprint, which the meta of a failed
+// transaction does not record
+func extractLedgerKeys(envelopeXdr, metaXdr string) ([]string, error) {
 	data, err := base64.StdEncoding.DecodeString(metaXdr)
 	if err != nil {
 		return nil, err
@@ -527,71 +620,24 @@ func extractLedgerKeys(metaXdr string) ([]string, error) {
 	}
 
 	keysMap := make(map[string]struct{})
-	addKey := func(k xdr.LedgerKey) {
-		b, _ := k.MarshalBinary()
-		keysMap[base64.StdEncoding.EncodeToString(b)] = struct{}{}
-	}
-
-	collectChanges := func(changes xdr.LedgerEntryChanges) {
-		for _, c := range changes {
-			switch c.Type {
-			case xdr.LedgerEntryChangeTypeLedgerEntryCreated:
-				k, err := c.Created.LedgerKey()
-				if err == nil {
-					addKey(k)
-				}
-			case xdr.LedgerEntryChangeTypeLedgerEntryUpdated:
-				k, err := c.Updated.LedgerKey()
-				if err == nil {
-					addKey(k)
-				}
-			case xdr.LedgerEntryChangeTypeLedgerEntryRemoved:
-				if c.Removed != nil {
-					addKey(*c.Removed)
-				}
-			case xdr.LedgerEntryChangeTypeLedgerEntryState:
-				k, err := c.State.LedgerKey()
-				if err == nil {
-					addKey(k)
-				}
+	fee, apply := snapshot.LedgerChanges(meta)
+	for _, changes := range []xdr.LedgerEntryChanges{fee, apply} {
+		for i := range changes {
+			k, err := changes[i].LedgerKey()
+			if err != nil {
+				continue
 			}
+			b, _ := k.MarshalBinary()
+			keysMap[base64.StdEncoding.EncodeToString(b)] = struct{}{}
 		}
 	}
 
-	// 1. Fee processing changes
-	collectChanges(meta.FeeProcessing)
-
-	// 2. Transaction apply processing changes
-	switch meta.TxApplyProcessing.V {
-	case 0:
-		if meta.TxApplyProcessing.Operations != nil {
-			for _, op := range *meta.TxApplyProcessing.Operations {
-				collectChanges(op.Changes)
-			}
-		}
-	case 1:
-		if v1 := meta.TxApplyProcessing.V1; v1 != nil {
-			collectChanges(v1.TxChanges)
-			for _, op := range v1.Operations {
-				collectChanges(op.Changes)
-			}
-		}
-	case 2:
-		if v2 := meta.TxApplyProcessing.V2; v2 != nil {
-			collectChanges(v2.TxChangesBefore)
-			collectChanges(v2.TxChangesAfter)
-			for _, op := range v2.Operations {
-				collectChanges(op.Changes)
-			}
-		}
-	case 3:
-		if v3 := meta.TxApplyProcessing.V3; v3 != nil {
-			collectChanges(v3.TxChangesBefore)
-			collectChanges(v3.TxChangesAfter)
-			for _, op := range v3.Operations {
-				collectChanges(op.Changes)
-			}
-		}
+	footprint, err := snapshot.FootprintKeys(envelopeXdr)
+	if err != nil {
+		return nil, err
+	}
+	for _, k := range footprint {
+		keysMap[k] = struct{}{}
 	}
 
 	res := make([]string, 0, len(keysMap))
@@ -601,44 +647,277 @@ func extractLedgerKeys(metaXdr string) ([]string, error) {
 	return res, nil
 }
 
+// printReconstruction reports how much of the pre-execution state could be
+// rebuilt from the transaction meta. Keys that could not be rolled back are
+// replayed with their current value, which may differ from the failing ledger.
+func printReconstruction(r *snapshot.Reconstruction) {
+	fmt.Printf("Reconstructed %d ledger entries at the transaction's ledger (%d absent before the transaction)\n",
+		len(r.Entries), len(r.Absent))
+	if len(r.Unreconstructed) == 0 {
+		return
+	}
+	color.Yellow("Warning: %d ledger entries could not be reconstructed and will use current state", len(r.Unreconstructed))
+	if verbose {
+		for _, key := range r.Unreconstructed {
+			fmt.Printf("  %s\n", key)
+		}
+	}
+}
+
+// printTransactionResult explains the result the network recorded for a
+// fetched transaction, pointing at the operation that failed
+func printTransactionResult(resp *rpc.TransactionResponse) {
+	if resp.ResultXdr == "" {
+		return
+	}
+	result, err := decoder.AnalyzeResult(resp.ResultXdr)
+	if err != nil {
+		logger.Logger.Warn("Failed to decode transaction result", "error", err)
+		return
+	}
+	env, err := decoder.AnalyzeEnvelope(resp.EnvelopeXdr)
+	if err != nil {
+		logger.Logger.Debug("Failed to decode transaction envelope", "error", err)
+		env = nil
+	}
+
+	fmt.Printf("\n=== On-Chain Result ===\n")
+	_ = decoder.WriteResult(os.Stdout, result, env)
+}
+
 func printSimulationResult(network string, res *simulator.SimulationResponse) {
-	fmt.Printf("
---- Result for %s ---
-", network)
-	fmt.Printf("Status: %s
-", res.Status)
+	fmt.Printf("\n--- Result for %s ---\n", network)
+	fmt.Printf("Status: %s\n", res.Status)
 	if res.Error != "" {
-		fmt.Printf("Error: %s
-", res.Error)
+		fmt.Printf("Error: %s\n", res.Error)
+	}
+	fmt.Printf("Events: %d, Logs: %d\n", len(res.Events), len(res.Logs))
+	printErrorHints(res)
+}
+
+// printErrorHints explains the host errors of a simulation, from its error
+// message and its diagnostic error events, once each
+func printErrorHints(res *simulator.SimulationResponse) {
+	var hints []decoder.ScErrorInfo
+	seen := make(map[string]bool)
+	add := func(info decoder.ScErrorInfo) {
+		if !seen[info.Error] {
+			seen[info.Error] = true
+			hints = append(hints, info)
+		}
+	}
+
+	if info, ok := decoder.ExplainErrorMessage(res.Error); ok {
+		add(info)
+	}
+	for _, event := range res.Events {
+		if scErr, ok := event.ScError(); ok {
+			add(decoder.ExplainScError(scErr))
+		}
+	}
+
+	for _, info := range hints {
+		fmt.Printf("\n%s\n", info.Error)
+		fmt.Printf("  %s\n", info.Explanation)
+		fmt.Printf("  Fix: %s\n", info.Remediation)
+	}
+}
+
+// writeExecutionTrace records the steps of a simulation to --trace-output, or
+// to trace-<hash>.etrace. Files ending in .json are written in the JSON trace
+// format, anything else as a stream that is written step by step. The
+// simulator returns all its events in one response, so resp is in memory
+// either way; only the trace file itself is streamed.
+func writeExecutionTrace(txHash string, resp *simulator.SimulationResponse) (string, error) {
+	path := traceOutputFile
+	if path == "" {
+		path = fmt.Sprintf("trace-%s%s", txHash[:min(8, len(txHash))], trace.StreamFileExtension)
+	}
+
+	if filepath.Ext(path) == ".json" {
+		data, err := simulationTrace(txHash, resp).ToJSON()
+		if err != nil {
+			return "", fmt.Errorf("failed to encode trace: %w", err)
+		}
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			return "", fmt.Errorf("failed to write trace file: %w", err)
+		}
+		return path, nil
+	}
+
+	writer, err := trace.CreateStreamFile(path, txHash, trace.StreamOptions{})
+	if err != nil {
+		return "", err
+	}
+	for _, state := range trace.ExecutionStates(traceResponse(resp)) {
+		if err := writer.AddState(state); err != nil {
+			writer.Close()
+			return "", err
+		}
+	}
+	return path, writer.Close()
+}
+
+// loadWasmDebug reads the DWARF debug info of --wasm-debug, nil without it
+func loadWasmDebug() (*wasmdebug.DebugInfo, error) {
+	if wasmDebugPath == "" {
+		return nil, nil
+	}
+	return wasmdebug.Load(wasmDebugPath)
+}
+
+// loadContractSpecs sets up contractSpecs: specs are fetched through client in
+// network mode, and --contract-spec, or in local replay the --wasm module,
+// gives the spec of contracts without one
+func loadContractSpecs(ctx context.Context, client *rpc.Client) error {
+	var loader *contractspec.Loader
+	if client != nil {
+		loader = contractspec.NewLoader(client, getCacheDir())
+	}
+	contractSpecs = contractspec.NewContracts(ctx, loader)
+
+	if contractSpecPath == "" {
+		if wasmPath != "" {
+			spec, err := contractspec.Load(wasmPath)
+			if err != nil {
+				logger.Logger.Debug("Local contract has no spec", "wasm", wasmPath, "error", err)
+				return nil
+			}
+			contractSpecs.Default = spec
+		}
+		return nil
+	}
+
+	spec, err := contractspec.Load(contractSpecPath)
+	if err != nil {
+		return err
+	}
+	contractSpecs.Default = spec
+	return nil
+}
+
+// printSourceLocations maps the code offsets the simulator reported, such as
+// where a contract trapped, to Rust source lines
+func printSourceLocations(debugInfo *wasmdebug.DebugInfo, resp *simulator.SimulationResponse) {
+	fmt.Printf("\n=== Source Locations ===\n")
+	root, err := trace.ParseSimulationResponse(traceResponse(resp))
+	if err != nil {
+		fmt.Printf("Failed to build call tree: %v\n", err)
+		return
+	}
+	if debugInfo.Annotate(root) == 0 {
+		fmt.Println("The simulator reported no WASM code offsets")
+		return
+	}
+
+	for _, node := range root.FlattenAll() {
+		if len(node.Source) == 0 {
+			continue
+		}
+		what := node.Function
+		switch {
+		case node.Error != "":
+			what = node.Error
+		case what == "":
+			what = node.EventData
+		}
+		if node.Failed || node.Type == "error" {
+			color.Red("✗ %s", what)
+		} else {
+			fmt.Printf("• %s\n", what)
+		}
+		for _, location := range node.Source {
+			fmt.Printf("    at %s\n", location)
+		}
+	}
+}
+
+// writeFlamegraph writes the flamegraph of a simulation to
+// flamegraph-<name>.svg, and its collapsed stacks to flamegraph-<name>.folded,
+// weighted by the CPU instructions the simulator metered. It fails when the
+// simulator metered none, rather than profiling something else.
+func writeFlamegraph(name string, resp *simulator.SimulationResponse) error {
+	folded, err := trace.FoldStacks(simulationTrace(name, resp), trace.WeightCPU)
+	if err != nil {
+		return fmt.Errorf("failed to profile simulation, the simulator reported no metering: %w", err)
+	}
+
+	base := fmt.Sprintf("flamegraph-%s", name[:min(8, len(name))])
+	title := fmt.Sprintf("erst debug %s (%s)", name, folded.Weight)
+	if err := writeOutputFile(base+".svg", func(w io.Writer) error { return folded.WriteSVG(w, title) }); err != nil {
+		return err
+	}
+	if err := writeOutputFile(base+".folded", folded.Write); err != nil {
+		return err
 	}
-	fmt.Printf("Events: %d, Logs: %d
-", len(res.Events), len(res.Logs))
+	fmt.Printf("Flamegraph (%s) written to %s.svg and %s.folded\n", folded.Weight, base, base)
+	return nil
 }
 
+// simulationTrace records the steps of a simulation in an execution trace
+func simulationTrace(txHash string, resp *simulator.SimulationResponse) *trace.ExecutionTrace {
+	executionTrace := trace.NewExecutionTrace(txHash, 0)
+	for _, state := range trace.ExecutionStates(traceResponse(resp)) {
+		executionTrace.AddState(state)
+	}
+	return executionTrace
+}
+
+func traceResponse(resp *simulator.SimulationResponse) *trace.SimulationResponse {
+	traceResp := &trace.SimulationResponse{
+		Status: resp.Status,
+		Error:  resp.Error,
+		Events: resp.Events,
+		Logs:   resp.Logs,
+	}
+	if contractSpecs != nil {
+		traceResp.Contracts = contractSpecs
+	}
+	traceResp.LedgerEntries = replayEntries
+	traceResp.LedgerWrites = replayWrites
+	return traceResp
+}
+
+// diffResults prints how the calls made on the two networks differ
 func diffResults(res1, res2 *simulator.SimulationResponse, net1, net2 string) {
 	if res1.Status != res2.Status {
-		fmt.Printf("
-[DIFF] Status mismatch: %s vs %s
-", res1.Status, res2.Status)
+		fmt.Printf("\n[DIFF] Status mismatch: %s vs %s\n", res1.Status, res2.Status)
 	}
-	if len(res1.Events) != len(res2.Events) {
-		fmt.Printf("[DIFF] Events count mismatch: %d vs %d
-", len(res1.Events), len(res2.Events))
+
+	left, err := trace.ParseSimulationResponse(traceResponse(res1))
+	if err != nil {
+		fmt.Printf("[DIFF] %v\n", err)
+		return
 	}
+	right, err := trace.ParseSimulationResponse(traceResponse(res2))
+	if err != nil {
+		fmt.Printf("[DIFF] %v\n", err)
+		return
+	}
+	diff := trace.DiffTrees(left, right)
+	diff.Left, diff.Right = net1, net2
+	fmt.Println()
+	fmt.Print(diff.Render())
 }
 
 func init() {
-	debugCmd.Flags().StringVarP(&networkFlag, "network", "n", "mainnet", "Stellar network")
+	debugCmd.Flags().StringVarP(&networkFlag, "network", "n", "mainnet", "Stellar network (testnet, mainnet, futurenet or a saved custom network)")
 	debugCmd.Flags().StringVar(&rpcURLFlag, "rpc-url", "", "Custom RPC URL")
+	debugCmd.Flags().StringVar(&rpcTokenFlag, "rpc-token", "", "RPC authentication token (can also use ERST_RPC_TOKEN env var)")
 	debugCmd.Flags().BoolVar(&tracingEnabled, "tracing", false, "Enable tracing")
 	debugCmd.Flags().StringVar(&otlpExporterURL, "otlp-url", "http://localhost:4318", "OTLP URL")
-	debugCmd.Flags().BoolVar(&generateTrace, "generate-trace", false, "Generate trace file")
-	debugCmd.Flags().StringVar(&traceOutputFile, "trace-output", "", "Trace output file")
+	debugCmd.Flags().BoolVar(&generateTrace, "generate-trace", false, "Record the execution steps to a trace file for erst trace")
+	debugCmd.Flags().StringVar(&traceOutputFile, "trace-output", "", "Trace output file, .json for the JSON format (default trace-<hash>.etrace)")
 	debugCmd.Flags().StringVar(&snapshotFlag, "snapshot", "", "Snapshot file")
 	debugCmd.Flags().StringVar(&compareNetworkFlag, "compare-network", "", "Network to compare")
 	debugCmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "Enable verbose output")
 	debugCmd.Flags().StringVar(&wasmPath, "wasm", "", "Path to local WASM file for local replay (no network required)")
+	debugCmd.Flags().StringVar(&wasmDebugPath, "wasm-debug", "", "Unstripped build of the contract WASM, to map traps to Rust source lines with its DWARF debug info")
+	debugCmd.Flags().StringVar(&contractSpecPath, "contract-spec", "", "Contract WASM whose contractspecv0 interface decodes the calls and errors of contracts without a fetched one")
 	debugCmd.Flags().StringSliceVar(&args, "args", []string{}, "Mock arguments for local replay (JSON array of strings)")
+	debugCmd.Flags().BoolVar(&offlineFlag, "offline", false, "Serve ledger entries only from the local cache, without getLedgerEntries calls (the transaction is still fetched)")
+	debugCmd.Flags().StringVar(&cacheModeFlag, "cache-mode", string(rpc.CacheModeReadThrough), "Ledger entry cache mode (read-through, write-through, offline, off)")
+	debugCmd.Flags().DurationVar(&simTimeoutFlag, "sim-timeout", simulator.DefaultLimits.Timeout, "Kill the simulator if a run takes longer than this (0 disables the timeout)")
 
 	rootCmd.AddCommand(debugCmd)
 }
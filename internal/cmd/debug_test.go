@@ -15,16 +15,22 @@
 package cmd
 
 import (
+	"context"
 	"encoding/base64"
 	"encoding/json"
 	"os"
 	"path/filepath"
 	"testing"
 
+	"github.com/dotandev/hintents/internal/diagnostic"
+	"github.com/dotandev/hintents/internal/rpc"
 	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/dotandev/hintents/internal/trace"
+	"github.com/stellar/go/strkey"
 	"github.com/stellar/go/xdr"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 func TestLoadOverrideState(t *testing.T) {
@@ -179,6 +185,10 @@ func (m *MockRunner) Run(req *simulator.SimulationRequest) (*simulator.Simulatio
 	return args.Get(0).(*simulator.SimulationResponse), args.Error(1)
 }
 
+func (m *MockRunner) RunContext(ctx context.Context, req *simulator.SimulationRequest) (*simulator.SimulationResponse, error) {
+	return m.Run(req)
+}
+
 func TestDebugCommand_Setup(t *testing.T) {
 	// Test that the debugCmd is properly initialized
 	assert.NotNil(t, debugCmd)
@@ -205,7 +215,7 @@ func TestMockRunner_ImplementsInterface(t *testing.T) {
 	}
 	expectedResp := &simulator.SimulationResponse{
 		Status: "success",
-		Events: []string{"test-event"},
+		Events: []diagnostic.Event{{Type: diagnostic.TypeContract, Kind: diagnostic.KindContract}},
 	}
 
 	mockRunner.On("Run", req).Return(expectedResp, nil)
@@ -280,9 +290,36 @@ func TestExtractLedgerKeys(t *testing.T) {
 	assert.NoError(t, err)
 	metaB64 := base64.StdEncoding.EncodeToString(metaBytes)
 
+	// A footprint key the meta does not record, as in a failed transaction
+	contractKey := xdr.LedgerKey{
+		Type:         xdr.LedgerEntryTypeContractCode,
+		ContractCode: &xdr.LedgerKeyContractCode{Hash: xdr.Hash{4, 5, 6}},
+	}
+	envB64, err := xdr.MarshalBase64(xdr.TransactionEnvelope{
+		Type: xdr.EnvelopeTypeEnvelopeTypeTx,
+		V1: &xdr.TransactionV1Envelope{
+			Tx: xdr.Transaction{
+				SourceAccount: xdr.MustMuxedAddress("GCRRSYF5JBFPXHN5DCG65A4J3MUYE53QMQ4XMXZ3CNKWFJIJJTGMH6MZ"),
+				Ext: xdr.TransactionExt{
+					V: 1,
+					SorobanData: &xdr.SorobanTransactionData{
+						Resources: xdr.SorobanResources{
+							Footprint: xdr.LedgerFootprint{ReadOnly: []xdr.LedgerKey{contractKey}},
+						},
+					},
+				},
+			},
+		},
+	})
+	assert.NoError(t, err)
+
 	// Test extraction
-	keys, err := extractLedgerKeys(metaB64)
+	keys, err := extractLedgerKeys(envB64, metaB64)
+	assert.NoError(t, err)
+
+	contractKeyB64, err := xdr.MarshalBase64(contractKey)
 	assert.NoError(t, err)
+	assert.Contains(t, keys, contractKeyB64)
 
 	// We should have at least one key (the one from FeeProcessing and one from Operations)
 	// Both are the same, so map should de-duplicate.
@@ -301,3 +338,124 @@ func TestExtractLedgerKeys(t *testing.T) {
 	}
 	assert.True(t, found, "Key not found in extracted keys")
 }
+
+func TestBuildReplayRequest_UsesTransactionLedger(t *testing.T) {
+	resp := &rpc.TransactionResponse{
+		EnvelopeXdr:    "AAAAAgAAAABi/B0L0JGythwN1lY0aypo19NHxvLCyO5tBEcCVvwF9wAAAGQAAAAAAAAAAQAAAAAAAAAAAAAAAQAAAAAAAAALAAAAAAAAAAIAAAAAAAAAAA==",
+		ResultMetaXdr:  "AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAZAAAAAAAAAAAAAAAAAAAAAAAAAABAAAAAAAAAAA=",
+		LedgerSequence: 123456,
+	}
+	entries := map[string]string{"key": "entry"}
+
+	req, err := buildReplayRequest(resp, entries, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(123456), req.LedgerSequence)
+
+	entries["key"] = "changed"
+	assert.Equal(t, "entry", req.LedgerEntries["key"])
+}
+
+func TestWriteExecutionTrace_HostState(t *testing.T) {
+	const contractID = "CDLZFC3SYJYDZT7K67VZ75HPJVIEUVNIXF47ZG2FB2RMQQVU2HHGCYSC"
+	raw := strkey.MustDecode(strkey.VersionByteContract, contractID)
+	var id xdr.ContractId
+	copy(id[:], raw)
+	sym := func(s string) xdr.ScVal {
+		v := xdr.ScSymbol(s)
+		return xdr.ScVal{Type: xdr.ScValTypeScvSymbol, Sym: &v}
+	}
+	balance := func(n uint32) xdr.LedgerEntry {
+		v := xdr.Uint32(n)
+		return xdr.LedgerEntry{Data: xdr.LedgerEntryData{
+			Type: xdr.LedgerEntryTypeContractData,
+			ContractData: &xdr.ContractDataEntry{
+				Contract:   xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeContract, ContractId: &id},
+				Key:        sym("balance"),
+				Durability: xdr.ContractDataDurabilityPersistent,
+				Val:        xdr.ScVal{Type: xdr.ScValTypeScvU32, U32: &v},
+			},
+		}}
+	}
+	entry := balance(0)
+	key, err := entry.LedgerKey()
+	require.NoError(t, err)
+	keyXdr, err := xdr.MarshalBase64(key)
+	require.NoError(t, err)
+	before, err := xdr.MarshalBase64(balance(100))
+	require.NoError(t, err)
+	after, err := xdr.MarshalBase64(balance(60))
+	require.NoError(t, err)
+
+	replayEntries = map[string]string{keyXdr: before}
+	replayWrites = map[string]string{keyXdr: after}
+	traceOutputFile = filepath.Join(t.TempDir(), "run.etrace")
+	t.Cleanup(func() { replayEntries, replayWrites, traceOutputFile = nil, nil, "" })
+
+	called := xdr.ScBytes(raw)
+	args := &xdr.ScVec{}
+	resp := &simulator.SimulationResponse{Status: "success", Events: []diagnostic.Event{
+		diagnostic.New(diagnostic.TypeDiagnostic, "",
+			[]xdr.ScVal{sym("fn_call"), {Type: xdr.ScValTypeScvBytes, Bytes: &called}, sym("withdraw")},
+			xdr.ScVal{Type: xdr.ScValTypeScvVec, Vec: &args}),
+		diagnostic.New(diagnostic.TypeContract, contractID, []xdr.ScVal{sym("withdraw")}, sym("done")),
+		diagnostic.New(diagnostic.TypeDiagnostic, contractID, []xdr.ScVal{sym("fn_return"), sym("withdraw")}, xdr.ScVal{Type: xdr.ScValTypeScvVoid}),
+	}}
+
+	path, err := writeExecutionTrace("abcd1234", resp)
+	require.NoError(t, err)
+	loaded, err := trace.OpenTrace(path)
+	require.NoError(t, err)
+	defer loaded.Close()
+
+	hostKey := "ContractData(" + contractID + ",balance,Persistent)"
+	bp, err := trace.ParseBreakpoint("host=" + hostKey)
+	require.NoError(t, err)
+	set := trace.NewBreakpointSet()
+	_, err = set.Add(bp)
+	require.NoError(t, err)
+
+	state, hit, err := loaded.Continue(set)
+	require.NoError(t, err)
+	require.NotNil(t, hit, "the write of the contract stops the host breakpoint")
+	assert.Equal(t, 2, state.Step)
+
+	first, err := loaded.ReconstructStateAt(0)
+	require.NoError(t, err)
+	last, err := loaded.ReconstructStateAt(2)
+	require.NoError(t, err)
+	assert.Equal(t, "100", first.HostState[hostKey])
+	assert.Equal(t, "60", last.HostState[hostKey])
+}
+
+func TestWriteFlamegraph_RequiresMetering(t *testing.T) {
+	t.Chdir(t.TempDir())
+	const contractID = "CDLZFC3SYJYDZT7K67VZ75HPJVIEUVNIXF47ZG2FB2RMQQVU2HHGCYSC"
+	id := xdr.ScBytes(strkey.MustDecode(strkey.VersionByteContract, contractID))
+	fn := xdr.ScSymbol("transfer")
+	call := diagnostic.New(diagnostic.TypeDiagnostic, "", []xdr.ScVal{
+		{Type: xdr.ScValTypeScvSymbol, Sym: ptrSymbol("fn_call")},
+		{Type: xdr.ScValTypeScvBytes, Bytes: &id},
+		{Type: xdr.ScValTypeScvSymbol, Sym: &fn},
+	}, xdr.ScVal{Type: xdr.ScValTypeScvVoid})
+	ret := diagnostic.New(diagnostic.TypeDiagnostic, contractID, []xdr.ScVal{
+		{Type: xdr.ScValTypeScvSymbol, Sym: ptrSymbol("fn_return")},
+		{Type: xdr.ScValTypeScvSymbol, Sym: &fn},
+	}, xdr.ScVal{Type: xdr.ScValTypeScvVoid})
+
+	resp := &simulator.SimulationResponse{Status: "success", Events: []diagnostic.Event{call, ret}}
+	err := writeFlamegraph("unmetered", resp)
+	assert.ErrorContains(t, err, "no metering")
+
+	call.CPUInstructions, ret.CPUInstructions = 1000, 5000
+	resp.Events = []diagnostic.Event{call, ret}
+	require.NoError(t, writeFlamegraph("metered", resp))
+	folded, err := os.ReadFile("flamegraph-metered.folded")
+	require.NoError(t, err)
+	assert.Contains(t, string(folded), "transfer")
+	assert.FileExists(t, "flamegraph-metered.svg")
+}
+
+func ptrSymbol(s string) *xdr.ScSymbol {
+	sym := xdr.ScSymbol(s)
+	return &sym
+}
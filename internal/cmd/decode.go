@@ -0,0 +1,143 @@
+// Copyright (c) 2026 dotandev
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/dotandev/hintents/internal/config"
+	"github.com/dotandev/hintents/internal/decoder"
+	"github.com/dotandev/hintents/internal/rpc"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	decodeFormat   string
+	decodeNetwork  string
+	decodeRPCURL   string
+	decodeRPCToken string
+)
+
+var decodeCmd = &cobra.Command{
+	Use:   "decode [xdr|tx-hash]",
+	Short: "Decode a transaction envelope",
+	Long: `Decode a base64 TransactionEnvelope XDR, or the envelope of a transaction
+fetched by its hash, showing every operation in full.
+
+Soroban invocations show the host function with its contract, function and
+arguments, the authorization entries with their invocation trees, and the
+footprint and resources of the transaction's SorobanTransactionData.
+
+Without an argument, or with "-", the XDR is read from stdin.`,
+	Example: `  erst decode AAAAAgAAAAB...
+  erst decode --network testnet 5c0a1234...
+  cat envelope.xdr | erst decode --format yaml`,
+	Args: cobra.MaximumNArgs(1),
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		switch decodeFormat {
+		case "text", "json", "yaml":
+			return nil
+		default:
+			return fmt.Errorf("unknown format %q, use text, json or yaml", decodeFormat)
+		}
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		input := "-"
+		if len(args) == 1 {
+			input = args[0]
+		}
+		if input == "-" {
+			if len(args) == 0 && isTerminal(os.Stdin) {
+				return fmt.Errorf("expected an envelope XDR or transaction hash, or XDR piped on stdin")
+			}
+			data, err := io.ReadAll(cmd.InOrStdin())
+			if err != nil {
+				return fmt.Errorf("failed to read stdin: %w", err)
+			}
+			input = string(data)
+		}
+		input = strings.Join(strings.Fields(input), "")
+
+		if isTxHash(input) {
+			if _, err := config.ResolveNetwork(decodeNetwork); err != nil {
+				return err
+			}
+			client, err := config.NewNetworkClient(decodeNetwork, decodeRPCURL, decodeRPCToken)
+			if err != nil {
+				return err
+			}
+			resp, err := client.GetTransaction(cmd.Context(), input)
+			if err != nil {
+				return fmt.Errorf("failed to fetch transaction: %w", err)
+			}
+			input = resp.EnvelopeXdr
+		}
+
+		env, err := decoder.AnalyzeEnvelope(input)
+		if err != nil {
+			return fmt.Errorf("failed to decode envelope: %w", err)
+		}
+		return writeEnvelope(cmd.OutOrStdout(), env, decodeFormat)
+	},
+}
+
+func writeEnvelope(w io.Writer, env *decoder.DecodedEnvelope, format string) error {
+	switch format {
+	case "json":
+		data, err := json.MarshalIndent(env, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(w, string(data))
+		return err
+	case "yaml":
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+		if err := enc.Encode(env); err != nil {
+			return err
+		}
+		return enc.Close()
+	default:
+		return decoder.WriteEnvelope(w, env)
+	}
+}
+
+// isTxHash tells a transaction hash, 64 hex digits, from envelope XDR
+func isTxHash(s string) bool {
+	if len(s) != 64 {
+		return false
+	}
+	_, err := hex.DecodeString(s)
+	return err == nil
+}
+
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+func init() {
+	decodeCmd.Flags().StringVar(&decodeFormat, "format", "text", "Output format (text, json, yaml)")
+	decodeCmd.Flags().StringVarP(&decodeNetwork, "network", "n", string(rpc.Mainnet), "Stellar network to fetch transactions from (testnet, mainnet, futurenet or a saved custom network)")
+	decodeCmd.Flags().StringVar(&decodeRPCURL, "rpc-url", "", "Custom RPC URL")
+	decodeCmd.Flags().StringVar(&decodeRPCToken, "rpc-token", "", "RPC authentication token (can also use ERST_RPC_TOKEN env var)")
+	rootCmd.AddCommand(decodeCmd)
+}
@@ -17,6 +17,7 @@ package cmd
 import (
 	"fmt"
 
+	"github.com/dotandev/hintents/internal/config"
 	"github.com/dotandev/hintents/internal/rpc"
 	"github.com/dotandev/hintents/internal/testgen"
 	"github.com/spf13/cobra"
@@ -41,15 +42,17 @@ Example:
   erst generate-test 5c0a1234567890abcdef1234567890abcdef1234567890abcdef1234567890ab
   erst generate-test --lang go --name my_test <tx-hash>`,
 	Args: cobra.ExactArgs(1),
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		_, err := config.ResolveNetwork(networkFlag)
+		return err
+	},
 	RunE: func(cmd *cobra.Command, args []string) error {
 		txHash := args[0]
 
 		// Create RPC client
-		var client *rpc.Client
-		if rpcURLFlag != "" {
-			client = rpc.NewClientWithURL(rpcURLFlag, rpc.Network(networkFlag), rpcTokenFlag)
-		} else {
-			client = rpc.NewClient(rpc.Network(networkFlag), rpcTokenFlag)
+		client, err := config.NewNetworkClient(networkFlag, rpcURLFlag, rpcTokenFlag)
+		if err != nil {
+			return err
 		}
 
 		// Get current working directory as default output
@@ -61,8 +64,7 @@ Example:
 		generator := testgen.NewTestGenerator(client, genTestOutput)
 
 		// Generate tests
-		fmt.Printf("Generating %s regression test(s) for transaction: %s
-", genTestLang, txHash)
+		fmt.Printf("Generating %s regression test(s) for transaction: %s\n", genTestLang, txHash)
 		if err := generator.GenerateTests(cmd.Context(), txHash, genTestLang, genTestName); err != nil {
 			return fmt.Errorf("failed to generate tests: %w", err)
 		}
@@ -76,7 +78,7 @@ func init() {
 	generateTestCmd.Flags().StringVarP(&genTestLang, "lang", "l", "both", "Target language (go, rust, or both)")
 	generateTestCmd.Flags().StringVarP(&genTestOutput, "output", "o", "", "Output directory (defaults to current directory)")
 	generateTestCmd.Flags().StringVarP(&genTestName, "name", "", "", "Custom test name (defaults to transaction hash)")
-	generateTestCmd.Flags().StringVarP(&networkFlag, "network", "n", string(rpc.Mainnet), "Stellar network to use (testnet, mainnet, futurenet)")
+	generateTestCmd.Flags().StringVarP(&networkFlag, "network", "n", string(rpc.Mainnet), "Stellar network to use (testnet, mainnet, futurenet or a saved custom network)")
 	generateTestCmd.Flags().StringVar(&rpcURLFlag, "rpc-url", "", "Custom Horizon RPC URL to use")
 	generateTestCmd.Flags().StringVar(&rpcTokenFlag, "rpc-token", "", "RPC authentication token (can also use ERST_RPC_TOKEN env var)")
 
@@ -0,0 +1,205 @@
+// Copyright (c) 2026 dotandev
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/dotandev/hintents/internal/config"
+	"github.com/dotandev/hintents/internal/rpc"
+	"github.com/spf13/cobra"
+)
+
+var (
+	networkHorizonURLFlag      string
+	networkPassphraseFlag      string
+	networkSorobanRPCFlag      string
+	networkHorizonFallbackFlag []string
+	networkSorobanFallbackFlag []string
+	networkShowJSONFlag        bool
+	networkListAllFlag         bool
+)
+
+var networkCmd = &cobra.Command{
+	Use:   "network",
+	Short: "Manage custom Stellar networks",
+	Long: `Save, inspect and remove custom network profiles.
+
+Custom networks are stored in ~/.erst/networks.json and can be used anywhere a
+--network flag is accepted, alongside the built-in testnet, mainnet and futurenet.
+
+Available subcommands:
+  add     - Save a custom network
+  list    - List built-in and saved networks
+  show    - Show the configuration of a network
+  remove  - Remove a saved network`,
+	Example: `  # Save a local quickstart network
+  erst network add local \
+    --horizon-url http://localhost:8000 \
+    --network-passphrase "Standalone Network ; February 2017" \
+    --soroban-rpc http://localhost:8000/soroban/rpc
+
+  # Debug a transaction on it
+  erst debug --network local <tx-hash>`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmd.Help()
+	},
+}
+
+var networkAddCmd = &cobra.Command{
+	Use:   "add <name>",
+	Short: "Save a custom network",
+	Long: `Save a custom network profile. An existing profile with the same name is
+replaced. Built-in network names cannot be used.
+
+Fallback endpoints are tried after the primary ones when they fail; see
+'erst rpc status' to check their health.`,
+	Example: `  erst network add staging \
+    --horizon-url https://horizon-staging.example.com \
+    --network-passphrase "Staging Network ; January 2025" \
+    --soroban-rpc https://soroban-staging.example.com \
+    --soroban-rpc-fallback https://soroban-staging-2.example.com`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := args[0]
+		if config.IsBuiltinNetwork(name) {
+			return fmt.Errorf("'%s' is a built-in network and cannot be redefined", name)
+		}
+
+		netConfig := rpc.NetworkConfig{
+			Name:              name,
+			HorizonURL:        networkHorizonURLFlag,
+			NetworkPassphrase: networkPassphraseFlag,
+			SorobanRPCURL:     networkSorobanRPCFlag,
+			HorizonURLs:       networkHorizonFallbackFlag,
+			SorobanRPCURLs:    networkSorobanFallbackFlag,
+		}
+		if err := config.ValidateNetworkConfig(netConfig); err != nil {
+			return err
+		}
+
+		if err := config.AddCustomNetwork(name, netConfig); err != nil {
+			return fmt.Errorf("failed to save network: %w", err)
+		}
+
+		fmt.Printf("Network '%s' saved\n", name)
+		return nil
+	},
+}
+
+var networkListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List built-in and saved networks",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		names, err := config.ListCustomNetworks()
+		if err != nil {
+			return fmt.Errorf("failed to load networks: %w", err)
+		}
+		networks, err := config.LoadCustomNetworks()
+		if err != nil {
+			return fmt.Errorf("failed to load networks: %w", err)
+		}
+
+		if networkListAllFlag {
+			for _, net := range []rpc.Network{rpc.Mainnet, rpc.Testnet, rpc.Futurenet} {
+				fmt.Printf("  %-20s (built-in)\n", net)
+			}
+		}
+
+		if len(names) == 0 {
+			fmt.Println("No custom networks saved. Use 'erst network add' to create one.")
+			return nil
+		}
+		for _, name := range names {
+			fmt.Printf("  %-20s %s\n", name, networks.Networks[name].HorizonURL)
+		}
+		return nil
+	},
+}
+
+var networkShowCmd = &cobra.Command{
+	Use:   "show <name>",
+	Short: "Show the configuration of a network",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		netConfig, err := config.ResolveNetwork(args[0])
+		if err != nil {
+			return err
+		}
+
+		if networkShowJSONFlag {
+			data, err := json.MarshalIndent(netConfig, "", "  ")
+			if err != nil {
+				return fmt.Errorf("failed to marshal network: %w", err)
+			}
+			fmt.Println(string(data))
+			return nil
+		}
+
+		kind := "custom"
+		if config.IsBuiltinNetwork(args[0]) {
+			kind = "built-in"
+		}
+		fmt.Printf("Name:               %s (%s)\n", args[0], kind)
+		fmt.Printf("Network passphrase: %s\n", netConfig.NetworkPassphrase)
+		fmt.Printf("Horizon:            %s\n", strings.Join(netConfig.HorizonEndpoints(), ", "))
+		soroban := netConfig.SorobanEndpoints()
+		if len(soroban) == 0 {
+			fmt.Println("Soroban RPC:        (none, Horizon URL is used)")
+		} else {
+			fmt.Printf("Soroban RPC:        %s\n", strings.Join(soroban, ", "))
+		}
+		return nil
+	},
+}
+
+var networkRemoveCmd = &cobra.Command{
+	Use:   "remove <name>",
+	Short: "Remove a saved network",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if config.IsBuiltinNetwork(args[0]) {
+			return fmt.Errorf("'%s' is a built-in network and cannot be removed", args[0])
+		}
+		if err := config.RemoveCustomNetwork(args[0]); err != nil {
+			return err
+		}
+		fmt.Printf("Network '%s' removed\n", args[0])
+		return nil
+	},
+}
+
+func init() {
+	networkAddCmd.Flags().StringVar(&networkHorizonURLFlag, "horizon-url", "", "Horizon URL of the network")
+	networkAddCmd.Flags().StringVar(&networkPassphraseFlag, "network-passphrase", "", "Network passphrase")
+	networkAddCmd.Flags().StringVar(&networkSorobanRPCFlag, "soroban-rpc", "", "Soroban RPC URL (defaults to the Horizon URL)")
+	networkAddCmd.Flags().StringSliceVar(&networkHorizonFallbackFlag, "horizon-fallback", nil, "Fallback Horizon URLs, in priority order")
+	networkAddCmd.Flags().StringSliceVar(&networkSorobanFallbackFlag, "soroban-rpc-fallback", nil, "Fallback Soroban RPC URLs, in priority order")
+	_ = networkAddCmd.MarkFlagRequired("horizon-url")
+	_ = networkAddCmd.MarkFlagRequired("network-passphrase")
+
+	networkListCmd.Flags().BoolVar(&networkListAllFlag, "all", false, "Include built-in networks")
+	networkShowCmd.Flags().BoolVar(&networkShowJSONFlag, "json", false, "Output as JSON")
+
+	networkCmd.AddCommand(networkAddCmd)
+	networkCmd.AddCommand(networkListCmd)
+	networkCmd.AddCommand(networkShowCmd)
+	networkCmd.AddCommand(networkRemoveCmd)
+	rootCmd.AddCommand(networkCmd)
+}
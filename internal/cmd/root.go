@@ -15,6 +15,11 @@
 package cmd
 
 import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
 	"github.com/dotandev/hintents/internal/localization"
 	"github.com/spf13/cobra"
 )
@@ -48,6 +53,7 @@ Examples:
   erst debug abc123...def                    Debug a transaction
   erst debug --network testnet abc123...def  Debug on testnet
   erst debug --wasm ./contract.wasm          Test contract locally
+  erst decode AAAAAgAAAAB...                 Decode a transaction envelope
   erst session list                          View saved sessions
   erst cache status                          Check cache usage
 
@@ -61,8 +67,16 @@ Get started with 'erst debug --help' or visit the documentation.`,
 
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
+// The command context is cancelled on SIGINT/SIGTERM, which stops running simulations.
 func Execute() error {
-	return rootCmd.Execute()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		// Restore default handling so a second signal terminates immediately
+		<-ctx.Done()
+		stop()
+	}()
+	return rootCmd.ExecuteContext(ctx)
 }
 
 func init() {
@@ -85,7 +99,7 @@ func init() {
 		&ProfileFlag,
 		"profile",
 		false,
-		"Enable CPU/Memory profiling and generate a flamegraph SVG",
+		"Enable CPU/Memory profiling and write a flamegraph SVG and collapsed stacks",
 	)
 
 	// Register commands
@@ -0,0 +1,127 @@
+// Copyright (c) 2026 dotandev
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/dotandev/hintents/internal/config"
+	"github.com/dotandev/hintents/internal/rpc"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var (
+	rpcStatusNetworkFlag string
+	rpcStatusRoundsFlag  int
+	rpcStatusTimeoutFlag time.Duration
+	rpcStatusJSONFlag    bool
+)
+
+var rpcCmd = &cobra.Command{
+	Use:   "rpc",
+	Short: "Inspect the RPC endpoints used by erst",
+	Long: `Inspect the Horizon and Soroban RPC endpoints erst talks to.
+
+Each network can list several endpoints in priority order. erst tracks latency
+and errors per endpoint and fails over to the healthiest one.
+
+Available subcommands:
+  status  - Probe every endpoint and show its health`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmd.Help()
+	},
+}
+
+var rpcStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Probe the endpoint pool of a network and display its health",
+	Long: `Probe every Horizon and Soroban RPC endpoint configured for a network and
+display the pool statistics: requests, failures, average latency, health score
+and whether the endpoint is currently used for failover.
+
+Fallback endpoints are configured per custom network in ~/.erst/networks.json
+through the horizon_urls and soroban_rpc_urls lists.`,
+	Example: `  # Check the default mainnet endpoints
+  erst rpc status
+
+  # Probe a custom network five times and print JSON
+  erst rpc status --network my-net --rounds 5 --json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if rpcStatusRoundsFlag < 1 {
+			return fmt.Errorf("--rounds must be at least 1")
+		}
+
+		client, err := config.NewNetworkClient(rpcStatusNetworkFlag, "", "")
+		if err != nil {
+			return err
+		}
+
+		ctx, cancel := context.WithTimeout(cmd.Context(), rpcStatusTimeoutFlag)
+		defer cancel()
+		client.CheckEndpoints(ctx, rpcStatusRoundsFlag)
+
+		stats := client.PoolStats()
+		if rpcStatusJSONFlag {
+			data, err := json.MarshalIndent(stats, "", "  ")
+			if err != nil {
+				return fmt.Errorf("failed to marshal pool stats: %w", err)
+			}
+			fmt.Println(string(data))
+			return nil
+		}
+
+		fmt.Printf("Network: %s\n", client.GetNetworkName())
+		printEndpointStats("Horizon", stats.Horizon)
+		printEndpointStats("Soroban RPC", stats.Soroban)
+		return nil
+	},
+}
+
+func printEndpointStats(title string, stats []rpc.EndpointStats) {
+	fmt.Printf("\n%s endpoints:\n", title)
+	if len(stats) == 0 {
+		fmt.Println("  (none configured)")
+		return
+	}
+
+	fmt.Printf("  %-3s %-9s %-6s %-8s %-8s %-10s %s\n", "#", "STATUS", "SCORE", "REQUESTS", "FAILURES", "LATENCY", "URL")
+	for i, s := range stats {
+		status := color.GreenString("%-9s", "healthy")
+		if !s.Healthy {
+			status = color.RedString("%-9s", "unhealthy")
+		}
+		fmt.Printf("  %-3d %s %-6.2f %-8d %-8d %-10s %s\n",
+			i+1, status, s.Score, s.Requests, s.Failures, s.AvgLatency.Round(time.Millisecond), s.URL)
+		if s.LastError != "" {
+			fmt.Printf("      last error: %s\n", s.LastError)
+		}
+	}
+}
+
+func init() {
+	rpcStatusCmd.Flags().StringVarP(&rpcStatusNetworkFlag, "network", "n", string(rpc.Mainnet), "Stellar network (testnet, mainnet, futurenet or a saved custom network)")
+	rpcStatusCmd.Flags().IntVar(&rpcStatusRoundsFlag, "rounds", 3, "Number of probes per endpoint")
+	rpcStatusCmd.Flags().DurationVar(&rpcStatusTimeoutFlag, "timeout", 30*time.Second, "Overall timeout for probing")
+	rpcStatusCmd.Flags().BoolVar(&rpcStatusJSONFlag, "json", false, "Output as JSON")
+
+	rpcCmd.AddCommand(rpcStatusCmd)
+	rootCmd.AddCommand(rpcCmd)
+}
@@ -15,15 +15,21 @@
 package cmd
 
 import (
+	"bufio"
+	"context"
 	"fmt"
+	"io"
 	"os"
 
+	"github.com/dotandev/hintents/internal/logger"
+	"github.com/dotandev/hintents/internal/session"
 	"github.com/dotandev/hintents/internal/trace"
 	"github.com/spf13/cobra"
 )
 
 var (
-	traceFile string
+	traceFile      string
+	traceSessionID string
 )
 
 var traceCmd = &cobra.Command{
@@ -31,15 +37,30 @@ var traceCmd = &cobra.Command{
 	Short: "Interactive trace navigation and debugging",
 	Long: `Launch an interactive trace viewer for bi-directional navigation through execution traces.
 
-The trace viewer allows you to:
+The trace viewer is a full-screen terminal UI that allows you to:
+- Browse the call tree, expanding and collapsing calls
 - Step forward and backward through execution
-- Jump to specific steps
-- Reconstruct state at any point
-- View memory and host state changes
+- Inspect the state at any step and what changed since the previous one
+- Search contract IDs, functions, arguments and errors with / and n/N
+- Set breakpoints on contracts, functions, errors and state changes, and
+  continue or reverse-continue to them with > and <
+
+Breakpoints are saved per session, the transaction hash of the trace unless
+--session is given, and restored the next time the trace is opened.
+
+Both the JSON trace format and the stream format (.etrace) written by
+'erst debug --generate-trace' can be opened. Stream files are read lazily, so
+traces larger than memory can be browsed; use 'erst trace convert' to turn a
+JSON trace into one, 'erst trace diff' to compare two executions and
+'erst trace export' to open a trace in Perfetto.
+
+Press ? in the viewer for all key bindings.
 
 Example:
   erst trace execution.json
-  erst trace --file debug_trace.json`,
+  erst trace trace-abcd1234.etrace
+  erst trace --file debug_trace.json
+  erst trace --session abcd1234-1700000000 execution.json`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		var filename string
@@ -56,24 +77,321 @@ Example:
 			return fmt.Errorf("trace file not found: %s", filename)
 		}
 
-		// Load trace from file
-		data, err := os.ReadFile(filename)
-		if err != nil {
-			return fmt.Errorf("failed to read trace file: %w", err)
-		}
-
-		executionTrace, err := trace.FromJSON(data)
+		// Load trace from file. Stream files are read as the viewer needs them.
+		executionTrace, err := trace.OpenTrace(filename)
 		if err != nil {
-			return fmt.Errorf("failed to parse trace file: %w", err)
+			return err
 		}
+		defer executionTrace.Close()
 
 		// Start interactive viewer
 		viewer := trace.NewInteractiveViewer(executionTrace)
+
+		sessionID := traceSessionID
+		if sessionID == "" {
+			sessionID = executionTrace.TransactionHash
+		}
+		if sessionID != "" {
+			store, err := session.NewStore()
+			if err != nil {
+				logger.Logger.Warn("Breakpoints will not be saved", "error", err)
+				return viewer.Start()
+			}
+			defer store.Close()
+
+			ctx := cmd.Context()
+			breakpoints, err := store.LoadBreakpoints(ctx, sessionID)
+			if err != nil {
+				return fmt.Errorf("failed to load breakpoints: %w", err)
+			}
+			viewer.UseBreakpoints(breakpoints, func(set *trace.BreakpointSet) error {
+				return store.SaveBreakpoints(ctx, sessionID, set)
+			})
+		}
+
 		return viewer.Start()
 	},
 }
 
+var traceConvertChunkSize int
+
+var traceConvertCmd = &cobra.Command{
+	Use:   "convert <trace.json> <trace.etrace>",
+	Short: "Convert a JSON trace to the stream format",
+	Long: `Convert a trace in the JSON format to the chunked, indexed stream format.
+
+The JSON trace is decoded one step at a time, so traces larger than memory can
+be converted.`,
+	Example: `  erst trace convert execution.json execution.etrace`,
+	Args:    cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		in, err := os.Open(args[0])
+		if err != nil {
+			return fmt.Errorf("failed to open trace file: %w", err)
+		}
+		defer in.Close()
+
+		out, err := os.Create(args[1])
+		if err != nil {
+			return fmt.Errorf("failed to create trace file: %w", err)
+		}
+
+		err = trace.ConvertJSONToStream(bufio.NewReader(in), out, trace.StreamOptions{ChunkSize: traceConvertChunkSize})
+		if closeErr := out.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			os.Remove(args[1])
+			return err
+		}
+
+		fmt.Printf("Converted %s to %s\n", args[0], args[1])
+		return nil
+	},
+}
+
+var traceDiffJSON bool
+
+var traceDiffCmd = &cobra.Command{
+	Use:   "diff <left> <right>",
+	Short: "Compare the calls of two executions",
+	Long: `Compare two executions call by call: calls are aligned by contract and
+function, and the first divergent call, the arguments, return values and errors
+that differ and the host state each side wrote are shown.
+
+Each side is a trace file, in the JSON or stream format, or the ID of a saved
+session.`,
+	Example: `  erst trace diff before.etrace after.etrace
+  erst trace diff --json abcd1234-1700000000 trace.json`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		left, err := openTraceOrSession(cmd.Context(), args[0])
+		if err != nil {
+			return err
+		}
+		defer left.Close()
+		right, err := openTraceOrSession(cmd.Context(), args[1])
+		if err != nil {
+			return err
+		}
+		defer right.Close()
+
+		diff, err := trace.DiffTraces(left, right)
+		if err != nil {
+			return fmt.Errorf("failed to compare traces: %w", err)
+		}
+		diff.Left, diff.Right = args[0], args[1]
+
+		if traceDiffJSON {
+			data, err := diff.ToJSON()
+			if err != nil {
+				return fmt.Errorf("failed to encode diff: %w", err)
+			}
+			fmt.Println(string(data))
+			return nil
+		}
+		fmt.Print(diff.Render())
+		return nil
+	},
+}
+
+// openTraceOrSession opens the trace file at name, or rebuilds the trace of the
+// saved session called name
+func openTraceOrSession(ctx context.Context, name string) (*trace.ExecutionTrace, error) {
+	if _, err := os.Stat(name); err == nil {
+		return trace.OpenTrace(name)
+	}
+
+	store, err := session.NewStore()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open session store: %w", err)
+	}
+	defer store.Close()
+
+	data, err := store.Load(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("%s is neither a trace file nor a saved session: %w", name, err)
+	}
+	resp, err := data.ToSimulationResponse()
+	if err != nil {
+		return nil, fmt.Errorf("session %s: %w", name, err)
+	}
+	return simulationTrace(data.TxHash, resp), nil
+}
+
+var (
+	traceExportFormat   string
+	traceExportTimeline string
+	traceExportOutput   string
+)
+
+var traceExportCmd = &cobra.Command{
+	Use:   "export <trace>",
+	Short: "Export a trace for other tools",
+	Long: `Export the call tree of a trace file or saved session.
+
+Formats:
+  perfetto  Chrome Trace Event Format, opened by https://ui.perfetto.dev and
+            chrome://tracing. Every call frame is one event that spans the calls
+            nested in it, with its decoded arguments, return value and error.
+
+The timeline counts steps by default. With --timeline cpu, steps are placed at
+the CPU instructions the host had metered, for traces that recorded them.`,
+	Example: `  erst trace export --format perfetto -o calls.json trace.etrace
+  erst trace export --format perfetto --timeline cpu abcd1234-1700000000 > calls.json`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		switch traceExportFormat {
+		case "perfetto", "chrome":
+		default:
+			return fmt.Errorf("unknown export format %q, use perfetto", traceExportFormat)
+		}
+
+		executionTrace, err := openTraceOrSession(cmd.Context(), args[0])
+		if err != nil {
+			return err
+		}
+		defer executionTrace.Close()
+
+		events, err := trace.ExportTraceEvents(executionTrace, trace.Timeline(traceExportTimeline))
+		if err != nil {
+			return err
+		}
+
+		if traceExportOutput == "" {
+			return events.Write(os.Stdout)
+		}
+		if err := writeOutputFile(traceExportOutput, events.Write); err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "Exported %s to %s\n", args[0], traceExportOutput)
+		return nil
+	},
+}
+
+var (
+	traceFlamegraphWeight string
+	traceFlamegraphFolded bool
+	traceFlamegraphDiff   string
+	traceFlamegraphOutput string
+)
+
+var traceFlamegraphCmd = &cobra.Command{
+	Use:   "flamegraph <trace>",
+	Short: "Render a flamegraph of a trace",
+	Long: `Render the call tree of a trace file or saved session as an SVG flamegraph,
+where every call frame is as wide as the resources spent in it and in the calls
+it made.
+
+Weights:
+  cpu     CPU instructions metered while the frame ran
+  memory  memory bytes metered while the frame ran
+  steps   one per step, for traces that did not record resource counts
+
+The weight defaults to cpu when the trace recorded CPU instructions and to steps,
+with a warning, otherwise. An explicit --weight cpu or memory fails on a trace
+without those counts. With --folded the collapsed stacks are written instead, one
+"frame;frame;frame weight" line per stack, as read by flamegraph.pl, speedscope
+and most profilers.
+
+With --diff, the trace is compared with a base trace: frames are sized by the
+trace and colored red where they grew and blue where they shrank. --folded then
+writes "stack base current" lines.`,
+	Example: `  erst trace flamegraph -o transfer.svg trace.etrace
+  erst trace flamegraph --weight memory --folded abcd1234-1700000000 > transfer.folded
+  erst trace flamegraph --diff before.etrace -o regression.svg after.etrace`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		weight := trace.Weight(traceFlamegraphWeight)
+		current, err := foldTrace(cmd.Context(), args[0], weight)
+		if err != nil {
+			return err
+		}
+		if weight == "" && current.Weight != trace.WeightCPU {
+			fmt.Fprintf(os.Stderr, "Warning: %s has no CPU instruction counts, frames are sized by %s\n", args[0], current.Weight)
+		}
+
+		var write func(io.Writer) error
+		title := fmt.Sprintf("%s (%s)", args[0], current.Weight)
+		if traceFlamegraphDiff != "" {
+			// Both sides must be folded by the same weight
+			base, err := foldTrace(cmd.Context(), traceFlamegraphDiff, current.Weight)
+			if err != nil {
+				return err
+			}
+			diff, err := trace.DiffStacks(base, current)
+			if err != nil {
+				return err
+			}
+			title = fmt.Sprintf("%s → %s (%s)", traceFlamegraphDiff, args[0], current.Weight)
+			write = func(w io.Writer) error { return diff.WriteSVG(w, title) }
+			if traceFlamegraphFolded {
+				write = diff.Write
+			}
+		} else {
+			write = func(w io.Writer) error { return current.WriteSVG(w, title) }
+			if traceFlamegraphFolded {
+				write = current.Write
+			}
+		}
+
+		if traceFlamegraphOutput == "" {
+			return write(os.Stdout)
+		}
+		if err := writeOutputFile(traceFlamegraphOutput, write); err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "Wrote %s flamegraph of %s to %s\n", current.Weight, args[0], traceFlamegraphOutput)
+		return nil
+	},
+}
+
+// foldTrace folds the stacks of the trace file or saved session called name
+func foldTrace(ctx context.Context, name string, weight trace.Weight) (*trace.FoldedStacks, error) {
+	executionTrace, err := openTraceOrSession(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	defer executionTrace.Close()
+
+	folded, err := trace.FoldStacks(executionTrace, weight)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return folded, nil
+}
+
+// writeOutputFile creates path and writes it with write
+func writeOutputFile(path string, write func(io.Writer) error) error {
+	out, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create output file: %w", err)
+	}
+	err = write(out)
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write output file: %w", err)
+	}
+	return nil
+}
+
 func init() {
 	traceCmd.Flags().StringVarP(&traceFile, "file", "f", "", "Trace file to load")
+	traceCmd.Flags().StringVar(&traceSessionID, "session", "", "Session to save breakpoints under (defaults to the transaction hash)")
+	traceConvertCmd.Flags().IntVar(&traceConvertChunkSize, "chunk-size", trace.DefaultStreamChunkSize, "Steps per chunk")
+	traceDiffCmd.Flags().BoolVar(&traceDiffJSON, "json", false, "Print the diff as JSON")
+	traceExportCmd.Flags().StringVar(&traceExportFormat, "format", "perfetto", "Export format (perfetto)")
+	traceExportCmd.Flags().StringVar(&traceExportTimeline, "timeline", string(trace.TimelineSteps), "What the timeline counts (steps, cpu)")
+	traceExportCmd.Flags().StringVarP(&traceExportOutput, "output", "o", "", "Output file (default stdout)")
+	traceFlamegraphCmd.Flags().StringVar(&traceFlamegraphWeight, "weight", "", "What frames are sized by (cpu, memory, steps; default cpu when recorded)")
+	traceFlamegraphCmd.Flags().BoolVar(&traceFlamegraphFolded, "folded", false, "Write collapsed stacks instead of an SVG")
+	traceFlamegraphCmd.Flags().StringVar(&traceFlamegraphDiff, "diff", "", "Base trace or session to compare with")
+	traceFlamegraphCmd.Flags().StringVarP(&traceFlamegraphOutput, "output", "o", "", "Output file (default stdout)")
+	traceCmd.AddCommand(traceConvertCmd)
+	traceCmd.AddCommand(traceDiffCmd)
+	traceCmd.AddCommand(traceExportCmd)
+	traceCmd.AddCommand(traceFlamegraphCmd)
 	rootCmd.AddCommand(traceCmd)
 }
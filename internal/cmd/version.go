@@ -27,8 +27,7 @@ var versionCmd = &cobra.Command{
 	Long:  `Display the current version of the erst CLI tool.`,
 	Args:  cobra.NoArgs,
 	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Printf("erst version %s
-", Version)
+		fmt.Printf("erst version %s\n", Version)
 	},
 }
 
@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 
 	"github.com/dotandev/hintents/internal/rpc"
 )
@@ -103,8 +104,16 @@ func SaveCustomNetworks(config *CustomNetworkConfig) error {
 	return nil
 }
 
-// AddCustomNetwork adds or updates a custom network configuration
+// AddCustomNetwork adds or updates a custom network configuration.
+// Built-in network names cannot be overridden.
 func AddCustomNetwork(name string, config rpc.NetworkConfig) error {
+	if name == "" {
+		return fmt.Errorf("network name is required")
+	}
+	if IsBuiltinNetwork(name) {
+		return fmt.Errorf("'%s' is a built-in network and cannot be redefined", name)
+	}
+
 	networks, err := LoadCustomNetworks()
 	if err != nil {
 		return err
@@ -142,6 +151,7 @@ func ListCustomNetworks() ([]string, error) {
 	for name := range networks.Networks {
 		names = append(names, name)
 	}
+	sort.Strings(names)
 
 	return names, nil
 }
@@ -0,0 +1,98 @@
+// Copyright (c) 2026 dotandev
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/dotandev/hintents/internal/rpc"
+)
+
+// builtinNetworks are the networks erst knows without any configuration.
+// Custom networks cannot reuse these names.
+var builtinNetworks = map[rpc.Network]rpc.NetworkConfig{
+	rpc.Testnet:   rpc.TestnetConfig,
+	rpc.Mainnet:   rpc.MainnetConfig,
+	rpc.Futurenet: rpc.FuturenetConfig,
+}
+
+// IsBuiltinNetwork reports whether name is testnet, mainnet or futurenet
+func IsBuiltinNetwork(name string) bool {
+	_, ok := builtinNetworks[rpc.Network(name)]
+	return ok
+}
+
+// ResolveNetwork returns the configuration of a built-in network or of a custom
+// network saved in ~/.erst/networks.json. Unknown names yield an error
+// wrapping errors.ErrInvalidNetwork.
+func ResolveNetwork(name string) (*rpc.NetworkConfig, error) {
+	if cfg, ok := builtinNetworks[rpc.Network(name)]; ok {
+		return &cfg, nil
+	}
+
+	networks, err := LoadCustomNetworks()
+	if err != nil {
+		return nil, err
+	}
+	cfg, ok := networks.Networks[name]
+	if !ok {
+		return nil, errors.WrapInvalidNetwork(name)
+	}
+	cfg.Name = name
+	return &cfg, nil
+}
+
+// NewNetworkClient creates an RPC client for a built-in or custom network.
+// rpcURL, when set, replaces the network's Horizon endpoints.
+// Token can be provided via the token parameter or ERST_RPC_TOKEN environment variable.
+func NewNetworkClient(name, rpcURL, token string) (*rpc.Client, error) {
+	if IsBuiltinNetwork(name) {
+		if rpcURL != "" {
+			return rpc.NewClientWithURL(rpcURL, rpc.Network(name), token), nil
+		}
+		return rpc.NewClient(rpc.Network(name), token), nil
+	}
+
+	cfg, err := ResolveNetwork(name)
+	if err != nil {
+		return nil, err
+	}
+	if rpcURL != "" {
+		cfg.HorizonURL = rpcURL
+		cfg.HorizonURLs = nil
+	}
+	return rpc.NewCustomClientWithToken(*cfg, token)
+}
+
+// ValidateNetworkConfig checks that a custom network can be used to create a client
+func ValidateNetworkConfig(cfg rpc.NetworkConfig) error {
+	if cfg.HorizonURL == "" {
+		return fmt.Errorf("horizon URL is required")
+	}
+	if cfg.NetworkPassphrase == "" {
+		return fmt.Errorf("network passphrase is required")
+	}
+
+	urls := append(cfg.HorizonEndpoints(), cfg.SorobanEndpoints()...)
+	for _, raw := range urls {
+		u, err := url.Parse(raw)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid endpoint URL: %s", raw)
+		}
+	}
+	return nil
+}
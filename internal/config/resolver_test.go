@@ -0,0 +1,95 @@
+// Copyright (c) 2026 dotandev
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"testing"
+
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/dotandev/hintents/internal/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var localNetwork = rpc.NetworkConfig{
+	HorizonURL:        "http://localhost:8000",
+	NetworkPassphrase: "Standalone Network ; February 2017",
+	SorobanRPCURL:     "http://localhost:8000/soroban/rpc",
+}
+
+func TestResolveNetwork(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	require.NoError(t, AddCustomNetwork("local", localNetwork))
+
+	cfg, err := ResolveNetwork("testnet")
+	require.NoError(t, err)
+	assert.Equal(t, rpc.TestnetConfig.HorizonURL, cfg.HorizonURL)
+
+	cfg, err = ResolveNetwork("local")
+	require.NoError(t, err)
+	assert.Equal(t, "local", cfg.Name)
+	assert.Equal(t, localNetwork.NetworkPassphrase, cfg.NetworkPassphrase)
+
+	_, err = ResolveNetwork("unknown")
+	require.Error(t, err)
+	assert.ErrorIs(t, err, errors.ErrInvalidNetwork)
+	assert.Contains(t, err.Error(), "erst network add")
+}
+
+func TestNewNetworkClient(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	require.NoError(t, AddCustomNetwork("local", localNetwork))
+
+	client, err := NewNetworkClient("futurenet", "", "")
+	require.NoError(t, err)
+	assert.Equal(t, rpc.Futurenet, client.Network)
+
+	client, err = NewNetworkClient("local", "", "")
+	require.NoError(t, err)
+	assert.Equal(t, rpc.Network("local"), client.Network)
+	assert.Equal(t, localNetwork.SorobanRPCURL, client.SorobanURL)
+	assert.Equal(t, localNetwork.NetworkPassphrase, client.GetNetworkPassphrase())
+
+	client, err = NewNetworkClient("local", "http://localhost:9000", "")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"http://localhost:9000"}, client.HorizonPool.URLs())
+
+	_, err = NewNetworkClient("unknown", "", "")
+	assert.ErrorIs(t, err, errors.ErrInvalidNetwork)
+}
+
+func TestAddCustomNetwork_RejectsBuiltinNames(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	err := AddCustomNetwork("mainnet", localNetwork)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "built-in")
+}
+
+func TestValidateNetworkConfig(t *testing.T) {
+	assert.NoError(t, ValidateNetworkConfig(localNetwork))
+
+	missingHorizon := localNetwork
+	missingHorizon.HorizonURL = ""
+	assert.Error(t, ValidateNetworkConfig(missingHorizon))
+
+	missingPassphrase := localNetwork
+	missingPassphrase.NetworkPassphrase = ""
+	assert.Error(t, ValidateNetworkConfig(missingPassphrase))
+
+	badFallback := localNetwork
+	badFallback.SorobanRPCURLs = []string{"localhost:8001"}
+	assert.Error(t, ValidateNetworkConfig(badFallback))
+}
@@ -0,0 +1,227 @@
+// Copyright (c) 2026 dotandev
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package contractspec
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/dotandev/hintents/internal/decoder"
+	"github.com/stellar/go/xdr"
+)
+
+// FormatArgs renders the arguments of a call to function as "name: value",
+// nil when the contract has no such function
+func (s *Spec) FormatArgs(function string, args []xdr.ScVal) []string {
+	fn, ok := s.Functions[function]
+	if !ok {
+		return nil
+	}
+	out := make([]string, len(args))
+	for i, arg := range args {
+		if i >= len(fn.Inputs) {
+			out[i] = decoder.FormatScVal(arg)
+			continue
+		}
+		out[i] = fn.Inputs[i].Name + ": " + s.FormatValue(fn.Inputs[i].Type, arg)
+	}
+	return out
+}
+
+// FormatValue renders a value as the type t of the spec, naming the fields of
+// structs and the cases of enums and unions:
+//
+//	Transfer{from: GABC..., amount: 100}  Asset::Stellar(CDEF...)  Status::Active
+//
+// Values that do not match t render as decoder.FormatScVal does.
+func (s *Spec) FormatValue(t xdr.ScSpecTypeDef, v xdr.ScVal) string {
+	switch t.Type {
+	case xdr.ScSpecTypeScSpecTypeOption:
+		if v.Type == xdr.ScValTypeScvVoid {
+			return "None"
+		}
+		return s.FormatValue(t.Option.ValueType, v)
+	case xdr.ScSpecTypeScSpecTypeResult:
+		if v.Type == xdr.ScValTypeScvError {
+			return s.FormatValue(t.Result.ErrorType, v)
+		}
+		return s.FormatValue(t.Result.OkType, v)
+	case xdr.ScSpecTypeScSpecTypeVec:
+		if items, ok := vecItems(v); ok {
+			parts := make([]string, len(items))
+			for i, item := range items {
+				parts[i] = s.FormatValue(t.Vec.ElementType, item)
+			}
+			return "[" + strings.Join(parts, ", ") + "]"
+		}
+	case xdr.ScSpecTypeScSpecTypeMap:
+		if m, ok := v.GetMap(); ok && m != nil {
+			parts := make([]string, len(*m))
+			for i, entry := range *m {
+				parts[i] = s.FormatValue(t.Map.KeyType, entry.Key) + ": " + s.FormatValue(t.Map.ValueType, entry.Val)
+			}
+			return "{" + strings.Join(parts, ", ") + "}"
+		}
+	case xdr.ScSpecTypeScSpecTypeTuple:
+		if items, ok := vecItems(v); ok && len(items) == len(t.Tuple.ValueTypes) {
+			parts := make([]string, len(items))
+			for i, item := range items {
+				parts[i] = s.FormatValue(t.Tuple.ValueTypes[i], item)
+			}
+			return "(" + strings.Join(parts, ", ") + ")"
+		}
+	case xdr.ScSpecTypeScSpecTypeError:
+		if name, ok := s.contractErrorName(v); ok {
+			return name
+		}
+	case xdr.ScSpecTypeScSpecTypeUdt:
+		if formatted, ok := s.formatUdt(t.Udt.Name, v); ok {
+			return formatted
+		}
+	}
+	return decoder.FormatScVal(v)
+}
+
+// formatUdt renders a value of the user-defined type name
+func (s *Spec) formatUdt(name string, v xdr.ScVal) (string, bool) {
+	if st, ok := s.Structs[name]; ok {
+		return s.formatStruct(st, v)
+	}
+	if union, ok := s.Unions[name]; ok {
+		return s.formatUnion(union, v)
+	}
+	if enum, ok := s.Enums[name]; ok {
+		if n, ok := v.GetU32(); ok {
+			for _, c := range enum.Cases {
+				if c.Value == n {
+					return name + "::" + c.Name, true
+				}
+			}
+		}
+		return "", false
+	}
+	if enum, ok := s.ErrorEnums[name]; ok {
+		code, ok := v.GetU32()
+		if scErr, isErr := v.GetError(); isErr && scErr.ContractCode != nil {
+			code, ok = *scErr.ContractCode, true
+		}
+		if ok {
+			for _, c := range enum.Cases {
+				if c.Value == code {
+					return c.Name, true
+				}
+			}
+		}
+	}
+	return "", false
+}
+
+// formatStruct renders a struct, a map keyed by field name, or a tuple struct,
+// a vec of its fields "0", "1" and so on
+func (s *Spec) formatStruct(st xdr.ScSpecUdtStructV0, v xdr.ScVal) (string, bool) {
+	if isTupleStruct(st) {
+		items, ok := vecItems(v)
+		if !ok || len(items) != len(st.Fields) {
+			return "", false
+		}
+		parts := make([]string, len(items))
+		for i, item := range items {
+			parts[i] = s.FormatValue(st.Fields[i].Type, item)
+		}
+		return st.Name + "(" + strings.Join(parts, ", ") + ")", true
+	}
+
+	m, ok := v.GetMap()
+	if !ok || m == nil || len(*m) != len(st.Fields) {
+		return "", false
+	}
+	fields := make(map[string]xdr.ScVal, len(*m))
+	for _, entry := range *m {
+		if sym, ok := entry.Key.GetSym(); ok {
+			fields[string(sym)] = entry.Val
+		}
+	}
+	parts := make([]string, len(st.Fields))
+	for i, field := range st.Fields {
+		val, ok := fields[field.Name]
+		if !ok {
+			return "", false
+		}
+		parts[i] = field.Name + ": " + s.FormatValue(field.Type, val)
+	}
+	return st.Name + "{" + strings.Join(parts, ", ") + "}", true
+}
+
+// formatUnion renders a union, a vec of the case name and its values
+func (s *Spec) formatUnion(union xdr.ScSpecUdtUnionV0, v xdr.ScVal) (string, bool) {
+	items, ok := vecItems(v)
+	if !ok || len(items) == 0 {
+		return "", false
+	}
+	caseName, ok := items[0].GetSym()
+	if !ok {
+		return "", false
+	}
+
+	for _, c := range union.Cases {
+		switch c.Kind {
+		case xdr.ScSpecUdtUnionCaseV0KindScSpecUdtUnionCaseVoidV0:
+			if c.VoidCase.Name == string(caseName) && len(items) == 1 {
+				return union.Name + "::" + c.VoidCase.Name, true
+			}
+		case xdr.ScSpecUdtUnionCaseV0KindScSpecUdtUnionCaseTupleV0:
+			if c.TupleCase.Name != string(caseName) || len(items)-1 != len(c.TupleCase.Type) {
+				continue
+			}
+			parts := make([]string, len(c.TupleCase.Type))
+			for i, t := range c.TupleCase.Type {
+				parts[i] = s.FormatValue(t, items[i+1])
+			}
+			return union.Name + "::" + c.TupleCase.Name + "(" + strings.Join(parts, ", ") + ")", true
+		}
+	}
+	return "", false
+}
+
+// contractErrorName names the contract error a value holds
+func (s *Spec) contractErrorName(v xdr.ScVal) (string, bool) {
+	scErr, ok := v.GetError()
+	if !ok || scErr.Type != xdr.ScErrorTypeSceContract || scErr.ContractCode == nil {
+		return "", false
+	}
+	return s.ErrorName(uint32(*scErr.ContractCode))
+}
+
+// isTupleStruct tells whether a struct is a Rust tuple struct, whose fields
+// the SDK names after their position
+func isTupleStruct(st xdr.ScSpecUdtStructV0) bool {
+	if len(st.Fields) == 0 {
+		return false
+	}
+	for i, field := range st.Fields {
+		if field.Name != strconv.Itoa(i) {
+			return false
+		}
+	}
+	return true
+}
+
+func vecItems(v xdr.ScVal) (xdr.ScVec, bool) {
+	vec, ok := v.GetVec()
+	if !ok || vec == nil {
+		return nil, false
+	}
+	return *vec, true
+}
@@ -0,0 +1,252 @@
+// Copyright (c) 2026 dotandev
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package contractspec
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/dotandev/hintents/internal/decoder"
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/dotandev/hintents/internal/logger"
+	"github.com/stellar/go/xdr"
+)
+
+// specsDir is the cache subdirectory holding contract specs
+const specsDir = "contract-specs"
+
+// LedgerEntryFetcher fetches ledger entries by base64 LedgerKey XDR, as
+// rpc.Client does
+type LedgerEntryFetcher interface {
+	GetLedgerEntries(ctx context.Context, keys []string) (map[string]string, error)
+}
+
+// Loader loads the specs of deployed contracts through Soroban RPC. Specs are
+// cached per WASM hash, in memory and, with a cache directory, on disk, so
+// the code of a contract is fetched once for every contract deployed from it.
+type Loader struct {
+	rpc      LedgerEntryFetcher
+	cacheDir string
+
+	mu    sync.Mutex
+	specs map[xdr.Hash]*Spec
+}
+
+// NewLoader creates a loader fetching ledger entries from rpc. Specs are
+// stored under cacheDir (usually ~/.erst/cache), or only in memory when
+// cacheDir is empty.
+func NewLoader(rpc LedgerEntryFetcher, cacheDir string) *Loader {
+	return &Loader{
+		rpc:      rpc,
+		cacheDir: cacheDir,
+		specs:    make(map[xdr.Hash]*Spec),
+	}
+}
+
+// Load returns the spec of a deployed contract, reading the WASM hash of its
+// instance and then the spec of that code
+func (l *Loader) Load(ctx context.Context, contractID string) (*Spec, error) {
+	address, err := decoder.ParseScAddress(contractID)
+	if err != nil || address.Type != xdr.ScAddressTypeScAddressTypeContract {
+		return nil, fmt.Errorf("invalid contract ID %q", contractID)
+	}
+
+	data, err := l.fetch(ctx, xdr.LedgerKey{
+		Type: xdr.LedgerEntryTypeContractData,
+		ContractData: &xdr.LedgerKeyContractData{
+			Contract:   address,
+			Key:        xdr.ScVal{Type: xdr.ScValTypeScvLedgerKeyContractInstance},
+			Durability: xdr.ContractDataDurabilityPersistent,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if data == nil || data.ContractData == nil {
+		return nil, errors.WrapContractNotFound(contractID)
+	}
+
+	instance, ok := data.ContractData.Val.GetInstance()
+	if !ok {
+		return nil, fmt.Errorf("contract %s has no instance", contractID)
+	}
+	hash, ok := instance.Executable.GetWasmHash()
+	if !ok {
+		// Stellar asset contracts are built into the host
+		return nil, errors.WrapNoContractSpec(contractID)
+	}
+	return l.LoadHash(ctx, hash)
+}
+
+// LoadHash returns the spec of the WASM code with the given hash
+func (l *Loader) LoadHash(ctx context.Context, hash xdr.Hash) (*Spec, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if spec, ok := l.specs[hash]; ok {
+		return spec, nil
+	}
+	name := hex.EncodeToString(hash[:])
+
+	path := ""
+	if l.cacheDir != "" {
+		path = filepath.Join(l.cacheDir, specsDir, name+".xdr")
+		if section, err := os.ReadFile(path); err == nil {
+			spec, err := ParseEntries(section)
+			if err == nil {
+				l.specs[hash] = spec
+				return spec, nil
+			}
+			logger.Logger.Warn("Discarding corrupt contract spec", "path", path, "error", err)
+			_ = os.Remove(path)
+		}
+	}
+
+	data, err := l.fetch(ctx, xdr.LedgerKey{
+		Type:         xdr.LedgerEntryTypeContractCode,
+		ContractCode: &xdr.LedgerKeyContractCode{Hash: hash},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if data == nil || data.ContractCode == nil {
+		return nil, fmt.Errorf("WASM code %s not found", name)
+	}
+
+	section, err := specSection(name, data.ContractCode.Code)
+	if err != nil {
+		return nil, err
+	}
+	spec, err := ParseEntries(section)
+	if err != nil {
+		return nil, errors.WrapInvalidWasm(fmt.Sprintf("%s: %v", name, err))
+	}
+	l.specs[hash] = spec
+
+	if path != "" {
+		if err := writeFile(path, section); err != nil {
+			logger.Logger.Warn("Failed to cache contract spec", "path", path, "error", err)
+		}
+	}
+	return spec, nil
+}
+
+// fetch returns the data of a ledger entry, nil when it does not exist
+func (l *Loader) fetch(ctx context.Context, key xdr.LedgerKey) (*xdr.LedgerEntryData, error) {
+	encoded, err := xdr.MarshalBase64(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode ledger key: %w", err)
+	}
+	entries, err := l.rpc.GetLedgerEntries(ctx, []string{encoded})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch ledger entries: %w", err)
+	}
+	value, ok := entries[encoded]
+	if !ok {
+		return nil, nil
+	}
+
+	// Soroban RPC returns the entry data, snapshots hold whole entries
+	var data xdr.LedgerEntryData
+	if err := xdr.SafeUnmarshalBase64(value, &data); err == nil {
+		return &data, nil
+	}
+	var entry xdr.LedgerEntry
+	if err := xdr.SafeUnmarshalBase64(value, &entry); err != nil {
+		return nil, fmt.Errorf("failed to decode ledger entry: %w", err)
+	}
+	return &entry.Data, nil
+}
+
+// writeFile writes through a temp file so a concurrent reader never sees a
+// partial spec
+func writeFile(path string, contents []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, contents, 0644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	return nil
+}
+
+// Contracts decodes the calls and errors of the contracts of a trace with
+// their specs, implementing trace.ContractDecoder. Specs are loaded on first
+// use, and contracts whose spec cannot be loaded are decoded without one.
+type Contracts struct {
+	// Default is the spec of every contract without one of its own, such as
+	// the contract of a local replay
+	Default *Spec
+
+	ctx    context.Context
+	loader *Loader
+	specs  map[string]*Spec
+}
+
+// NewContracts creates a set of contract specs loaded through loader, which
+// may be nil to only use the specs that are set
+func NewContracts(ctx context.Context, loader *Loader) *Contracts {
+	return &Contracts{
+		ctx:    ctx,
+		loader: loader,
+		specs:  make(map[string]*Spec),
+	}
+}
+
+// Set sets the spec of a contract
+func (c *Contracts) Set(contractID string, spec *Spec) {
+	c.specs[contractID] = spec
+}
+
+// Spec returns the spec of a contract, nil when it is unknown
+func (c *Contracts) Spec(contractID string) *Spec {
+	spec, ok := c.specs[contractID]
+	if !ok && c.loader != nil && contractID != "" {
+		var err error
+		if spec, err = c.loader.Load(c.ctx, contractID); err != nil {
+			logger.Logger.Debug("Contract spec unavailable", "contract", contractID, "error", err)
+		}
+		c.specs[contractID] = spec
+	}
+	if spec == nil {
+		return c.Default
+	}
+	return spec
+}
+
+// FormatArgs implements trace.ContractDecoder
+func (c *Contracts) FormatArgs(contractID, function string, args []xdr.ScVal) []string {
+	if spec := c.Spec(contractID); spec != nil {
+		return spec.FormatArgs(function, args)
+	}
+	return nil
+}
+
+// ErrorName implements trace.ContractDecoder
+func (c *Contracts) ErrorName(contractID string, code uint32) (string, bool) {
+	if spec := c.Spec(contractID); spec != nil {
+		return spec.ErrorName(code)
+	}
+	return "", false
+}
@@ -0,0 +1,154 @@
+// Copyright (c) 2026 dotandev
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package contractspec
+
+import (
+	"context"
+	"crypto/sha256"
+	"testing"
+
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/dotandev/hintents/internal/trace"
+	"github.com/stellar/go/strkey"
+	"github.com/stellar/go/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var _ trace.ContractDecoder = (*Contracts)(nil)
+
+// fakeRPC serves ledger entries by key and counts the keys it was asked for
+type fakeRPC struct {
+	entries map[string]string
+	fetched int
+}
+
+func (f *fakeRPC) GetLedgerEntries(_ context.Context, keys []string) (map[string]string, error) {
+	out := make(map[string]string)
+	for _, key := range keys {
+		f.fetched++
+		if value, ok := f.entries[key]; ok {
+			out[key] = value
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeRPC) put(t *testing.T, key xdr.LedgerKey, data xdr.LedgerEntryData) {
+	t.Helper()
+	k, err := xdr.MarshalBase64(key)
+	require.NoError(t, err)
+	v, err := xdr.MarshalBase64(data)
+	require.NoError(t, err)
+	f.entries[k] = v
+}
+
+// deploy adds a contract running module to the ledger, returning its ID
+func (f *fakeRPC) deploy(t *testing.T, seed byte, module []byte) string {
+	t.Helper()
+	hash := xdr.Hash(sha256.Sum256(module))
+	f.put(t, xdr.LedgerKey{Type: xdr.LedgerEntryTypeContractCode, ContractCode: &xdr.LedgerKeyContractCode{Hash: hash}},
+		xdr.LedgerEntryData{Type: xdr.LedgerEntryTypeContractCode, ContractCode: &xdr.ContractCodeEntry{Hash: hash, Code: module}})
+
+	id := xdr.ContractId{seed}
+	address, err := xdr.NewScAddress(xdr.ScAddressTypeScAddressTypeContract, id)
+	require.NoError(t, err)
+	instanceKey := xdr.ScVal{Type: xdr.ScValTypeScvLedgerKeyContractInstance}
+	f.put(t, xdr.LedgerKey{Type: xdr.LedgerEntryTypeContractData, ContractData: &xdr.LedgerKeyContractData{
+		Contract: address, Key: instanceKey, Durability: xdr.ContractDataDurabilityPersistent,
+	}}, xdr.LedgerEntryData{Type: xdr.LedgerEntryTypeContractData, ContractData: &xdr.ContractDataEntry{
+		Contract:   address,
+		Key:        instanceKey,
+		Durability: xdr.ContractDataDurabilityPersistent,
+		Val: xdr.ScVal{Type: xdr.ScValTypeScvContractInstance, Instance: &xdr.ScContractInstance{
+			Executable: xdr.ContractExecutable{Type: xdr.ContractExecutableTypeContractExecutableWasm, WasmHash: &hash},
+		}},
+	}})
+
+	contractID, err := strkey.Encode(strkey.VersionByteContract, id[:])
+	require.NoError(t, err)
+	return contractID
+}
+
+func TestLoader_Load(t *testing.T) {
+	rpc := &fakeRPC{entries: make(map[string]string)}
+	module := testModule([2][]byte{[]byte(SectionName), testSection(t)})
+	first := rpc.deploy(t, 1, module)
+	second := rpc.deploy(t, 2, module)
+	cacheDir := t.TempDir()
+
+	loader := NewLoader(rpc, cacheDir)
+	spec, err := loader.Load(context.Background(), first)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"from", "to", "amount"}, spec.ArgNames("transfer"))
+	assert.Equal(t, 2, rpc.fetched)
+
+	// Contracts deployed from the same code share its spec
+	again, err := loader.Load(context.Background(), second)
+	require.NoError(t, err)
+	assert.Same(t, spec, again)
+	assert.Equal(t, 3, rpc.fetched)
+
+	// A new loader reads the spec from the cache directory
+	rpc.fetched = 0
+	spec, err = NewLoader(rpc, cacheDir).Load(context.Background(), first)
+	require.NoError(t, err)
+	assert.Contains(t, spec.Functions, "pay")
+	assert.Equal(t, 1, rpc.fetched)
+}
+
+func TestLoader_Errors(t *testing.T) {
+	rpc := &fakeRPC{entries: make(map[string]string)}
+	loader := NewLoader(rpc, "")
+
+	missing, err := strkey.Encode(strkey.VersionByteContract, make([]byte, 32))
+	require.NoError(t, err)
+	_, err = loader.Load(context.Background(), missing)
+	assert.ErrorIs(t, err, errors.ErrContractNotFound)
+
+	noSpec := rpc.deploy(t, 3, testModule())
+	_, err = loader.Load(context.Background(), noSpec)
+	assert.ErrorIs(t, err, errors.ErrNoContractSpec)
+
+	_, err = loader.Load(context.Background(), testAccount)
+	assert.Error(t, err)
+}
+
+func TestContracts(t *testing.T) {
+	rpc := &fakeRPC{entries: make(map[string]string)}
+	deployed := rpc.deploy(t, 1, testModule([2][]byte{[]byte(SectionName), testSection(t)}))
+
+	contracts := NewContracts(context.Background(), NewLoader(rpc, ""))
+	name, ok := contracts.ErrorName(deployed, 7)
+	assert.True(t, ok)
+	assert.Equal(t, "InsufficientAllowance", name)
+	assert.Equal(t, []string{"payment: 1", "memo: None"}, contracts.FormatArgs(deployed, "pay", []xdr.ScVal{u32(1), {Type: xdr.ScValTypeScvVoid}}))
+
+	// Unknown contracts are looked up once
+	missing, err := strkey.Encode(strkey.VersionByteContract, make([]byte, 32))
+	require.NoError(t, err)
+	assert.Nil(t, contracts.FormatArgs(missing, "pay", nil))
+	fetched := rpc.fetched
+	_, ok = contracts.ErrorName(missing, 7)
+	assert.False(t, ok)
+	assert.Equal(t, fetched, rpc.fetched)
+
+	// The default spec applies to every contract without its own
+	local := NewContracts(context.Background(), nil)
+	local.Default = testSpec(t)
+	name, ok = local.ErrorName("CLOCAL", 1)
+	assert.True(t, ok)
+	assert.Equal(t, "InsufficientBalance", name)
+}
@@ -0,0 +1,145 @@
+// Copyright (c) 2026 dotandev
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package contractspec reads the interface of a Soroban contract, the
+// contractspecv0 custom section of its WASM module, to decode the calls,
+// values and errors of the contract by name.
+package contractspec
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/dotandev/hintents/internal/wasm"
+	"github.com/stellar/go/xdr"
+)
+
+// SectionName is the custom section the Soroban SDK writes the contract
+// interface to, as a stream of ScSpecEntry XDR values
+const SectionName = "contractspecv0"
+
+// Spec is the interface of a contract: its functions, and the user-defined
+// types and error enums they use, by name
+type Spec struct {
+	Functions  map[string]xdr.ScSpecFunctionV0
+	Structs    map[string]xdr.ScSpecUdtStructV0
+	Unions     map[string]xdr.ScSpecUdtUnionV0
+	Enums      map[string]xdr.ScSpecUdtEnumV0
+	ErrorEnums map[string]xdr.ScSpecUdtErrorEnumV0
+}
+
+// Load reads the spec of the WASM module at path
+func Load(path string) (*Spec, error) {
+	module, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read WASM module: %w", err)
+	}
+	return Parse(path, module)
+}
+
+// Parse reads the spec of a WASM module, name being used in errors
+func Parse(name string, module []byte) (*Spec, error) {
+	section, err := specSection(name, module)
+	if err != nil {
+		return nil, err
+	}
+	spec, err := ParseEntries(section)
+	if err != nil {
+		return nil, errors.WrapInvalidWasm(fmt.Sprintf("%s: %v", name, err))
+	}
+	return spec, nil
+}
+
+// specSection returns the contractspecv0 section of a WASM module
+func specSection(name string, module []byte) ([]byte, error) {
+	sections, err := wasm.CustomSections(module)
+	if err != nil {
+		return nil, err
+	}
+	section, ok := sections[SectionName]
+	if !ok {
+		return nil, errors.WrapNoContractSpec(name)
+	}
+	return section, nil
+}
+
+// ParseEntries decodes the contents of a contractspecv0 section
+func ParseEntries(section []byte) (*Spec, error) {
+	spec := &Spec{
+		Functions:  make(map[string]xdr.ScSpecFunctionV0),
+		Structs:    make(map[string]xdr.ScSpecUdtStructV0),
+		Unions:     make(map[string]xdr.ScSpecUdtUnionV0),
+		Enums:      make(map[string]xdr.ScSpecUdtEnumV0),
+		ErrorEnums: make(map[string]xdr.ScSpecUdtErrorEnumV0),
+	}
+
+	dec := xdr.NewBytesDecoder()
+	for offset := 0; offset < len(section); {
+		var entry xdr.ScSpecEntry
+		n, err := dec.DecodeBytes(&entry, section[offset:])
+		if err != nil {
+			return nil, fmt.Errorf("invalid contract spec entry at offset %d: %w", offset, err)
+		}
+		offset += n
+
+		switch entry.Kind {
+		case xdr.ScSpecEntryKindScSpecEntryFunctionV0:
+			spec.Functions[string(entry.FunctionV0.Name)] = *entry.FunctionV0
+		case xdr.ScSpecEntryKindScSpecEntryUdtStructV0:
+			spec.Structs[entry.UdtStructV0.Name] = *entry.UdtStructV0
+		case xdr.ScSpecEntryKindScSpecEntryUdtUnionV0:
+			spec.Unions[entry.UdtUnionV0.Name] = *entry.UdtUnionV0
+		case xdr.ScSpecEntryKindScSpecEntryUdtEnumV0:
+			spec.Enums[entry.UdtEnumV0.Name] = *entry.UdtEnumV0
+		case xdr.ScSpecEntryKindScSpecEntryUdtErrorEnumV0:
+			spec.ErrorEnums[entry.UdtErrorEnumV0.Name] = *entry.UdtErrorEnumV0
+		}
+	}
+	return spec, nil
+}
+
+// ArgNames returns the names of the arguments of a function, nil when the
+// contract has no such function
+func (s *Spec) ArgNames(function string) []string {
+	fn, ok := s.Functions[function]
+	if !ok {
+		return nil
+	}
+	names := make([]string, len(fn.Inputs))
+	for i, input := range fn.Inputs {
+		names[i] = input.Name
+	}
+	return names
+}
+
+// ErrorName names a contract error code after the #[contracterror] enum case
+// declaring it, "InsufficientAllowance" for Error(Contract, #7)
+func (s *Spec) ErrorName(code uint32) (string, bool) {
+	names := make([]string, 0, len(s.ErrorEnums))
+	for name := range s.ErrorEnums {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		for _, c := range s.ErrorEnums[name].Cases {
+			if uint32(c.Value) == code {
+				return c.Name, true
+			}
+		}
+	}
+	return "", false
+}
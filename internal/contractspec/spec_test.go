@@ -0,0 +1,267 @@
+// Copyright (c) 2026 dotandev
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package contractspec
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/stellar/go/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func typ(t xdr.ScSpecType) xdr.ScSpecTypeDef {
+	return xdr.ScSpecTypeDef{Type: t}
+}
+
+func udt(name string) xdr.ScSpecTypeDef {
+	return xdr.ScSpecTypeDef{Type: xdr.ScSpecTypeScSpecTypeUdt, Udt: &xdr.ScSpecTypeUdt{Name: name}}
+}
+
+// testEntries is the spec of a token contract
+//
+//	fn transfer(from: Address, to: Address, amount: i128)
+//	fn pay(payment: Payment, memo: Option<Memo>)
+//	struct Payment { to: Address, amount: i128 }
+//	struct Pair(u32, u32)
+//	enum Memo { None, Text(String) }
+//	enum Status { Active = 1, Frozen = 2 }
+//	enum TokenError { InsufficientBalance = 1, InsufficientAllowance = 7 }
+func testEntries() []xdr.ScSpecEntry {
+	return []xdr.ScSpecEntry{
+		{Kind: xdr.ScSpecEntryKindScSpecEntryFunctionV0, FunctionV0: &xdr.ScSpecFunctionV0{
+			Name: "transfer",
+			Inputs: []xdr.ScSpecFunctionInputV0{
+				{Name: "from", Type: typ(xdr.ScSpecTypeScSpecTypeAddress)},
+				{Name: "to", Type: typ(xdr.ScSpecTypeScSpecTypeAddress)},
+				{Name: "amount", Type: typ(xdr.ScSpecTypeScSpecTypeI128)},
+			},
+		}},
+		{Kind: xdr.ScSpecEntryKindScSpecEntryFunctionV0, FunctionV0: &xdr.ScSpecFunctionV0{
+			Name: "pay",
+			Inputs: []xdr.ScSpecFunctionInputV0{
+				{Name: "payment", Type: udt("Payment")},
+				{Name: "memo", Type: xdr.ScSpecTypeDef{Type: xdr.ScSpecTypeScSpecTypeOption, Option: &xdr.ScSpecTypeOption{ValueType: udt("Memo")}}},
+			},
+		}},
+		{Kind: xdr.ScSpecEntryKindScSpecEntryUdtStructV0, UdtStructV0: &xdr.ScSpecUdtStructV0{
+			Name: "Payment",
+			Fields: []xdr.ScSpecUdtStructFieldV0{
+				{Name: "to", Type: typ(xdr.ScSpecTypeScSpecTypeAddress)},
+				{Name: "amount", Type: typ(xdr.ScSpecTypeScSpecTypeI128)},
+			},
+		}},
+		{Kind: xdr.ScSpecEntryKindScSpecEntryUdtStructV0, UdtStructV0: &xdr.ScSpecUdtStructV0{
+			Name: "Pair",
+			Fields: []xdr.ScSpecUdtStructFieldV0{
+				{Name: "0", Type: typ(xdr.ScSpecTypeScSpecTypeU32)},
+				{Name: "1", Type: typ(xdr.ScSpecTypeScSpecTypeU32)},
+			},
+		}},
+		{Kind: xdr.ScSpecEntryKindScSpecEntryUdtUnionV0, UdtUnionV0: &xdr.ScSpecUdtUnionV0{
+			Name: "Memo",
+			Cases: []xdr.ScSpecUdtUnionCaseV0{
+				{Kind: xdr.ScSpecUdtUnionCaseV0KindScSpecUdtUnionCaseVoidV0, VoidCase: &xdr.ScSpecUdtUnionCaseVoidV0{Name: "None"}},
+				{Kind: xdr.ScSpecUdtUnionCaseV0KindScSpecUdtUnionCaseTupleV0, TupleCase: &xdr.ScSpecUdtUnionCaseTupleV0{Name: "Text", Type: []xdr.ScSpecTypeDef{typ(xdr.ScSpecTypeScSpecTypeString)}}},
+			},
+		}},
+		{Kind: xdr.ScSpecEntryKindScSpecEntryUdtEnumV0, UdtEnumV0: &xdr.ScSpecUdtEnumV0{
+			Name:  "Status",
+			Cases: []xdr.ScSpecUdtEnumCaseV0{{Name: "Active", Value: 1}, {Name: "Frozen", Value: 2}},
+		}},
+		{Kind: xdr.ScSpecEntryKindScSpecEntryUdtErrorEnumV0, UdtErrorEnumV0: &xdr.ScSpecUdtErrorEnumV0{
+			Name:  "TokenError",
+			Cases: []xdr.ScSpecUdtErrorEnumCaseV0{{Name: "InsufficientBalance", Value: 1}, {Name: "InsufficientAllowance", Value: 7}},
+		}},
+	}
+}
+
+func testSection(t *testing.T) []byte {
+	t.Helper()
+	var section bytes.Buffer
+	for _, entry := range testEntries() {
+		_, err := xdr.Marshal(&section, entry)
+		require.NoError(t, err)
+	}
+	return section.Bytes()
+}
+
+// testModule builds a WASM module holding custom sections, in order
+func testModule(sections ...[2][]byte) []byte {
+	module := []byte("\x00asm\x01\x00\x00\x00")
+	for _, section := range sections {
+		payload := append(uleb128(len(section[0])), section[0]...)
+		payload = append(payload, section[1]...)
+		module = append(module, 0)
+		module = append(module, uleb128(len(payload))...)
+		module = append(module, payload...)
+	}
+	return module
+}
+
+func uleb128(v int) []byte {
+	var out []byte
+	for {
+		b := byte(v & 0x7f)
+		v >>= 7
+		if v != 0 {
+			out = append(out, b|0x80)
+			continue
+		}
+		return append(out, b)
+	}
+}
+
+func testSpec(t *testing.T) *Spec {
+	t.Helper()
+	spec, err := Parse("token.wasm", testModule([2][]byte{[]byte(SectionName), testSection(t)}))
+	require.NoError(t, err)
+	return spec
+}
+
+func sym(s string) xdr.ScVal {
+	v := xdr.ScSymbol(s)
+	return xdr.ScVal{Type: xdr.ScValTypeScvSymbol, Sym: &v}
+}
+
+func str(s string) xdr.ScVal {
+	v := xdr.ScString(s)
+	return xdr.ScVal{Type: xdr.ScValTypeScvString, Str: &v}
+}
+
+func u32(n uint32) xdr.ScVal {
+	v := xdr.Uint32(n)
+	return xdr.ScVal{Type: xdr.ScValTypeScvU32, U32: &v}
+}
+
+func i128(n int64) xdr.ScVal {
+	return xdr.ScVal{Type: xdr.ScValTypeScvI128, I128: &xdr.Int128Parts{Lo: xdr.Uint64(n)}}
+}
+
+func vec(items ...xdr.ScVal) xdr.ScVal {
+	v := xdr.ScVec(items)
+	p := &v
+	return xdr.ScVal{Type: xdr.ScValTypeScvVec, Vec: &p}
+}
+
+func scMap(entries ...xdr.ScMapEntry) xdr.ScVal {
+	m := xdr.ScMap(entries)
+	p := &m
+	return xdr.ScVal{Type: xdr.ScValTypeScvMap, Map: &p}
+}
+
+func account(t *testing.T, address string) xdr.ScVal {
+	t.Helper()
+	var id xdr.AccountId
+	require.NoError(t, id.SetAddress(address))
+	a, err := xdr.NewScAddress(xdr.ScAddressTypeScAddressTypeAccount, id)
+	require.NoError(t, err)
+	return xdr.ScVal{Type: xdr.ScValTypeScvAddress, Address: &a}
+}
+
+const testAccount = "GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H"
+
+func TestParse(t *testing.T) {
+	spec := testSpec(t)
+
+	assert.Len(t, spec.Functions, 2)
+	assert.Equal(t, []string{"from", "to", "amount"}, spec.ArgNames("transfer"))
+	assert.Nil(t, spec.ArgNames("burn"))
+
+	name, ok := spec.ErrorName(7)
+	assert.True(t, ok)
+	assert.Equal(t, "InsufficientAllowance", name)
+	_, ok = spec.ErrorName(3)
+	assert.False(t, ok)
+}
+
+func TestParse_Errors(t *testing.T) {
+	_, err := Parse("token.wasm", []byte("not wasm"))
+	assert.ErrorIs(t, err, errors.ErrInvalidWasm)
+
+	_, err = Parse("token.wasm", testModule())
+	assert.ErrorIs(t, err, errors.ErrNoContractSpec)
+	assert.ErrorContains(t, err, "token.wasm")
+
+	section := testSection(t)
+	_, err = Parse("token.wasm", testModule([2][]byte{[]byte(SectionName), section[:len(section)-3]}))
+	assert.ErrorIs(t, err, errors.ErrInvalidWasm)
+}
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "token.wasm")
+	module := testModule([2][]byte{[]byte("other"), {1, 2}}, [2][]byte{[]byte(SectionName), testSection(t)})
+	require.NoError(t, os.WriteFile(path, module, 0644))
+
+	spec, err := Load(path)
+	require.NoError(t, err)
+	assert.Contains(t, spec.Functions, "pay")
+
+	_, err = Load(filepath.Join(t.TempDir(), "missing.wasm"))
+	assert.Error(t, err)
+}
+
+func TestFormatArgs(t *testing.T) {
+	spec := testSpec(t)
+	from := account(t, testAccount)
+
+	assert.Equal(t,
+		[]string{"from: " + testAccount, "to: " + testAccount, "amount: 100"},
+		spec.FormatArgs("transfer", []xdr.ScVal{from, from, i128(100)}))
+
+	payment := scMap(
+		xdr.ScMapEntry{Key: sym("amount"), Val: i128(5)},
+		xdr.ScMapEntry{Key: sym("to"), Val: from},
+	)
+	assert.Equal(t,
+		[]string{"payment: Payment{to: " + testAccount + ", amount: 5}", `memo: Memo::Text("rent")`},
+		spec.FormatArgs("pay", []xdr.ScVal{payment, vec(sym("Text"), str("rent"))}))
+	assert.Equal(t,
+		[]string{"payment: Payment{to: " + testAccount + ", amount: 5}", "memo: None"},
+		spec.FormatArgs("pay", []xdr.ScVal{payment, {Type: xdr.ScValTypeScvVoid}}))
+
+	// Extra arguments are kept without a name
+	assert.Equal(t, []string{"payment: 1", "memo: None", "2"}, spec.FormatArgs("pay", []xdr.ScVal{u32(1), {Type: xdr.ScValTypeScvVoid}, u32(2)}))
+	assert.Nil(t, spec.FormatArgs("burn", []xdr.ScVal{u32(1)}))
+}
+
+func TestFormatValue(t *testing.T) {
+	spec := testSpec(t)
+
+	assert.Equal(t, "Pair(1, 2)", spec.FormatValue(udt("Pair"), vec(u32(1), u32(2))))
+	assert.Equal(t, "Status::Frozen", spec.FormatValue(udt("Status"), u32(2)))
+	assert.Equal(t, "Memo::None", spec.FormatValue(udt("Memo"), vec(sym("None"))))
+	assert.Equal(t, "[Status::Active, Status::Frozen]", spec.FormatValue(
+		xdr.ScSpecTypeDef{Type: xdr.ScSpecTypeScSpecTypeVec, Vec: &xdr.ScSpecTypeVec{ElementType: udt("Status")}},
+		vec(u32(1), u32(2))))
+
+	code := xdr.Uint32(1)
+	contractErr := xdr.ScVal{Type: xdr.ScValTypeScvError, Error: &xdr.ScError{Type: xdr.ScErrorTypeSceContract, ContractCode: &code}}
+	result := xdr.ScSpecTypeDef{Type: xdr.ScSpecTypeScSpecTypeResult, Result: &xdr.ScSpecTypeResult{
+		OkType:    typ(xdr.ScSpecTypeScSpecTypeU32),
+		ErrorType: udt("TokenError"),
+	}}
+	assert.Equal(t, "InsufficientBalance", spec.FormatValue(result, contractErr))
+	assert.Equal(t, "4", spec.FormatValue(result, u32(4)))
+
+	// Values that do not match their type are rendered as they are
+	assert.Equal(t, "9", spec.FormatValue(udt("Status"), u32(9)))
+	assert.Equal(t, "{amount: 5}", spec.FormatValue(udt("Payment"), scMap(xdr.ScMapEntry{Key: sym("amount"), Val: i128(5)})))
+	assert.Equal(t, "7", spec.FormatValue(udt("Unknown"), u32(7)))
+}
@@ -21,6 +21,7 @@ import (
 	"net/http"
 	"strings"
 
+	"github.com/dotandev/hintents/internal/config"
 	"github.com/dotandev/hintents/internal/logger"
 	stellarrpc "github.com/dotandev/hintents/internal/rpc"
 	"github.com/dotandev/hintents/internal/simulator"
@@ -33,7 +34,7 @@ import (
 // Server represents the JSON-RPC daemon server
 type Server struct {
 	rpcClient *stellarrpc.Client
-	simulator *simulator.Runner
+	simulator simulator.RunnerInterface
 	authToken string
 }
 
@@ -43,6 +44,12 @@ type Config struct {
 	Network   string
 	RPCURL    string
 	AuthToken string
+	// SimulatorLimits bounds every simulation run on behalf of a client.
+	// The zero value means simulator.DefaultLimits.
+	SimulatorLimits simulator.Limits
+	// SimulatorWorkers is the number of long-lived simulator processes
+	// serving clients. Zero starts one process per simulation.
+	SimulatorWorkers int
 }
 
 // DebugTransactionRequest represents the debug_transaction RPC request
@@ -69,25 +76,36 @@ type GetTraceResponse struct {
 	Traces []map[string]interface{} `json:"traces"`
 }
 
-// NewServer creates a new JSON-RPC server
-func NewServer(config Config) (*Server, error) {
-	var client *stellarrpc.Client
-	if config.RPCURL != "" {
-		client = stellarrpc.NewClientWithURL(config.RPCURL, stellarrpc.Network(config.Network))
-	} else {
-		client = stellarrpc.NewClient(stellarrpc.Network(config.Network))
+// NewServer creates a new JSON-RPC server. Network may be a built-in or a
+// saved custom network.
+func NewServer(cfg Config) (*Server, error) {
+	client, err := config.NewNetworkClient(cfg.Network, cfg.RPCURL, "")
+	if err != nil {
+		return nil, err
 	}
 
-	sim, err := simulator.NewRunner()
+	sim, err := simulator.NewRunner("", false)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create simulator: %w", err)
 	}
+	if cfg.SimulatorLimits != (simulator.Limits{}) {
+		sim.Limits = cfg.SimulatorLimits
+	}
 
-	return &Server{
+	server := &Server{
 		rpcClient: client,
 		simulator: sim,
-		authToken: config.AuthToken,
-	}, nil
+		authToken: cfg.AuthToken,
+	}
+	if cfg.SimulatorWorkers > 0 {
+		pool, err := simulator.NewPool(sim, cfg.SimulatorWorkers)
+		if err != nil {
+			logger.Logger.Warn("Simulator pool unavailable, starting one process per simulation", "error", err)
+		} else {
+			server.simulator = pool
+		}
+	}
+	return server, nil
 }
 
 // authenticate validates the authorization token
@@ -186,8 +204,12 @@ func (s *Server) Start(ctx context.Context, port string) error {
 
 	// Health check endpoint
 	http.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
+		health := map[string]interface{}{"status": "ok"}
+		if pool, ok := s.simulator.(*simulator.Pool); ok {
+			health["simulator_pool"] = pool.Stats()
+		}
 		w.Header().Set("Content-Type", "application/json")
-		_ = json.NewEncoder(w).Encode(map[string]string{"status": "ok"})
+		_ = json.NewEncoder(w).Encode(health)
 	})
 
 	logger.Logger.Info("Starting JSON-RPC server", "port", port)
@@ -206,5 +228,9 @@ func (s *Server) Start(ctx context.Context, port string) error {
 	// Wait for context cancellation
 	<-ctx.Done()
 	logger.Logger.Info("Shutting down JSON-RPC server")
-	return srv.Shutdown(context.Background())
+	err := srv.Shutdown(context.Background())
+	if pool, ok := s.simulator.(*simulator.Pool); ok {
+		_ = pool.Close()
+	}
+	return err
 }
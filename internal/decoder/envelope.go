@@ -15,17 +15,37 @@
 package decoder
 
 import (
+	"encoding/hex"
 	"fmt"
 
 	"github.com/stellar/go/xdr"
 )
 
+// DecodedEnvelope is a decoded transaction envelope, rendered as text by
+// WriteEnvelope and as JSON or YAML through its tags
 type DecodedEnvelope struct {
-	Type       string
-	Source     string
-	Fee        int64
-	Operations []xdr.Operation
-	InnerTx    *DecodedEnvelope // for FeeBump
+	Type          string           `json:"type" yaml:"type"`
+	Source        string           `json:"source" yaml:"source"`
+	Fee           int64            `json:"fee" yaml:"fee"`
+	Sequence      int64            `json:"sequence,omitempty" yaml:"sequence,omitempty"`
+	Memo          string           `json:"memo,omitempty" yaml:"memo,omitempty"`
+	Preconditions Fields           `json:"preconditions,omitempty" yaml:"preconditions,omitempty"`
+	Operations    []Operation      `json:"operations,omitempty" yaml:"operations,omitempty"`
+	Soroban       *SorobanData     `json:"soroban,omitempty" yaml:"soroban,omitempty"`
+	Signatures    int              `json:"signatures" yaml:"signatures"`
+	InnerTx       *DecodedEnvelope `json:"inner_tx,omitempty" yaml:"inner_tx,omitempty"` // for FeeBump
+}
+
+// SorobanData is the SorobanTransactionData of a transaction: the ledger
+// entries it may access and the resources it pays for
+type SorobanData struct {
+	ReadOnly        []string `json:"read_only" yaml:"read_only"`
+	ReadWrite       []string `json:"read_write" yaml:"read_write"`
+	Instructions    uint32   `json:"instructions" yaml:"instructions"`
+	DiskReadBytes   uint32   `json:"disk_read_bytes" yaml:"disk_read_bytes"`
+	WriteBytes      uint32   `json:"write_bytes" yaml:"write_bytes"`
+	ResourceFee     int64    `json:"resource_fee" yaml:"resource_fee"`
+	ArchivedEntries []uint32 `json:"archived_entries,omitempty" yaml:"archived_entries,omitempty"`
 }
 
 func AnalyzeEnvelope(b64 string) (*DecodedEnvelope, error) {
@@ -37,59 +57,187 @@ func AnalyzeEnvelope(b64 string) (*DecodedEnvelope, error) {
 
 	switch env.Type {
 	case xdr.EnvelopeTypeEnvelopeTypeTxV0:
-		return decodeV0(env.V0.Tx)
+		return decodeV0(*env.V0)
 
 	case xdr.EnvelopeTypeEnvelopeTypeTx:
-		return decodeV1(env.V1.Tx)
+		return decodeV1(*env.V1)
 
 	case xdr.EnvelopeTypeEnvelopeTypeTxFeeBump:
-		return decodeFeeBump(env.FeeBump.Tx)
+		return decodeFeeBump(*env.FeeBump)
 
 	default:
 		return nil, fmt.Errorf("unsupported envelope type: %s", env.Type)
 	}
 }
 
-func decodeV0(tx xdr.TransactionV0) (*DecodedEnvelope, error) {
+func decodeV0(env xdr.TransactionV0Envelope) (*DecodedEnvelope, error) {
+	tx := env.Tx
 	source := xdr.AccountId{
 		Type:    xdr.PublicKeyTypePublicKeyTypeEd25519,
 		Ed25519: &tx.SourceAccountEd25519,
 	}
-	return &DecodedEnvelope{
+	d := &DecodedEnvelope{
 		Type:       "TransactionV0",
 		Source:     source.Address(),
 		Fee:        int64(tx.Fee),
-		Operations: tx.Operations,
-	}, nil
+		Sequence:   int64(tx.SeqNum),
+		Memo:       formatMemo(tx.Memo),
+		Operations: decodeOperations(tx.Operations),
+		Signatures: len(env.Signatures),
+	}
+	if tx.TimeBounds != nil {
+		d.Preconditions = Fields{{"TimeBounds", formatTimeBounds(*tx.TimeBounds)}}
+	}
+	return d, nil
 }
-func decodeV1(tx xdr.Transaction) (*DecodedEnvelope, error) {
-	return &DecodedEnvelope{
-		Type:       "TransactionV1",
-		Source:     tx.SourceAccount.Address(),
-		Fee:        int64(tx.Fee),
-		Operations: tx.Operations,
-	}, nil
+
+func decodeV1(env xdr.TransactionV1Envelope) (*DecodedEnvelope, error) {
+	tx := env.Tx
+	d := &DecodedEnvelope{
+		Type:          "TransactionV1",
+		Source:        tx.SourceAccount.Address(),
+		Fee:           int64(tx.Fee),
+		Sequence:      int64(tx.SeqNum),
+		Memo:          formatMemo(tx.Memo),
+		Preconditions: preconditionDetails(tx.Cond),
+		Operations:    decodeOperations(tx.Operations),
+		Signatures:    len(env.Signatures),
+	}
+	if data, ok := tx.Ext.GetSorobanData(); ok {
+		d.Soroban = decodeSorobanData(data)
+	}
+	return d, nil
 }
 
-func decodeFeeBump(fb xdr.FeeBumpTransaction) (*DecodedEnvelope, error) {
+func decodeFeeBump(env xdr.FeeBumpTransactionEnvelope) (*DecodedEnvelope, error) {
+	fb := env.Tx
 	inner, err := DecodeEnvelopeFromInner(fb.InnerTx)
 	if err != nil {
 		return nil, err
 	}
 
 	return &DecodedEnvelope{
-		Type:    "FeeBumpTransaction",
-		Source:  fb.FeeSource.Address(),
-		Fee:     int64(fb.Fee),
-		InnerTx: inner,
+		Type:       "FeeBumpTransaction",
+		Source:     fb.FeeSource.Address(),
+		Fee:        int64(fb.Fee),
+		Signatures: len(env.Signatures),
+		InnerTx:    inner,
 	}, nil
 }
 
 func DecodeEnvelopeFromInner(inner xdr.FeeBumpTransactionInnerTx) (*DecodedEnvelope, error) {
 	switch inner.Type {
 	case xdr.EnvelopeTypeEnvelopeTypeTx:
-		return decodeV1(inner.V1.Tx)
+		return decodeV1(*inner.V1)
 	default:
 		return nil, fmt.Errorf("unsupported inner tx type")
 	}
 }
+
+func decodeOperations(ops []xdr.Operation) []Operation {
+	decoded := make([]Operation, len(ops))
+	for i, op := range ops {
+		decoded[i] = DecodeOperation(op)
+	}
+	return decoded
+}
+
+func decodeSorobanData(data xdr.SorobanTransactionData) *SorobanData {
+	resources := data.Resources
+	d := &SorobanData{
+		ReadOnly:      formatLedgerKeys(resources.Footprint.ReadOnly),
+		ReadWrite:     formatLedgerKeys(resources.Footprint.ReadWrite),
+		Instructions:  uint32(resources.Instructions),
+		DiskReadBytes: uint32(resources.DiskReadBytes),
+		WriteBytes:    uint32(resources.WriteBytes),
+		ResourceFee:   int64(data.ResourceFee),
+	}
+	if ext, ok := data.Ext.GetResourceExt(); ok {
+		for _, index := range ext.ArchivedSorobanEntries {
+			d.ArchivedEntries = append(d.ArchivedEntries, uint32(index))
+		}
+	}
+	return d
+}
+
+func formatLedgerKeys(keys []xdr.LedgerKey) []string {
+	out := make([]string, len(keys))
+	for i, key := range keys {
+		out[i] = FormatLedgerKey(key)
+	}
+	return out
+}
+
+func formatMemo(memo xdr.Memo) string {
+	switch memo.Type {
+	case xdr.MemoTypeMemoText:
+		if memo.Text != nil {
+			return fmt.Sprintf("Text(%q)", *memo.Text)
+		}
+	case xdr.MemoTypeMemoId:
+		if memo.Id != nil {
+			return fmt.Sprintf("ID(%d)", *memo.Id)
+		}
+	case xdr.MemoTypeMemoHash:
+		if memo.Hash != nil {
+			return "Hash(" + hex.EncodeToString(memo.Hash[:]) + ")"
+		}
+	case xdr.MemoTypeMemoReturn:
+		if memo.RetHash != nil {
+			return "Return(" + hex.EncodeToString(memo.RetHash[:]) + ")"
+		}
+	}
+	return ""
+}
+
+func preconditionDetails(cond xdr.Preconditions) Fields {
+	if tb, ok := cond.GetTimeBounds(); ok {
+		return Fields{{"TimeBounds", formatTimeBounds(tb)}}
+	}
+	v2, ok := cond.GetV2()
+	if !ok {
+		return nil
+	}
+
+	var fields Fields
+	if v2.TimeBounds != nil {
+		fields = append(fields, Field{"TimeBounds", formatTimeBounds(*v2.TimeBounds)})
+	}
+	if v2.LedgerBounds != nil {
+		to := "unbounded"
+		if v2.LedgerBounds.MaxLedger != 0 {
+			to = fmt.Sprint(v2.LedgerBounds.MaxLedger)
+		}
+		fields = append(fields, Field{"LedgerBounds", fmt.Sprintf("%d to %s", v2.LedgerBounds.MinLedger, to)})
+	}
+	if v2.MinSeqNum != nil {
+		fields = append(fields, Field{"MinSeqNum", int64(*v2.MinSeqNum)})
+	}
+	if v2.MinSeqAge != 0 {
+		fields = append(fields, Field{"MinSeqAge", formatDuration(uint64(v2.MinSeqAge))})
+	}
+	if v2.MinSeqLedgerGap != 0 {
+		fields = append(fields, Field{"MinSeqLedgerGap", uint32(v2.MinSeqLedgerGap)})
+	}
+	if len(v2.ExtraSigners) > 0 {
+		signers := make([]string, len(v2.ExtraSigners))
+		for i, key := range v2.ExtraSigners {
+			signers[i] = formatSignerKey(key)
+		}
+		fields = append(fields, Field{"ExtraSigners", signers})
+	}
+	return fields
+}
+
+// formatTimeBounds renders the validity window of a transaction, a zero
+// bound being open
+func formatTimeBounds(tb xdr.TimeBounds) string {
+	from, to := "unbounded", "unbounded"
+	if tb.MinTime != 0 {
+		from = formatTimepoint(uint64(tb.MinTime))
+	}
+	if tb.MaxTime != 0 {
+		to = formatTimepoint(uint64(tb.MaxTime))
+	}
+	return from + " to " + to
+}
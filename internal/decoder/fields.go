@@ -0,0 +1,107 @@
+// Copyright (c) 2026 dotandev
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package decoder
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Field is a named parameter of a decoded operation. Values are strings,
+// numbers, []string, Fields or []Fields.
+type Field struct {
+	Name  string
+	Value interface{}
+}
+
+// Fields is an ordered list of parameters. It renders as an object keyed by
+// the snake_case field names in JSON and YAML, keeping the field order.
+type Fields []Field
+
+// MarshalJSON implements json.Marshaler
+func (f Fields) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, field := range f {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(fieldKey(field.Name))
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(field.Value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// MarshalYAML implements yaml.Marshaler
+func (f Fields) MarshalYAML() (interface{}, error) {
+	node := &yaml.Node{Kind: yaml.MappingNode}
+	for _, field := range f {
+		var value yaml.Node
+		if err := value.Encode(field.Value); err != nil {
+			return nil, err
+		}
+		node.Content = append(node.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: fieldKey(field.Name)},
+			&value,
+		)
+	}
+	return node, nil
+}
+
+// fieldKey is the JSON and YAML key of a field, "starting_balance" for
+// StartingBalance
+func fieldKey(name string) string {
+	return strings.ToLower(strings.Join(fieldWords(name), "_"))
+}
+
+// fieldLabel is the text label of a field, "Starting Balance" for
+// StartingBalance
+func fieldLabel(name string) string {
+	return strings.Join(fieldWords(name), " ")
+}
+
+// fieldWords splits a CamelCase name into words, keeping acronyms such as
+// ID whole
+func fieldWords(name string) []string {
+	runes := []rune(name)
+	var words []string
+	start := 0
+	for i := 1; i < len(runes); i++ {
+		if !unicode.IsUpper(runes[i]) {
+			continue
+		}
+		prevLower := !unicode.IsUpper(runes[i-1])
+		nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+		if prevLower || nextLower {
+			words = append(words, string(runes[start:i]))
+			start = i
+		}
+	}
+	return append(words, string(runes[start:]))
+}
@@ -0,0 +1,668 @@
+// Copyright (c) 2026 dotandev
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package decoder
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/stellar/go/amount"
+	"github.com/stellar/go/strkey"
+	"github.com/stellar/go/xdr"
+)
+
+// Operation is a decoded operation of a transaction
+type Operation struct {
+	Type    string `json:"type" yaml:"type"`
+	Source  string `json:"source,omitempty" yaml:"source,omitempty"`
+	Details Fields `json:"details,omitempty" yaml:"details,omitempty"`
+}
+
+// DecodeOperation decodes the type, source account and parameters of an
+// operation
+func DecodeOperation(op xdr.Operation) Operation {
+	decoded := Operation{
+		Type:    OperationTypeName(op.Body.Type),
+		Details: operationDetails(op.Body),
+	}
+	if op.SourceAccount != nil {
+		decoded.Source = op.SourceAccount.Address()
+	}
+	return decoded
+}
+
+// OperationTypeName names an operation type, "InvokeHostFunction" for
+// OperationTypeInvokeHostFunction
+func OperationTypeName(t xdr.OperationType) string {
+	return strings.TrimPrefix(t.String(), "OperationType")
+}
+
+// Account flags of SetOptions, by bit
+var accountFlagNames = []string{"AuthRequired", "AuthRevocable", "AuthImmutable", "AuthClawbackEnabled"}
+
+// Trustline flags of SetTrustLineFlags, by bit
+var trustLineFlagNames = []string{"Authorized", "AuthorizedToMaintainLiabilities", "ClawbackEnabled"}
+
+func operationDetails(body xdr.OperationBody) Fields {
+	switch body.Type {
+	case xdr.OperationTypeCreateAccount:
+		if op, ok := body.GetCreateAccountOp(); ok {
+			return Fields{
+				{"Destination", op.Destination.Address()},
+				{"StartingBalance", amount.String(op.StartingBalance)},
+			}
+		}
+
+	case xdr.OperationTypePayment:
+		if op, ok := body.GetPaymentOp(); ok {
+			return Fields{
+				{"Destination", op.Destination.Address()},
+				{"Asset", formatAsset(op.Asset)},
+				{"Amount", amount.String(op.Amount)},
+			}
+		}
+
+	case xdr.OperationTypePathPaymentStrictReceive:
+		if op, ok := body.GetPathPaymentStrictReceiveOp(); ok {
+			return Fields{
+				{"SendAsset", formatAsset(op.SendAsset)},
+				{"SendMax", amount.String(op.SendMax)},
+				{"Destination", op.Destination.Address()},
+				{"DestAsset", formatAsset(op.DestAsset)},
+				{"DestAmount", amount.String(op.DestAmount)},
+				{"Path", formatAssets(op.Path)},
+			}
+		}
+
+	case xdr.OperationTypePathPaymentStrictSend:
+		if op, ok := body.GetPathPaymentStrictSendOp(); ok {
+			return Fields{
+				{"SendAsset", formatAsset(op.SendAsset)},
+				{"SendAmount", amount.String(op.SendAmount)},
+				{"Destination", op.Destination.Address()},
+				{"DestAsset", formatAsset(op.DestAsset)},
+				{"DestMin", amount.String(op.DestMin)},
+				{"Path", formatAssets(op.Path)},
+			}
+		}
+
+	case xdr.OperationTypeManageSellOffer:
+		if op, ok := body.GetManageSellOfferOp(); ok {
+			return Fields{
+				{"Selling", formatAsset(op.Selling)},
+				{"Buying", formatAsset(op.Buying)},
+				{"Amount", amount.String(op.Amount)},
+				{"Price", op.Price.String()},
+				{"OfferID", int64(op.OfferId)},
+			}
+		}
+
+	case xdr.OperationTypeManageBuyOffer:
+		if op, ok := body.GetManageBuyOfferOp(); ok {
+			return Fields{
+				{"Selling", formatAsset(op.Selling)},
+				{"Buying", formatAsset(op.Buying)},
+				{"BuyAmount", amount.String(op.BuyAmount)},
+				{"Price", op.Price.String()},
+				{"OfferID", int64(op.OfferId)},
+			}
+		}
+
+	case xdr.OperationTypeCreatePassiveSellOffer:
+		if op, ok := body.GetCreatePassiveSellOfferOp(); ok {
+			return Fields{
+				{"Selling", formatAsset(op.Selling)},
+				{"Buying", formatAsset(op.Buying)},
+				{"Amount", amount.String(op.Amount)},
+				{"Price", op.Price.String()},
+			}
+		}
+
+	case xdr.OperationTypeSetOptions:
+		if op, ok := body.GetSetOptionsOp(); ok {
+			return setOptionsDetails(op)
+		}
+
+	case xdr.OperationTypeChangeTrust:
+		if op, ok := body.GetChangeTrustOp(); ok {
+			return Fields{
+				{"Line", formatChangeTrustAsset(op.Line)},
+				{"Limit", amount.String(op.Limit)},
+			}
+		}
+
+	case xdr.OperationTypeAllowTrust:
+		if op, ok := body.GetAllowTrustOp(); ok {
+			return Fields{
+				{"Trustor", op.Trustor.Address()},
+				{"Asset", formatAssetCode(op.Asset)},
+				{"Authorize", flagNames(uint32(op.Authorize), trustLineFlagNames)},
+			}
+		}
+
+	case xdr.OperationTypeAccountMerge:
+		if destination, ok := body.GetDestination(); ok {
+			return Fields{{"Destination", destination.Address()}}
+		}
+
+	case xdr.OperationTypeManageData:
+		if op, ok := body.GetManageDataOp(); ok {
+			fields := Fields{{"Name", string(op.DataName)}}
+			if op.DataValue != nil {
+				fields = append(fields, Field{"Value", formatData(*op.DataValue)})
+			}
+			return fields
+		}
+
+	case xdr.OperationTypeBumpSequence:
+		if op, ok := body.GetBumpSequenceOp(); ok {
+			return Fields{{"BumpTo", int64(op.BumpTo)}}
+		}
+
+	case xdr.OperationTypeCreateClaimableBalance:
+		if op, ok := body.GetCreateClaimableBalanceOp(); ok {
+			claimants := make([]Fields, 0, len(op.Claimants))
+			for _, c := range op.Claimants {
+				if v0, ok := c.GetV0(); ok {
+					claimants = append(claimants, Fields{
+						{"Destination", v0.Destination.Address()},
+						{"Predicate", formatPredicate(v0.Predicate)},
+					})
+				}
+			}
+			return Fields{
+				{"Asset", formatAsset(op.Asset)},
+				{"Amount", amount.String(op.Amount)},
+				{"Claimants", claimants},
+			}
+		}
+
+	case xdr.OperationTypeClaimClaimableBalance:
+		if op, ok := body.GetClaimClaimableBalanceOp(); ok {
+			return Fields{{"BalanceID", formatBalanceID(op.BalanceId)}}
+		}
+
+	case xdr.OperationTypeBeginSponsoringFutureReserves:
+		if op, ok := body.GetBeginSponsoringFutureReservesOp(); ok {
+			return Fields{{"SponsoredID", op.SponsoredId.Address()}}
+		}
+
+	case xdr.OperationTypeRevokeSponsorship:
+		if op, ok := body.GetRevokeSponsorshipOp(); ok {
+			if key, ok := op.GetLedgerKey(); ok {
+				return Fields{{"LedgerKey", FormatLedgerKey(key)}}
+			}
+			if signer, ok := op.GetSigner(); ok {
+				return Fields{
+					{"Account", signer.AccountId.Address()},
+					{"Signer", formatSignerKey(signer.SignerKey)},
+				}
+			}
+		}
+
+	case xdr.OperationTypeClawback:
+		if op, ok := body.GetClawbackOp(); ok {
+			return Fields{
+				{"Asset", formatAsset(op.Asset)},
+				{"From", op.From.Address()},
+				{"Amount", amount.String(op.Amount)},
+			}
+		}
+
+	case xdr.OperationTypeClawbackClaimableBalance:
+		if op, ok := body.GetClawbackClaimableBalanceOp(); ok {
+			return Fields{{"BalanceID", formatBalanceID(op.BalanceId)}}
+		}
+
+	case xdr.OperationTypeSetTrustLineFlags:
+		if op, ok := body.GetSetTrustLineFlagsOp(); ok {
+			return Fields{
+				{"Trustor", op.Trustor.Address()},
+				{"Asset", formatAsset(op.Asset)},
+				{"ClearFlags", flagNames(uint32(op.ClearFlags), trustLineFlagNames)},
+				{"SetFlags", flagNames(uint32(op.SetFlags), trustLineFlagNames)},
+			}
+		}
+
+	case xdr.OperationTypeLiquidityPoolDeposit:
+		if op, ok := body.GetLiquidityPoolDepositOp(); ok {
+			return Fields{
+				{"LiquidityPoolID", formatPoolID(op.LiquidityPoolId)},
+				{"MaxAmountA", amount.String(op.MaxAmountA)},
+				{"MaxAmountB", amount.String(op.MaxAmountB)},
+				{"MinPrice", op.MinPrice.String()},
+				{"MaxPrice", op.MaxPrice.String()},
+			}
+		}
+
+	case xdr.OperationTypeLiquidityPoolWithdraw:
+		if op, ok := body.GetLiquidityPoolWithdrawOp(); ok {
+			return Fields{
+				{"LiquidityPoolID", formatPoolID(op.LiquidityPoolId)},
+				{"Amount", amount.String(op.Amount)},
+				{"MinAmountA", amount.String(op.MinAmountA)},
+				{"MinAmountB", amount.String(op.MinAmountB)},
+			}
+		}
+
+	case xdr.OperationTypeInvokeHostFunction:
+		if op, ok := body.GetInvokeHostFunctionOp(); ok {
+			fields := hostFunctionDetails(op.HostFunction)
+			if len(op.Auth) > 0 {
+				auth := make([]Fields, len(op.Auth))
+				for i, entry := range op.Auth {
+					auth[i] = authDetails(entry)
+				}
+				fields = append(fields, Field{"Auth", auth})
+			}
+			return fields
+		}
+
+	case xdr.OperationTypeExtendFootprintTtl:
+		if op, ok := body.GetExtendFootprintTtlOp(); ok {
+			return Fields{{"ExtendTo", uint32(op.ExtendTo)}}
+		}
+	}
+
+	// Inflation, EndSponsoringFutureReserves and RestoreFootprint have no
+	// parameters, the footprint restored being part of the Soroban data
+	return nil
+}
+
+func setOptionsDetails(op xdr.SetOptionsOp) Fields {
+	var fields Fields
+	if op.InflationDest != nil {
+		fields = append(fields, Field{"InflationDest", op.InflationDest.Address()})
+	}
+	if op.ClearFlags != nil {
+		fields = append(fields, Field{"ClearFlags", flagNames(uint32(*op.ClearFlags), accountFlagNames)})
+	}
+	if op.SetFlags != nil {
+		fields = append(fields, Field{"SetFlags", flagNames(uint32(*op.SetFlags), accountFlagNames)})
+	}
+	for _, weight := range []struct {
+		name  string
+		value *xdr.Uint32
+	}{
+		{"MasterWeight", op.MasterWeight},
+		{"LowThreshold", op.LowThreshold},
+		{"MedThreshold", op.MedThreshold},
+		{"HighThreshold", op.HighThreshold},
+	} {
+		if weight.value != nil {
+			fields = append(fields, Field{weight.name, uint32(*weight.value)})
+		}
+	}
+	if op.HomeDomain != nil {
+		fields = append(fields, Field{"HomeDomain", string(*op.HomeDomain)})
+	}
+	if op.Signer != nil {
+		fields = append(fields, Field{"Signer", Fields{
+			{"Key", formatSignerKey(op.Signer.Key)},
+			{"Weight", uint32(op.Signer.Weight)},
+		}})
+	}
+	return fields
+}
+
+// hostFunctionDetails decodes the host function an InvokeHostFunction
+// operation calls
+func hostFunctionDetails(fn xdr.HostFunction) Fields {
+	switch fn.Type {
+	case xdr.HostFunctionTypeHostFunctionTypeInvokeContract:
+		if invoke, ok := fn.GetInvokeContract(); ok {
+			return append(Fields{{"HostFunction", "InvokeContract"}}, invokeDetails(invoke)...)
+		}
+	case xdr.HostFunctionTypeHostFunctionTypeCreateContract:
+		if create, ok := fn.GetCreateContract(); ok {
+			return append(Fields{{"HostFunction", "CreateContract"}}, createDetails(create.ContractIdPreimage, create.Executable, nil)...)
+		}
+	case xdr.HostFunctionTypeHostFunctionTypeCreateContractV2:
+		if create, ok := fn.GetCreateContractV2(); ok {
+			return append(Fields{{"HostFunction", "CreateContractV2"}}, createDetails(create.ContractIdPreimage, create.Executable, create.ConstructorArgs)...)
+		}
+	case xdr.HostFunctionTypeHostFunctionTypeUploadContractWasm:
+		if wasm, ok := fn.GetWasm(); ok {
+			hash := sha256.Sum256(wasm)
+			return Fields{
+				{"HostFunction", "UploadContractWasm"},
+				{"WasmHash", hex.EncodeToString(hash[:])},
+				{"WasmSize", len(wasm)},
+			}
+		}
+	}
+	return Fields{{"HostFunction", fn.Type.String()}}
+}
+
+func invokeDetails(invoke xdr.InvokeContractArgs) Fields {
+	return Fields{
+		{"Contract", formatScAddress(invoke.ContractAddress)},
+		{"Function", string(invoke.FunctionName)},
+		{"Args", formatScVals(invoke.Args)},
+	}
+}
+
+func createDetails(preimage xdr.ContractIdPreimage, executable xdr.ContractExecutable, constructorArgs []xdr.ScVal) Fields {
+	var fields Fields
+	if from, ok := preimage.GetFromAddress(); ok {
+		fields = append(fields,
+			Field{"Deployer", formatScAddress(from.Address)},
+			Field{"Salt", hex.EncodeToString(from.Salt[:])},
+		)
+	}
+	if asset, ok := preimage.GetFromAsset(); ok {
+		fields = append(fields, Field{"Asset", formatAsset(asset)})
+	}
+
+	if hash, ok := executable.GetWasmHash(); ok {
+		fields = append(fields, Field{"Executable", "Wasm(" + hex.EncodeToString(hash[:]) + ")"})
+	} else {
+		fields = append(fields, Field{"Executable", "StellarAsset"})
+	}
+	if constructorArgs != nil {
+		fields = append(fields, Field{"ConstructorArgs", formatScVals(constructorArgs)})
+	}
+	return fields
+}
+
+// authDetails decodes an authorization entry: whose credentials sign it and
+// the tree of invocations it authorizes
+func authDetails(entry xdr.SorobanAuthorizationEntry) Fields {
+	var fields Fields
+	if creds, ok := entry.Credentials.GetAddress(); ok {
+		fields = Fields{
+			{"Credentials", "Address"},
+			{"Address", formatScAddress(creds.Address)},
+			{"Nonce", int64(creds.Nonce)},
+			{"SignatureExpirationLedger", uint32(creds.SignatureExpirationLedger)},
+			{"Signature", FormatScVal(creds.Signature)},
+		}
+	} else {
+		fields = Fields{{"Credentials", "SourceAccount"}}
+	}
+	return append(fields, Field{"Invocation", invocationDetails(entry.RootInvocation)})
+}
+
+func invocationDetails(inv xdr.SorobanAuthorizedInvocation) Fields {
+	var fields Fields
+	fn := inv.Function
+	switch fn.Type {
+	case xdr.SorobanAuthorizedFunctionTypeSorobanAuthorizedFunctionTypeContractFn:
+		if invoke, ok := fn.GetContractFn(); ok {
+			fields = invokeDetails(invoke)
+		}
+	case xdr.SorobanAuthorizedFunctionTypeSorobanAuthorizedFunctionTypeCreateContractHostFn:
+		if create, ok := fn.GetCreateContractHostFn(); ok {
+			fields = append(Fields{{"HostFunction", "CreateContract"}}, createDetails(create.ContractIdPreimage, create.Executable, nil)...)
+		}
+	case xdr.SorobanAuthorizedFunctionTypeSorobanAuthorizedFunctionTypeCreateContractV2HostFn:
+		if create, ok := fn.GetCreateContractV2HostFn(); ok {
+			fields = append(Fields{{"HostFunction", "CreateContractV2"}}, createDetails(create.ContractIdPreimage, create.Executable, create.ConstructorArgs)...)
+		}
+	}
+
+	if len(inv.SubInvocations) > 0 {
+		subs := make([]Fields, len(inv.SubInvocations))
+		for i, sub := range inv.SubInvocations {
+			subs[i] = invocationDetails(sub)
+		}
+		fields = append(fields, Field{"SubInvocations", subs})
+	}
+	return fields
+}
+
+// FormatLedgerKey renders the key of a ledger entry, as in the footprint of
+// a Soroban transaction:
+//
+//	Account(GABC...)  ContractData(CDEF..., Sym(balance), Persistent)  ContractCode(3f2a...)
+func FormatLedgerKey(key xdr.LedgerKey) string {
+	switch key.Type {
+	case xdr.LedgerEntryTypeAccount:
+		if k, ok := key.GetAccount(); ok {
+			return "Account(" + k.AccountId.Address() + ")"
+		}
+	case xdr.LedgerEntryTypeTrustline:
+		if k, ok := key.GetTrustLine(); ok {
+			return "Trustline(" + k.AccountId.Address() + ", " + formatTrustLineAsset(k.Asset) + ")"
+		}
+	case xdr.LedgerEntryTypeOffer:
+		if k, ok := key.GetOffer(); ok {
+			return fmt.Sprintf("Offer(%s, %d)", k.SellerId.Address(), k.OfferId)
+		}
+	case xdr.LedgerEntryTypeData:
+		if k, ok := key.GetData(); ok {
+			return fmt.Sprintf("Data(%s, %q)", k.AccountId.Address(), string(k.DataName))
+		}
+	case xdr.LedgerEntryTypeClaimableBalance:
+		if k, ok := key.GetClaimableBalance(); ok {
+			return "ClaimableBalance(" + formatBalanceID(k.BalanceId) + ")"
+		}
+	case xdr.LedgerEntryTypeLiquidityPool:
+		if k, ok := key.GetLiquidityPool(); ok {
+			return "LiquidityPool(" + formatPoolID(k.LiquidityPoolId) + ")"
+		}
+	case xdr.LedgerEntryTypeContractData:
+		if k, ok := key.GetContractData(); ok {
+			durability := strings.TrimPrefix(k.Durability.String(), "ContractDataDurability")
+			return "ContractData(" + formatScAddress(k.Contract) + ", " + FormatScVal(k.Key) + ", " + durability + ")"
+		}
+	case xdr.LedgerEntryTypeContractCode:
+		if k, ok := key.GetContractCode(); ok {
+			return "ContractCode(" + hex.EncodeToString(k.Hash[:]) + ")"
+		}
+	case xdr.LedgerEntryTypeConfigSetting:
+		if k, ok := key.GetConfigSetting(); ok {
+			return "ConfigSetting(" + strings.TrimPrefix(k.ConfigSettingId.String(), "ConfigSettingIdConfigSetting") + ")"
+		}
+	case xdr.LedgerEntryTypeTtl:
+		if k, ok := key.GetTtl(); ok {
+			return "Ttl(" + hex.EncodeToString(k.KeyHash[:]) + ")"
+		}
+	}
+	return key.Type.String()
+}
+
+// FormatLedgerEntry renders the value of a ledger entry, the counterpart of
+// FormatLedgerKey:
+//
+//	balance 100.0000000, seq 42  {owner: GABC..., amount: 5}  1024 bytes of wasm
+func FormatLedgerEntry(data xdr.LedgerEntryData) string {
+	switch data.Type {
+	case xdr.LedgerEntryTypeAccount:
+		if a, ok := data.GetAccount(); ok {
+			return fmt.Sprintf("balance %s, seq %d", amount.String(a.Balance), int64(a.SeqNum))
+		}
+	case xdr.LedgerEntryTypeTrustline:
+		if t, ok := data.GetTrustLine(); ok {
+			return fmt.Sprintf("balance %s, limit %s", amount.String(t.Balance), amount.String(t.Limit))
+		}
+	case xdr.LedgerEntryTypeContractData:
+		if d, ok := data.GetContractData(); ok {
+			return FormatScVal(d.Val)
+		}
+	case xdr.LedgerEntryTypeContractCode:
+		if c, ok := data.GetContractCode(); ok {
+			return fmt.Sprintf("%d bytes of wasm", len(c.Code))
+		}
+	case xdr.LedgerEntryTypeTtl:
+		if t, ok := data.GetTtl(); ok {
+			return fmt.Sprintf("live until ledger %d", t.LiveUntilLedgerSeq)
+		}
+	}
+	return strings.TrimPrefix(data.Type.String(), "LedgerEntryType")
+}
+
+func formatScAddress(address xdr.ScAddress) string {
+	s, err := address.String()
+	if err != nil {
+		return "<invalid address>"
+	}
+	return s
+}
+
+// formatScVals renders values as FormatScVal does, never returning nil so
+// an empty list stays a list in JSON
+func formatScVals(vals []xdr.ScVal) []string {
+	out := make([]string, len(vals))
+	for i, v := range vals {
+		out[i] = FormatScVal(v)
+	}
+	return out
+}
+
+func formatAsset(asset xdr.Asset) string {
+	var typ, code, issuer string
+	if err := asset.Extract(&typ, &code, &issuer); err != nil {
+		return "<invalid asset>"
+	}
+	if asset.Type == xdr.AssetTypeAssetTypeNative {
+		return "native"
+	}
+	return code + ":" + issuer
+}
+
+func formatAssets(assets []xdr.Asset) []string {
+	out := make([]string, len(assets))
+	for i, asset := range assets {
+		out[i] = formatAsset(asset)
+	}
+	return out
+}
+
+func formatChangeTrustAsset(asset xdr.ChangeTrustAsset) string {
+	pool, ok := asset.GetLiquidityPool()
+	if !ok {
+		return formatAsset(asset.ToAsset())
+	}
+	params, ok := pool.GetConstantProduct()
+	if !ok {
+		return "LiquidityPool"
+	}
+	return fmt.Sprintf("LiquidityPool(%s, %s, fee %d bps)", formatAsset(params.AssetA), formatAsset(params.AssetB), params.Fee)
+}
+
+func formatTrustLineAsset(asset xdr.TrustLineAsset) string {
+	if pool, ok := asset.GetLiquidityPoolId(); ok {
+		return "LiquidityPool(" + formatPoolID(pool) + ")"
+	}
+	return formatAsset(asset.ToAsset())
+}
+
+func formatAssetCode(code xdr.AssetCode) string {
+	if c, ok := code.GetAssetCode4(); ok {
+		return strings.TrimRight(string(c[:]), "\x00")
+	}
+	if c, ok := code.GetAssetCode12(); ok {
+		return strings.TrimRight(string(c[:]), "\x00")
+	}
+	return ""
+}
+
+func formatBalanceID(id xdr.ClaimableBalanceId) string {
+	s, err := id.EncodeToStrkey()
+	if err != nil {
+		return "<invalid balance ID>"
+	}
+	return s
+}
+
+func formatPoolID(id xdr.PoolId) string {
+	s, err := strkey.Encode(strkey.VersionByteLiquidityPool, id[:])
+	if err != nil {
+		return hex.EncodeToString(id[:])
+	}
+	return s
+}
+
+func formatSignerKey(key xdr.SignerKey) string {
+	s, err := key.GetAddress()
+	if err != nil {
+		return "<invalid signer key>"
+	}
+	return s
+}
+
+// formatData renders a ManageData value as text when it is printable, as
+// base64 otherwise
+func formatData(value xdr.DataValue) string {
+	if utf8.Valid(value) && strings.IndexFunc(string(value), isNotPrint) < 0 {
+		return string(value)
+	}
+	return "base64:" + base64.StdEncoding.EncodeToString(value)
+}
+
+func isNotPrint(r rune) bool {
+	return !strconv.IsPrint(r)
+}
+
+// formatPredicate renders the condition for claiming a claimable balance:
+//
+//	And(BeforeAbsoluteTime(2026-01-01T00:00:00Z), Not(BeforeRelativeTime(1h0m0s)))
+func formatPredicate(p xdr.ClaimPredicate) string {
+	switch p.Type {
+	case xdr.ClaimPredicateTypeClaimPredicateUnconditional:
+		return "Unconditional"
+	case xdr.ClaimPredicateTypeClaimPredicateAnd, xdr.ClaimPredicateTypeClaimPredicateOr:
+		preds := p.AndPredicates
+		name := "And"
+		if p.Type == xdr.ClaimPredicateTypeClaimPredicateOr {
+			preds, name = p.OrPredicates, "Or"
+		}
+		if preds == nil {
+			return name + "()"
+		}
+		parts := make([]string, len(*preds))
+		for i, sub := range *preds {
+			parts[i] = formatPredicate(sub)
+		}
+		return name + "(" + strings.Join(parts, ", ") + ")"
+	case xdr.ClaimPredicateTypeClaimPredicateNot:
+		if p.NotPredicate == nil || *p.NotPredicate == nil {
+			return "Not()"
+		}
+		return "Not(" + formatPredicate(**p.NotPredicate) + ")"
+	case xdr.ClaimPredicateTypeClaimPredicateBeforeAbsoluteTime:
+		if p.AbsBefore != nil {
+			return "BeforeAbsoluteTime(" + formatTimepoint(uint64(*p.AbsBefore)) + ")"
+		}
+	case xdr.ClaimPredicateTypeClaimPredicateBeforeRelativeTime:
+		if p.RelBefore != nil {
+			return "BeforeRelativeTime(" + formatDuration(uint64(*p.RelBefore)) + ")"
+		}
+	}
+	return p.Type.String()
+}
+
+// flagNames names the bits set in flags, unknown bits by their value
+func flagNames(flags uint32, names []string) []string {
+	out := []string{}
+	for bit := 0; bit < 32; bit++ {
+		mask := uint32(1) << bit
+		if flags&mask == 0 {
+			continue
+		}
+		if bit < len(names) {
+			out = append(out, names[bit])
+		} else {
+			out = append(out, "0x"+strconv.FormatUint(uint64(mask), 16))
+		}
+	}
+	return out
+}
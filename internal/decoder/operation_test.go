@@ -0,0 +1,302 @@
+// Copyright (c) 2026 dotandev
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package decoder
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stellar/go/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	opTestAccount  = "GAAQEAYEAUDAOCAJBIFQYDIOB4IBCEQTCQKRMFYYDENBWHA5DYPSABOV"
+	opTestContract = "CAAQEAYEAUDAOCAJBIFQYDIOB4IBCEQTCQKRMFYYDENBWHA5DYPSBFLM"
+)
+
+func testScAddress(t *testing.T, address string) xdr.ScAddress {
+	t.Helper()
+	a, err := ParseScAddress(address)
+	require.NoError(t, err)
+	return a
+}
+
+// sorobanEnvelope builds a transfer call authorized by its sender, with the
+// footprint and resources of the call
+func sorobanEnvelope(t *testing.T) string {
+	t.Helper()
+	contract := testScAddress(t, opTestContract)
+	account := testScAddress(t, opTestAccount)
+	amount := xdr.Int128Parts{Lo: 100}
+	invoke := xdr.InvokeContractArgs{
+		ContractAddress: contract,
+		FunctionName:    "transfer",
+		Args: []xdr.ScVal{
+			{Type: xdr.ScValTypeScvAddress, Address: &account},
+			{Type: xdr.ScValTypeScvAddress, Address: &contract},
+			{Type: xdr.ScValTypeScvI128, I128: &amount},
+		},
+	}
+	sig := xdr.ScVal{Type: xdr.ScValTypeScvVoid}
+	balance := xdr.ScSymbol("Balance")
+	balanceVec := &xdr.ScVec{{Type: xdr.ScValTypeScvSymbol, Sym: &balance}, {Type: xdr.ScValTypeScvAddress, Address: &account}}
+
+	tx := xdr.Transaction{
+		SourceAccount: xdr.MustMuxedAddress(opTestAccount),
+		Fee:           100000,
+		SeqNum:        42,
+		Memo:          xdr.MemoText("invoice 7"),
+		Cond:          xdr.Preconditions{Type: xdr.PreconditionTypePrecondTime, TimeBounds: &xdr.TimeBounds{MaxTime: 1700000000}},
+		Operations: []xdr.Operation{{
+			Body: xdr.OperationBody{
+				Type: xdr.OperationTypeInvokeHostFunction,
+				InvokeHostFunctionOp: &xdr.InvokeHostFunctionOp{
+					HostFunction: xdr.HostFunction{Type: xdr.HostFunctionTypeHostFunctionTypeInvokeContract, InvokeContract: &invoke},
+					Auth: []xdr.SorobanAuthorizationEntry{{
+						Credentials: xdr.SorobanCredentials{
+							Type: xdr.SorobanCredentialsTypeSorobanCredentialsAddress,
+							Address: &xdr.SorobanAddressCredentials{
+								Address:                   account,
+								Nonce:                     7,
+								SignatureExpirationLedger: 1000,
+								Signature:                 sig,
+							},
+						},
+						RootInvocation: xdr.SorobanAuthorizedInvocation{
+							Function: xdr.SorobanAuthorizedFunction{
+								Type:       xdr.SorobanAuthorizedFunctionTypeSorobanAuthorizedFunctionTypeContractFn,
+								ContractFn: &invoke,
+							},
+						},
+					}},
+				},
+			},
+		}},
+		Ext: xdr.TransactionExt{V: 1, SorobanData: &xdr.SorobanTransactionData{
+			Resources: xdr.SorobanResources{
+				Footprint: xdr.LedgerFootprint{
+					ReadOnly: []xdr.LedgerKey{{
+						Type: xdr.LedgerEntryTypeContractData,
+						ContractData: &xdr.LedgerKeyContractData{
+							Contract:   contract,
+							Key:        xdr.ScVal{Type: xdr.ScValTypeScvLedgerKeyContractInstance},
+							Durability: xdr.ContractDataDurabilityPersistent,
+						},
+					}},
+					ReadWrite: []xdr.LedgerKey{{
+						Type: xdr.LedgerEntryTypeContractData,
+						ContractData: &xdr.LedgerKeyContractData{
+							Contract:   contract,
+							Key:        xdr.ScVal{Type: xdr.ScValTypeScvVec, Vec: &balanceVec},
+							Durability: xdr.ContractDataDurabilityPersistent,
+						},
+					}},
+				},
+				Instructions:  2500000,
+				DiskReadBytes: 1024,
+				WriteBytes:    256,
+			},
+			ResourceFee: 90000,
+		}},
+	}
+	env := xdr.TransactionEnvelope{Type: xdr.EnvelopeTypeEnvelopeTypeTx, V1: &xdr.TransactionV1Envelope{Tx: tx}}
+	b64, err := xdr.MarshalBase64(env)
+	require.NoError(t, err)
+	return b64
+}
+
+func TestAnalyzeEnvelope_Soroban(t *testing.T) {
+	d, err := AnalyzeEnvelope(sorobanEnvelope(t))
+	require.NoError(t, err)
+
+	assert.Equal(t, "TransactionV1", d.Type)
+	assert.Equal(t, int64(42), d.Sequence)
+	assert.Equal(t, `Text("invoice 7")`, d.Memo)
+	assert.Equal(t, Fields{{"TimeBounds", "unbounded to 2023-11-14T22:13:20Z"}}, d.Preconditions)
+
+	require.Len(t, d.Operations, 1)
+	op := d.Operations[0]
+	assert.Equal(t, "InvokeHostFunction", op.Type)
+	call := Fields{
+		{"Contract", opTestContract},
+		{"Function", "transfer"},
+		{"Args", []string{opTestAccount, opTestContract, "100"}},
+	}
+	assert.Equal(t, append(Fields{{"HostFunction", "InvokeContract"}}, call...), op.Details[:4])
+	assert.Equal(t, Field{"Auth", []Fields{{
+		{"Credentials", "Address"},
+		{"Address", opTestAccount},
+		{"Nonce", int64(7)},
+		{"SignatureExpirationLedger", uint32(1000)},
+		{"Signature", "(void)"},
+		{"Invocation", call},
+	}}}, op.Details[4])
+
+	require.NotNil(t, d.Soroban)
+	assert.Equal(t, []string{"ContractData(" + opTestContract + ", LedgerKeyContractInstance, Persistent)"}, d.Soroban.ReadOnly)
+	assert.Equal(t, []string{"ContractData(" + opTestContract + ", [Balance, " + opTestAccount + "], Persistent)"}, d.Soroban.ReadWrite)
+	assert.Equal(t, uint32(2500000), d.Soroban.Instructions)
+	assert.Equal(t, int64(90000), d.Soroban.ResourceFee)
+}
+
+func TestAnalyzeEnvelope_Formats(t *testing.T) {
+	d, err := AnalyzeEnvelope(sorobanEnvelope(t))
+	require.NoError(t, err)
+
+	var text strings.Builder
+	require.NoError(t, WriteEnvelope(&text, d))
+	for _, line := range []string{
+		"  [0] InvokeHostFunction\n",
+		"      Host Function: InvokeContract\n",
+		"      Args:\n        - " + opTestAccount + "\n",
+		"      Auth:\n        [0]\n          Credentials: Address\n",
+		"          Signature Expiration Ledger: 1000\n",
+		"          Invocation:\n            Contract: " + opTestContract + "\n",
+		"Soroban Resources:\n  Read Only:\n    - ContractData(",
+		"  Disk Read Bytes: 1024\n",
+	} {
+		assert.Contains(t, text.String(), line)
+	}
+
+	data, err := json.Marshal(d)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"details":{"host_function":"InvokeContract","contract":"`+opTestContract+`","function":"transfer"`)
+	assert.Contains(t, string(data), `"signature_expiration_ledger":1000`)
+	assert.Contains(t, string(data), `"soroban":{"read_only":[`)
+
+	out, err := yaml.Marshal(d)
+	require.NoError(t, err)
+	var back map[string]interface{}
+	require.NoError(t, yaml.Unmarshal(out, &back))
+	ops := back["operations"].([]interface{})
+	details := ops[0].(map[string]interface{})["details"].(map[string]interface{})
+	assert.Equal(t, "transfer", details["function"])
+	auth := details["auth"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, 7, auth["nonce"])
+	assert.Contains(t, string(out), "details:\n        host_function: InvokeContract\n        contract: ")
+}
+
+func TestDecodeOperation_Classic(t *testing.T) {
+	account := xdr.MustAddress(opTestAccount)
+	usdc := xdr.MustNewCreditAsset("USDC", opTestAccount)
+	expiry := xdr.Int64(1700000000)
+	relative := xdr.Int64(3600)
+	notRelative := &xdr.ClaimPredicate{Type: xdr.ClaimPredicateTypeClaimPredicateBeforeRelativeTime, RelBefore: &relative}
+	master := xdr.Uint32(0)
+	setFlags := xdr.Uint32(xdr.AccountFlagsAuthRequiredFlag | xdr.AccountFlagsAuthRevocableFlag)
+	value := xdr.DataValue("hello")
+
+	tests := []struct {
+		name string
+		body xdr.OperationBody
+		want Fields
+	}{
+		{
+			name: "payment",
+			body: xdr.OperationBody{Type: xdr.OperationTypePayment, PaymentOp: &xdr.PaymentOp{
+				Destination: account.ToMuxedAccount(), Asset: usdc, Amount: 15000000,
+			}},
+			want: Fields{{"Destination", opTestAccount}, {"Asset", "USDC:" + opTestAccount}, {"Amount", "1.5000000"}},
+		},
+		{
+			name: "manage sell offer",
+			body: xdr.OperationBody{Type: xdr.OperationTypeManageSellOffer, ManageSellOfferOp: &xdr.ManageSellOfferOp{
+				Selling: xdr.MustNewNativeAsset(), Buying: usdc, Amount: 10000000, Price: xdr.Price{N: 1, D: 4}, OfferId: 9,
+			}},
+			want: Fields{{"Selling", "native"}, {"Buying", "USDC:" + opTestAccount}, {"Amount", "1.0000000"}, {"Price", "0.2500000"}, {"OfferID", int64(9)}},
+		},
+		{
+			name: "set options",
+			body: xdr.OperationBody{Type: xdr.OperationTypeSetOptions, SetOptionsOp: &xdr.SetOptionsOp{
+				SetFlags: &setFlags, MasterWeight: &master,
+			}},
+			want: Fields{{"SetFlags", []string{"AuthRequired", "AuthRevocable"}}, {"MasterWeight", uint32(0)}},
+		},
+		{
+			name: "manage data",
+			body: xdr.OperationBody{Type: xdr.OperationTypeManageData, ManageDataOp: &xdr.ManageDataOp{DataName: "greeting", DataValue: &value}},
+			want: Fields{{"Name", "greeting"}, {"Value", "hello"}},
+		},
+		{
+			name: "create claimable balance",
+			body: xdr.OperationBody{Type: xdr.OperationTypeCreateClaimableBalance, CreateClaimableBalanceOp: &xdr.CreateClaimableBalanceOp{
+				Asset:  usdc,
+				Amount: 10000000,
+				Claimants: []xdr.Claimant{{Type: xdr.ClaimantTypeClaimantTypeV0, V0: &xdr.ClaimantV0{
+					Destination: account,
+					Predicate: xdr.ClaimPredicate{Type: xdr.ClaimPredicateTypeClaimPredicateAnd, AndPredicates: &[]xdr.ClaimPredicate{
+						{Type: xdr.ClaimPredicateTypeClaimPredicateBeforeAbsoluteTime, AbsBefore: &expiry},
+						{Type: xdr.ClaimPredicateTypeClaimPredicateNot, NotPredicate: &notRelative},
+					}},
+				}}},
+			}},
+			want: Fields{{"Asset", "USDC:" + opTestAccount}, {"Amount", "1.0000000"}, {"Claimants", []Fields{{
+				{"Destination", opTestAccount},
+				{"Predicate", "And(BeforeAbsoluteTime(2023-11-14T22:13:20Z), Not(BeforeRelativeTime(1h0m0s)))"},
+			}}}},
+		},
+		{
+			name: "extend footprint ttl",
+			body: xdr.OperationBody{Type: xdr.OperationTypeExtendFootprintTtl, ExtendFootprintTtlOp: &xdr.ExtendFootprintTtlOp{ExtendTo: 500}},
+			want: Fields{{"ExtendTo", uint32(500)}},
+		},
+		{
+			name: "inflation",
+			body: xdr.OperationBody{Type: xdr.OperationTypeInflation},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			op := DecodeOperation(xdr.Operation{Body: tt.body})
+			assert.Equal(t, tt.want, op.Details)
+		})
+	}
+}
+
+func TestFieldWords(t *testing.T) {
+	assert.Equal(t, "starting_balance", fieldKey("StartingBalance"))
+	assert.Equal(t, "offer_id", fieldKey("OfferID"))
+	assert.Equal(t, "max_amount_a", fieldKey("MaxAmountA"))
+	assert.Equal(t, "Liquidity Pool ID", fieldLabel("LiquidityPoolID"))
+	assert.Equal(t, "Args", fieldLabel("Args"))
+}
+
+func TestFormatLedgerEntry(t *testing.T) {
+	sym := xdr.ScSymbol("owner")
+	tests := []struct {
+		data xdr.LedgerEntryData
+		want string
+	}{
+		{xdr.LedgerEntryData{Type: xdr.LedgerEntryTypeAccount, Account: &xdr.AccountEntry{
+			AccountId: xdr.MustAddress(opTestAccount), Balance: 15000000, SeqNum: 42,
+		}}, "balance 1.5000000, seq 42"},
+		{xdr.LedgerEntryData{Type: xdr.LedgerEntryTypeContractData, ContractData: &xdr.ContractDataEntry{
+			Val: xdr.ScVal{Type: xdr.ScValTypeScvSymbol, Sym: &sym},
+		}}, "owner"},
+		{xdr.LedgerEntryData{Type: xdr.LedgerEntryTypeContractCode, ContractCode: &xdr.ContractCodeEntry{
+			Code: make([]byte, 1024),
+		}}, "1024 bytes of wasm"},
+		{xdr.LedgerEntryData{Type: xdr.LedgerEntryTypeTtl, Ttl: &xdr.TtlEntry{LiveUntilLedgerSeq: 900}}, "live until ledger 900"},
+		{xdr.LedgerEntryData{Type: xdr.LedgerEntryTypeOffer, Offer: &xdr.OfferEntry{}}, "Offer"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, FormatLedgerEntry(tt.data))
+	}
+}
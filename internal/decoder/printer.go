@@ -16,58 +16,105 @@ package decoder
 
 import (
 	"fmt"
-
-	"github.com/stellar/go/xdr"
+	"io"
+	"os"
+	"strings"
 )
 
 func PrintEnvelope(d *DecodedEnvelope) {
-	fmt.Println("Transaction Type:", d.Type)
-	fmt.Println("Source Account:", maskAccount(d.Source))
-	fmt.Println("Fee:", d.Fee)
+	_ = WriteEnvelope(os.Stdout, d)
+}
+
+// WriteEnvelope renders a decoded envelope as indented text
+func WriteEnvelope(w io.Writer, d *DecodedEnvelope) error {
+	var sb strings.Builder
+	writeEnvelope(&sb, d)
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+func writeEnvelope(sb *strings.Builder, d *DecodedEnvelope) {
+	fmt.Fprintln(sb, "Transaction Type:", d.Type)
+	fmt.Fprintln(sb, "Source Account:", d.Source)
+	fmt.Fprintln(sb, "Fee:", d.Fee)
+	if d.Sequence != 0 {
+		fmt.Fprintln(sb, "Sequence:", d.Sequence)
+	}
+	if d.Memo != "" {
+		fmt.Fprintln(sb, "Memo:", d.Memo)
+	}
+	if len(d.Preconditions) > 0 {
+		fmt.Fprintln(sb, "Preconditions:")
+		writeFields(sb, d.Preconditions, "  ")
+	}
 
 	if len(d.Operations) > 0 {
-		fmt.Println("Operations:")
+		fmt.Fprintln(sb, "Operations:")
 		for i, op := range d.Operations {
-			printOperation(i, op)
+			printOperation(sb, i, op)
+		}
+	}
+
+	if s := d.Soroban; s != nil {
+		fmt.Fprintln(sb, "Soroban Resources:")
+		writeFields(sb, Fields{
+			{"ReadOnly", s.ReadOnly},
+			{"ReadWrite", s.ReadWrite},
+			{"Instructions", s.Instructions},
+			{"DiskReadBytes", s.DiskReadBytes},
+			{"WriteBytes", s.WriteBytes},
+			{"ResourceFee", s.ResourceFee},
+		}, "  ")
+		if len(s.ArchivedEntries) > 0 {
+			fmt.Fprintf(sb, "  Archived Entries: %v\n", s.ArchivedEntries)
 		}
 	}
+	fmt.Fprintln(sb, "Signatures:", d.Signatures)
 
 	if d.InnerTx != nil {
-		fmt.Println("
---- Inner Transaction ---")
-		PrintEnvelope(d.InnerTx)
+		fmt.Fprintln(sb, "\n--- Inner Transaction ---")
+		writeEnvelope(sb, d.InnerTx)
 	}
 }
 
-func printOperation(i int, op xdr.Operation) {
-	fmt.Printf("  [%d] %s
-", i, op.Body.Type)
-
-	switch op.Body.Type {
-
-	case xdr.OperationTypeInvokeHostFunction:
-		fmt.Println("      Soroban: Invoke Host Function")
-
-	case xdr.OperationTypeExtendFootprintTtl:
-		fmt.Println("      Soroban: Extend Footprint TTL")
-
-	case xdr.OperationTypeRestoreFootprint:
-		fmt.Println("      Soroban: Restore Footprint")
-
-	case xdr.OperationTypePayment:
-		p := op.Body.PaymentOp
-		fmt.Println("      Payment")
-		fmt.Println("      To:", maskAccount(p.Destination.Address()))
-		fmt.Println("      Amount:", p.Amount)
-
-	default:
-		fmt.Println("      (details omitted)")
+func printOperation(sb *strings.Builder, i int, op Operation) {
+	fmt.Fprintf(sb, "  [%d] %s\n", i, op.Type)
+	if op.Source != "" {
+		fmt.Fprintln(sb, "      Source:", op.Source)
 	}
+	writeFields(sb, op.Details, "      ")
 }
 
-func maskAccount(addr string) string {
-	if len(addr) < 8 {
-		return addr
+// writeFields renders fields one per line, nesting lists and objects under
+// their label
+func writeFields(sb *strings.Builder, fields Fields, indent string) {
+	for _, field := range fields {
+		label := fieldLabel(field.Name)
+		switch v := field.Value.(type) {
+		case []string:
+			if len(v) == 0 {
+				fmt.Fprintf(sb, "%s%s: none\n", indent, label)
+				continue
+			}
+			fmt.Fprintf(sb, "%s%s:\n", indent, label)
+			for _, item := range v {
+				fmt.Fprintf(sb, "%s  - %s\n", indent, item)
+			}
+		case Fields:
+			fmt.Fprintf(sb, "%s%s:\n", indent, label)
+			writeFields(sb, v, indent+"  ")
+		case []Fields:
+			if len(v) == 0 {
+				fmt.Fprintf(sb, "%s%s: none\n", indent, label)
+				continue
+			}
+			fmt.Fprintf(sb, "%s%s:\n", indent, label)
+			for j, item := range v {
+				fmt.Fprintf(sb, "%s  [%d]\n", indent, j)
+				writeFields(sb, item, indent+"    ")
+			}
+		default:
+			fmt.Fprintf(sb, "%s%s: %v\n", indent, label, v)
+		}
 	}
-	return addr[:4] + "…" + addr[len(addr)-4:]
 }
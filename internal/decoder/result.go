@@ -0,0 +1,233 @@
+// Copyright (c) 2026 dotandev
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package decoder
+
+import (
+	"encoding/hex"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"github.com/stellar/go/xdr"
+)
+
+// DecodedResult is a decoded TransactionResult
+type DecodedResult struct {
+	TransactionResultCodeInfo
+	FeeCharged int64
+	// InnerHash and Inner are the hash and result of the transaction wrapped
+	// by a fee bump
+	InnerHash  string
+	Inner      *DecodedResult
+	Operations []OperationResult
+}
+
+// OperationResult is the result of one operation of a transaction
+type OperationResult struct {
+	OperationResultCodeInfo
+	// Index is the position of the operation in the envelope
+	Index int
+	// Type is the operation type, empty when the operation did not run
+	Type   string
+	Failed bool
+}
+
+// AnalyzeResult decodes a base64-encoded TransactionResult XDR
+func AnalyzeResult(b64 string) (*DecodedResult, error) {
+	var result xdr.TransactionResult
+	if err := xdr.SafeUnmarshalBase64(b64, &result); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal transaction result: %w", err)
+	}
+	return DecodeResult(result), nil
+}
+
+// DecodeResult decodes a transaction result with the result of each of its
+// operations, and the inner transaction of a fee bump
+func DecodeResult(result xdr.TransactionResult) *DecodedResult {
+	d := &DecodedResult{
+		TransactionResultCodeInfo: DecodeTransactionResultCode(result.Result.Code),
+		FeeCharged:                int64(result.FeeCharged),
+		Operations:                decodeOperationResults(result.Result.Results),
+	}
+	if pair, ok := result.Result.GetInnerResultPair(); ok {
+		d.InnerHash = hex.EncodeToString(pair.TransactionHash[:])
+		d.Inner = &DecodedResult{
+			TransactionResultCodeInfo: DecodeTransactionResultCode(pair.Result.Result.Code),
+			FeeCharged:                int64(pair.Result.FeeCharged),
+			Operations:                decodeOperationResults(pair.Result.Result.Results),
+		}
+	}
+	return d
+}
+
+// FailedOperation returns the first operation that failed, in the inner
+// transaction of a fee bump
+func (d *DecodedResult) FailedOperation() (OperationResult, bool) {
+	if d.Inner != nil {
+		return d.Inner.FailedOperation()
+	}
+	for _, op := range d.Operations {
+		if op.Failed {
+			return op, true
+		}
+	}
+	return OperationResult{}, false
+}
+
+func decodeOperationResults(results *[]xdr.OperationResult) []OperationResult {
+	if results == nil {
+		return nil
+	}
+	decoded := make([]OperationResult, len(*results))
+	for i, r := range *results {
+		decoded[i] = decodeOperationResult(i, r)
+	}
+	return decoded
+}
+
+func decodeOperationResult(index int, r xdr.OperationResult) OperationResult {
+	tr, ok := r.GetTr()
+	if r.Code != xdr.OperationResultCodeOpInner || !ok {
+		return OperationResult{
+			OperationResultCodeInfo: DecodeOperationResultCode(r.Code),
+			Index:                   index,
+			Failed:                  true,
+		}
+	}
+
+	op := OperationResult{Index: index, Type: OperationTypeName(tr.Type)}
+	switch tr.Type {
+	case xdr.OperationTypeCreateAccount:
+		r := tr.MustCreateAccountResult()
+		op.OperationResultCodeInfo = DecodeCreateAccountResultCode(r.Code)
+		op.Failed = r.Code != xdr.CreateAccountResultCodeCreateAccountSuccess
+	case xdr.OperationTypePayment:
+		r := tr.MustPaymentResult()
+		op.OperationResultCodeInfo = DecodePaymentResultCode(r.Code)
+		op.Failed = r.Code != xdr.PaymentResultCodePaymentSuccess
+	case xdr.OperationTypeInvokeHostFunction:
+		r := tr.MustInvokeHostFunctionResult()
+		op.OperationResultCodeInfo = DecodeInvokeHostFunctionResultCode(r.Code)
+		op.Failed = r.Code != xdr.InvokeHostFunctionResultCodeInvokeHostFunctionSuccess
+	case xdr.OperationTypeExtendFootprintTtl:
+		r := tr.MustExtendFootprintTtlResult()
+		op.OperationResultCodeInfo = DecodeExtendFootprintTtlResultCode(r.Code)
+		op.Failed = r.Code != xdr.ExtendFootprintTtlResultCodeExtendFootprintTtlSuccess
+	case xdr.OperationTypeRestoreFootprint:
+		r := tr.MustRestoreFootprintResult()
+		op.OperationResultCodeInfo = DecodeRestoreFootprintResultCode(r.Code)
+		op.Failed = r.Code != xdr.RestoreFootprintResultCodeRestoreFootprintSuccess
+	default:
+		op.OperationResultCodeInfo, op.Failed = decodeInnerResultCode(tr)
+	}
+	if op.Failed {
+		op.Remediation = resultRemediation(op.Code)
+	}
+	return op
+}
+
+// decodeInnerResultCode names the result code of the operations without a
+// table of explanations. Every result of OperationResultTr holds a Code
+// enum whose zero value is success, so it is read generically.
+func decodeInnerResultCode(tr xdr.OperationResultTr) (OperationResultCodeInfo, bool) {
+	opType := OperationTypeName(tr.Type)
+	arm, ok := tr.ArmForSwitch(int32(tr.Type))
+	if !ok || arm == "" {
+		return OperationResultCodeInfo{Code: "op_inner", Description: opType, Explanation: "No result recorded"}, false
+	}
+	result := reflect.ValueOf(tr).FieldByName(arm)
+	if result.Kind() != reflect.Ptr || result.IsNil() {
+		return OperationResultCodeInfo{Code: "op_inner", Description: opType, Explanation: "No result recorded"}, false
+	}
+	code := result.Elem().FieldByName("Code")
+
+	// ChangeTrustResultCodeChangeTrustNoIssuer is change_trust_no_issuer
+	name := fmt.Sprint(code.Interface())
+	if i := strings.Index(name, "ResultCode"); i >= 0 {
+		name = name[i+len("ResultCode"):]
+	}
+	info := OperationResultCodeInfo{
+		Code:        fieldKey(name),
+		Description: fieldLabel(strings.TrimPrefix(name, opType)),
+	}
+	if code.Int() == 0 {
+		info.Explanation = "Operation completed successfully"
+		return info, false
+	}
+	info.Explanation = fmt.Sprintf("%s failed with %s", opType, info.Code)
+	return info, true
+}
+
+// WriteResult renders a decoded result as text. With the envelope of the
+// transaction, the operation that failed is shown with its parameters.
+func WriteResult(w io.Writer, d *DecodedResult, env *DecodedEnvelope) error {
+	var sb strings.Builder
+	writeResult(&sb, d, env)
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+func writeResult(sb *strings.Builder, d *DecodedResult, env *DecodedEnvelope) {
+	fmt.Fprintf(sb, "Transaction Result: %s\n", d.Description)
+	fmt.Fprintf(sb, "Code: %s\n", d.Code)
+	fmt.Fprintf(sb, "Explanation: %s\n", d.Explanation)
+	if d.FeeCharged != 0 {
+		fmt.Fprintf(sb, "Fee Charged: %d\n", d.FeeCharged)
+	}
+
+	if d.Inner != nil {
+		var inner *DecodedEnvelope
+		if env != nil {
+			inner = env.InnerTx
+		}
+		fmt.Fprintf(sb, "\n--- Inner Transaction %s ---\n", d.InnerHash)
+		writeResult(sb, d.Inner, inner)
+		return
+	}
+
+	// Operations only need explaining when the transaction failed
+	if len(d.Operations) == 0 || d.Code == "tx_success" {
+		return
+	}
+	fmt.Fprintf(sb, "\nOperation Results:\n")
+	for _, op := range d.Operations {
+		name := op.Type
+		if name == "" && env != nil && op.Index < len(env.Operations) {
+			name = env.Operations[op.Index].Type
+		}
+		if name != "" {
+			name = " " + name
+		}
+		fmt.Fprintf(sb, "  Operation %d%s: %s (%s)\n", op.Index, name, op.Description, op.Code)
+		if op.Failed {
+			fmt.Fprintf(sb, "    %s\n", op.Explanation)
+		}
+		if op.Remediation != "" {
+			fmt.Fprintf(sb, "    Fix: %s\n", op.Remediation)
+		}
+	}
+
+	failed, ok := d.FailedOperation()
+	if !ok || env == nil || failed.Index >= len(env.Operations) {
+		return
+	}
+	op := env.Operations[failed.Index]
+	fmt.Fprintf(sb, "\nFailed Operation [%d] %s:\n", failed.Index, op.Type)
+	if op.Source != "" {
+		fmt.Fprintln(sb, "  Source:", op.Source)
+	}
+	writeFields(sb, op.Details, "  ")
+}
@@ -16,6 +16,7 @@ package decoder
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/stellar/go/xdr"
 )
@@ -32,6 +33,9 @@ type OperationResultCodeInfo struct {
 	Code        string
 	Description string
 	Explanation string
+	// Remediation is the steps that usually fix a failure, empty when there
+	// are none to suggest
+	Remediation string
 }
 
 // DecodeTransactionResultCode converts a TransactionResultCode to human-readable format
@@ -328,34 +332,129 @@ func DecodePaymentResultCode(code xdr.PaymentResultCode) OperationResultCodeInfo
 	}
 }
 
-// FormatTransactionResult formats a complete transaction result with human-readable errors
-func FormatTransactionResult(result xdr.TransactionResult) string {
-	txCodeInfo := DecodeTransactionResultCode(result.Result.Code)
+// DecodeInvokeHostFunctionResultCode decodes InvokeHostFunction operation specific codes
+func DecodeInvokeHostFunctionResultCode(code xdr.InvokeHostFunctionResultCode) OperationResultCodeInfo {
+	switch code {
+	case xdr.InvokeHostFunctionResultCodeInvokeHostFunctionSuccess:
+		return OperationResultCodeInfo{
+			Code:        "invoke_host_function_success",
+			Description: "Invocation Successful",
+			Explanation: "The host function ran to completion",
+		}
+	case xdr.InvokeHostFunctionResultCodeInvokeHostFunctionMalformed:
+		return OperationResultCodeInfo{
+			Code:        "invoke_host_function_malformed",
+			Description: "Malformed Invocation",
+			Explanation: "The host function or its footprint is invalid, e.g. a key missing from the footprint or an oversized contract",
+		}
+	case xdr.InvokeHostFunctionResultCodeInvokeHostFunctionTrapped:
+		return OperationResultCodeInfo{
+			Code:        "invoke_host_function_trapped",
+			Description: "Contract Trapped",
+			Explanation: "The contract panicked or returned an error. The diagnostic events and the replay trace show where",
+		}
+	case xdr.InvokeHostFunctionResultCodeInvokeHostFunctionResourceLimitExceeded:
+		return OperationResultCodeInfo{
+			Code:        "invoke_host_function_resource_limit_exceeded",
+			Description: "Resource Limit Exceeded",
+			Explanation: "The invocation used more instructions, memory or I/O than the transaction declared. Simulate again and raise the resources",
+		}
+	case xdr.InvokeHostFunctionResultCodeInvokeHostFunctionEntryArchived:
+		return OperationResultCodeInfo{
+			Code:        "invoke_host_function_entry_archived",
+			Description: "Entry Archived",
+			Explanation: "A ledger entry in the footprint has expired and been archived. Restore it with RestoreFootprint before invoking",
+		}
+	case xdr.InvokeHostFunctionResultCodeInvokeHostFunctionInsufficientRefundableFee:
+		return OperationResultCodeInfo{
+			Code:        "invoke_host_function_insufficient_refundable_fee",
+			Description: "Insufficient Refundable Fee",
+			Explanation: "The resource fee does not cover the rent and events of the invocation. Raise the resource fee",
+		}
+	default:
+		return OperationResultCodeInfo{
+			Code:        fmt.Sprintf("invoke_host_function_unknown_%d", code),
+			Description: "Unknown Error",
+			Explanation: fmt.Sprintf("Unrecognized invoke host function result code: %d", code),
+		}
+	}
+}
 
-	output := fmt.Sprintf("Transaction Result: %s
-", txCodeInfo.Description)
-	output += fmt.Sprintf("Code: %s
-", txCodeInfo.Code)
-	output += fmt.Sprintf("Explanation: %s
-", txCodeInfo.Explanation)
+// DecodeExtendFootprintTtlResultCode decodes ExtendFootprintTtl operation specific codes
+func DecodeExtendFootprintTtlResultCode(code xdr.ExtendFootprintTtlResultCode) OperationResultCodeInfo {
+	switch code {
+	case xdr.ExtendFootprintTtlResultCodeExtendFootprintTtlSuccess:
+		return OperationResultCodeInfo{
+			Code:        "extend_footprint_ttl_success",
+			Description: "TTL Extended",
+			Explanation: "The read-only footprint entries live until the requested ledger",
+		}
+	case xdr.ExtendFootprintTtlResultCodeExtendFootprintTtlMalformed:
+		return OperationResultCodeInfo{
+			Code:        "extend_footprint_ttl_malformed",
+			Description: "Malformed Request",
+			Explanation: "The footprint has read-write entries or entries that cannot be extended, or the TTL is beyond the maximum",
+		}
+	case xdr.ExtendFootprintTtlResultCodeExtendFootprintTtlResourceLimitExceeded:
+		return OperationResultCodeInfo{
+			Code:        "extend_footprint_ttl_resource_limit_exceeded",
+			Description: "Resource Limit Exceeded",
+			Explanation: "Reading the footprint entries took more bytes than the transaction declared",
+		}
+	case xdr.ExtendFootprintTtlResultCodeExtendFootprintTtlInsufficientRefundableFee:
+		return OperationResultCodeInfo{
+			Code:        "extend_footprint_ttl_insufficient_refundable_fee",
+			Description: "Insufficient Refundable Fee",
+			Explanation: "The resource fee does not cover the rent for the extension",
+		}
+	default:
+		return OperationResultCodeInfo{
+			Code:        fmt.Sprintf("extend_footprint_ttl_unknown_%d", code),
+			Description: "Unknown Error",
+			Explanation: fmt.Sprintf("Unrecognized extend footprint TTL result code: %d", code),
+		}
+	}
+}
 
-	// If transaction failed, show operation results
-	if result.Result.Code == xdr.TransactionResultCodeTxFailed {
-		if results := result.Result.Results; results != nil && len(*results) > 0 {
-			output += "
-Operation Results:
-"
-			for i, opResult := range *results {
-				opCodeInfo := DecodeOperationResultCode(opResult.Code)
-				output += fmt.Sprintf("  Operation %d: %s (%s)
-", i, opCodeInfo.Description, opCodeInfo.Code)
-				if opCodeInfo.Code != "op_inner" {
-					output += fmt.Sprintf("    %s
-", opCodeInfo.Explanation)
-				}
-			}
+// DecodeRestoreFootprintResultCode decodes RestoreFootprint operation specific codes
+func DecodeRestoreFootprintResultCode(code xdr.RestoreFootprintResultCode) OperationResultCodeInfo {
+	switch code {
+	case xdr.RestoreFootprintResultCodeRestoreFootprintSuccess:
+		return OperationResultCodeInfo{
+			Code:        "restore_footprint_success",
+			Description: "Footprint Restored",
+			Explanation: "The archived read-write footprint entries are live again",
+		}
+	case xdr.RestoreFootprintResultCodeRestoreFootprintMalformed:
+		return OperationResultCodeInfo{
+			Code:        "restore_footprint_malformed",
+			Description: "Malformed Request",
+			Explanation: "The footprint has read-only entries or entries that cannot be restored",
+		}
+	case xdr.RestoreFootprintResultCodeRestoreFootprintResourceLimitExceeded:
+		return OperationResultCodeInfo{
+			Code:        "restore_footprint_resource_limit_exceeded",
+			Description: "Resource Limit Exceeded",
+			Explanation: "Restoring the entries took more bytes than the transaction declared",
+		}
+	case xdr.RestoreFootprintResultCodeRestoreFootprintInsufficientRefundableFee:
+		return OperationResultCodeInfo{
+			Code:        "restore_footprint_insufficient_refundable_fee",
+			Description: "Insufficient Refundable Fee",
+			Explanation: "The resource fee does not cover the rent for the restored entries",
+		}
+	default:
+		return OperationResultCodeInfo{
+			Code:        fmt.Sprintf("restore_footprint_unknown_%d", code),
+			Description: "Unknown Error",
+			Explanation: fmt.Sprintf("Unrecognized restore footprint result code: %d", code),
 		}
 	}
+}
 
-	return output
+// FormatTransactionResult formats a complete transaction result with human-readable errors
+func FormatTransactionResult(result xdr.TransactionResult) string {
+	var sb strings.Builder
+	writeResult(&sb, DecodeResult(result), nil)
+	return sb.String()
 }
@@ -0,0 +1,174 @@
+// Copyright (c) 2026 dotandev
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package decoder
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dotandev/hintents/internal/localization"
+	"github.com/stellar/go/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func invokeResult(code xdr.InvokeHostFunctionResultCode) xdr.OperationResult {
+	return xdr.OperationResult{
+		Code: xdr.OperationResultCodeOpInner,
+		Tr: &xdr.OperationResultTr{
+			Type:                     xdr.OperationTypeInvokeHostFunction,
+			InvokeHostFunctionResult: &xdr.InvokeHostFunctionResult{Code: code},
+		},
+	}
+}
+
+func TestAnalyzeResult_InvokeHostFunction(t *testing.T) {
+	tests := []struct {
+		name     string
+		code     xdr.InvokeHostFunctionResultCode
+		wantCode string
+	}{
+		{"resource limit", xdr.InvokeHostFunctionResultCodeInvokeHostFunctionResourceLimitExceeded, "invoke_host_function_resource_limit_exceeded"},
+		{"entry archived", xdr.InvokeHostFunctionResultCodeInvokeHostFunctionEntryArchived, "invoke_host_function_entry_archived"},
+		{"trapped", xdr.InvokeHostFunctionResultCodeInvokeHostFunctionTrapped, "invoke_host_function_trapped"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results := []xdr.OperationResult{invokeResult(tt.code)}
+			b64, err := xdr.MarshalBase64(xdr.TransactionResult{
+				FeeCharged: 12345,
+				Result:     xdr.TransactionResultResult{Code: xdr.TransactionResultCodeTxFailed, Results: &results},
+			})
+			require.NoError(t, err)
+
+			d, err := AnalyzeResult(b64)
+			require.NoError(t, err)
+			assert.Equal(t, "tx_failed", d.Code)
+			assert.Equal(t, int64(12345), d.FeeCharged)
+
+			failed, ok := d.FailedOperation()
+			require.True(t, ok)
+			assert.Equal(t, 0, failed.Index)
+			assert.Equal(t, "InvokeHostFunction", failed.Type)
+			assert.Equal(t, tt.wantCode, failed.Code)
+			assert.NotEmpty(t, failed.Explanation)
+		})
+	}
+}
+
+func TestWriteResult_PointsAtEnvelope(t *testing.T) {
+	env, err := AnalyzeEnvelope(sorobanEnvelope(t))
+	require.NoError(t, err)
+
+	results := []xdr.OperationResult{invokeResult(xdr.InvokeHostFunctionResultCodeInvokeHostFunctionEntryArchived)}
+	d := DecodeResult(xdr.TransactionResult{
+		Result: xdr.TransactionResultResult{Code: xdr.TransactionResultCodeTxFailed, Results: &results},
+	})
+
+	var sb strings.Builder
+	require.NoError(t, WriteResult(&sb, d, env))
+	out := sb.String()
+	assert.Contains(t, out, "Code: tx_failed")
+	assert.Contains(t, out, "Operation 0 InvokeHostFunction:")
+	assert.Contains(t, out, "(invoke_host_function_entry_archived)")
+	assert.Contains(t, out, "Failed Operation [0] InvokeHostFunction:")
+	assert.Contains(t, out, "Function: transfer")
+}
+
+func TestWriteResult_Remediation(t *testing.T) {
+	require.NoError(t, localization.LoadTranslations())
+
+	results := []xdr.OperationResult{invokeResult(xdr.InvokeHostFunctionResultCodeInvokeHostFunctionEntryArchived)}
+	d := DecodeResult(xdr.TransactionResult{
+		Result: xdr.TransactionResultResult{Code: xdr.TransactionResultCodeTxFailed, Results: &results},
+	})
+
+	failed, ok := d.FailedOperation()
+	require.True(t, ok)
+	assert.Contains(t, failed.Remediation, "RestoreFootprint")
+
+	var sb strings.Builder
+	require.NoError(t, WriteResult(&sb, d, nil))
+	assert.Contains(t, sb.String(), "    Fix: Run RestoreFootprint")
+}
+
+func TestDecodeResult_FeeBump(t *testing.T) {
+	results := []xdr.OperationResult{
+		{
+			Code: xdr.OperationResultCodeOpInner,
+			Tr: &xdr.OperationResultTr{
+				Type:          xdr.OperationTypePayment,
+				PaymentResult: &xdr.PaymentResult{Code: xdr.PaymentResultCodePaymentSuccess},
+			},
+		},
+		{
+			Code: xdr.OperationResultCodeOpInner,
+			Tr: &xdr.OperationResultTr{
+				Type:              xdr.OperationTypeChangeTrust,
+				ChangeTrustResult: &xdr.ChangeTrustResult{Code: xdr.ChangeTrustResultCodeChangeTrustNoIssuer},
+			},
+		},
+	}
+	d := DecodeResult(xdr.TransactionResult{
+		FeeCharged: 200,
+		Result: xdr.TransactionResultResult{
+			Code: xdr.TransactionResultCodeTxFeeBumpInnerFailed,
+			InnerResultPair: &xdr.InnerTransactionResultPair{
+				TransactionHash: xdr.Hash{0xab},
+				Result: xdr.InnerTransactionResult{
+					FeeCharged: 100,
+					Result:     xdr.InnerTransactionResultResult{Code: xdr.TransactionResultCodeTxFailed, Results: &results},
+				},
+			},
+		},
+	})
+
+	assert.Equal(t, "tx_fee_bump_inner_failed", d.Code)
+	require.NotNil(t, d.Inner)
+	assert.True(t, strings.HasPrefix(d.InnerHash, "ab00"))
+	assert.Equal(t, "tx_failed", d.Inner.Code)
+
+	require.Len(t, d.Inner.Operations, 2)
+	assert.False(t, d.Inner.Operations[0].Failed)
+
+	failed, ok := d.FailedOperation()
+	require.True(t, ok)
+	assert.Equal(t, 1, failed.Index)
+	assert.Equal(t, "ChangeTrust", failed.Type)
+	assert.Equal(t, "change_trust_no_issuer", failed.Code)
+	assert.Equal(t, "No Issuer", failed.Description)
+
+	out := FormatTransactionResult(xdr.TransactionResult{
+		Result: xdr.TransactionResultResult{
+			Code:            xdr.TransactionResultCodeTxFeeBumpInnerFailed,
+			InnerResultPair: &xdr.InnerTransactionResultPair{Result: xdr.InnerTransactionResult{Result: xdr.InnerTransactionResultResult{Code: xdr.TransactionResultCodeTxFailed, Results: &results}}},
+		},
+	})
+	assert.Contains(t, out, "--- Inner Transaction")
+	assert.Contains(t, out, "Operation 1 ChangeTrust: No Issuer (change_trust_no_issuer)")
+}
+
+func TestDecodeResult_OperationNotRun(t *testing.T) {
+	results := []xdr.OperationResult{{Code: xdr.OperationResultCodeOpBadAuth}}
+	d := DecodeResult(xdr.TransactionResult{
+		Result: xdr.TransactionResultResult{Code: xdr.TransactionResultCodeTxFailed, Results: &results},
+	})
+
+	failed, ok := d.FailedOperation()
+	require.True(t, ok)
+	assert.Equal(t, "op_bad_auth", failed.Code)
+	assert.Empty(t, failed.Type)
+}
@@ -0,0 +1,153 @@
+// Copyright (c) 2026 dotandev
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package decoder
+
+import (
+	"regexp"
+	"strconv"
+
+	"github.com/dotandev/hintents/internal/localization"
+	"github.com/stellar/go/xdr"
+)
+
+// ScErrorInfo explains an ScError with the steps that usually fix it, in the
+// language of the localization package
+type ScErrorInfo struct {
+	Error       string `json:"error"`
+	Explanation string `json:"explanation"`
+	Remediation string `json:"remediation"`
+}
+
+// scErrorPattern matches an error as the host renders it, "Error(Storage,
+// MissingValue)" or "Error(Contract, #3)"
+var scErrorPattern = regexp.MustCompile(`Error\((\w+),\s*(#?\w+)\)`)
+
+// ExplainScError explains an error from the catalog. Every combination of
+// type and code is covered: the common ones have their own messages, the
+// others combine the messages of their type and code.
+func ExplainScError(e xdr.ScError) ScErrorInfo {
+	info := ScErrorInfo{Error: FormatScError(e)}
+	typeKey := fieldKey(ScErrorTypeName(e.Type))
+
+	if e.Type == xdr.ScErrorTypeSceContract {
+		info.Explanation = localization.Get("scerror.contract")
+		if e.ContractCode != nil {
+			info.Explanation = localization.Translate("scerror.contract.code", uint32(*e.ContractCode))
+		}
+		info.Remediation = localization.Get("scerror.fix.contract")
+		return info
+	}
+
+	info.Explanation = localization.Get("scerror.type." + typeKey)
+	info.Remediation = localization.Get("scerror.fix." + typeKey)
+	if e.Code == nil {
+		return info
+	}
+
+	codeKey := fieldKey(ScErrorCodeName(*e.Code))
+	if msg, ok := localization.Lookup("scerror." + typeKey + "." + codeKey); ok {
+		info.Explanation = msg
+	} else {
+		info.Explanation += " " + localization.Get("scerror.code."+codeKey)
+	}
+	if fix, ok := localization.Lookup("scerror." + typeKey + "." + codeKey + ".fix"); ok {
+		info.Remediation = fix
+	}
+	return info
+}
+
+// ScErrorCatalog explains every combination of error type and code, with
+// one entry for contract errors
+func ScErrorCatalog() []ScErrorInfo {
+	var catalog []ScErrorInfo
+	for i := int32(0); xdr.ScErrorType(0).ValidEnum(i); i++ {
+		t := xdr.ScErrorType(i)
+		if t == xdr.ScErrorTypeSceContract {
+			catalog = append(catalog, ExplainScError(xdr.ScError{Type: t}))
+			continue
+		}
+		for j := int32(0); xdr.ScErrorCode(0).ValidEnum(j); j++ {
+			code := xdr.ScErrorCode(j)
+			catalog = append(catalog, ExplainScError(xdr.ScError{Type: t, Code: &code}))
+		}
+	}
+	return catalog
+}
+
+// ParseScError finds the first error rendered by the host in a message, such
+// as a simulator error "HostError: Error(WasmVm, InvalidAction)"
+func ParseScError(msg string) (xdr.ScError, bool) {
+	m := scErrorPattern.FindStringSubmatch(msg)
+	if m == nil {
+		return xdr.ScError{}, false
+	}
+	t, ok := scErrorTypeByName(m[1])
+	if !ok {
+		return xdr.ScError{}, false
+	}
+	scErr := xdr.ScError{Type: t}
+
+	if t == xdr.ScErrorTypeSceContract {
+		n, err := strconv.ParseUint(m[2][1:], 10, 32)
+		if m[2][0] != '#' || err != nil {
+			return xdr.ScError{}, false
+		}
+		code := xdr.Uint32(n)
+		scErr.ContractCode = &code
+		return scErr, true
+	}
+
+	code, ok := scErrorCodeByName(m[2])
+	if !ok {
+		return xdr.ScError{}, false
+	}
+	scErr.Code = &code
+	return scErr, true
+}
+
+// ExplainErrorMessage explains the first error rendered by the host in a
+// message
+func ExplainErrorMessage(msg string) (ScErrorInfo, bool) {
+	scErr, ok := ParseScError(msg)
+	if !ok {
+		return ScErrorInfo{}, false
+	}
+	return ExplainScError(scErr), true
+}
+
+// resultRemediation returns the steps that usually fix an operation result
+// code, empty when there are none
+func resultRemediation(code string) string {
+	fix, _ := localization.Lookup("result." + code + ".fix")
+	return fix
+}
+
+func scErrorTypeByName(name string) (xdr.ScErrorType, bool) {
+	for i := int32(0); xdr.ScErrorType(0).ValidEnum(i); i++ {
+		if t := xdr.ScErrorType(i); ScErrorTypeName(t) == name {
+			return t, true
+		}
+	}
+	return 0, false
+}
+
+func scErrorCodeByName(name string) (xdr.ScErrorCode, bool) {
+	for i := int32(0); xdr.ScErrorCode(0).ValidEnum(i); i++ {
+		if code := xdr.ScErrorCode(i); ScErrorCodeName(code) == name {
+			return code, true
+		}
+	}
+	return 0, false
+}
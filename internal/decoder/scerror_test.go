@@ -0,0 +1,98 @@
+// Copyright (c) 2026 dotandev
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package decoder
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dotandev/hintents/internal/localization"
+	"github.com/stellar/go/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScErrorCatalog_Complete(t *testing.T) {
+	require.NoError(t, localization.LoadTranslations())
+	defer localization.SetLanguage(localization.English)
+
+	for _, lang := range []localization.Language{localization.English, localization.Spanish, localization.Chinese} {
+		require.NoError(t, localization.SetLanguage(lang))
+		catalog := ScErrorCatalog()
+		// 9 host error types with 10 codes each, and contract errors
+		assert.Len(t, catalog, 91)
+		for _, info := range catalog {
+			assert.NotEmpty(t, info.Explanation, info.Error)
+			assert.NotContains(t, info.Explanation, "scerror.", "%s in %s", info.Error, lang)
+			assert.NotContains(t, info.Remediation, "scerror.", "%s in %s", info.Error, lang)
+		}
+	}
+}
+
+func TestExplainScError(t *testing.T) {
+	require.NoError(t, localization.LoadTranslations())
+
+	code := xdr.ScErrorCodeScecMissingValue
+	info := ExplainScError(xdr.ScError{Type: xdr.ScErrorTypeSceStorage, Code: &code})
+	assert.Equal(t, "Error(Storage, MissingValue)", info.Error)
+	assert.Contains(t, info.Remediation, "RestoreFootprint")
+
+	// Combinations without messages of their own combine type and code
+	code = xdr.ScErrorCodeScecUnexpectedSize
+	info = ExplainScError(xdr.ScError{Type: xdr.ScErrorTypeSceEvents, Code: &code})
+	assert.Equal(t, "Raised while emitting a contract event. A value had an unexpected size.", info.Explanation)
+	assert.Equal(t, localization.Get("scerror.fix.events"), info.Remediation)
+
+	contractCode := xdr.Uint32(7)
+	info = ExplainScError(xdr.ScError{Type: xdr.ScErrorTypeSceContract, ContractCode: &contractCode})
+	assert.Equal(t, "The contract returned its own error #7.", info.Explanation)
+}
+
+func TestExplainScError_Localized(t *testing.T) {
+	require.NoError(t, localization.LoadTranslations())
+	require.NoError(t, localization.SetLanguage(localization.Spanish))
+	defer localization.SetLanguage(localization.English)
+
+	code := xdr.ScErrorCodeScecInvalidAction
+	info := ExplainScError(xdr.ScError{Type: xdr.ScErrorTypeSceAuth, Code: &code})
+	assert.Equal(t, "Error(Auth, InvalidAction)", info.Error)
+	assert.True(t, strings.HasPrefix(info.Explanation, "require_auth falló"))
+}
+
+func TestParseScError(t *testing.T) {
+	tests := []struct {
+		msg  string
+		want string
+		ok   bool
+	}{
+		{"HostError: Error(WasmVm, InvalidAction)", "Error(WasmVm, InvalidAction)", true},
+		{"Host Trap: HostError: Error(Context, InvalidInput)\n\nEvent log: ...", "Error(Context, InvalidInput)", true},
+		{"Error(Contract, #12): insufficient balance", "Error(Contract, #12)", true},
+		{"Error(Storage,ExceededLimit)", "Error(Storage, ExceededLimit)", true},
+		{"Error(Contract, Twelve)", "", false},
+		{"Error(Bogus, MissingValue)", "", false},
+		{"VM Trap: Unreachable Instruction", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.msg, func(t *testing.T) {
+			scErr, ok := ParseScError(tt.msg)
+			require.Equal(t, tt.ok, ok)
+			if ok {
+				assert.Equal(t, tt.want, FormatScError(scErr))
+			}
+		})
+	}
+}
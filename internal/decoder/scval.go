@@ -0,0 +1,272 @@
+// Copyright (c) 2026 dotandev
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package decoder
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/stellar/go/xdr"
+)
+
+// FormatScVal renders a contract value on one line, the way Soroban SDKs
+// print them:
+//
+//	true  (void)  42  -17  340282366920938463463374607431768211455
+//	2024-03-01T12:00:00Z  1h30m0s  0xdeadbeef  "a string"  a_symbol
+//	[1, 2, 3]  {balance: 100, owner: GABC...}  Error(Contract, #3)
+//	ContractInstance(wasm 5a1f..., {admin: GABC...})  Nonce(7)
+//
+// Strings are quoted so they read apart from symbols. The zero ScVal, as left
+// by callers that never set a value, renders as void.
+func FormatScVal(v xdr.ScVal) string {
+	var sb strings.Builder
+	writeScVal(&sb, v)
+	return sb.String()
+}
+
+func writeScVal(sb *strings.Builder, v xdr.ScVal) {
+	switch v.Type {
+	case xdr.ScValTypeScvBool:
+		if v.B == nil {
+			sb.WriteString("(void)")
+			return
+		}
+		sb.WriteString(strconv.FormatBool(bool(*v.B)))
+		return
+	case xdr.ScValTypeScvVoid:
+		sb.WriteString("(void)")
+		return
+	case xdr.ScValTypeScvError:
+		if v.Error == nil {
+			break
+		}
+		sb.WriteString(FormatScError(*v.Error))
+		return
+	case xdr.ScValTypeScvTimepoint:
+		if v.Timepoint == nil {
+			break
+		}
+		sb.WriteString(formatTimepoint(uint64(*v.Timepoint)))
+		return
+	case xdr.ScValTypeScvDuration:
+		if v.Duration == nil {
+			break
+		}
+		sb.WriteString(formatDuration(uint64(*v.Duration)))
+		return
+	case xdr.ScValTypeScvBytes:
+		if v.Bytes == nil {
+			break
+		}
+		sb.WriteString("0x" + hex.EncodeToString(*v.Bytes))
+		return
+	case xdr.ScValTypeScvString:
+		if v.Str == nil {
+			break
+		}
+		sb.WriteString(strconv.Quote(string(*v.Str)))
+		return
+	case xdr.ScValTypeScvSymbol:
+		if v.Sym == nil {
+			break
+		}
+		sb.WriteString(string(*v.Sym))
+		return
+	case xdr.ScValTypeScvVec:
+		if v.Vec == nil {
+			break
+		}
+		if *v.Vec == nil {
+			sb.WriteString("null")
+			return
+		}
+		sb.WriteString("[")
+		for i, item := range **v.Vec {
+			if i > 0 {
+				sb.WriteString(", ")
+			}
+			writeScVal(sb, item)
+		}
+		sb.WriteString("]")
+		return
+	case xdr.ScValTypeScvMap:
+		if v.Map == nil {
+			break
+		}
+		writeScMap(sb, *v.Map)
+		return
+	case xdr.ScValTypeScvAddress:
+		if v.Address == nil {
+			break
+		}
+		address, err := v.Address.String()
+		if err != nil {
+			break
+		}
+		sb.WriteString(address)
+		return
+	case xdr.ScValTypeScvContractInstance:
+		if v.Instance == nil {
+			break
+		}
+		sb.WriteString("ContractInstance(")
+		switch v.Instance.Executable.Type {
+		case xdr.ContractExecutableTypeContractExecutableWasm:
+			if hash := v.Instance.Executable.WasmHash; hash != nil {
+				sb.WriteString("wasm " + hex.EncodeToString(hash[:]))
+			}
+		case xdr.ContractExecutableTypeContractExecutableStellarAsset:
+			sb.WriteString("stellar asset")
+		}
+		if v.Instance.Storage != nil {
+			sb.WriteString(", ")
+			writeScMap(sb, v.Instance.Storage)
+		}
+		sb.WriteString(")")
+		return
+	case xdr.ScValTypeScvLedgerKeyContractInstance:
+		sb.WriteString("LedgerKeyContractInstance")
+		return
+	case xdr.ScValTypeScvLedgerKeyNonce:
+		if v.NonceKey == nil {
+			break
+		}
+		fmt.Fprintf(sb, "Nonce(%d)", int64(v.NonceKey.Nonce))
+		return
+	default:
+		if n, ok := ScValInt(v); ok {
+			sb.WriteString(n.String())
+			return
+		}
+	}
+	fmt.Fprintf(sb, "(invalid %s)", scValTypeName(v.Type))
+}
+
+func writeScMap(sb *strings.Builder, m *xdr.ScMap) {
+	if m == nil {
+		sb.WriteString("null")
+		return
+	}
+	sb.WriteString("{")
+	for i, entry := range *m {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		writeScVal(sb, entry.Key)
+		sb.WriteString(": ")
+		writeScVal(sb, entry.Val)
+	}
+	sb.WriteString("}")
+}
+
+// FormatScError renders an error as the host does, "Error(Storage,
+// MissingValue)", or "Error(Contract, #3)" for contract errors
+func FormatScError(e xdr.ScError) string {
+	if e.Type == xdr.ScErrorTypeSceContract {
+		if e.ContractCode == nil {
+			return "Error(Contract)"
+		}
+		return fmt.Sprintf("Error(Contract, #%d)", uint32(*e.ContractCode))
+	}
+	if e.Code == nil {
+		return fmt.Sprintf("Error(%s)", ScErrorTypeName(e.Type))
+	}
+	return fmt.Sprintf("Error(%s, %s)", ScErrorTypeName(e.Type), ScErrorCodeName(*e.Code))
+}
+
+// ScErrorTypeName returns the short name of an error type, "WasmVm" for
+// SCE_WASM_VM
+func ScErrorTypeName(t xdr.ScErrorType) string {
+	return strings.TrimPrefix(t.String(), "ScErrorTypeSce")
+}
+
+// ScErrorCodeName returns the short name of an error code, "MissingValue" for
+// SCEC_MISSING_VALUE
+func ScErrorCodeName(c xdr.ScErrorCode) string {
+	return strings.TrimPrefix(c.String(), "ScErrorCodeScec")
+}
+
+// ScValInt returns the value of any integer ScVal, from u32 to i256
+func ScValInt(v xdr.ScVal) (*big.Int, bool) {
+	switch v.Type {
+	case xdr.ScValTypeScvU32:
+		if v.U32 != nil {
+			return new(big.Int).SetUint64(uint64(*v.U32)), true
+		}
+	case xdr.ScValTypeScvI32:
+		if v.I32 != nil {
+			return big.NewInt(int64(*v.I32)), true
+		}
+	case xdr.ScValTypeScvU64:
+		if v.U64 != nil {
+			return new(big.Int).SetUint64(uint64(*v.U64)), true
+		}
+	case xdr.ScValTypeScvI64:
+		if v.I64 != nil {
+			return big.NewInt(int64(*v.I64)), true
+		}
+	case xdr.ScValTypeScvU128:
+		if p := v.U128; p != nil {
+			return joinParts(new(big.Int).SetUint64(uint64(p.Hi)), uint64(p.Lo)), true
+		}
+	case xdr.ScValTypeScvI128:
+		if p := v.I128; p != nil {
+			return joinParts(big.NewInt(int64(p.Hi)), uint64(p.Lo)), true
+		}
+	case xdr.ScValTypeScvU256:
+		if p := v.U256; p != nil {
+			return joinParts(new(big.Int).SetUint64(uint64(p.HiHi)), uint64(p.HiLo), uint64(p.LoHi), uint64(p.LoLo)), true
+		}
+	case xdr.ScValTypeScvI256:
+		if p := v.I256; p != nil {
+			return joinParts(big.NewInt(int64(p.HiHi)), uint64(p.HiLo), uint64(p.LoHi), uint64(p.LoLo)), true
+		}
+	}
+	return nil, false
+}
+
+// joinParts shifts in the lower 64-bit parts of a 128 or 256-bit integer
+// after its high part, which carries the sign of signed integers
+func joinParts(hi *big.Int, parts ...uint64) *big.Int {
+	part := new(big.Int)
+	for _, p := range parts {
+		hi.Lsh(hi, 64)
+		hi.Or(hi, part.SetUint64(p))
+	}
+	return hi
+}
+
+// maxTimepoint is the last second of year 9999, beyond which RFC 3339 cannot
+// render a time
+const maxTimepoint = 253402300799
+
+func formatTimepoint(seconds uint64) string {
+	if seconds > maxTimepoint {
+		return strconv.FormatUint(seconds, 10)
+	}
+	return time.Unix(int64(seconds), 0).UTC().Format(time.RFC3339)
+}
+
+func formatDuration(seconds uint64) string {
+	if seconds > uint64(time.Duration(1<<63-1)/time.Second) {
+		return strconv.FormatUint(seconds, 10) + "s"
+	}
+	return (time.Duration(seconds) * time.Second).String()
+}
@@ -0,0 +1,627 @@
+// Copyright (c) 2026 dotandev
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package decoder
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/stellar/go/strkey"
+	"github.com/stellar/go/xdr"
+)
+
+// ScValJSON encodes an ScVal in a lossless JSON form, one {"type", "value"}
+// object per value:
+//
+//	{"type":"u32","value":42}
+//	{"type":"i128","value":"-170141183460469231731687303715884105728"}
+//	{"type":"bytes","value":"deadbeef"}
+//	{"type":"vec","value":[{"type":"symbol","value":"a"}]}
+//	{"type":"map","value":[{"key":{"type":"symbol","value":"owner"},"val":{"type":"address","value":"GABC..."}}]}
+//	{"type":"error","value":{"type":"Storage","code":"MissingValue"}}
+//
+// Integers of 64 bits and wider are strings, so they survive parsers that read
+// numbers as doubles. A vec or map without a value, unlike an empty one, has
+// no "value", and strings that are not valid UTF-8 are hex encoded under
+// "hex" instead. Decoding the JSON gives back the same ScVal.
+type ScValJSON struct {
+	xdr.ScVal
+}
+
+type scValWire struct {
+	Type  string          `json:"type"`
+	Value json.RawMessage `json:"value,omitempty"`
+	Hex   string          `json:"hex,omitempty"`
+}
+
+type scMapEntryWire struct {
+	Key ScValJSON `json:"key"`
+	Val ScValJSON `json:"val"`
+}
+
+type scErrorWire struct {
+	Type string          `json:"type"`
+	Code json.RawMessage `json:"code,omitempty"`
+}
+
+type scInstanceWire struct {
+	Executable string            `json:"executable"`
+	WasmHash   string            `json:"wasm_hash,omitempty"`
+	Storage    *[]scMapEntryWire `json:"storage,omitempty"`
+}
+
+var scValTypeNames = map[xdr.ScValType]string{
+	xdr.ScValTypeScvBool:                      "bool",
+	xdr.ScValTypeScvVoid:                      "void",
+	xdr.ScValTypeScvError:                     "error",
+	xdr.ScValTypeScvU32:                       "u32",
+	xdr.ScValTypeScvI32:                       "i32",
+	xdr.ScValTypeScvU64:                       "u64",
+	xdr.ScValTypeScvI64:                       "i64",
+	xdr.ScValTypeScvTimepoint:                 "timepoint",
+	xdr.ScValTypeScvDuration:                  "duration",
+	xdr.ScValTypeScvU128:                      "u128",
+	xdr.ScValTypeScvI128:                      "i128",
+	xdr.ScValTypeScvU256:                      "u256",
+	xdr.ScValTypeScvI256:                      "i256",
+	xdr.ScValTypeScvBytes:                     "bytes",
+	xdr.ScValTypeScvString:                    "string",
+	xdr.ScValTypeScvSymbol:                    "symbol",
+	xdr.ScValTypeScvVec:                       "vec",
+	xdr.ScValTypeScvMap:                       "map",
+	xdr.ScValTypeScvAddress:                   "address",
+	xdr.ScValTypeScvContractInstance:          "contract_instance",
+	xdr.ScValTypeScvLedgerKeyContractInstance: "ledger_key_contract_instance",
+	xdr.ScValTypeScvLedgerKeyNonce:            "ledger_key_nonce",
+}
+
+var scValTypesByName = func() map[string]xdr.ScValType {
+	types := make(map[string]xdr.ScValType, len(scValTypeNames))
+	for t, name := range scValTypeNames {
+		types[name] = t
+	}
+	return types
+}()
+
+func scValTypeName(t xdr.ScValType) string {
+	if name, ok := scValTypeNames[t]; ok {
+		return name
+	}
+	return t.String()
+}
+
+// MarshalJSON implements json.Marshaler
+func (v ScValJSON) MarshalJSON() ([]byte, error) {
+	w, err := encodeScVal(v.ScVal)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(w)
+}
+
+func encodeScVal(v xdr.ScVal) (scValWire, error) {
+	w := scValWire{Type: scValTypeName(v.Type)}
+	invalid := fmt.Errorf("invalid %s ScVal", w.Type)
+
+	var value interface{}
+	switch v.Type {
+	case xdr.ScValTypeScvBool:
+		if v.B == nil {
+			return w, invalid
+		}
+		value = bool(*v.B)
+	case xdr.ScValTypeScvVoid, xdr.ScValTypeScvLedgerKeyContractInstance:
+		return w, nil
+	case xdr.ScValTypeScvError:
+		if v.Error == nil {
+			return w, invalid
+		}
+		e := scErrorWire{Type: ScErrorTypeName(v.Error.Type)}
+		switch {
+		case v.Error.Type == xdr.ScErrorTypeSceContract && v.Error.ContractCode != nil:
+			e.Code = json.RawMessage(strconv.FormatUint(uint64(*v.Error.ContractCode), 10))
+		case v.Error.Type != xdr.ScErrorTypeSceContract && v.Error.Code != nil:
+			e.Code = json.RawMessage(strconv.Quote(ScErrorCodeName(*v.Error.Code)))
+		default:
+			return w, invalid
+		}
+		value = e
+	case xdr.ScValTypeScvU32:
+		if v.U32 == nil {
+			return w, invalid
+		}
+		value = uint32(*v.U32)
+	case xdr.ScValTypeScvI32:
+		if v.I32 == nil {
+			return w, invalid
+		}
+		value = int32(*v.I32)
+	case xdr.ScValTypeScvTimepoint:
+		if v.Timepoint == nil {
+			return w, invalid
+		}
+		value = strconv.FormatUint(uint64(*v.Timepoint), 10)
+	case xdr.ScValTypeScvDuration:
+		if v.Duration == nil {
+			return w, invalid
+		}
+		value = strconv.FormatUint(uint64(*v.Duration), 10)
+	case xdr.ScValTypeScvU64, xdr.ScValTypeScvI64, xdr.ScValTypeScvU128, xdr.ScValTypeScvI128, xdr.ScValTypeScvU256, xdr.ScValTypeScvI256:
+		n, ok := ScValInt(v)
+		if !ok {
+			return w, invalid
+		}
+		value = n.String()
+	case xdr.ScValTypeScvBytes:
+		if v.Bytes == nil {
+			return w, invalid
+		}
+		value = hex.EncodeToString(*v.Bytes)
+	case xdr.ScValTypeScvString:
+		if v.Str == nil {
+			return w, invalid
+		}
+		if !utf8.ValidString(string(*v.Str)) {
+			w.Hex = hex.EncodeToString([]byte(*v.Str))
+			return w, nil
+		}
+		value = string(*v.Str)
+	case xdr.ScValTypeScvSymbol:
+		if v.Sym == nil {
+			return w, invalid
+		}
+		value = string(*v.Sym)
+	case xdr.ScValTypeScvVec:
+		if v.Vec == nil {
+			return w, invalid
+		}
+		if *v.Vec == nil {
+			return w, nil
+		}
+		items := make([]ScValJSON, len(**v.Vec))
+		for i, item := range **v.Vec {
+			items[i] = ScValJSON{item}
+		}
+		value = items
+	case xdr.ScValTypeScvMap:
+		if v.Map == nil {
+			return w, invalid
+		}
+		if *v.Map == nil {
+			return w, nil
+		}
+		value = mapEntries(**v.Map)
+	case xdr.ScValTypeScvAddress:
+		if v.Address == nil {
+			return w, invalid
+		}
+		address, err := v.Address.String()
+		if err != nil {
+			return w, fmt.Errorf("invalid address ScVal: %w", err)
+		}
+		value = address
+	case xdr.ScValTypeScvContractInstance:
+		if v.Instance == nil {
+			return w, invalid
+		}
+		instance := scInstanceWire{}
+		switch v.Instance.Executable.Type {
+		case xdr.ContractExecutableTypeContractExecutableWasm:
+			if v.Instance.Executable.WasmHash == nil {
+				return w, invalid
+			}
+			instance.Executable = "wasm"
+			instance.WasmHash = hex.EncodeToString(v.Instance.Executable.WasmHash[:])
+		case xdr.ContractExecutableTypeContractExecutableStellarAsset:
+			instance.Executable = "stellar_asset"
+		default:
+			return w, invalid
+		}
+		if v.Instance.Storage != nil {
+			entries := mapEntries(*v.Instance.Storage)
+			instance.Storage = &entries
+		}
+		value = instance
+	case xdr.ScValTypeScvLedgerKeyNonce:
+		if v.NonceKey == nil {
+			return w, invalid
+		}
+		value = strconv.FormatInt(int64(v.NonceKey.Nonce), 10)
+	default:
+		return w, fmt.Errorf("unknown ScVal type %d", v.Type)
+	}
+
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return w, err
+	}
+	w.Value = raw
+	return w, nil
+}
+
+func mapEntries(m xdr.ScMap) []scMapEntryWire {
+	entries := make([]scMapEntryWire, len(m))
+	for i, entry := range m {
+		entries[i] = scMapEntryWire{Key: ScValJSON{entry.Key}, Val: ScValJSON{entry.Val}}
+	}
+	return entries
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (v *ScValJSON) UnmarshalJSON(data []byte) error {
+	var w scValWire
+	if err := json.Unmarshal(data, &w); err != nil {
+		return err
+	}
+	t, ok := scValTypesByName[w.Type]
+	if !ok {
+		return fmt.Errorf("unknown ScVal type %q", w.Type)
+	}
+
+	value, err := decodeScVal(t, w)
+	if err != nil {
+		return fmt.Errorf("invalid %s ScVal: %w", w.Type, err)
+	}
+	v.ScVal = value
+	return nil
+}
+
+func decodeScVal(t xdr.ScValType, w scValWire) (xdr.ScVal, error) {
+	v := xdr.ScVal{Type: t}
+	switch t {
+	case xdr.ScValTypeScvVoid, xdr.ScValTypeScvLedgerKeyContractInstance:
+		return v, nil
+	case xdr.ScValTypeScvString:
+		if w.Hex != "" {
+			raw, err := hex.DecodeString(w.Hex)
+			if err != nil {
+				return v, err
+			}
+			s := xdr.ScString(raw)
+			v.Str = &s
+			return v, nil
+		}
+	case xdr.ScValTypeScvVec:
+		if w.Value == nil {
+			var none *xdr.ScVec
+			v.Vec = &none
+			return v, nil
+		}
+	case xdr.ScValTypeScvMap:
+		if w.Value == nil {
+			var none *xdr.ScMap
+			v.Map = &none
+			return v, nil
+		}
+	}
+	if w.Value == nil {
+		return v, fmt.Errorf("missing value")
+	}
+
+	switch t {
+	case xdr.ScValTypeScvBool:
+		var b bool
+		if err := json.Unmarshal(w.Value, &b); err != nil {
+			return v, err
+		}
+		v.B = &b
+	case xdr.ScValTypeScvError:
+		var e scErrorWire
+		if err := json.Unmarshal(w.Value, &e); err != nil {
+			return v, err
+		}
+		scErr, err := decodeScError(e)
+		if err != nil {
+			return v, err
+		}
+		v.Error = &scErr
+	case xdr.ScValTypeScvU32:
+		var n uint32
+		if err := json.Unmarshal(w.Value, &n); err != nil {
+			return v, err
+		}
+		u := xdr.Uint32(n)
+		v.U32 = &u
+	case xdr.ScValTypeScvI32:
+		var n int32
+		if err := json.Unmarshal(w.Value, &n); err != nil {
+			return v, err
+		}
+		i := xdr.Int32(n)
+		v.I32 = &i
+	case xdr.ScValTypeScvU64, xdr.ScValTypeScvI64, xdr.ScValTypeScvTimepoint, xdr.ScValTypeScvDuration,
+		xdr.ScValTypeScvU128, xdr.ScValTypeScvI128, xdr.ScValTypeScvU256, xdr.ScValTypeScvI256:
+		n, err := decodeBigInt(w.Value)
+		if err != nil {
+			return v, err
+		}
+		return intScVal(t, n)
+	case xdr.ScValTypeScvBytes:
+		var s string
+		if err := json.Unmarshal(w.Value, &s); err != nil {
+			return v, err
+		}
+		raw, err := hex.DecodeString(s)
+		if err != nil {
+			return v, err
+		}
+		b := xdr.ScBytes(raw)
+		v.Bytes = &b
+	case xdr.ScValTypeScvString:
+		var s string
+		if err := json.Unmarshal(w.Value, &s); err != nil {
+			return v, err
+		}
+		str := xdr.ScString(s)
+		v.Str = &str
+	case xdr.ScValTypeScvSymbol:
+		var s string
+		if err := json.Unmarshal(w.Value, &s); err != nil {
+			return v, err
+		}
+		sym := xdr.ScSymbol(s)
+		v.Sym = &sym
+	case xdr.ScValTypeScvVec:
+		var items []ScValJSON
+		if err := json.Unmarshal(w.Value, &items); err != nil {
+			return v, err
+		}
+		vec := make(xdr.ScVec, len(items))
+		for i, item := range items {
+			vec[i] = item.ScVal
+		}
+		some := &vec
+		v.Vec = &some
+	case xdr.ScValTypeScvMap:
+		var entries []scMapEntryWire
+		if err := json.Unmarshal(w.Value, &entries); err != nil {
+			return v, err
+		}
+		m := scMap(entries)
+		some := &m
+		v.Map = &some
+	case xdr.ScValTypeScvAddress:
+		var s string
+		if err := json.Unmarshal(w.Value, &s); err != nil {
+			return v, err
+		}
+		address, err := ParseScAddress(s)
+		if err != nil {
+			return v, err
+		}
+		v.Address = &address
+	case xdr.ScValTypeScvContractInstance:
+		var instance scInstanceWire
+		if err := json.Unmarshal(w.Value, &instance); err != nil {
+			return v, err
+		}
+		decoded := xdr.ScContractInstance{}
+		switch instance.Executable {
+		case "wasm":
+			raw, err := hex.DecodeString(instance.WasmHash)
+			if err != nil || len(raw) != 32 {
+				return v, fmt.Errorf("invalid wasm hash %q", instance.WasmHash)
+			}
+			var hash xdr.Hash
+			copy(hash[:], raw)
+			decoded.Executable = xdr.ContractExecutable{Type: xdr.ContractExecutableTypeContractExecutableWasm, WasmHash: &hash}
+		case "stellar_asset":
+			decoded.Executable = xdr.ContractExecutable{Type: xdr.ContractExecutableTypeContractExecutableStellarAsset}
+		default:
+			return v, fmt.Errorf("unknown executable %q", instance.Executable)
+		}
+		if instance.Storage != nil {
+			m := scMap(*instance.Storage)
+			decoded.Storage = &m
+		}
+		v.Instance = &decoded
+	case xdr.ScValTypeScvLedgerKeyNonce:
+		var s string
+		if err := json.Unmarshal(w.Value, &s); err != nil {
+			return v, err
+		}
+		nonce, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return v, err
+		}
+		v.NonceKey = &xdr.ScNonceKey{Nonce: xdr.Int64(nonce)}
+	}
+	return v, nil
+}
+
+func scMap(entries []scMapEntryWire) xdr.ScMap {
+	m := make(xdr.ScMap, len(entries))
+	for i, entry := range entries {
+		m[i] = xdr.ScMapEntry{Key: entry.Key.ScVal, Val: entry.Val.ScVal}
+	}
+	return m
+}
+
+func decodeScError(e scErrorWire) (xdr.ScError, error) {
+	t, ok := scErrorTypeByName(e.Type)
+	if !ok {
+		return xdr.ScError{}, fmt.Errorf("unknown error type %q", e.Type)
+	}
+	scErr := xdr.ScError{Type: t}
+
+	if scErr.Type == xdr.ScErrorTypeSceContract {
+		var code uint32
+		if err := json.Unmarshal(e.Code, &code); err != nil {
+			return scErr, fmt.Errorf("invalid contract error code: %w", err)
+		}
+		c := xdr.Uint32(code)
+		scErr.ContractCode = &c
+		return scErr, nil
+	}
+
+	var name string
+	if err := json.Unmarshal(e.Code, &name); err != nil {
+		return scErr, fmt.Errorf("invalid error code: %w", err)
+	}
+	code, ok := scErrorCodeByName(name)
+	if !ok {
+		return scErr, fmt.Errorf("unknown error code %q", name)
+	}
+	scErr.Code = &code
+	return scErr, nil
+}
+
+func decodeBigInt(raw json.RawMessage) (*big.Int, error) {
+	var s string
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return nil, err
+	}
+	n, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid integer %q", s)
+	}
+	return n, nil
+}
+
+// intScVal builds an integer ScVal of type t, failing when n is out of its
+// range
+func intScVal(t xdr.ScValType, n *big.Int) (xdr.ScVal, error) {
+	v := xdr.ScVal{Type: t}
+	switch t {
+	case xdr.ScValTypeScvU64, xdr.ScValTypeScvTimepoint, xdr.ScValTypeScvDuration:
+		parts, err := splitParts(n, 1, false)
+		if err != nil {
+			return v, err
+		}
+		switch t {
+		case xdr.ScValTypeScvU64:
+			u := xdr.Uint64(parts[0])
+			v.U64 = &u
+		case xdr.ScValTypeScvTimepoint:
+			tp := xdr.TimePoint(parts[0])
+			v.Timepoint = &tp
+		default:
+			d := xdr.Duration(parts[0])
+			v.Duration = &d
+		}
+	case xdr.ScValTypeScvI64:
+		parts, err := splitParts(n, 1, true)
+		if err != nil {
+			return v, err
+		}
+		i := xdr.Int64(parts[0])
+		v.I64 = &i
+	case xdr.ScValTypeScvU128:
+		parts, err := splitParts(n, 2, false)
+		if err != nil {
+			return v, err
+		}
+		v.U128 = &xdr.UInt128Parts{Hi: xdr.Uint64(parts[0]), Lo: xdr.Uint64(parts[1])}
+	case xdr.ScValTypeScvI128:
+		parts, err := splitParts(n, 2, true)
+		if err != nil {
+			return v, err
+		}
+		v.I128 = &xdr.Int128Parts{Hi: xdr.Int64(parts[0]), Lo: xdr.Uint64(parts[1])}
+	case xdr.ScValTypeScvU256:
+		parts, err := splitParts(n, 4, false)
+		if err != nil {
+			return v, err
+		}
+		v.U256 = &xdr.UInt256Parts{HiHi: xdr.Uint64(parts[0]), HiLo: xdr.Uint64(parts[1]), LoHi: xdr.Uint64(parts[2]), LoLo: xdr.Uint64(parts[3])}
+	case xdr.ScValTypeScvI256:
+		parts, err := splitParts(n, 4, true)
+		if err != nil {
+			return v, err
+		}
+		v.I256 = &xdr.Int256Parts{HiHi: xdr.Int64(parts[0]), HiLo: xdr.Uint64(parts[1]), LoHi: xdr.Uint64(parts[2]), LoLo: xdr.Uint64(parts[3])}
+	}
+	return v, nil
+}
+
+// splitParts splits n into 64-bit words, most significant first, in two's
+// complement when signed
+func splitParts(n *big.Int, words int, signed bool) ([]uint64, error) {
+	bits := uint(64 * words)
+	limit := new(big.Int).Lsh(big.NewInt(1), bits)
+	low := new(big.Int)
+	high := new(big.Int).Set(limit)
+	if signed {
+		high.Rsh(limit, 1)
+		low.Neg(high)
+	}
+	if n.Cmp(low) < 0 || n.Cmp(high) >= 0 {
+		return nil, fmt.Errorf("%s is out of range", n)
+	}
+
+	u := new(big.Int).Set(n)
+	if u.Sign() < 0 {
+		u.Add(u, limit)
+	}
+	parts := make([]uint64, words)
+	mask := new(big.Int).SetUint64(^uint64(0))
+	word := new(big.Int)
+	for i := words - 1; i >= 0; i-- {
+		parts[i] = word.And(u, mask).Uint64()
+		u.Rsh(u, 64)
+	}
+	return parts, nil
+}
+
+// ParseScAddress parses the strkey of an account (G...), contract (C...),
+// muxed account (M...), claimable balance (B...) or liquidity pool (L...)
+func ParseScAddress(address string) (xdr.ScAddress, error) {
+	switch {
+	case strings.HasPrefix(address, "G"):
+		id, err := xdr.AddressToAccountId(address)
+		if err != nil {
+			return xdr.ScAddress{}, err
+		}
+		return xdr.NewScAddress(xdr.ScAddressTypeScAddressTypeAccount, id)
+	case strings.HasPrefix(address, "C"):
+		raw, err := strkey.Decode(strkey.VersionByteContract, address)
+		if err != nil {
+			return xdr.ScAddress{}, err
+		}
+		var id xdr.ContractId
+		copy(id[:], raw)
+		return xdr.NewScAddress(xdr.ScAddressTypeScAddressTypeContract, id)
+	case strings.HasPrefix(address, "M"):
+		muxed, err := xdr.AddressToMuxedAccount(address)
+		if err != nil {
+			return xdr.ScAddress{}, err
+		}
+		med, ok := muxed.GetMed25519()
+		if !ok {
+			return xdr.ScAddress{}, fmt.Errorf("%s is not a muxed account", address)
+		}
+		return xdr.NewScAddress(xdr.ScAddressTypeScAddressTypeMuxedAccount, xdr.MuxedEd25519Account{Id: med.Id, Ed25519: med.Ed25519})
+	case strings.HasPrefix(address, "B"):
+		var id xdr.ClaimableBalanceId
+		if err := id.DecodeFromStrkey(address); err != nil {
+			return xdr.ScAddress{}, err
+		}
+		return xdr.NewScAddress(xdr.ScAddressTypeScAddressTypeClaimableBalance, id)
+	case strings.HasPrefix(address, "L"):
+		raw, err := strkey.Decode(strkey.VersionByteLiquidityPool, address)
+		if err != nil {
+			return xdr.ScAddress{}, err
+		}
+		var id xdr.PoolId
+		copy(id[:], raw)
+		return xdr.NewScAddress(xdr.ScAddressTypeScAddressTypeLiquidityPool, id)
+	}
+	return xdr.ScAddress{}, fmt.Errorf("unknown address %q", address)
+}
@@ -0,0 +1,190 @@
+// Copyright (c) 2026 dotandev
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package decoder
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/stellar/go/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func sym(s string) xdr.ScVal {
+	v := xdr.ScSymbol(s)
+	return xdr.ScVal{Type: xdr.ScValTypeScvSymbol, Sym: &v}
+}
+
+func u32(n uint32) xdr.ScVal {
+	v := xdr.Uint32(n)
+	return xdr.ScVal{Type: xdr.ScValTypeScvU32, U32: &v}
+}
+
+func vec(items ...xdr.ScVal) xdr.ScVal {
+	v := xdr.ScVec(items)
+	p := &v
+	return xdr.ScVal{Type: xdr.ScValTypeScvVec, Vec: &p}
+}
+
+func scMapVal(entries ...xdr.ScMapEntry) xdr.ScVal {
+	m := xdr.ScMap(entries)
+	p := &m
+	return xdr.ScVal{Type: xdr.ScValTypeScvMap, Map: &p}
+}
+
+func address(t *testing.T, typ xdr.ScAddressType, id interface{}) xdr.ScVal {
+	t.Helper()
+	a, err := xdr.NewScAddress(typ, id)
+	require.NoError(t, err)
+	return xdr.ScVal{Type: xdr.ScValTypeScvAddress, Address: &a}
+}
+
+// testScVals returns one value of every ScVal type, with their human-readable
+// forms
+func testScVals(t *testing.T) map[string]xdr.ScVal {
+	t.Helper()
+
+	b := true
+	errCode := xdr.ScErrorCodeScecMissingValue
+	contractCode := xdr.Uint32(3)
+	i32 := xdr.Int32(-17)
+	u64 := xdr.Uint64(math.MaxUint64)
+	i64 := xdr.Int64(math.MinInt64)
+	timepoint := xdr.TimePoint(1709294400)
+	duration := xdr.Duration(5400)
+	bytes := xdr.ScBytes{0xde, 0xad, 0xbe, 0xef}
+	str := xdr.ScString("a \"string\"")
+	nonce := xdr.ScNonceKey{Nonce: -7}
+	hash := xdr.Hash{0xab, 0xcd}
+	storage := xdr.ScMap{{Key: sym("admin"), Val: u32(1)}}
+
+	var account xdr.AccountId
+	require.NoError(t, account.SetAddress("GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H"))
+
+	return map[string]xdr.ScVal{
+		"true":                         {Type: xdr.ScValTypeScvBool, B: &b},
+		"(void)":                       {Type: xdr.ScValTypeScvVoid},
+		"Error(Storage, MissingValue)": {Type: xdr.ScValTypeScvError, Error: &xdr.ScError{Type: xdr.ScErrorTypeSceStorage, Code: &errCode}},
+		"Error(Contract, #3)":          {Type: xdr.ScValTypeScvError, Error: &xdr.ScError{Type: xdr.ScErrorTypeSceContract, ContractCode: &contractCode}},
+		"42":                           u32(42),
+		"-17":                          {Type: xdr.ScValTypeScvI32, I32: &i32},
+		"18446744073709551615":         {Type: xdr.ScValTypeScvU64, U64: &u64},
+		"-9223372036854775808":         {Type: xdr.ScValTypeScvI64, I64: &i64},
+		"2024-03-01T12:00:00Z":         {Type: xdr.ScValTypeScvTimepoint, Timepoint: &timepoint},
+		"1h30m0s":                      {Type: xdr.ScValTypeScvDuration, Duration: &duration},
+		"340282366920938463463374607431768211455":  {Type: xdr.ScValTypeScvU128, U128: &xdr.UInt128Parts{Hi: math.MaxUint64, Lo: math.MaxUint64}},
+		"-170141183460469231731687303715884105728": {Type: xdr.ScValTypeScvI128, I128: &xdr.Int128Parts{Hi: math.MinInt64, Lo: 0}},
+		"18446744073709551616":                     {Type: xdr.ScValTypeScvU256, U256: &xdr.UInt256Parts{LoHi: 1}},
+		"-1":                                       {Type: xdr.ScValTypeScvI256, I256: &xdr.Int256Parts{HiHi: -1, HiLo: math.MaxUint64, LoHi: math.MaxUint64, LoLo: math.MaxUint64}},
+		"0xdeadbeef":                               {Type: xdr.ScValTypeScvBytes, Bytes: &bytes},
+		`"a \"string\""`:                           {Type: xdr.ScValTypeScvString, Str: &str},
+		"transfer":                                 sym("transfer"),
+		"[1, transfer]":                            vec(u32(1), sym("transfer")),
+		"{balance: 100}":                           scMapVal(xdr.ScMapEntry{Key: sym("balance"), Val: u32(100)}),
+		"GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H": address(t, xdr.ScAddressTypeScAddressTypeAccount, account),
+		"CAAQEAYEAUDAOCAJBIFQYDIOB4IBCEQTCQKRMFYYDENBWHA5DYPSBFLM": address(t, xdr.ScAddressTypeScAddressTypeContract, xdr.ContractId{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22, 23, 24, 25, 26, 27, 28, 29, 30, 31, 32}),
+		"ContractInstance(wasm abcd000000000000000000000000000000000000000000000000000000000000, {admin: 1})": {
+			Type: xdr.ScValTypeScvContractInstance,
+			Instance: &xdr.ScContractInstance{
+				Executable: xdr.ContractExecutable{Type: xdr.ContractExecutableTypeContractExecutableWasm, WasmHash: &hash},
+				Storage:    &storage,
+			},
+		},
+		"ContractInstance(stellar asset)": {
+			Type:     xdr.ScValTypeScvContractInstance,
+			Instance: &xdr.ScContractInstance{Executable: xdr.ContractExecutable{Type: xdr.ContractExecutableTypeContractExecutableStellarAsset}},
+		},
+		"LedgerKeyContractInstance": {Type: xdr.ScValTypeScvLedgerKeyContractInstance},
+		"Nonce(-7)":                 {Type: xdr.ScValTypeScvLedgerKeyNonce, NonceKey: &nonce},
+	}
+}
+
+func TestFormatScVal(t *testing.T) {
+	for want, v := range testScVals(t) {
+		assert.Equal(t, want, FormatScVal(v))
+	}
+
+	assert.Equal(t, "(void)", FormatScVal(xdr.ScVal{}))
+	assert.Equal(t, "(invalid u32)", FormatScVal(xdr.ScVal{Type: xdr.ScValTypeScvU32}))
+
+	var none *xdr.ScVec
+	assert.Equal(t, "null", FormatScVal(xdr.ScVal{Type: xdr.ScValTypeScvVec, Vec: &none}))
+	assert.Equal(t, "[]", FormatScVal(vec()))
+
+	far := xdr.TimePoint(math.MaxUint64)
+	assert.Equal(t, "18446744073709551615", FormatScVal(xdr.ScVal{Type: xdr.ScValTypeScvTimepoint, Timepoint: &far}))
+}
+
+func TestScValJSON_RoundTrip(t *testing.T) {
+	values := testScVals(t)
+
+	var none *xdr.ScMap
+	values["null map"] = xdr.ScVal{Type: xdr.ScValTypeScvMap, Map: &none}
+	values["empty vec"] = vec()
+	invalid := xdr.ScString("\xff\xfe")
+	values["invalid utf-8"] = xdr.ScVal{Type: xdr.ScValTypeScvString, Str: &invalid}
+
+	for name, v := range values {
+		data, err := json.Marshal(ScValJSON{v})
+		require.NoError(t, err, name)
+
+		var decoded ScValJSON
+		require.NoError(t, json.Unmarshal(data, &decoded), "%s: %s", name, data)
+
+		want, err := v.MarshalBinary()
+		require.NoError(t, err)
+		got, err := decoded.ScVal.MarshalBinary()
+		require.NoError(t, err)
+		assert.Equal(t, want, got, "%s: %s", name, data)
+	}
+}
+
+func TestScValJSON_Format(t *testing.T) {
+	u128 := xdr.ScVal{Type: xdr.ScValTypeScvU128, U128: &xdr.UInt128Parts{Hi: 1, Lo: 0}}
+	data, err := json.Marshal(ScValJSON{vec(u32(7), u128)})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"type":"vec","value":[{"type":"u32","value":7},{"type":"u128","value":"18446744073709551616"}]}`, string(data))
+
+	invalid := xdr.ScString("\xff")
+	data, err = json.Marshal(ScValJSON{xdr.ScVal{Type: xdr.ScValTypeScvString, Str: &invalid}})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"type":"string","hex":"ff"}`, string(data))
+
+	code := xdr.ScErrorCodeScecMissingValue
+	data, err = json.Marshal(ScValJSON{xdr.ScVal{Type: xdr.ScValTypeScvError, Error: &xdr.ScError{Type: xdr.ScErrorTypeSceStorage, Code: &code}}})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"type":"error","value":{"type":"Storage","code":"MissingValue"}}`, string(data))
+
+	_, err = json.Marshal(ScValJSON{xdr.ScVal{Type: xdr.ScValTypeScvU32}})
+	assert.Error(t, err)
+}
+
+func TestScValJSON_Errors(t *testing.T) {
+	for _, data := range []string{
+		`{"type":"u8","value":1}`,
+		`{"type":"u32"}`,
+		`{"type":"u64","value":"18446744073709551616"}`,
+		`{"type":"i128","value":"-170141183460469231731687303715884105729"}`,
+		`{"type":"u128","value":"-1"}`,
+		`{"type":"bytes","value":"xyz"}`,
+		`{"type":"address","value":"GNOPE"}`,
+		`{"type":"error","value":{"type":"Storage","code":"Nope"}}`,
+	} {
+		var v ScValJSON
+		assert.Error(t, json.Unmarshal([]byte(data), &v), data)
+	}
+}
@@ -0,0 +1,322 @@
+// Copyright (c) 2026 dotandev
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package diagnostic models the events emitted by the Soroban host during a
+// simulation, as returned by erst-sim.
+package diagnostic
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/dotandev/hintents/internal/decoder"
+	"github.com/stellar/go/strkey"
+	"github.com/stellar/go/xdr"
+)
+
+// Type is the ContractEventType of an event
+type Type string
+
+const (
+	TypeContract   Type = "contract"
+	TypeSystem     Type = "system"
+	TypeDiagnostic Type = "diagnostic"
+)
+
+// Kind tells what an event was emitted for. Diagnostic events are classified
+// by their first topic, as the host emits them.
+type Kind string
+
+const (
+	// KindContract is an event published by a contract
+	KindContract Kind = "contract"
+	// KindSystem is an event published by the host on behalf of the system
+	KindSystem Kind = "system"
+	// KindFnCall is emitted when a contract function is called
+	KindFnCall Kind = "fn_call"
+	// KindFnReturn is emitted when a contract function returns
+	KindFnReturn Kind = "fn_return"
+	// KindLog is a message logged by a contract or the host
+	KindLog Kind = "log"
+	// KindError is emitted when the host or a contract raises an error
+	KindError Kind = "error"
+	// KindCoreMetrics reports resource usage at the end of an invocation
+	KindCoreMetrics Kind = "core_metrics"
+	// KindDiagnostic is any other diagnostic event
+	KindDiagnostic Kind = "diagnostic"
+)
+
+// Event is a single event emitted while simulating a transaction
+type Event struct {
+	Type Type
+	Kind Kind
+	// ContractID is the strkey (C...) of the emitting contract, empty for
+	// events emitted by the host outside of a contract
+	ContractID string
+	Topics     []xdr.ScVal
+	Data       xdr.ScVal
+	// InSuccessfulContractCall is false when the call that emitted the event
+	// failed, and its effects were rolled back
+	InSuccessfulContractCall bool
+	// CallDepth is the nesting depth of the contract call the event belongs
+	// to, 0 outside of any call. A fn_call and its fn_return share the depth
+	// of the called frame.
+	CallDepth int
+	// CPUInstructions and MemoryBytes are the resources the host had metered
+	// when the event was emitted, 0 when the simulator did not meter events
+	CPUInstructions uint64
+	MemoryBytes     uint64
+	// WasmBacktrace holds the code offsets of the WASM frames that were
+	// running when the event was emitted, innermost first, empty when the
+	// simulator did not report them. Offsets are relative to the start of the
+	// code section of the contract's module, as DWARF addresses are.
+	WasmBacktrace []uint64
+}
+
+// New returns an event of a successful call, classified by its type and
+// topics
+func New(t Type, contractID string, topics []xdr.ScVal, data xdr.ScVal) Event {
+	return Event{
+		Type:                     t,
+		Kind:                     kindOf(t, topics),
+		ContractID:               contractID,
+		Topics:                   topics,
+		Data:                     data,
+		InSuccessfulContractCall: true,
+	}
+}
+
+// Name returns the first topic when it is a symbol or a string, which is how
+// the host and most contracts name their events
+func (e Event) Name() string {
+	if len(e.Topics) == 0 {
+		return ""
+	}
+	name, _ := Text(e.Topics[0])
+	return name
+}
+
+// Function returns the called function of a fn_call or fn_return event
+func (e Event) Function() string {
+	var i int
+	switch e.Kind {
+	case KindFnCall:
+		// fn_call, contract ID, function
+		i = 2
+	case KindFnReturn:
+		// fn_return, function
+		i = 1
+	default:
+		return ""
+	}
+	if len(e.Topics) <= i {
+		return ""
+	}
+	name, _ := Text(e.Topics[i])
+	return name
+}
+
+// CalledContract returns the strkey of the contract called by a fn_call event
+func (e Event) CalledContract() string {
+	if e.Kind != KindFnCall || len(e.Topics) < 2 {
+		return ""
+	}
+	id, ok := e.Topics[1].GetBytes()
+	if !ok || len(id) != 32 {
+		return ""
+	}
+	contractID, err := strkey.Encode(strkey.VersionByteContract, id)
+	if err != nil {
+		return ""
+	}
+	return contractID
+}
+
+// Args returns the arguments of a fn_call event, which the host passes as a
+// vector in the event data
+func (e Event) Args() []xdr.ScVal {
+	if e.Kind != KindFnCall {
+		return nil
+	}
+	data := orVoid(e.Data)
+	if vec, ok := data.GetVec(); ok {
+		if vec == nil {
+			return nil
+		}
+		return *vec
+	}
+	if data.Type == xdr.ScValTypeScvVoid {
+		return nil
+	}
+	return []xdr.ScVal{data}
+}
+
+// ScError returns the error carried by an error event
+func (e Event) ScError() (xdr.ScError, bool) {
+	if e.Kind != KindError || len(e.Topics) < 2 {
+		return xdr.ScError{}, false
+	}
+	for _, topic := range e.Topics[1:] {
+		if scErr, ok := topic.GetError(); ok {
+			return scErr, true
+		}
+	}
+	return xdr.ScError{}, false
+}
+
+// String renders the event on one line, e.g.
+// "fn_call CDLZ...: [fn_call, ..., transfer] => [...]"
+func (e Event) String() string {
+	var sb strings.Builder
+	sb.WriteString(string(e.Kind))
+	if e.ContractID != "" {
+		sb.WriteString(" ")
+		sb.WriteString(e.ContractID)
+	}
+	sb.WriteString(": [")
+	for i, topic := range e.Topics {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteString(decoder.FormatScVal(topic))
+	}
+	sb.WriteString("] => ")
+	sb.WriteString(decoder.FormatScVal(e.Data))
+	if !e.InSuccessfulContractCall {
+		sb.WriteString(" (failed call)")
+	}
+	return sb.String()
+}
+
+// Text returns the content of a symbol or string value
+func Text(v xdr.ScVal) (string, bool) {
+	if sym, ok := v.GetSym(); ok {
+		return string(sym), true
+	}
+	if str, ok := v.GetStr(); ok {
+		return string(str), true
+	}
+	return "", false
+}
+
+// orVoid returns void for the zero ScVal, so that events built without data
+// can be printed and encoded
+func orVoid(v xdr.ScVal) xdr.ScVal {
+	if v.Type == xdr.ScValTypeScvBool && v.B == nil {
+		return xdr.ScVal{Type: xdr.ScValTypeScvVoid}
+	}
+	return v
+}
+
+// kindOf classifies an event from its type and first topic
+func kindOf(t Type, topics []xdr.ScVal) Kind {
+	switch t {
+	case TypeContract:
+		return KindContract
+	case TypeSystem:
+		return KindSystem
+	}
+	if len(topics) > 0 {
+		if sym, ok := topics[0].GetSym(); ok {
+			switch k := Kind(sym); k {
+			case KindFnCall, KindFnReturn, KindLog, KindError, KindCoreMetrics:
+				return k
+			}
+		}
+	}
+	return KindDiagnostic
+}
+
+// -------------------- JSON --------------------
+
+// wireEvent is the JSON form of an event, see the DiagnosticEvent definition
+// in docs/schema/common.schema.json
+type wireEvent struct {
+	Type                     Type     `json:"type"`
+	Kind                     Kind     `json:"kind,omitempty"`
+	ContractID               string   `json:"contract_id,omitempty"`
+	Topics                   []string `json:"topics"`
+	Data                     string   `json:"data"`
+	InSuccessfulContractCall bool     `json:"in_successful_contract_call"`
+	CallDepth                int      `json:"call_depth"`
+	CPUInstructions          uint64   `json:"cpu_instructions,omitempty"`
+	MemoryBytes              uint64   `json:"memory_bytes,omitempty"`
+	WasmBacktrace            []uint64 `json:"wasm_backtrace,omitempty"`
+}
+
+// MarshalJSON encodes topics and data as base64 ScVal XDR
+func (e Event) MarshalJSON() ([]byte, error) {
+	w := wireEvent{
+		Type:                     e.Type,
+		Kind:                     e.Kind,
+		ContractID:               e.ContractID,
+		Topics:                   make([]string, len(e.Topics)),
+		InSuccessfulContractCall: e.InSuccessfulContractCall,
+		CallDepth:                e.CallDepth,
+		CPUInstructions:          e.CPUInstructions,
+		MemoryBytes:              e.MemoryBytes,
+		WasmBacktrace:            e.WasmBacktrace,
+	}
+	for i, topic := range e.Topics {
+		encoded, err := xdr.MarshalBase64(topic)
+		if err != nil {
+			return nil, fmt.Errorf("topic %d: %w", i, err)
+		}
+		w.Topics[i] = encoded
+	}
+	data, err := xdr.MarshalBase64(orVoid(e.Data))
+	if err != nil {
+		return nil, fmt.Errorf("data: %w", err)
+	}
+	w.Data = data
+	return json.Marshal(w)
+}
+
+// UnmarshalJSON decodes an event, deriving its kind from its topics when the
+// simulator did not set it
+func (e *Event) UnmarshalJSON(data []byte) error {
+	var w wireEvent
+	if err := json.Unmarshal(data, &w); err != nil {
+		return err
+	}
+
+	topics := make([]xdr.ScVal, len(w.Topics))
+	for i, topic := range w.Topics {
+		if err := xdr.SafeUnmarshalBase64(topic, &topics[i]); err != nil {
+			return fmt.Errorf("invalid event topic %d: %w", i, err)
+		}
+	}
+	var value xdr.ScVal
+	if err := xdr.SafeUnmarshalBase64(w.Data, &value); err != nil {
+		return fmt.Errorf("invalid event data: %w", err)
+	}
+
+	*e = Event{
+		Type:                     w.Type,
+		Kind:                     w.Kind,
+		ContractID:               w.ContractID,
+		Topics:                   topics,
+		Data:                     value,
+		InSuccessfulContractCall: w.InSuccessfulContractCall,
+		CallDepth:                w.CallDepth,
+		CPUInstructions:          w.CPUInstructions,
+		MemoryBytes:              w.MemoryBytes,
+		WasmBacktrace:            w.WasmBacktrace,
+	}
+	if e.Kind == "" {
+		e.Kind = kindOf(e.Type, topics)
+	}
+	return nil
+}
@@ -0,0 +1,156 @@
+// Copyright (c) 2026 dotandev
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diagnostic
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stellar/go/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testContractID = "CDLZFC3SYJYDZT7K67VZ75HPJVIEUVNIXF47ZG2FB2RMQQVU2HHGCYSC"
+
+func sym(s string) xdr.ScVal {
+	v := xdr.ScSymbol(s)
+	return xdr.ScVal{Type: xdr.ScValTypeScvSymbol, Sym: &v}
+}
+
+func u32(n uint32) xdr.ScVal {
+	v := xdr.Uint32(n)
+	return xdr.ScVal{Type: xdr.ScValTypeScvU32, U32: &v}
+}
+
+func scError(t xdr.ScErrorType, code xdr.ScErrorCode) xdr.ScVal {
+	return xdr.ScVal{Type: xdr.ScValTypeScvError, Error: &xdr.ScError{Type: t, Code: &code}}
+}
+
+func TestNew_Kind(t *testing.T) {
+	tests := []struct {
+		name   string
+		typ    Type
+		topics []xdr.ScVal
+		want   Kind
+	}{
+		{"contract event", TypeContract, []xdr.ScVal{sym("fn_call")}, KindContract},
+		{"system event", TypeSystem, nil, KindSystem},
+		{"fn_call", TypeDiagnostic, []xdr.ScVal{sym("fn_call")}, KindFnCall},
+		{"fn_return", TypeDiagnostic, []xdr.ScVal{sym("fn_return")}, KindFnReturn},
+		{"log", TypeDiagnostic, []xdr.ScVal{sym("log")}, KindLog},
+		{"error", TypeDiagnostic, []xdr.ScVal{sym("error")}, KindError},
+		{"core_metrics", TypeDiagnostic, []xdr.ScVal{sym("core_metrics")}, KindCoreMetrics},
+		{"other symbol", TypeDiagnostic, []xdr.ScVal{sym("custom")}, KindDiagnostic},
+		{"no topics", TypeDiagnostic, nil, KindDiagnostic},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := New(tt.typ, "", tt.topics, xdr.ScVal{})
+			assert.Equal(t, tt.want, e.Kind)
+			assert.True(t, e.InSuccessfulContractCall)
+		})
+	}
+}
+
+func TestEvent_Function(t *testing.T) {
+	call := New(TypeDiagnostic, "", []xdr.ScVal{sym("fn_call"), u32(1), sym("transfer")}, xdr.ScVal{})
+	ret := New(TypeDiagnostic, testContractID, []xdr.ScVal{sym("fn_return"), sym("transfer")}, u32(7))
+	logEvent := New(TypeDiagnostic, testContractID, []xdr.ScVal{sym("log")}, xdr.ScVal{})
+
+	assert.Equal(t, "transfer", call.Function())
+	assert.Equal(t, "transfer", ret.Function())
+	assert.Equal(t, "", logEvent.Function())
+	assert.Equal(t, "log", logEvent.Name())
+}
+
+func TestEvent_CalledContractAndArgs(t *testing.T) {
+	raw := make([]byte, 32)
+	raw[0] = 1
+	id := xdr.ScBytes(raw)
+	args := &xdr.ScVec{u32(1), sym("to")}
+	call := New(TypeDiagnostic, "", []xdr.ScVal{sym("fn_call"), {Type: xdr.ScValTypeScvBytes, Bytes: &id}, sym("transfer")},
+		xdr.ScVal{Type: xdr.ScValTypeScvVec, Vec: &args})
+
+	assert.Regexp(t, "^C[A-Z2-7]{55}$", call.CalledContract())
+	assert.Equal(t, []xdr.ScVal{u32(1), sym("to")}, call.Args())
+
+	single := New(TypeDiagnostic, "", []xdr.ScVal{sym("fn_call")}, u32(5))
+	assert.Equal(t, "", single.CalledContract())
+	assert.Equal(t, []xdr.ScVal{u32(5)}, single.Args())
+
+	none := New(TypeDiagnostic, "", []xdr.ScVal{sym("fn_call")}, xdr.ScVal{})
+	assert.Empty(t, none.Args())
+	assert.Empty(t, New(TypeDiagnostic, "", []xdr.ScVal{sym("log")}, u32(5)).Args())
+}
+
+func TestEvent_ScError(t *testing.T) {
+	e := New(TypeDiagnostic, testContractID,
+		[]xdr.ScVal{sym("error"), scError(xdr.ScErrorTypeSceAuth, xdr.ScErrorCodeScecInvalidAction)}, xdr.ScVal{})
+
+	scErr, ok := e.ScError()
+	require.True(t, ok)
+	assert.Equal(t, xdr.ScErrorTypeSceAuth, scErr.Type)
+
+	_, ok = New(TypeDiagnostic, "", []xdr.ScVal{sym("error")}, xdr.ScVal{}).ScError()
+	assert.False(t, ok)
+	_, ok = New(TypeContract, "", []xdr.ScVal{sym("error"), scError(xdr.ScErrorTypeSceAuth, 0)}, xdr.ScVal{}).ScError()
+	assert.False(t, ok, "contract events are not host errors")
+}
+
+func TestEvent_JSONRoundTrip(t *testing.T) {
+	e := New(TypeDiagnostic, testContractID, []xdr.ScVal{sym("fn_return"), sym("balance")}, u32(42))
+	e.InSuccessfulContractCall = false
+	e.CallDepth = 2
+
+	data, err := json.Marshal(e)
+	require.NoError(t, err)
+
+	var decoded Event
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, e, decoded)
+}
+
+func TestEvent_UnmarshalDerivesKind(t *testing.T) {
+	topic, err := xdr.MarshalBase64(sym("fn_call"))
+	require.NoError(t, err)
+	data, err := xdr.MarshalBase64(xdr.ScVal{Type: xdr.ScValTypeScvVoid})
+	require.NoError(t, err)
+
+	var e Event
+	raw := `{"type":"diagnostic","topics":["` + topic + `"],"data":"` + data + `","in_successful_contract_call":true,"call_depth":1}`
+	require.NoError(t, json.Unmarshal([]byte(raw), &e))
+	assert.Equal(t, KindFnCall, e.Kind)
+	assert.Equal(t, 1, e.CallDepth)
+	assert.Zero(t, e.CPUInstructions)
+
+	raw = `{"type":"diagnostic","topics":["` + topic + `"],"data":"` + data + `","in_successful_contract_call":true,"call_depth":1,"cpu_instructions":1500,"memory_bytes":64,"wasm_backtrace":[26,4]}`
+	require.NoError(t, json.Unmarshal([]byte(raw), &e))
+	assert.Equal(t, uint64(1500), e.CPUInstructions)
+	assert.Equal(t, uint64(64), e.MemoryBytes)
+	assert.Equal(t, []uint64{26, 4}, e.WasmBacktrace)
+
+	assert.Error(t, json.Unmarshal([]byte(`{"type":"diagnostic","topics":["not xdr"],"data":""}`), &e))
+}
+
+func TestEvent_String(t *testing.T) {
+	e := New(TypeContract, testContractID, []xdr.ScVal{sym("transfer")}, u32(100))
+	assert.Equal(t, "contract "+testContractID+": [transfer] => 100", e.String())
+
+	e = New(TypeDiagnostic, "", []xdr.ScVal{sym("log")}, xdr.ScVal{})
+	e.InSuccessfulContractCall = false
+	assert.Equal(t, "log: [log] => (void) (failed call)", e.String())
+}
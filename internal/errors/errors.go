@@ -17,18 +17,29 @@ package errors
 import (
 	"errors"
 	"fmt"
+	"time"
 )
 
 // Sentinel errors for comparison with errors.Is
 var (
-	ErrTransactionNotFound  = errors.New("transaction not found")
-	ErrRPCConnectionFailed  = errors.New("RPC connection failed")
-	ErrSimulatorNotFound    = errors.New("simulator binary not found")
-	ErrSimulationFailed     = errors.New("simulation execution failed")
-	ErrInvalidNetwork       = errors.New("invalid network")
-	ErrMarshalFailed        = errors.New("failed to marshal request")
-	ErrUnmarshalFailed      = errors.New("failed to unmarshal response")
-	ErrSimulationLogicError = errors.New("simulation logic error")
+	ErrTransactionNotFound    = errors.New("transaction not found")
+	ErrRPCConnectionFailed    = errors.New("RPC connection failed")
+	ErrSimulatorNotFound      = errors.New("simulator binary not found")
+	ErrSimulationFailed       = errors.New("simulation execution failed")
+	ErrInvalidNetwork         = errors.New("invalid network")
+	ErrMarshalFailed          = errors.New("failed to marshal request")
+	ErrUnmarshalFailed        = errors.New("failed to unmarshal response")
+	ErrSimulationLogicError   = errors.New("simulation logic error")
+	ErrSimulationTimeout      = errors.New("simulation timed out")
+	ErrSimulationCancelled    = errors.New("simulation cancelled")
+	ErrSimulatorOutputLimit   = errors.New("simulator output exceeded limit")
+	ErrSimulatorResourceLimit = errors.New("simulator exceeded resource limit")
+	ErrSimulatorProtocol      = errors.New("simulator protocol mismatch")
+	ErrTraceFileCorrupt       = errors.New("trace file corrupt")
+	ErrInvalidWasm            = errors.New("invalid WASM module")
+	ErrNoDebugInfo            = errors.New("no DWARF debug info")
+	ErrNoContractSpec         = errors.New("no contract spec")
+	ErrContractNotFound       = errors.New("contract not found")
 )
 
 // Wrap functions for consistent error wrapping
@@ -49,7 +60,7 @@ func WrapSimulationFailed(err error, stderr string) error {
 }
 
 func WrapInvalidNetwork(network string) error {
-	return fmt.Errorf("%w: %s. Must be one of: testnet, mainnet, futurenet", ErrInvalidNetwork, network)
+	return fmt.Errorf("%w: %s. Must be one of: testnet, mainnet, futurenet, or a custom network added with 'erst network add'", ErrInvalidNetwork, network)
 }
 
 func WrapMarshalFailed(err error) error {
@@ -63,3 +74,43 @@ func WrapUnmarshalFailed(err error, output string) error {
 func WrapSimulationLogicError(msg string) error {
 	return fmt.Errorf("%w: %s", ErrSimulationLogicError, msg)
 }
+
+func WrapSimulationTimeout(timeout time.Duration) error {
+	return fmt.Errorf("%w after %s", ErrSimulationTimeout, timeout)
+}
+
+func WrapSimulationCancelled(err error) error {
+	return fmt.Errorf("%w: %w", ErrSimulationCancelled, err)
+}
+
+func WrapSimulatorOutputLimit(stream string, limit int64) error {
+	return fmt.Errorf("%w: %s is larger than %d bytes", ErrSimulatorOutputLimit, stream, limit)
+}
+
+func WrapSimulatorResourceLimit(resource string, stderr string) error {
+	return fmt.Errorf("%w: %s, stderr: %s", ErrSimulatorResourceLimit, resource, stderr)
+}
+
+func WrapSimulatorProtocol(msg string) error {
+	return fmt.Errorf("%w: %s", ErrSimulatorProtocol, msg)
+}
+
+func WrapTraceFileCorrupt(msg string) error {
+	return fmt.Errorf("%w: %s", ErrTraceFileCorrupt, msg)
+}
+
+func WrapInvalidWasm(msg string) error {
+	return fmt.Errorf("%w: %s", ErrInvalidWasm, msg)
+}
+
+func WrapNoDebugInfo(module string) error {
+	return fmt.Errorf("%w in %s, build the contract with debug info", ErrNoDebugInfo, module)
+}
+
+func WrapNoContractSpec(module string) error {
+	return fmt.Errorf("%w in %s", ErrNoContractSpec, module)
+}
+
+func WrapContractNotFound(contractID string) error {
+	return fmt.Errorf("%w: %s", ErrContractNotFound, contractID)
+}
@@ -15,9 +15,11 @@
 package errors
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -79,6 +81,47 @@ func TestErrorWrapping(t *testing.T) {
 	wrappedErr = WrapSimulationLogicError("logic error")
 	assert.True(t, errors.Is(wrappedErr, ErrSimulationLogicError))
 	assert.Contains(t, wrappedErr.Error(), "logic error")
+
+	// Test simulator limit errors
+	wrappedErr = WrapSimulationTimeout(30 * time.Second)
+	assert.True(t, errors.Is(wrappedErr, ErrSimulationTimeout))
+	assert.Contains(t, wrappedErr.Error(), "30s")
+
+	wrappedErr = WrapSimulationCancelled(context.Canceled)
+	assert.True(t, errors.Is(wrappedErr, ErrSimulationCancelled))
+	assert.True(t, errors.Is(wrappedErr, context.Canceled))
+
+	wrappedErr = WrapSimulatorOutputLimit("stdout", 1024)
+	assert.True(t, errors.Is(wrappedErr, ErrSimulatorOutputLimit))
+	assert.Contains(t, wrappedErr.Error(), "stdout is larger than 1024 bytes")
+
+	wrappedErr = WrapSimulatorResourceLimit("cpu time", "killed")
+	assert.True(t, errors.Is(wrappedErr, ErrSimulatorResourceLimit))
+	assert.Contains(t, wrappedErr.Error(), "cpu time")
+
+	wrappedErr = WrapSimulatorProtocol("expected protocol 1.0")
+	assert.True(t, errors.Is(wrappedErr, ErrSimulatorProtocol))
+	assert.Contains(t, wrappedErr.Error(), "expected protocol 1.0")
+
+	wrappedErr = WrapTraceFileCorrupt("bad checksum at offset 8")
+	assert.True(t, errors.Is(wrappedErr, ErrTraceFileCorrupt))
+	assert.Contains(t, wrappedErr.Error(), "bad checksum at offset 8")
+
+	wrappedErr = WrapInvalidWasm("bad magic")
+	assert.True(t, errors.Is(wrappedErr, ErrInvalidWasm))
+	assert.Contains(t, wrappedErr.Error(), "bad magic")
+
+	wrappedErr = WrapNoDebugInfo("token.wasm")
+	assert.True(t, errors.Is(wrappedErr, ErrNoDebugInfo))
+	assert.Contains(t, wrappedErr.Error(), "token.wasm")
+
+	wrappedErr = WrapNoContractSpec("token.wasm")
+	assert.True(t, errors.Is(wrappedErr, ErrNoContractSpec))
+	assert.Contains(t, wrappedErr.Error(), "token.wasm")
+
+	wrappedErr = WrapContractNotFound("CABC")
+	assert.True(t, errors.Is(wrappedErr, ErrContractNotFound))
+	assert.Contains(t, wrappedErr.Error(), "CABC")
 }
 
 func TestErrorComparison(t *testing.T) {
@@ -0,0 +1,43 @@
+// Copyright (c) 2026 dotandev
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipc
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dotandev/hintents/internal/errors"
+)
+
+// ProtocolVersion is the newest protocol version erst speaks
+const ProtocolVersion = "1.0"
+
+// SupportedVersions lists the protocol versions erst speaks, newest first
+var SupportedVersions = []string{ProtocolVersion}
+
+// Negotiate picks the newest protocol version supported by both erst and a
+// simulator offering the given versions
+func Negotiate(offered []string) (string, error) {
+	for _, supported := range SupportedVersions {
+		for _, v := range offered {
+			if v == supported {
+				return v, nil
+			}
+		}
+	}
+	return "", errors.WrapSimulatorProtocol(fmt.Sprintf(
+		"erst speaks protocol %s but the simulator only speaks %s; install erst-sim from the same release as erst",
+		strings.Join(SupportedVersions, ", "), strings.Join(offered, ", ")))
+}
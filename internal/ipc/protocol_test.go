@@ -0,0 +1,34 @@
+// Copyright (c) 2026 dotandev
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipc
+
+import (
+	"testing"
+
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNegotiate(t *testing.T) {
+	version, err := Negotiate([]string{"2.0", ProtocolVersion})
+	require.NoError(t, err)
+	assert.Equal(t, ProtocolVersion, version)
+
+	_, err = Negotiate([]string{"0.9"})
+	require.Error(t, err)
+	assert.ErrorIs(t, err, errors.ErrSimulatorProtocol)
+	assert.Contains(t, err.Error(), "only speaks 0.9")
+}
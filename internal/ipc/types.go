@@ -36,16 +36,53 @@ func (r *SimulationResponseSchema) Marshal() ([]byte, error) {
 	return json.Marshal(r)
 }
 
+func UnmarshalHandshake(data []byte) (Handshake, error) {
+	var r Handshake
+	err := json.Unmarshal(data, &r)
+	return r, err
+}
+
+func (r *Handshake) Marshal() ([]byte, error) {
+	return json.Marshal(r)
+}
+
 type SimulationRequestSchema struct {
-	Network                                      Network `json:"network"`
-	// Client-generated unique request identifier        
-	RequestID                                    string  `json:"request_id"`
-	Version                                      string  `json:"version"`
-	Xdr                                          string  `json:"xdr"`
+	Version string `json:"version"`
+	// Client-generated unique request identifier
+	RequestID string `json:"request_id"`
+	// Network the transaction comes from: public, testnet, futurenet or a custom network name
+	Network Network `json:"network,omitempty"`
+	// Transaction envelope to replay
+	Xdr string `json:"xdr,omitempty"`
+	// Result meta of the original execution
+	ResultMetaXdr string `json:"result_meta_xdr,omitempty"`
+	// Ledger entries keyed by LedgerKey XDR
+	LedgerEntries map[string]string `json:"ledger_entries,omitempty"`
+	// Ledger close time override, in Unix seconds
+	Timestamp int64 `json:"timestamp,omitempty"`
+	// Ledger sequence override
+	LedgerSequence uint32 `json:"ledger_sequence,omitempty"`
+	// Local WASM file to run instead of replaying a transaction
+	WasmPath *string `json:"wasm_path,omitempty"`
+	// Arguments for local WASM replay
+	MockArgs *[]string `json:"mock_args,omitempty"`
+	// Collect a CPU/memory flamegraph
+	Profile       bool              `json:"profile,omitempty"`
+	AuthTraceOpts *AuthTraceOptions `json:"auth_trace_opts,omitempty"`
+	// Custom account contract configuration for auth tracing
+	CustomAuthConfig map[string]interface{} `json:"custom_auth_config,omitempty"`
+}
+
+type AuthTraceOptions struct {
+	CaptureSigDetails    bool `json:"capture_sig_details"`
+	Enabled              bool `json:"enabled"`
+	MaxEventDepth        int  `json:"max_event_depth,omitempty"`
+	TraceCustomContracts bool `json:"trace_custom_contracts"`
 }
 
 type SimulationResponseSchema struct {
-	Error     *Error  `json:"error,omitempty"`
+	Error *Error `json:"error,omitempty"`
+	// request_id of the request being answered
 	RequestID string  `json:"request_id"`
 	Result    *Result `json:"result,omitempty"`
 	Success   bool    `json:"success"`
@@ -53,13 +90,29 @@ type SimulationResponseSchema struct {
 }
 
 type Error struct {
+	// invalid_request, unsupported_version or simulation_failed
 	Code    string `json:"code"`
 	Message string `json:"message"`
 }
 
 type Result struct {
-	// Fee charged in stroops       
-	FeeCharged               string `json:"fee_charged"`
+	AuthTrace json.RawMessage `json:"auth_trace,omitempty"`
+	// Diagnostic events, see the DiagnosticEvent definition in common.schema.json
+	Events json.RawMessage `json:"events,omitempty"`
+	// Fee charged in stroops
+	FeeCharged string `json:"fee_charged,omitempty"`
+	// SVG flamegraph, when profiling was requested
+	Flamegraph         string          `json:"flamegraph,omitempty"`
+	Logs               []string        `json:"logs,omitempty"`
+	SecurityViolations json.RawMessage `json:"security_violations,omitempty"`
+}
+
+// First line printed by erst-sim in --handshake and --serve mode
+type Handshake struct {
+	// Protocol versions the simulator speaks
+	ProtocolVersions []string `json:"protocol_versions"`
+	// Version of the erst-sim binary
+	SimulatorVersion string `json:"simulator_version,omitempty"`
 }
 
 type Network string
@@ -69,3 +122,10 @@ const (
 	Public    Network = "public"
 	Testnet   Network = "testnet"
 )
+
+// Error codes reported by the simulator
+const (
+	ErrorCodeInvalidRequest     = "invalid_request"
+	ErrorCodeUnsupportedVersion = "unsupported_version"
+	ErrorCodeSimulationFailed   = "simulation_failed"
+)
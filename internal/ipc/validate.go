@@ -0,0 +1,517 @@
+// Copyright (c) 2026 dotandev
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipc
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const (
+	requestSchemaFile   = "simulation-request.schema.json"
+	responseSchemaFile  = "simulation-response.schema.json"
+	handshakeSchemaFile = "handshake.schema.json"
+)
+
+// ValidateRequest checks a request against docs/schema/simulation-request.schema.json
+func ValidateRequest(data []byte) error {
+	return validate(requestSchemaFile, data)
+}
+
+// ValidateResponse checks a response against docs/schema/simulation-response.schema.json
+func ValidateResponse(data []byte) error {
+	return validate(responseSchemaFile, data)
+}
+
+// ValidateHandshake checks a handshake against docs/schema/handshake.schema.json
+func ValidateHandshake(data []byte) error {
+	return validate(handshakeSchemaFile, data)
+}
+
+// ValidationError lists every way a document violates its schema
+type ValidationError struct {
+	Schema     string
+	Violations []string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("does not match %s: %s", e.Schema, strings.Join(e.Violations, "; "))
+}
+
+var (
+	loadOnce sync.Once
+	loaded   *schemaSet
+	loadErr  error
+)
+
+func validate(file string, data []byte) error {
+	loadOnce.Do(func() {
+		var fsys fs.FS
+		if fsys, loadErr = fs.Sub(schemaFS, "schema"); loadErr == nil {
+			loaded, loadErr = loadSchemas(fsys)
+		}
+	})
+	if loadErr != nil {
+		return loadErr
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var doc interface{}
+	if err := decoder.Decode(&doc); err != nil {
+		return fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	v := &validator{set: loaded, file: file}
+	v.check(loaded.docs[file], doc, "")
+	if len(v.violations) > 0 {
+		return &ValidationError{Schema: file, Violations: v.violations}
+	}
+	return nil
+}
+
+// -------------------- Schema validation --------------------
+
+// schemaSet holds the schema documents by file name, so that references such
+// as "common.schema.json#/definitions/Version" resolve without the network
+type schemaSet struct {
+	docs map[string]map[string]interface{}
+
+	mu       sync.Mutex
+	patterns map[string]*regexp.Regexp
+}
+
+func loadSchemas(fsys fs.FS) (*schemaSet, error) {
+	files, err := fs.Glob(fsys, "*.schema.json")
+	if err != nil {
+		return nil, err
+	}
+
+	set := &schemaSet{docs: map[string]map[string]interface{}{}, patterns: map[string]*regexp.Regexp{}}
+	for _, file := range files {
+		data, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return nil, err
+		}
+		var doc map[string]interface{}
+		if err := json.Unmarshal(data, &doc); err != nil {
+			return nil, fmt.Errorf("invalid schema %s: %w", file, err)
+		}
+		set.docs[file] = doc
+	}
+
+	for _, file := range files {
+		if err := set.checkKeywords(file, set.docs[file], "#"); err != nil {
+			return nil, fmt.Errorf("unsupported schema %s: %w", file, err)
+		}
+	}
+	return set, nil
+}
+
+// validationKeywords are the JSON Schema keywords the validator enforces, and
+// definitions, which holds the targets of $ref
+var validationKeywords = map[string]bool{
+	"type": true, "enum": true, "const": true,
+	"minLength": true, "pattern": true, "minimum": true,
+	"items": true, "minItems": true,
+	"required": true, "properties": true, "additionalProperties": true, "propertyNames": true,
+	"allOf": true, "anyOf": true, "if": true, "then": true, "else": true,
+	"$ref": true, "definitions": true,
+}
+
+// annotationKeywords are the keywords the validator accepts without checking
+// anything
+var annotationKeywords = map[string]bool{
+	"$schema": true, "$id": true, "$comment": true, "title": true, "description": true,
+	"contentEncoding": true, "default": true, "examples": true,
+}
+
+// checkKeywords fails on any keyword the validator does not implement, and on
+// keywords used in forms it does not implement, such as a list of types, so
+// that a schema change cannot be silently ignored
+func (s *schemaSet) checkKeywords(file string, schema map[string]interface{}, path string) error {
+	keywords := make([]string, 0, len(schema))
+	for keyword := range schema {
+		keywords = append(keywords, keyword)
+	}
+	sort.Strings(keywords)
+
+	for _, keyword := range keywords {
+		value := schema[keyword]
+		if annotationKeywords[keyword] {
+			continue
+		}
+		if !validationKeywords[keyword] {
+			return fmt.Errorf("%s: keyword %q is not supported", path, keyword)
+		}
+
+		at := path + "/" + keyword
+		var subschemas []interface{}
+		switch keyword {
+		case "type", "pattern", "$ref":
+			if _, ok := value.(string); !ok {
+				return fmt.Errorf("%s: must be a string", at)
+			}
+			if keyword == "$ref" {
+				v := &validator{set: s, file: file}
+				if _, _, err := v.resolve(value.(string)); err != nil {
+					return fmt.Errorf("%s: %w", at, err)
+				}
+			}
+		case "minLength", "minItems", "minimum":
+			if _, ok := value.(float64); !ok {
+				return fmt.Errorf("%s: must be a number", at)
+			}
+		case "enum", "required":
+			if _, ok := value.([]interface{}); !ok {
+				return fmt.Errorf("%s: must be an array", at)
+			}
+		case "items", "propertyNames", "if", "then", "else":
+			subschemas = []interface{}{value}
+		case "additionalProperties":
+			if _, ok := value.(bool); !ok {
+				subschemas = []interface{}{value}
+			}
+		case "allOf", "anyOf":
+			list, ok := value.([]interface{})
+			if !ok {
+				return fmt.Errorf("%s: must be an array", at)
+			}
+			subschemas = list
+		case "properties", "definitions":
+			props, ok := value.(map[string]interface{})
+			if !ok {
+				return fmt.Errorf("%s: must be an object", at)
+			}
+			for name, sub := range props {
+				sub, ok := sub.(map[string]interface{})
+				if !ok {
+					return fmt.Errorf("%s/%s: must be a schema object", at, name)
+				}
+				if err := s.checkKeywords(file, sub, at+"/"+name); err != nil {
+					return err
+				}
+			}
+		}
+
+		for i, sub := range subschemas {
+			subschema, ok := sub.(map[string]interface{})
+			if !ok {
+				return fmt.Errorf("%s: must be a schema object", at)
+			}
+			subPath := at
+			if keyword == "allOf" || keyword == "anyOf" {
+				subPath = fmt.Sprintf("%s/%d", at, i)
+			}
+			if err := s.checkKeywords(file, subschema, subPath); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (s *schemaSet) pattern(expr string) (*regexp.Regexp, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if re, ok := s.patterns[expr]; ok {
+		return re, nil
+	}
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, err
+	}
+	s.patterns[expr] = re
+	return re, nil
+}
+
+// validator implements the subset of JSON Schema (draft-07) used by
+// docs/schema, see validationKeywords: type (a single type name), enum, const,
+// minLength, pattern, minimum, items (a single schema), minItems, required,
+// properties, additionalProperties, propertyNames, allOf, anyOf, if/then/else
+// and $ref to a JSON pointer in one of the schema files. loadSchemas rejects
+// schemas using anything else.
+type validator struct {
+	set        *schemaSet
+	file       string
+	violations []string
+}
+
+func (v *validator) fail(path, format string, args ...interface{}) {
+	if path == "" {
+		path = "/"
+	}
+	v.violations = append(v.violations, path+": "+fmt.Sprintf(format, args...))
+}
+
+// valid reports whether doc matches schema without recording violations
+func (v *validator) valid(schema map[string]interface{}, doc interface{}, path string) bool {
+	sub := &validator{set: v.set, file: v.file}
+	sub.check(schema, doc, path)
+	return len(sub.violations) == 0
+}
+
+func (v *validator) check(schema map[string]interface{}, doc interface{}, path string) {
+	if ref, ok := schema["$ref"].(string); ok {
+		resolved, file, err := v.resolve(ref)
+		if err != nil {
+			v.fail(path, "%v", err)
+			return
+		}
+		prev := v.file
+		v.file = file
+		v.check(resolved, doc, path)
+		v.file = prev
+	}
+
+	if t, ok := schema["type"].(string); ok && !hasType(doc, t) {
+		v.fail(path, "expected %s, got %s", t, typeName(doc))
+		return
+	}
+	if values, ok := schema["enum"].([]interface{}); ok && !containsValue(values, doc) {
+		v.fail(path, "must be one of %v", values)
+	}
+	if c, ok := schema["const"]; ok && !equalValues(c, doc) {
+		v.fail(path, "must be %v", c)
+	}
+
+	switch d := doc.(type) {
+	case string:
+		v.checkString(schema, d, path)
+	case json.Number:
+		if min, ok := schema["minimum"].(float64); ok {
+			if f, err := d.Float64(); err == nil && f < min {
+				v.fail(path, "must be at least %v", min)
+			}
+		}
+	case []interface{}:
+		v.checkArray(schema, d, path)
+	case map[string]interface{}:
+		v.checkObject(schema, d, path)
+	}
+
+	if all, ok := schema["allOf"].([]interface{}); ok {
+		for _, s := range all {
+			if sub, ok := s.(map[string]interface{}); ok {
+				v.check(sub, doc, path)
+			}
+		}
+	}
+	if anyOf, ok := schema["anyOf"].([]interface{}); ok {
+		matched := false
+		for _, s := range anyOf {
+			if sub, ok := s.(map[string]interface{}); ok && v.valid(sub, doc, path) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			v.fail(path, "must match at least one of %s", describeAlternatives(anyOf))
+		}
+	}
+	if cond, ok := schema["if"].(map[string]interface{}); ok {
+		branch := "else"
+		if v.valid(cond, doc, path) {
+			branch = "then"
+		}
+		if sub, ok := schema[branch].(map[string]interface{}); ok {
+			v.check(sub, doc, path)
+		}
+	}
+}
+
+func (v *validator) checkString(schema map[string]interface{}, s, path string) {
+	if min, ok := schema["minLength"].(float64); ok && float64(len(s)) < min {
+		v.fail(path, "must be at least %v characters", min)
+	}
+	if expr, ok := schema["pattern"].(string); ok {
+		re, err := v.set.pattern(expr)
+		if err != nil {
+			v.fail(path, "invalid pattern %q in schema: %v", expr, err)
+		} else if !re.MatchString(s) {
+			v.fail(path, "%q does not match %s", truncate(s), expr)
+		}
+	}
+}
+
+func (v *validator) checkArray(schema map[string]interface{}, items []interface{}, path string) {
+	if min, ok := schema["minItems"].(float64); ok && float64(len(items)) < min {
+		v.fail(path, "must have at least %v items", min)
+	}
+	if itemSchema, ok := schema["items"].(map[string]interface{}); ok {
+		for i, item := range items {
+			v.check(itemSchema, item, fmt.Sprintf("%s/%d", path, i))
+		}
+	}
+}
+
+func (v *validator) checkObject(schema map[string]interface{}, obj map[string]interface{}, path string) {
+	if required, ok := schema["required"].([]interface{}); ok {
+		for _, r := range required {
+			name, _ := r.(string)
+			if _, present := obj[name]; !present {
+				v.fail(path, "missing required property %q", name)
+			}
+		}
+	}
+
+	properties, _ := schema["properties"].(map[string]interface{})
+	names := make([]string, 0, len(obj))
+	for name := range obj {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		value := obj[name]
+		propPath := path + "/" + name
+		if nameSchema, ok := schema["propertyNames"].(map[string]interface{}); ok {
+			v.check(nameSchema, name, propPath)
+		}
+		if propSchema, ok := properties[name].(map[string]interface{}); ok {
+			v.check(propSchema, value, propPath)
+			continue
+		}
+		switch additional := schema["additionalProperties"].(type) {
+		case bool:
+			if !additional {
+				v.fail(path, "unknown property %q", name)
+			}
+		case map[string]interface{}:
+			v.check(additional, value, propPath)
+		}
+	}
+}
+
+// resolve follows a $ref of the form "[file]#/definitions/Name"
+func (v *validator) resolve(ref string) (map[string]interface{}, string, error) {
+	file, pointer, _ := strings.Cut(ref, "#")
+	if file == "" {
+		file = v.file
+	}
+	node, ok := v.set.docs[file]
+	if !ok {
+		return nil, "", fmt.Errorf("unresolved schema reference %q", ref)
+	}
+
+	for _, part := range strings.Split(strings.TrimPrefix(pointer, "/"), "/") {
+		if part == "" {
+			continue
+		}
+		next, ok := node[part].(map[string]interface{})
+		if !ok {
+			return nil, "", fmt.Errorf("unresolved schema reference %q", ref)
+		}
+		node = next
+	}
+	return node, file, nil
+}
+
+func hasType(doc interface{}, t string) bool {
+	switch t {
+	case "object":
+		_, ok := doc.(map[string]interface{})
+		return ok
+	case "array":
+		_, ok := doc.([]interface{})
+		return ok
+	case "string":
+		_, ok := doc.(string)
+		return ok
+	case "boolean":
+		_, ok := doc.(bool)
+		return ok
+	case "null":
+		return doc == nil
+	case "number":
+		_, ok := doc.(json.Number)
+		return ok
+	case "integer":
+		n, ok := doc.(json.Number)
+		if !ok {
+			return false
+		}
+		_, err := n.Int64()
+		return err == nil
+	}
+	return false
+}
+
+func typeName(doc interface{}) string {
+	switch doc.(type) {
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case json.Number:
+		return "number"
+	case nil:
+		return "null"
+	}
+	return fmt.Sprintf("%T", doc)
+}
+
+func containsValue(values []interface{}, doc interface{}) bool {
+	for _, value := range values {
+		if equalValues(value, doc) {
+			return true
+		}
+	}
+	return false
+}
+
+// equalValues compares a schema value, decoded with float64 numbers, with a
+// document value, decoded with json.Number
+func equalValues(schemaValue, doc interface{}) bool {
+	if n, ok := doc.(json.Number); ok {
+		f, err := n.Float64()
+		return err == nil && reflect.DeepEqual(schemaValue, f)
+	}
+	return reflect.DeepEqual(schemaValue, doc)
+}
+
+func describeAlternatives(alternatives []interface{}) string {
+	parts := make([]string, 0, len(alternatives))
+	for _, alt := range alternatives {
+		if sub, ok := alt.(map[string]interface{}); ok {
+			if required, ok := sub["required"].([]interface{}); ok {
+				parts = append(parts, fmt.Sprintf("required %v", required))
+				continue
+			}
+		}
+		parts = append(parts, "a schema")
+	}
+	return strings.Join(parts, " or ")
+}
+
+func truncate(s string) string {
+	const max = 40
+	if len(s) > max {
+		return s[:max] + "..."
+	}
+	return s
+}
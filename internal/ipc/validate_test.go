@@ -0,0 +1,172 @@
+// Copyright (c) 2026 dotandev
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipc
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateRequest(t *testing.T) {
+	wasm := "contract.wasm"
+	valid := SimulationRequestSchema{
+		Version:       ProtocolVersion,
+		RequestID:     "sim-1",
+		Network:       Testnet,
+		Xdr:           "AAAA",
+		ResultMetaXdr: "AAAB",
+		LedgerEntries: map[string]string{"AAAC": "AAAD"},
+		Timestamp:     1700000000,
+		AuthTraceOpts: &AuthTraceOptions{Enabled: true, MaxEventDepth: 3},
+	}
+	data, err := valid.Marshal()
+	require.NoError(t, err)
+	assert.NoError(t, ValidateRequest(data))
+
+	local := SimulationRequestSchema{Version: ProtocolVersion, RequestID: "sim-2", WasmPath: &wasm}
+	data, err = local.Marshal()
+	require.NoError(t, err)
+	assert.NoError(t, ValidateRequest(data))
+
+	tests := []struct {
+		name    string
+		payload string
+		want    string
+	}{
+		{"not JSON", `{"version":`, "invalid JSON"},
+		{"missing request ID", `{"version":"1.0","wasm_path":"a.wasm"}`, `missing required property "request_id"`},
+		{"bad version", `{"version":"v1","request_id":"r","wasm_path":"a.wasm"}`, "/version"},
+		{"no payload", `{"version":"1.0","request_id":"r"}`, "must match at least one of"},
+		{"bad XDR", `{"version":"1.0","request_id":"r","xdr":"not base64!","result_meta_xdr":"AAAA"}`, "/xdr"},
+		{"bad ledger key", `{"version":"1.0","request_id":"r","wasm_path":"a","ledger_entries":{"k-1":"AAAA"}}`, "/ledger_entries/k-1"},
+		{"negative timestamp", `{"version":"1.0","request_id":"r","wasm_path":"a","timestamp":-1}`, "at least 0"},
+		{"unknown property", `{"version":"1.0","request_id":"r","wasm_path":"a","envelope_xdr":"AAAA"}`, `unknown property "envelope_xdr"`},
+		{"wrong type", `{"version":"1.0","request_id":"r","wasm_path":"a","profile":"yes"}`, "expected boolean, got string"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateRequest([]byte(tt.payload))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.want)
+		})
+	}
+}
+
+// testEvent is a fn_call diagnostic event without arguments
+const testEvent = `{"type":"diagnostic","contract_id":"CDLZFC3SYJYDZT7K67VZ75HPJVIEUVNIXF47ZG2FB2RMQQVU2HHGCYSC","topics":["AAAADwAAAAdmbl9jYWxsAA=="],"data":"AAAAAQ==","in_successful_contract_call":true,"call_depth":1}`
+
+func TestValidateResponse(t *testing.T) {
+	assert.NoError(t, ValidateResponse([]byte(
+		`{"version":"1.0","request_id":"r","success":true,"result":{"events":[`+testEvent+`],"fee_charged":"100"}}`)))
+	assert.NoError(t, ValidateResponse([]byte(
+		`{"version":"1.0","request_id":"r","success":false,"error":{"code":"simulation_failed","message":"boom"}}`)))
+
+	err := ValidateResponse([]byte(`{"version":"1.0","request_id":"r","success":true}`))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `missing required property "result"`)
+
+	err = ValidateResponse([]byte(`{"version":"1.0","request_id":"r","success":false}`))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `missing required property "error"`)
+
+	// Events as opaque strings
+	err = ValidateResponse([]byte(`{"version":"1.0","request_id":"r","success":true,"result":{"events":["e"]}}`))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "/result/events/0: expected object, got string")
+
+	err = ValidateResponse([]byte(`{"version":"1.0","request_id":"r","success":true,"result":{"events":[{"type":"host","topics":[],"data":"AAAAAQ==","in_successful_contract_call":true,"call_depth":0}]}}`))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "/result/events/0/type: must be one of")
+
+	// Responses of simulators predating the protocol
+	err = ValidateResponse([]byte(`{"status":"success","events":[]}`))
+	var validationErr *ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, responseSchemaFile, validationErr.Schema)
+	assert.Contains(t, validationErr.Violations, `/: unknown property "status"`)
+}
+
+func TestValidateHandshake(t *testing.T) {
+	assert.NoError(t, ValidateHandshake([]byte(`{"protocol_versions":["1.0"],"simulator_version":"0.1.0"}`)))
+	assert.Error(t, ValidateHandshake([]byte(`{"protocol_versions":[]}`)))
+	assert.Error(t, ValidateHandshake([]byte(`{"status":"error","error":"Invalid JSON"}`)))
+}
+
+func TestSchemasMatchDocs(t *testing.T) {
+	docs, err := filepath.Glob("../../docs/schema/*.schema.json")
+	require.NoError(t, err)
+	embedded, err := fs.Glob(schemaFS, "schema/*.schema.json")
+	require.NoError(t, err)
+	require.Len(t, embedded, len(docs), "run go generate ./internal/ipc")
+
+	for _, path := range docs {
+		want, err := os.ReadFile(path)
+		require.NoError(t, err)
+		got, err := fs.ReadFile(schemaFS, "schema/"+filepath.Base(path))
+		require.NoError(t, err, "run go generate ./internal/ipc")
+		assert.Equal(t, string(want), string(got), "%s is out of date, run go generate ./internal/ipc", filepath.Base(path))
+	}
+}
+
+func TestLoadSchemas_Keywords(t *testing.T) {
+	load := func(schema string) error {
+		_, err := loadSchemas(fstest.MapFS{"test.schema.json": {Data: []byte(schema)}})
+		return err
+	}
+
+	// Every supported keyword, in the forms the validator implements
+	assert.NoError(t, load(`{
+		"$schema": "http://json-schema.org/draft-07/schema#",
+		"$id": "test", "title": "t", "description": "d", "$comment": "c",
+		"definitions": {"Name": {"type": "string", "minLength": 1, "pattern": "^[a-z]+$", "contentEncoding": "base64"}},
+		"type": "object",
+		"required": ["a"],
+		"properties": {
+			"a": {"$ref": "#/definitions/Name"},
+			"b": {"type": "array", "items": {"type": "integer", "minimum": 0}, "minItems": 1},
+			"c": {"enum": ["x", "y"], "default": "x", "examples": ["y"]},
+			"d": {"const": true}
+		},
+		"propertyNames": {"pattern": "^[a-z]$"},
+		"additionalProperties": false,
+		"allOf": [{"if": {"required": ["d"]}, "then": {"required": ["c"]}, "else": {}}],
+		"anyOf": [{"required": ["a"]}, {"required": ["b"]}]
+	}`))
+
+	tests := []struct {
+		name   string
+		schema string
+		want   string
+	}{
+		{"unknown keyword", `{"type": "string", "maxLength": 3}`, `#: keyword "maxLength" is not supported`},
+		{"nested unknown keyword", `{"properties": {"a": {"oneOf": []}}}`, `#/properties/a: keyword "oneOf" is not supported`},
+		{"list of types", `{"type": ["string", "null"]}`, "#/type: must be a string"},
+		{"tuple items", `{"items": [{"type": "string"}]}`, "#/items: must be a schema object"},
+		{"unresolved ref", `{"$ref": "#/definitions/Missing"}`, `unresolved schema reference "#/definitions/Missing"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := load(tt.schema)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.want)
+		})
+	}
+}
@@ -116,6 +116,19 @@ func (l *Localizer) Get(key string) string {
 	return key
 }
 
+// Lookup returns the message for key in the current language, falling back
+// to the default language, and whether there is one
+func (l *Localizer) Lookup(key string) (string, bool) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	if msg, ok := l.messages[l.lang][key]; ok {
+		return msg, true
+	}
+	msg, ok := l.messages[l.defaultLang][key]
+	return msg, ok
+}
+
 func (l *Localizer) GetForLang(lang Language, key string) string {
 	l.mu.RLock()
 	defer l.mu.RUnlock()
@@ -153,6 +166,10 @@ func Get(key string) string {
 	return globalLocalizer.Get(key)
 }
 
+func Lookup(key string) (string, bool) {
+	return globalLocalizer.Lookup(key)
+}
+
 func Translate(key string, args ...interface{}) string {
 	return globalLocalizer.Translate(key, args...)
 }
@@ -161,6 +178,10 @@ func SetLanguage(lang Language) error {
 	return globalLocalizer.SetLanguage(lang)
 }
 
+func GetLanguage() Language {
+	return globalLocalizer.GetLanguage()
+}
+
 func RegisterMessages(lang Language, messages map[string]string) error {
 	return globalLocalizer.RegisterMessages(lang, messages)
 }
@@ -129,3 +129,20 @@ func TestMissingKeyFallback(t *testing.T) {
 		t.Errorf("expected key as fallback, got: %s", result)
 	}
 }
+
+func TestLookup(t *testing.T) {
+	l := New()
+	_ = l.RegisterMessages(English, map[string]string{"only.english": "hello"})
+	_ = l.RegisterMessages(Spanish, map[string]string{"both": "hola"})
+	_ = l.SetLanguage(Spanish)
+
+	if msg, ok := l.Lookup("both"); !ok || msg != "hola" {
+		t.Errorf("expected hola, got %q %v", msg, ok)
+	}
+	if msg, ok := l.Lookup("only.english"); !ok || msg != "hello" {
+		t.Errorf("expected fallback to hello, got %q %v", msg, ok)
+	}
+	if _, ok := l.Lookup("missing"); ok {
+		t.Error("expected missing key not to be found")
+	}
+}
@@ -156,5 +156,11 @@ func LoadTranslations() error {
 	if err := RegisterMessages(Chinese, ChineseMessages); err != nil {
 		return err
 	}
-	return nil
+	if err := RegisterMessages(English, EnglishScErrorMessages); err != nil {
+		return err
+	}
+	if err := RegisterMessages(Spanish, SpanishScErrorMessages); err != nil {
+		return err
+	}
+	return RegisterMessages(Chinese, ChineseScErrorMessages)
 }
@@ -0,0 +1,309 @@
+// Copyright (c) 2026 dotandev
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package localization
+
+// The Soroban error catalog. An ScError is explained by the sentence of its
+// type followed by the sentence of its code, with the remediation of its
+// type, unless the combination has messages of its own under
+// "scerror.<type>.<code>" and "scerror.<type>.<code>.fix".
+// "result.<code>.fix" holds the remediation of an operation result code.
+
+var EnglishScErrorMessages = map[string]string{
+	"scerror.type.wasm_vm": "Raised by the Wasm virtual machine running the contract.",
+	"scerror.type.context": "Raised by the host's call stack and invocation context.",
+	"scerror.type.storage": "Raised by contract storage or the transaction's footprint.",
+	"scerror.type.object":  "Raised by a host object such as a Vec, Map, Bytes or String.",
+	"scerror.type.crypto":  "Raised by a cryptographic host function.",
+	"scerror.type.events":  "Raised while emitting a contract event.",
+	"scerror.type.budget":  "Raised by the CPU instruction and memory budget.",
+	"scerror.type.value":   "Raised while converting or checking a value.",
+	"scerror.type.auth":    "Raised by Soroban authorization (require_auth).",
+
+	"scerror.code.arith_domain":    "An arithmetic operation overflowed or was outside its domain.",
+	"scerror.code.index_bounds":    "An index was out of bounds.",
+	"scerror.code.invalid_input":   "An input was malformed or invalid.",
+	"scerror.code.missing_value":   "A value expected to exist is missing.",
+	"scerror.code.existing_value":  "A value expected to be absent already exists.",
+	"scerror.code.exceeded_limit":  "A limit was exceeded.",
+	"scerror.code.invalid_action":  "The action is not allowed in this state.",
+	"scerror.code.internal_error":  "The host hit an internal error.",
+	"scerror.code.unexpected_type": "A value had an unexpected type.",
+	"scerror.code.unexpected_size": "A value had an unexpected size.",
+
+	"scerror.fix.contract": "Look the code up in the contract's error enum; erst debug names it when the contract's interface is known.",
+	"scerror.fix.wasm_vm":  "Read the diagnostic events before the failure, and map the trap to a source line with --wasm-debug.",
+	"scerror.fix.context":  "Follow the calls leading to the failing frame in the trace.",
+	"scerror.fix.storage":  "Simulate the transaction again to refresh its footprint, and check the keys the contract reads exist.",
+	"scerror.fix.object":   "Check the sizes, indices and keys the contract uses on host objects.",
+	"scerror.fix.crypto":   "Check the keys, signatures and hashes passed to the crypto function are well formed.",
+	"scerror.fix.events":   "Check the topics and data of the events the contract emits.",
+	"scerror.fix.budget":   "Simulate the transaction again for current resource limits, or reduce the work the contract does.",
+	"scerror.fix.value":    "Check the arguments match the types in the contract's interface.",
+	"scerror.fix.auth":     "Check the authorization entries cover every address that calls require_auth, with valid signatures.",
+
+	"scerror.contract.code": "The contract returned its own error #%d.",
+	"scerror.contract":      "The contract returned an error of its own.",
+
+	"scerror.wasm_vm.invalid_action":      "The contract trapped: it panicked, reached an unreachable instruction or failed a checked operation.",
+	"scerror.wasm_vm.invalid_action.fix":  "Read the diagnostic events and logs emitted before the trap, and map the trap to a Rust source line with --wasm-debug.",
+	"scerror.wasm_vm.exceeded_limit":      "The contract exceeded a Wasm VM limit such as stack depth, memory pages or table size.",
+	"scerror.wasm_vm.exceeded_limit.fix":  "Reduce recursion and large allocations in the contract.",
+	"scerror.wasm_vm.invalid_input":       "The contract's Wasm module failed validation or uses unsupported features.",
+	"scerror.wasm_vm.invalid_input.fix":   "Rebuild the contract for wasm32-unknown-unknown with a supported soroban-sdk and upload it again.",
+	"scerror.wasm_vm.missing_value":       "The called function is not exported by the contract.",
+	"scerror.wasm_vm.missing_value.fix":   "Check the function name against the contract's interface.",
+	"scerror.wasm_vm.unexpected_type":     "The function was called with, or returned, values of the wrong Wasm type.",
+	"scerror.wasm_vm.unexpected_type.fix": "Match the arguments to the function's signature in the contract's interface.",
+
+	"scerror.context.invalid_action":     "A call failed inside a nested contract invocation, or the contract made a re-entrant call.",
+	"scerror.context.invalid_action.fix": "Find the innermost failing call in the trace; its error is the root cause.",
+	"scerror.context.exceeded_limit":     "The limit on nested contract calls was exceeded.",
+	"scerror.context.exceeded_limit.fix": "Reduce the nesting of cross-contract calls.",
+	"scerror.context.invalid_input":      "A host function was called with invalid arguments.",
+	"scerror.context.invalid_input.fix":  "Check the arguments of the host function called just before the failure in the trace.",
+
+	"scerror.storage.missing_value":      "The contract read a ledger entry that does not exist, such as an unset key or an expired temporary entry.",
+	"scerror.storage.missing_value.fix":  "Write the key before reading it, or check it with has(); if the entry was archived, run RestoreFootprint.",
+	"scerror.storage.exceeded_limit":     "The contract accessed a ledger entry outside the transaction's footprint, or wrote to a read-only entry.",
+	"scerror.storage.exceeded_limit.fix": "Simulate the transaction again so the footprint covers every key the contract touches.",
+	"scerror.storage.existing_value":     "The ledger entry being created already exists, e.g. a contract deployed again with the same salt.",
+	"scerror.storage.existing_value.fix": "Use a new salt, or update the existing entry instead.",
+	"scerror.storage.invalid_action":     "The storage operation is not allowed, e.g. extending a TTL past the maximum or accessing an archived entry.",
+	"scerror.storage.invalid_action.fix": "Run RestoreFootprint on archived entries, and keep TTL extensions within the network maximum.",
+
+	"scerror.object.index_bounds":      "The contract indexed a Vec, Bytes or String out of bounds.",
+	"scerror.object.index_bounds.fix":  "Check the length before indexing, or use get(), which returns an Option.",
+	"scerror.object.missing_value":     "The contract looked up a key missing from a Map.",
+	"scerror.object.missing_value.fix": "Check the key with contains_key(), or use get(), which returns an Option.",
+
+	"scerror.budget.exceeded_limit":     "The invocation used up its CPU instruction or memory budget.",
+	"scerror.budget.exceeded_limit.fix": "Simulate again and raise the instructions in the transaction's resources, or reduce loops and storage reads in the contract.",
+
+	"scerror.value.unexpected_type":     "A value could not be converted to the type the contract expects, usually a mistyped argument.",
+	"scerror.value.unexpected_type.fix": "Pass arguments in the types of the contract's interface; erst decode shows the arguments sent.",
+	"scerror.value.arith_domain":        "An integer operation on host values overflowed.",
+	"scerror.value.arith_domain.fix":    "Check the amounts involved and use checked arithmetic in the contract.",
+	"scerror.value.invalid_input":       "A value is malformed, e.g. a symbol with invalid characters.",
+	"scerror.value.invalid_input.fix":   "Check the values the contract builds and the arguments passed to it.",
+
+	"scerror.auth.invalid_action":     "require_auth failed: no authorization entry matches the address and the invocation.",
+	"scerror.auth.invalid_action.fix": "Simulate to get the authorization entries, sign them for every address that calls require_auth, and check the invocation tree matches the call.",
+	"scerror.auth.invalid_input":      "An authorization signature is malformed or does not verify.",
+	"scerror.auth.invalid_input.fix":  "Sign the authorization entry for this network's passphrase with the address's key.",
+	"scerror.auth.existing_value":     "The authorization nonce was already used.",
+	"scerror.auth.existing_value.fix": "Simulate again for a fresh nonce and sign the new entries.",
+
+	"result.invoke_host_function_malformed.fix":                   "Simulate the transaction again to rebuild its footprint and resources.",
+	"result.invoke_host_function_trapped.fix":                     "Replay it with erst debug and read the diagnostic events of the failing call.",
+	"result.invoke_host_function_resource_limit_exceeded.fix":     "Simulate again and raise the instructions, read or write bytes in the transaction's resources.",
+	"result.invoke_host_function_entry_archived.fix":              "Run RestoreFootprint on the archived entries, then submit the transaction again.",
+	"result.invoke_host_function_insufficient_refundable_fee.fix": "Simulate again and raise the resource fee.",
+	"result.extend_footprint_ttl_resource_limit_exceeded.fix":     "Simulate again and raise the read bytes in the transaction's resources.",
+	"result.extend_footprint_ttl_insufficient_refundable_fee.fix": "Simulate again and raise the resource fee.",
+	"result.restore_footprint_resource_limit_exceeded.fix":        "Simulate again and raise the read or write bytes in the transaction's resources.",
+	"result.restore_footprint_insufficient_refundable_fee.fix":    "Simulate again and raise the resource fee.",
+}
+
+var SpanishScErrorMessages = map[string]string{
+	"scerror.type.wasm_vm": "Generado por la máquina virtual Wasm que ejecuta el contrato.",
+	"scerror.type.context": "Generado por la pila de llamadas y el contexto de invocación del host.",
+	"scerror.type.storage": "Generado por el almacenamiento del contrato o el footprint de la transacción.",
+	"scerror.type.object":  "Generado por un objeto del host como Vec, Map, Bytes o String.",
+	"scerror.type.crypto":  "Generado por una función criptográfica del host.",
+	"scerror.type.events":  "Generado al emitir un evento del contrato.",
+	"scerror.type.budget":  "Generado por el presupuesto de instrucciones de CPU y memoria.",
+	"scerror.type.value":   "Generado al convertir o comprobar un valor.",
+	"scerror.type.auth":    "Generado por la autorización de Soroban (require_auth).",
+
+	"scerror.code.arith_domain":    "Una operación aritmética desbordó o quedó fuera de su dominio.",
+	"scerror.code.index_bounds":    "Un índice estaba fuera de rango.",
+	"scerror.code.invalid_input":   "Una entrada estaba mal formada o no era válida.",
+	"scerror.code.missing_value":   "Falta un valor que debía existir.",
+	"scerror.code.existing_value":  "Ya existe un valor que no debía existir.",
+	"scerror.code.exceeded_limit":  "Se superó un límite.",
+	"scerror.code.invalid_action":  "La acción no está permitida en este estado.",
+	"scerror.code.internal_error":  "El host sufrió un error interno.",
+	"scerror.code.unexpected_type": "Un valor tenía un tipo inesperado.",
+	"scerror.code.unexpected_size": "Un valor tenía un tamaño inesperado.",
+
+	"scerror.fix.contract": "Busque el código en el enum de errores del contrato; erst debug lo nombra cuando conoce la interfaz del contrato.",
+	"scerror.fix.wasm_vm":  "Lea los eventos de diagnóstico anteriores al fallo y asocie el trap a una línea de código con --wasm-debug.",
+	"scerror.fix.context":  "Siga en la traza las llamadas que llevan al marco que falla.",
+	"scerror.fix.storage":  "Simule la transacción de nuevo para actualizar su footprint y compruebe que existen las claves que lee el contrato.",
+	"scerror.fix.object":   "Compruebe los tamaños, índices y claves que el contrato usa en los objetos del host.",
+	"scerror.fix.crypto":   "Compruebe que las claves, firmas y hashes pasados a la función criptográfica están bien formados.",
+	"scerror.fix.events":   "Compruebe los temas y datos de los eventos que emite el contrato.",
+	"scerror.fix.budget":   "Simule la transacción de nuevo para obtener los límites de recursos actuales, o reduzca el trabajo del contrato.",
+	"scerror.fix.value":    "Compruebe que los argumentos coinciden con los tipos de la interfaz del contrato.",
+	"scerror.fix.auth":     "Compruebe que las entradas de autorización cubren cada dirección que llama a require_auth, con firmas válidas.",
+
+	"scerror.contract.code": "El contrato devolvió su propio error #%d.",
+	"scerror.contract":      "El contrato devolvió un error propio.",
+
+	"scerror.wasm_vm.invalid_action":      "El contrato produjo un trap: entró en pánico, alcanzó una instrucción inalcanzable o falló una operación comprobada.",
+	"scerror.wasm_vm.invalid_action.fix":  "Lea los eventos de diagnóstico y los logs emitidos antes del trap, y asócielo a una línea de código Rust con --wasm-debug.",
+	"scerror.wasm_vm.exceeded_limit":      "El contrato superó un límite de la VM Wasm, como la profundidad de pila, las páginas de memoria o el tamaño de tabla.",
+	"scerror.wasm_vm.exceeded_limit.fix":  "Reduzca la recursión y las asignaciones grandes en el contrato.",
+	"scerror.wasm_vm.invalid_input":       "El módulo Wasm del contrato no pasó la validación o usa funciones no soportadas.",
+	"scerror.wasm_vm.invalid_input.fix":   "Recompile el contrato para wasm32-unknown-unknown con un soroban-sdk soportado y súbalo de nuevo.",
+	"scerror.wasm_vm.missing_value":       "El contrato no exporta la función llamada.",
+	"scerror.wasm_vm.missing_value.fix":   "Compruebe el nombre de la función en la interfaz del contrato.",
+	"scerror.wasm_vm.unexpected_type":     "La función se llamó con valores, o devolvió valores, de un tipo Wasm incorrecto.",
+	"scerror.wasm_vm.unexpected_type.fix": "Ajuste los argumentos a la firma de la función en la interfaz del contrato.",
+
+	"scerror.context.invalid_action":     "Una llamada falló dentro de una invocación anidada, o el contrato hizo una llamada reentrante.",
+	"scerror.context.invalid_action.fix": "Busque en la traza la llamada fallida más interna; su error es la causa raíz.",
+	"scerror.context.exceeded_limit":     "Se superó el límite de llamadas anidadas entre contratos.",
+	"scerror.context.exceeded_limit.fix": "Reduzca el anidamiento de llamadas entre contratos.",
+	"scerror.context.invalid_input":      "Se llamó a una función del host con argumentos no válidos.",
+	"scerror.context.invalid_input.fix":  "Compruebe en la traza los argumentos de la función del host llamada justo antes del fallo.",
+
+	"scerror.storage.missing_value":      "El contrato leyó una entrada del ledger que no existe, como una clave sin valor o una entrada temporal expirada.",
+	"scerror.storage.missing_value.fix":  "Escriba la clave antes de leerla, o compruébela con has(); si la entrada fue archivada, ejecute RestoreFootprint.",
+	"scerror.storage.exceeded_limit":     "El contrato accedió a una entrada del ledger fuera del footprint de la transacción, o escribió en una entrada de solo lectura.",
+	"scerror.storage.exceeded_limit.fix": "Simule la transacción de nuevo para que el footprint cubra todas las claves que usa el contrato.",
+	"scerror.storage.existing_value":     "La entrada del ledger que se crea ya existe, p. ej. un contrato desplegado de nuevo con la misma sal.",
+	"scerror.storage.existing_value.fix": "Use una sal nueva, o actualice la entrada existente.",
+	"scerror.storage.invalid_action":     "La operación de almacenamiento no está permitida, p. ej. extender un TTL más allá del máximo o acceder a una entrada archivada.",
+	"scerror.storage.invalid_action.fix": "Ejecute RestoreFootprint sobre las entradas archivadas y mantenga las extensiones de TTL dentro del máximo de la red.",
+
+	"scerror.object.index_bounds":      "El contrato accedió a un Vec, Bytes o String fuera de rango.",
+	"scerror.object.index_bounds.fix":  "Compruebe la longitud antes de indexar, o use get(), que devuelve un Option.",
+	"scerror.object.missing_value":     "El contrato buscó una clave que no está en un Map.",
+	"scerror.object.missing_value.fix": "Compruebe la clave con contains_key(), o use get(), que devuelve un Option.",
+
+	"scerror.budget.exceeded_limit":     "La invocación agotó su presupuesto de instrucciones de CPU o de memoria.",
+	"scerror.budget.exceeded_limit.fix": "Simule de nuevo y aumente las instrucciones en los recursos de la transacción, o reduzca los bucles y lecturas de almacenamiento del contrato.",
+
+	"scerror.value.unexpected_type":     "Un valor no pudo convertirse al tipo que espera el contrato, normalmente un argumento con el tipo incorrecto.",
+	"scerror.value.unexpected_type.fix": "Pase los argumentos con los tipos de la interfaz del contrato; erst decode muestra los argumentos enviados.",
+	"scerror.value.arith_domain":        "Una operación entera sobre valores del host desbordó.",
+	"scerror.value.arith_domain.fix":    "Compruebe las cantidades implicadas y use aritmética comprobada en el contrato.",
+	"scerror.value.invalid_input":       "Un valor está mal formado, p. ej. un símbolo con caracteres no válidos.",
+	"scerror.value.invalid_input.fix":   "Compruebe los valores que construye el contrato y los argumentos que recibe.",
+
+	"scerror.auth.invalid_action":     "require_auth falló: ninguna entrada de autorización coincide con la dirección y la invocación.",
+	"scerror.auth.invalid_action.fix": "Simule para obtener las entradas de autorización, fírmelas para cada dirección que llama a require_auth y compruebe que el árbol de invocación coincide con la llamada.",
+	"scerror.auth.invalid_input":      "Una firma de autorización está mal formada o no se verifica.",
+	"scerror.auth.invalid_input.fix":  "Firme la entrada de autorización para la frase de red correspondiente con la clave de la dirección.",
+	"scerror.auth.existing_value":     "El nonce de autorización ya se usó.",
+	"scerror.auth.existing_value.fix": "Simule de nuevo para obtener un nonce nuevo y firme las nuevas entradas.",
+
+	"result.invoke_host_function_malformed.fix":                   "Simule la transacción de nuevo para reconstruir su footprint y sus recursos.",
+	"result.invoke_host_function_trapped.fix":                     "Reprodúzcala con erst debug y lea los eventos de diagnóstico de la llamada que falla.",
+	"result.invoke_host_function_resource_limit_exceeded.fix":     "Simule de nuevo y aumente las instrucciones y los bytes de lectura o escritura en los recursos de la transacción.",
+	"result.invoke_host_function_entry_archived.fix":              "Ejecute RestoreFootprint sobre las entradas archivadas y envíe la transacción de nuevo.",
+	"result.invoke_host_function_insufficient_refundable_fee.fix": "Simule de nuevo y aumente la comisión de recursos.",
+	"result.extend_footprint_ttl_resource_limit_exceeded.fix":     "Simule de nuevo y aumente los bytes de lectura en los recursos de la transacción.",
+	"result.extend_footprint_ttl_insufficient_refundable_fee.fix": "Simule de nuevo y aumente la comisión de recursos.",
+	"result.restore_footprint_resource_limit_exceeded.fix":        "Simule de nuevo y aumente los bytes de lectura o escritura en los recursos de la transacción.",
+	"result.restore_footprint_insufficient_refundable_fee.fix":    "Simule de nuevo y aumente la comisión de recursos.",
+}
+
+var ChineseScErrorMessages = map[string]string{
+	"scerror.type.wasm_vm": "由运行合约的 Wasm 虚拟机引发。",
+	"scerror.type.context": "由主机的调用栈和调用上下文引发。",
+	"scerror.type.storage": "由合约存储或交易的 footprint 引发。",
+	"scerror.type.object":  "由 Vec、Map、Bytes 或 String 等主机对象引发。",
+	"scerror.type.crypto":  "由主机的密码学函数引发。",
+	"scerror.type.events":  "在发出合约事件时引发。",
+	"scerror.type.budget":  "由 CPU 指令和内存预算引发。",
+	"scerror.type.value":   "在转换或检查值时引发。",
+	"scerror.type.auth":    "由 Soroban 授权 (require_auth) 引发。",
+
+	"scerror.code.arith_domain":    "算术运算溢出或超出定义域。",
+	"scerror.code.index_bounds":    "索引越界。",
+	"scerror.code.invalid_input":   "输入格式错误或无效。",
+	"scerror.code.missing_value":   "应当存在的值缺失。",
+	"scerror.code.existing_value":  "应当不存在的值已经存在。",
+	"scerror.code.exceeded_limit":  "超出了限制。",
+	"scerror.code.invalid_action":  "当前状态下不允许该操作。",
+	"scerror.code.internal_error":  "主机发生内部错误。",
+	"scerror.code.unexpected_type": "值的类型不符合预期。",
+	"scerror.code.unexpected_size": "值的大小不符合预期。",
+
+	"scerror.fix.contract": "在合约的错误枚举中查找该代码；已知合约接口时 erst debug 会显示其名称。",
+	"scerror.fix.wasm_vm":  "查看失败前的诊断事件，并使用 --wasm-debug 将 trap 映射到源代码行。",
+	"scerror.fix.context":  "在追踪中跟随通向失败帧的调用。",
+	"scerror.fix.storage":  "重新模拟交易以刷新其 footprint，并检查合约读取的键是否存在。",
+	"scerror.fix.object":   "检查合约在主机对象上使用的大小、索引和键。",
+	"scerror.fix.crypto":   "检查传给密码学函数的密钥、签名和哈希格式是否正确。",
+	"scerror.fix.events":   "检查合约发出的事件的主题和数据。",
+	"scerror.fix.budget":   "重新模拟交易以获取当前的资源限制，或减少合约的工作量。",
+	"scerror.fix.value":    "检查参数是否与合约接口中的类型一致。",
+	"scerror.fix.auth":     "检查授权条目是否覆盖每个调用 require_auth 的地址，且签名有效。",
+
+	"scerror.contract.code": "合约返回了自定义错误 #%d。",
+	"scerror.contract":      "合约返回了自定义错误。",
+
+	"scerror.wasm_vm.invalid_action":      "合约触发了 trap：发生 panic、执行到 unreachable 指令或检查运算失败。",
+	"scerror.wasm_vm.invalid_action.fix":  "查看 trap 之前发出的诊断事件和日志，并使用 --wasm-debug 将 trap 映射到 Rust 源代码行。",
+	"scerror.wasm_vm.exceeded_limit":      "合约超出了 Wasm 虚拟机的限制，例如栈深度、内存页数或表大小。",
+	"scerror.wasm_vm.exceeded_limit.fix":  "减少合约中的递归和大块内存分配。",
+	"scerror.wasm_vm.invalid_input":       "合约的 Wasm 模块未通过验证或使用了不支持的特性。",
+	"scerror.wasm_vm.invalid_input.fix":   "使用受支持的 soroban-sdk 为 wasm32-unknown-unknown 重新编译合约并重新上传。",
+	"scerror.wasm_vm.missing_value":       "合约没有导出被调用的函数。",
+	"scerror.wasm_vm.missing_value.fix":   "对照合约接口检查函数名称。",
+	"scerror.wasm_vm.unexpected_type":     "调用函数时传入或返回了错误 Wasm 类型的值。",
+	"scerror.wasm_vm.unexpected_type.fix": "使参数与合约接口中的函数签名一致。",
+
+	"scerror.context.invalid_action":     "嵌套的合约调用中有调用失败，或合约进行了重入调用。",
+	"scerror.context.invalid_action.fix": "在追踪中找到最内层的失败调用；它的错误就是根本原因。",
+	"scerror.context.exceeded_limit":     "超出了合约嵌套调用的限制。",
+	"scerror.context.exceeded_limit.fix": "减少跨合约调用的嵌套层数。",
+	"scerror.context.invalid_input":      "调用主机函数时使用了无效参数。",
+	"scerror.context.invalid_input.fix":  "在追踪中检查失败前调用的主机函数的参数。",
+
+	"scerror.storage.missing_value":      "合约读取了不存在的账本条目，例如未设置的键或已过期的临时条目。",
+	"scerror.storage.missing_value.fix":  "先写入再读取该键，或用 has() 检查；如果条目已归档，请运行 RestoreFootprint。",
+	"scerror.storage.exceeded_limit":     "合约访问了交易 footprint 之外的账本条目，或写入了只读条目。",
+	"scerror.storage.exceeded_limit.fix": "重新模拟交易，使 footprint 覆盖合约访问的所有键。",
+	"scerror.storage.existing_value":     "要创建的账本条目已存在，例如使用相同 salt 再次部署合约。",
+	"scerror.storage.existing_value.fix": "使用新的 salt，或改为更新已有条目。",
+	"scerror.storage.invalid_action":     "不允许该存储操作，例如将 TTL 延长到超过最大值或访问已归档的条目。",
+	"scerror.storage.invalid_action.fix": "对已归档的条目运行 RestoreFootprint，并将 TTL 延长控制在网络最大值以内。",
+
+	"scerror.object.index_bounds":      "合约对 Vec、Bytes 或 String 的索引越界。",
+	"scerror.object.index_bounds.fix":  "在索引前检查长度，或使用返回 Option 的 get()。",
+	"scerror.object.missing_value":     "合约查找的键不在 Map 中。",
+	"scerror.object.missing_value.fix": "用 contains_key() 检查键，或使用返回 Option 的 get()。",
+
+	"scerror.budget.exceeded_limit":     "调用耗尽了 CPU 指令或内存预算。",
+	"scerror.budget.exceeded_limit.fix": "重新模拟并提高交易资源中的指令数，或减少合约中的循环和存储读取。",
+
+	"scerror.value.unexpected_type":     "值无法转换为合约期望的类型，通常是参数类型错误。",
+	"scerror.value.unexpected_type.fix": "按合约接口中的类型传递参数；erst decode 会显示发送的参数。",
+	"scerror.value.arith_domain":        "对主机值的整数运算溢出。",
+	"scerror.value.arith_domain.fix":    "检查涉及的金额，并在合约中使用带检查的算术运算。",
+	"scerror.value.invalid_input":       "值格式错误，例如包含无效字符的 symbol。",
+	"scerror.value.invalid_input.fix":   "检查合约构造的值及传给合约的参数。",
+
+	"scerror.auth.invalid_action":     "require_auth 失败：没有与该地址和调用匹配的授权条目。",
+	"scerror.auth.invalid_action.fix": "通过模拟获取授权条目，为每个调用 require_auth 的地址签名，并检查调用树与实际调用一致。",
+	"scerror.auth.invalid_input":      "授权签名格式错误或验证失败。",
+	"scerror.auth.invalid_input.fix":  "使用该地址的密钥，针对本网络的 passphrase 对授权条目签名。",
+	"scerror.auth.existing_value":     "授权 nonce 已被使用。",
+	"scerror.auth.existing_value.fix": "重新模拟以获取新的 nonce，并对新的条目签名。",
+
+	"result.invoke_host_function_malformed.fix":                   "重新模拟交易以重建其 footprint 和资源。",
+	"result.invoke_host_function_trapped.fix":                     "使用 erst debug 重放交易，并查看失败调用的诊断事件。",
+	"result.invoke_host_function_resource_limit_exceeded.fix":     "重新模拟并提高交易资源中的指令数、读取或写入字节数。",
+	"result.invoke_host_function_entry_archived.fix":              "对已归档的条目运行 RestoreFootprint，然后重新提交交易。",
+	"result.invoke_host_function_insufficient_refundable_fee.fix": "重新模拟并提高资源费用。",
+	"result.extend_footprint_ttl_resource_limit_exceeded.fix":     "重新模拟并提高交易资源中的读取字节数。",
+	"result.extend_footprint_ttl_insufficient_refundable_fee.fix": "重新模拟并提高资源费用。",
+	"result.restore_footprint_resource_limit_exceeded.fix":        "重新模拟并提高交易资源中的读取或写入字节数。",
+	"result.restore_footprint_insufficient_refundable_fee.fix":    "重新模拟并提高资源费用。",
+}
@@ -15,19 +15,17 @@
 package rpc
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
 	"net/http"
 	"os"
 	"time"
 
 	"github.com/dotandev/hintents/internal/logger"
-	"github.com/schollz/progressbar/v3"
 	"github.com/dotandev/hintents/internal/telemetry"
 	"github.com/stellar/go/clients/horizonclient"
+	hProtocol "github.com/stellar/go/protocols/horizon"
 	"go.opentelemetry.io/otel/attribute"
 )
 
@@ -68,12 +66,40 @@ func (t *authTransport) RoundTrip(req *http.Request) (*http.Response, error) {
 	}
 	return t.transport.RoundTrip(req)
 }
+
 // NetworkConfig represents a Stellar network configuration
 type NetworkConfig struct {
-	Name              string
-	HorizonURL        string
-	NetworkPassphrase string
-	SorobanRPCURL     string
+	Name              string `json:"name"`
+	HorizonURL        string `json:"horizon_url"`
+	NetworkPassphrase string `json:"network_passphrase"`
+	SorobanRPCURL     string `json:"soroban_rpc_url"`
+	// HorizonURLs and SorobanRPCURLs list fallback endpoints, in priority
+	// order. They are tried after HorizonURL and SorobanRPCURL respectively.
+	HorizonURLs    []string `json:"horizon_urls,omitempty"`
+	SorobanRPCURLs []string `json:"soroban_rpc_urls,omitempty"`
+}
+
+// HorizonEndpoints returns every Horizon endpoint of the network, primary first
+func (n NetworkConfig) HorizonEndpoints() []string {
+	return dedupeEndpoints(append([]string{n.HorizonURL}, n.HorizonURLs...))
+}
+
+// SorobanEndpoints returns every Soroban RPC endpoint of the network, primary first
+func (n NetworkConfig) SorobanEndpoints() []string {
+	return dedupeEndpoints(append([]string{n.SorobanRPCURL}, n.SorobanRPCURLs...))
+}
+
+func dedupeEndpoints(urls []string) []string {
+	seen := make(map[string]bool, len(urls))
+	out := make([]string, 0, len(urls))
+	for _, url := range urls {
+		if url == "" || seen[url] {
+			continue
+		}
+		seen[url] = true
+		out = append(out, url)
+	}
+	return out
 }
 
 // Predefined network configurations
@@ -107,13 +133,42 @@ type Client struct {
 	SorobanURL string
 	token      string // stored for reference, not logged
 	Config     NetworkConfig
+	// TxSources overrides the backends used by GetTransaction, in fallback order.
+	// When empty, Horizon is tried first and Soroban RPC second.
+	TxSources []TransactionSource
+	// LedgerCache, when set, is consulted by GetLedgerEntries according to its Mode
+	LedgerCache *LedgerCache
+	// LedgerSequence is the ledger entries are read for, usually that of the
+	// transaction being replayed. Read-through skips cached entries that were
+	// fetched before it or expired by then. Zero serves any cached entry.
+	LedgerSequence uint32
+	// LedgerEntriesBatchSize caps the number of keys sent in one getLedgerEntries
+	// call. Zero means DefaultLedgerEntriesBatchSize.
+	LedgerEntriesBatchSize int
+	// LedgerEntriesConcurrency caps the number of batches fetched in parallel.
+	// Zero means DefaultLedgerEntriesConcurrency.
+	LedgerEntriesConcurrency int
+	// Retry controls backoff for rate-limited and failed Soroban RPC calls.
+	// The zero value means DefaultRetryConfig.
+	Retry RetryConfig
+	// HorizonPool and SorobanPool track the health of every configured
+	// endpoint and fail over between them. When a pool is nil, Horizon and
+	// SorobanURL are used directly.
+	HorizonPool *EndpointPool
+	SorobanPool *EndpointPool
+	httpClient  *http.Client
 }
 
 // TransactionResponse contains the raw XDR fields needed for simulation
 type TransactionResponse struct {
-	EnvelopeXdr   string
-	ResultXdr     string
-	ResultMetaXdr string
+	Hash            string
+	EnvelopeXdr     string
+	ResultXdr       string
+	ResultMetaXdr   string
+	LedgerSequence  uint32
+	LedgerCloseTime time.Time
+	Status          string // SUCCESS or FAILED
+	Source          string // backend the transaction was fetched from
 }
 
 // NewClient creates a new RPC client with the specified network
@@ -167,11 +222,14 @@ func NewClient(net Network, token string) *Client {
 	}
 
 	return &Client{
-		Horizon:    horizonClient,
-		Network:    net,
-		SorobanURL: sorobanURL,
-		token:      token,
-		Config:     config,
+		Horizon:     horizonClient,
+		Network:     net,
+		SorobanURL:  sorobanURL,
+		token:       token,
+		Config:      config,
+		HorizonPool: NewEndpointPool(config.HorizonEndpoints()),
+		SorobanPool: NewEndpointPool(config.SorobanEndpoints()),
+		httpClient:  httpClient,
 	}
 }
 
@@ -199,10 +257,14 @@ func NewClientWithURL(url string, net Network, token string) *Client {
 	}
 
 	return &Client{
-		Horizon:    horizonClient,
-		Network:    net,
-		SorobanURL: defaultClient.SorobanURL,
-		token:      token,
+		Horizon:     horizonClient,
+		Network:     net,
+		SorobanURL:  defaultClient.SorobanURL,
+		token:       token,
+		Config:      defaultClient.Config,
+		HorizonPool: NewEndpointPool([]string{url}),
+		SorobanPool: defaultClient.SorobanPool,
+		httpClient:  httpClient,
 	}
 }
 
@@ -217,12 +279,17 @@ func createHTTPClient(token string) *http.Client {
 			token:     token,
 			transport: http.DefaultTransport,
 		},
-		Config:     defaultClient.Config,
 	}
 }
 
 // NewCustomClient creates a new RPC client for a custom/private network
 func NewCustomClient(config NetworkConfig) (*Client, error) {
+	return NewCustomClientWithToken(config, "")
+}
+
+// NewCustomClientWithToken creates a new RPC client for a custom/private network
+// Token can be provided via the token parameter or ERST_RPC_TOKEN environment variable
+func NewCustomClientWithToken(config NetworkConfig, token string) (*Client, error) {
 	if config.HorizonURL == "" {
 		return nil, fmt.Errorf("horizon URL is required for custom network")
 	}
@@ -230,25 +297,42 @@ func NewCustomClient(config NetworkConfig) (*Client, error) {
 		return nil, fmt.Errorf("network passphrase is required for custom network")
 	}
 
+	if token == "" {
+		token = os.Getenv("ERST_RPC_TOKEN")
+	}
+	httpClient := createHTTPClient(token)
+
 	horizonClient := &horizonclient.Client{
 		HorizonURL: config.HorizonURL,
-		HTTP:       http.DefaultClient,
+		HTTP:       httpClient,
 	}
 
-	sorobanURL := config.SorobanRPCURL
-	if sorobanURL == "" {
-		sorobanURL = config.HorizonURL // Fallback to Horizon URL if no Soroban RPC specified
+	sorobanEndpoints := config.SorobanEndpoints()
+	if len(sorobanEndpoints) == 0 {
+		sorobanEndpoints = []string{config.HorizonURL} // Fallback to Horizon URL if no Soroban RPC specified
+	}
+
+	net := Network(config.Name)
+	if net == "" {
+		net = "custom"
 	}
 
 	return &Client{
-		Horizon:    horizonClient,
-		Network:    "custom",
-		SorobanURL: sorobanURL,
-		Config:     config,
+		Horizon:     horizonClient,
+		Network:     net,
+		SorobanURL:  sorobanEndpoints[0],
+		token:       token,
+		Config:      config,
+		HorizonPool: NewEndpointPool(config.HorizonEndpoints()),
+		SorobanPool: NewEndpointPool(sorobanEndpoints),
+		httpClient:  httpClient,
 	}, nil
 }
 
-// GetTransaction fetches the transaction details and full XDR data
+// GetTransaction fetches the transaction details and full XDR data.
+// Each configured transaction source is tried in order; if one fails the next
+// one is used, so transactions outside Horizon's retention window can still
+// be served by Soroban RPC and vice versa.
 func (c *Client) GetTransaction(ctx context.Context, hash string) (*TransactionResponse, error) {
 	tracer := telemetry.GetTracer()
 	_, span := tracer.Start(ctx, "rpc_get_transaction")
@@ -260,26 +344,98 @@ func (c *Client) GetTransaction(ctx context.Context, hash string) (*TransactionR
 
 	logger.Logger.Debug("Fetching transaction details", "hash", hash)
 
-	tx, err := c.Horizon.TransactionDetail(hash)
-	if err != nil {
-		span.RecordError(err)
-		logger.Logger.Error("Failed to fetch transaction", "hash", hash, "error", err)
-		return nil, fmt.Errorf("failed to fetch transaction: %w", err)
+	var errs []error
+	for _, source := range c.transactionSources() {
+		tx, err := source.GetTransaction(ctx, hash)
+		if err != nil {
+			logger.Logger.Warn("Transaction source failed", "hash", hash, "source", source.Name(), "error", err)
+			errs = append(errs, fmt.Errorf("%s: %w", source.Name(), err))
+			if ctx.Err() != nil {
+				break
+			}
+			continue
+		}
+
+		if tx.Hash == "" {
+			tx.Hash = hash
+		}
+		if tx.Source == "" {
+			tx.Source = source.Name()
+		}
+
+		span.SetAttributes(
+			attribute.String("transaction.source", tx.Source),
+			attribute.Int("envelope.size_bytes", len(tx.EnvelopeXdr)),
+			attribute.Int("result.size_bytes", len(tx.ResultXdr)),
+			attribute.Int("result_meta.size_bytes", len(tx.ResultMetaXdr)),
+		)
+
+		logger.Logger.Info("Transaction fetched successfully", "hash", hash, "source", tx.Source, "envelope_size", len(tx.EnvelopeXdr))
+
+		return tx, nil
 	}
 
+	err := errors.Join(errs...)
+	if err == nil {
+		err = fmt.Errorf("no transaction source configured")
+	}
+	span.RecordError(err)
+	logger.Logger.Error("Failed to fetch transaction", "hash", hash, "error", err)
+	return nil, fmt.Errorf("failed to fetch transaction: %w", err)
+}
+
+// GetTransactions lists transactions through Soroban RPC starting at startLedger,
+// or continuing from cursor when it is set. It returns the page and the cursor
+// for the next page.
+func (c *Client) GetTransactions(ctx context.Context, startLedger uint32, cursor string, limit uint) ([]*TransactionResponse, string, error) {
+	tracer := telemetry.GetTracer()
+	_, span := tracer.Start(ctx, "rpc_get_transactions")
 	span.SetAttributes(
-		attribute.Int("envelope.size_bytes", len(tx.EnvelopeXdr)),
-		attribute.Int("result.size_bytes", len(tx.ResultXdr)),
-		attribute.Int("result_meta.size_bytes", len(tx.ResultMetaXdr)),
+		attribute.String("network", string(c.Network)),
+		attribute.Int("ledger.start", int(startLedger)),
 	)
+	defer span.End()
 
-	logger.Logger.Info("Transaction fetched successfully", "hash", hash, "envelope_size", len(tx.EnvelopeXdr))
+	txs, next, err := c.sorobanSource().GetTransactions(ctx, startLedger, cursor, limit)
+	if err != nil {
+		span.RecordError(err)
+		return nil, "", fmt.Errorf("failed to fetch transactions: %w", err)
+	}
+	return txs, next, nil
+}
 
-	return &TransactionResponse{
-		EnvelopeXdr:   tx.EnvelopeXdr,
-		ResultXdr:     tx.ResultXdr,
-		ResultMetaXdr: tx.ResultMetaXdr,
-	}, nil
+// transactionSources returns the sources used by GetTransaction in fallback order
+func (c *Client) transactionSources() []TransactionSource {
+	if len(c.TxSources) > 0 {
+		return c.TxSources
+	}
+
+	var sources []TransactionSource
+	if c.Horizon != nil || c.HorizonPool.Len() > 0 {
+		source := NewHorizonTransactionSource(c.Horizon)
+		source.Pool = c.HorizonPool
+		source.HTTP = c.getHTTPClient()
+		sources = append(sources, source)
+	}
+	if c.SorobanURL != "" || c.SorobanPool.Len() > 0 {
+		sources = append(sources, c.sorobanSource())
+	}
+	return sources
+}
+
+// sorobanSource returns a Soroban RPC source sharing the client's endpoint pool
+func (c *Client) sorobanSource() *SorobanTransactionSource {
+	source := NewSorobanTransactionSource(c.SorobanURL, c.getHTTPClient())
+	source.Pool = c.SorobanPool
+	return source
+}
+
+// getHTTPClient returns the HTTP client carrying the client's auth transport
+func (c *Client) getHTTPClient() *http.Client {
+	if c.httpClient != nil {
+		return c.httpClient
+	}
+	return http.DefaultClient
 }
 
 // GetNetworkPassphrase returns the network passphrase for this client
@@ -303,23 +459,21 @@ type GetLedgerEntriesRequest struct {
 }
 
 type GetLedgerEntriesResponse struct {
-	Jsonrpc string `json:"jsonrpc"`
-	ID      int    `json:"id"`
-	Result  struct {
-		Entries []struct {
-			Key                string `json:"key"`
-			Xdr                string `json:"xdr"`
-			LastModifiedLedger int    `json:"lastModifiedLedgerSeq"`
-			LiveUntilLedger    int    `json:"liveUntilLedgerSeq"`
-		} `json:"entries"`
-		LatestLedger int `json:"latestLedger"`
-	} `json:"result"`
-	Error *struct {
+	Jsonrpc string                 `json:"jsonrpc"`
+	ID      int                    `json:"id"`
+	Result  GetLedgerEntriesResult `json:"result"`
+	Error   *struct {
 		Code    int    `json:"code"`
 		Message string `json:"message"`
 	} `json:"error,omitempty"`
 }
 
+// GetLedgerEntriesResult is the result of the Soroban RPC getLedgerEntries method
+type GetLedgerEntriesResult struct {
+	Entries      []LedgerEntryResult `json:"entries"`
+	LatestLedger int                 `json:"latestLedger"`
+}
+
 // GetLedgerHeader fetches ledger header details for a specific sequence.
 // This includes essential metadata like sequence number, timestamp, protocol version,
 // and XDR-encoded header data needed for transaction simulation.
@@ -331,9 +485,9 @@ type GetLedgerEntriesResponse struct {
 // Returns:
 //   - *LedgerHeaderResponse: Header data if successful
 //   - error: Typed error indicating failure reason:
-//     * LedgerNotFoundError: Ledger doesn't exist (future or invalid)
-//     * LedgerArchivedError: Ledger has been archived
-//     * RateLimitError: Too many requests
+//   - LedgerNotFoundError: Ledger doesn't exist (future or invalid)
+//   - LedgerArchivedError: Ledger has been archived
+//   - RateLimitError: Too many requests
 //
 // Example:
 //
@@ -360,7 +514,12 @@ func (c *Client) GetLedgerHeader(ctx context.Context, sequence uint32) (*LedgerH
 	logger.Logger.Debug("Fetching ledger header", "sequence", sequence, "network", c.Network)
 
 	// Fetch ledger from Horizon
-	ledger, err := c.Horizon.LedgerDetail(sequence)
+	var ledger hProtocol.Ledger
+	err := doHorizon(ctx, c.HorizonPool, c.getHTTPClient(), c.Horizon, func(h horizonclient.ClientInterface) error {
+		var err error
+		ledger, err = h.LedgerDetail(sequence)
+		return err
+	})
 	if err != nil {
 		span.RecordError(err)
 		return nil, c.handleLedgerError(err, sequence)
@@ -443,6 +602,8 @@ func (e *LedgerArchivedError) Error() string {
 // and the client should back off.
 type RateLimitError struct {
 	Message string
+	// RetryAfter is the delay requested by the server's Retry-After header, if any
+	RetryAfter time.Duration
 }
 
 func (e *RateLimitError) Error() string {
@@ -469,57 +630,74 @@ func IsRateLimitError(err error) bool {
 
 // GetLedgerEntries fetches the current state of ledger entries from Soroban RPC
 // keys should be a list of base64-encoded XDR LedgerKeys
+//
+// Large key sets are split into batches of LedgerEntriesBatchSize keys that are
+// fetched in parallel. Rate limited (429) and failed (5xx) calls are retried
+// with exponential backoff, honouring the server's Retry-After header.
+//
+// Returns a typed error once retries are exhausted:
+//   - RateLimitError: Too many requests
+//   - ServerError: Unexpected HTTP status from the RPC server
 func (c *Client) GetLedgerEntries(ctx context.Context, keys []string) (map[string]string, error) {
-	if len(keys) == 0 {
-		return map[string]string{}, nil
+	results, err := c.GetLedgerEntryResults(ctx, keys)
+	if err != nil {
+		return nil, err
 	}
 
-	logger.Logger.Debug("Fetching ledger entries", "count", len(keys), "url", c.SorobanURL)
-
-	reqBody := GetLedgerEntriesRequest{
-		Jsonrpc: "2.0",
-		ID:      1,
-		Method:  "getLedgerEntries",
-		Params:  []interface{}{keys},
+	entries := make(map[string]string, len(results))
+	for key, entry := range results {
+		entries[key] = entry.Xdr
 	}
+	return entries, nil
+}
 
-	bodyBytes, err := json.Marshal(reqBody)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request: %w", err)
+// GetLedgerEntryResults is like GetLedgerEntries but keeps the
+// lastModifiedLedgerSeq and liveUntilLedgerSeq of every entry.
+// If the client has a LedgerCache, it is used according to its Mode.
+func (c *Client) GetLedgerEntryResults(ctx context.Context, keys []string) (map[string]LedgerEntryResult, error) {
+	if len(keys) == 0 {
+		return map[string]LedgerEntryResult{}, nil
 	}
 
-	req, err := http.NewRequestWithContext(ctx, "POST", c.SorobanURL, bytes.NewBuffer(bodyBytes))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+	mode := CacheModeOff
+	if c.LedgerCache != nil {
+		mode = c.LedgerCache.Mode
 	}
-	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to execute request: %w", err)
-	}
-	defer resp.Body.Close()
+	results := make(map[string]LedgerEntryResult)
+	toFetch := keys
 
-	respBytes, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
+	switch mode {
+	case CacheModeOffline:
+		hits, misses := c.LedgerCache.Lookup(keys, 0)
+		if len(misses) > 0 {
+			logger.Logger.Warn("Ledger entries missing from cache in offline mode", "missing", len(misses), "requested", len(keys))
+		}
+		logger.Logger.Info("Ledger entries served from cache", "found", len(hits), "requested", len(keys))
+		return hits, nil
+	case CacheModeReadThrough:
+		var hits map[string]LedgerEntryResult
+		hits, toFetch = c.LedgerCache.Lookup(keys, c.LedgerSequence)
+		for key, entry := range hits {
+			results[key] = entry
+		}
+		logger.Logger.Debug("Ledger entry cache lookup", "hits", len(hits), "misses", len(toFetch))
+		if len(toFetch) == 0 {
+			return results, nil
+		}
 	}
 
-	var rpcResp GetLedgerEntriesResponse
-	if err := json.Unmarshal(respBytes, &rpcResp); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	fetched, latestLedger, err := c.fetchLedgerEntries(ctx, toFetch)
+	if err != nil {
+		return nil, err
 	}
 
-	if rpcResp.Error != nil {
-		return nil, fmt.Errorf("rpc error: %s (code %d)", rpcResp.Error.Message, rpcResp.Error.Code)
+	if mode == CacheModeReadThrough || mode == CacheModeWriteThrough {
+		c.LedgerCache.Store(fetched, latestLedger)
 	}
 
-	entries := make(map[string]string)
-	for _, entry := range rpcResp.Result.Entries {
-		entries[entry.Key] = entry.Xdr
+	for _, entry := range fetched {
+		results[entry.Key] = entry
 	}
-
-	logger.Logger.Info("Ledger entries fetched successfully", "found", len(entries), "requested", len(keys))
-
-	return entries, nil
+	return results, nil
 }
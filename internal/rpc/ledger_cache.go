@@ -0,0 +1,244 @@
+// Copyright (c) 2026 dotandev
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rpc
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/dotandev/hintents/internal/cache"
+	"github.com/dotandev/hintents/internal/logger"
+	"github.com/stellar/go/xdr"
+)
+
+// CacheMode controls how GetLedgerEntries uses the on-disk ledger entry cache
+type CacheMode string
+
+const (
+	// CacheModeOff disables the cache entirely
+	CacheModeOff CacheMode = "off"
+	// CacheModeReadThrough serves cached entries and fetches only the misses,
+	// storing whatever was fetched
+	CacheModeReadThrough CacheMode = "read-through"
+	// CacheModeWriteThrough always fetches from the network and refreshes the
+	// cache with the result
+	CacheModeWriteThrough CacheMode = "write-through"
+	// CacheModeOffline serves cached entries only and never touches the network
+	CacheModeOffline CacheMode = "offline"
+)
+
+// ParseCacheMode validates a cache mode name
+func ParseCacheMode(s string) (CacheMode, error) {
+	switch mode := CacheMode(s); mode {
+	case CacheModeOff, CacheModeReadThrough, CacheModeWriteThrough, CacheModeOffline:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("invalid cache mode: %s. Must be one of: off, read-through, write-through, offline", s)
+	}
+}
+
+// ledgerEntriesDir is the cache subdirectory holding ledger entries
+const ledgerEntriesDir = "ledger-entries"
+
+// LedgerEntryResult is a single ledger entry as returned by Soroban RPC
+type LedgerEntryResult struct {
+	Key                string `json:"key"`
+	Xdr                string `json:"xdr"`
+	LastModifiedLedger uint32 `json:"lastModifiedLedgerSeq"`
+	LiveUntilLedger    uint32 `json:"liveUntilLedgerSeq,omitempty"`
+}
+
+// cachedLedgerEntry is the on-disk representation of a cached ledger entry
+type cachedLedgerEntry struct {
+	LedgerEntryResult
+	Network  string    `json:"network"`
+	CachedAt time.Time `json:"cached_at"`
+	// FetchedAtLedger is the latest ledger of the RPC response the entry came
+	// from, zero when unknown
+	FetchedAtLedger uint32 `json:"fetched_at_ledger,omitempty"`
+}
+
+// usableAt reports whether the entry still reflects the state at ledger: it
+// must have been fetched at or after ledger and still be live. Any entry is
+// usable when ledger is zero.
+func (e *cachedLedgerEntry) usableAt(ledger uint32) bool {
+	if ledger == 0 {
+		return true
+	}
+	if e.LiveUntilLedger != 0 && e.LiveUntilLedger < ledger {
+		return false
+	}
+	return e.FetchedAtLedger >= ledger
+}
+
+// LedgerCache is a content-addressed on-disk cache of ledger entries.
+// Each entry is stored in its own file named after HashLedgerKey, so the
+// files can be evicted independently by cache.Manager.CleanLRU.
+type LedgerCache struct {
+	rootDir string
+	network string
+	Mode    CacheMode
+}
+
+// NewLedgerCache creates a ledger entry cache under rootDir (usually ~/.erst/cache).
+// Entries are namespaced per network so identical keys on different networks
+// never collide.
+func NewLedgerCache(rootDir, network string, mode CacheMode) *LedgerCache {
+	if network == "" {
+		network = "custom"
+	}
+	return &LedgerCache{
+		rootDir: rootDir,
+		network: network,
+		Mode:    mode,
+	}
+}
+
+// Dir returns the directory holding this network's cached entries
+func (lc *LedgerCache) Dir() string {
+	return filepath.Join(lc.rootDir, ledgerEntriesDir, lc.network)
+}
+
+// path returns the cache file for a base64-encoded XDR LedgerKey
+func (lc *LedgerCache) path(key string) (string, error) {
+	var ledgerKey xdr.LedgerKey
+	if err := xdr.SafeUnmarshalBase64(key, &ledgerKey); err != nil {
+		return "", fmt.Errorf("failed to decode ledger key: %w", err)
+	}
+
+	hash, err := HashLedgerKey(ledgerKey)
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(lc.Dir(), hash[:2], hash+".json"), nil
+}
+
+// Get returns the cached entry for key. The file's modification time is
+// bumped on every hit so that LRU eviction keeps frequently used entries.
+func (lc *LedgerCache) Get(key string) (*LedgerEntryResult, bool) {
+	entry, ok := lc.get(key)
+	if !ok {
+		return nil, false
+	}
+	return &entry.LedgerEntryResult, true
+}
+
+func (lc *LedgerCache) get(key string) (*cachedLedgerEntry, bool) {
+	path, err := lc.path(key)
+	if err != nil {
+		logger.Logger.Debug("Skipping cache lookup", "error", err)
+		return nil, false
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+
+	var entry cachedLedgerEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		logger.Logger.Warn("Discarding corrupt cache entry", "path", path, "error", err)
+		_ = os.Remove(path)
+		return nil, false
+	}
+
+	now := time.Now()
+	if err := os.Chtimes(path, now, now); err != nil {
+		logger.Logger.Debug("Failed to update cache access time", "path", path, "error", err)
+	}
+
+	return &entry, true
+}
+
+// Put stores an entry in the cache, fetched at an unknown ledger
+func (lc *LedgerCache) Put(entry LedgerEntryResult) error {
+	return lc.put(entry, 0)
+}
+
+func (lc *LedgerCache) put(entry LedgerEntryResult, fetchedAt uint32) error {
+	path, err := lc.path(entry.Key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	data, err := json.Marshal(cachedLedgerEntry{
+		LedgerEntryResult: entry,
+		Network:           lc.network,
+		CachedAt:          time.Now().UTC(),
+		FetchedAtLedger:   fetchedAt,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal cache entry: %w", err)
+	}
+
+	// Write to a temp file first so a concurrent reader never sees a partial entry
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+	return nil
+}
+
+// Lookup splits keys into cached entries and keys that still have to be
+// fetched. With a nonzero ledger, entries that may be stale at that ledger
+// count as misses, see usableAt.
+func (lc *LedgerCache) Lookup(keys []string, ledger uint32) (map[string]LedgerEntryResult, []string) {
+	hits := make(map[string]LedgerEntryResult)
+	var misses []string
+	for _, key := range keys {
+		if entry, ok := lc.get(key); ok && entry.usableAt(ledger) {
+			hits[key] = entry.LedgerEntryResult
+		} else {
+			misses = append(misses, key)
+		}
+	}
+	return hits, misses
+}
+
+// Store writes all entries, fetched at latestLedger, and then lets the cache
+// manager evict the least recently used files if the cache grew past its
+// configured limit.
+func (lc *LedgerCache) Store(entries []LedgerEntryResult, latestLedger uint32) {
+	stored := 0
+	for _, entry := range entries {
+		if err := lc.put(entry, latestLedger); err != nil {
+			logger.Logger.Warn("Failed to cache ledger entry", "key", entry.Key, "error", err)
+			continue
+		}
+		stored++
+	}
+
+	if stored == 0 {
+		return
+	}
+
+	logger.Logger.Debug("Cached ledger entries", "count", stored, "dir", lc.Dir())
+
+	if err := cache.CheckAndCleanup(lc.rootDir); err != nil {
+		logger.Logger.Warn("Cache cleanup failed", "error", err)
+	}
+}
@@ -0,0 +1,229 @@
+// Copyright (c) 2026 dotandev
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rpc
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dotandev/hintents/internal/cache"
+	"github.com/stellar/go/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testLedgerKeyB64(t *testing.T, address string) string {
+	t.Helper()
+	key := xdr.LedgerKey{
+		Type: xdr.LedgerEntryTypeAccount,
+		Account: &xdr.LedgerKeyAccount{
+			AccountId: xdr.MustAddress(address),
+		},
+	}
+	b64, err := xdr.MarshalBase64(key)
+	require.NoError(t, err)
+	return b64
+}
+
+const (
+	cacheTestAccountA = "GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H"
+	cacheTestAccountB = "GCRRSYF5JBFPXHN5DCG65A4J3MUYE53QMQ4XMXZ3CNKWFJIJJTGMH6MZ"
+)
+
+func newLedgerEntriesServer(t *testing.T, entries ...LedgerEntryResult) *MockServer {
+	t.Helper()
+	server := NewMockServer(nil)
+	t.Cleanup(server.Close)
+	server.AddRPCRoute("getLedgerEntries", RPCSuccessRoute(map[string]interface{}{
+		"entries":      entries,
+		"latestLedger": 2000,
+	}))
+	return server
+}
+
+func TestLedgerCache_PutGet(t *testing.T) {
+	lc := NewLedgerCache(t.TempDir(), "testnet", CacheModeReadThrough)
+	key := testLedgerKeyB64(t, cacheTestAccountA)
+
+	_, ok := lc.Get(key)
+	assert.False(t, ok)
+
+	entry := LedgerEntryResult{Key: key, Xdr: "entry-xdr", LastModifiedLedger: 100, LiveUntilLedger: 500}
+	require.NoError(t, lc.Put(entry))
+
+	got, ok := lc.Get(key)
+	require.True(t, ok)
+	assert.Equal(t, entry, *got)
+
+	// The file name is the HashLedgerKey of the decoded key
+	var ledgerKey xdr.LedgerKey
+	require.NoError(t, xdr.SafeUnmarshalBase64(key, &ledgerKey))
+	hash, err := HashLedgerKey(ledgerKey)
+	require.NoError(t, err)
+	_, err = os.Stat(filepath.Join(lc.Dir(), hash[:2], hash+".json"))
+	assert.NoError(t, err)
+}
+
+func TestLedgerCache_NetworksAreIsolated(t *testing.T) {
+	root := t.TempDir()
+	key := testLedgerKeyB64(t, cacheTestAccountA)
+
+	require.NoError(t, NewLedgerCache(root, "testnet", CacheModeReadThrough).Put(LedgerEntryResult{Key: key, Xdr: "x"}))
+
+	_, ok := NewLedgerCache(root, "mainnet", CacheModeReadThrough).Get(key)
+	assert.False(t, ok)
+}
+
+func TestLedgerCache_InvalidKey(t *testing.T) {
+	lc := NewLedgerCache(t.TempDir(), "testnet", CacheModeReadThrough)
+	assert.Error(t, lc.Put(LedgerEntryResult{Key: "not-xdr", Xdr: "x"}))
+	_, ok := lc.Get("not-xdr")
+	assert.False(t, ok)
+}
+
+func TestGetLedgerEntries_ReadThrough(t *testing.T) {
+	keyA := testLedgerKeyB64(t, cacheTestAccountA)
+	server := newLedgerEntriesServer(t, LedgerEntryResult{Key: keyA, Xdr: "xdr-a", LastModifiedLedger: 10, LiveUntilLedger: 20})
+
+	client := &Client{SorobanURL: server.URL(), LedgerCache: NewLedgerCache(t.TempDir(), "testnet", CacheModeReadThrough)}
+
+	entries, err := client.GetLedgerEntries(context.Background(), []string{keyA})
+	require.NoError(t, err)
+	assert.Equal(t, "xdr-a", entries[keyA])
+	assert.Equal(t, 1, server.CallCount("getLedgerEntries"))
+
+	// Second call is served from disk
+	results, err := client.GetLedgerEntryResults(context.Background(), []string{keyA})
+	require.NoError(t, err)
+	assert.Equal(t, uint32(10), results[keyA].LastModifiedLedger)
+	assert.Equal(t, uint32(20), results[keyA].LiveUntilLedger)
+	assert.Equal(t, 1, server.CallCount("getLedgerEntries"))
+}
+
+func TestGetLedgerEntries_ReadThroughBypassesStale(t *testing.T) {
+	keyA := testLedgerKeyB64(t, cacheTestAccountA)
+	keyB := testLedgerKeyB64(t, cacheTestAccountB)
+	server := newLedgerEntriesServer(t,
+		LedgerEntryResult{Key: keyA, Xdr: "fresh-a"},
+		LedgerEntryResult{Key: keyB, Xdr: "fresh-b"},
+	)
+
+	lc := NewLedgerCache(t.TempDir(), "testnet", CacheModeReadThrough)
+	// A was fetched before the target ledger, B expired before it
+	lc.Store([]LedgerEntryResult{{Key: keyA, Xdr: "old-a"}}, 1000)
+	lc.Store([]LedgerEntryResult{{Key: keyB, Xdr: "old-b", LiveUntilLedger: 1400}}, 3000)
+	client := &Client{SorobanURL: server.URL(), LedgerCache: lc, LedgerSequence: 1500}
+
+	entries, err := client.GetLedgerEntries(context.Background(), []string{keyA, keyB})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{keyA: "fresh-a", keyB: "fresh-b"}, entries)
+	assert.Equal(t, 1, server.CallCount("getLedgerEntries"))
+
+	// The refetched entries were recorded at latestLedger 2000 and now serve
+	// the target ledger from disk
+	_, err = client.GetLedgerEntries(context.Background(), []string{keyA, keyB})
+	require.NoError(t, err)
+	assert.Equal(t, 1, server.CallCount("getLedgerEntries"))
+
+	// Without a target ledger every cached entry is served
+	client.LedgerSequence = 0
+	lc.Store([]LedgerEntryResult{{Key: keyA, Xdr: "old-a"}}, 1000)
+	entries, err = client.GetLedgerEntries(context.Background(), []string{keyA})
+	require.NoError(t, err)
+	assert.Equal(t, "old-a", entries[keyA])
+}
+
+func TestGetLedgerEntries_WriteThrough(t *testing.T) {
+	keyA := testLedgerKeyB64(t, cacheTestAccountA)
+	server := newLedgerEntriesServer(t, LedgerEntryResult{Key: keyA, Xdr: "fresh"})
+
+	lc := NewLedgerCache(t.TempDir(), "testnet", CacheModeWriteThrough)
+	require.NoError(t, lc.Put(LedgerEntryResult{Key: keyA, Xdr: "stale"}))
+	client := &Client{SorobanURL: server.URL(), LedgerCache: lc}
+
+	entries, err := client.GetLedgerEntries(context.Background(), []string{keyA})
+	require.NoError(t, err)
+	assert.Equal(t, "fresh", entries[keyA])
+	assert.Equal(t, 1, server.CallCount("getLedgerEntries"))
+
+	cached, ok := lc.Get(keyA)
+	require.True(t, ok)
+	assert.Equal(t, "fresh", cached.Xdr)
+}
+
+func TestGetLedgerEntries_Offline(t *testing.T) {
+	keyA := testLedgerKeyB64(t, cacheTestAccountA)
+	keyB := testLedgerKeyB64(t, cacheTestAccountB)
+
+	lc := NewLedgerCache(t.TempDir(), "testnet", CacheModeOffline)
+	require.NoError(t, lc.Put(LedgerEntryResult{Key: keyA, Xdr: "xdr-a"}))
+
+	// No SorobanURL: any network access would fail
+	client := &Client{LedgerCache: lc}
+
+	entries, err := client.GetLedgerEntries(context.Background(), []string{keyA, keyB})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{keyA: "xdr-a"}, entries)
+}
+
+func TestLedgerCache_EvictedByCleanLRU(t *testing.T) {
+	root := t.TempDir()
+	lc := NewLedgerCache(root, "testnet", CacheModeReadThrough)
+	keyA := testLedgerKeyB64(t, cacheTestAccountA)
+	keyB := testLedgerKeyB64(t, cacheTestAccountB)
+
+	require.NoError(t, lc.Put(LedgerEntryResult{Key: keyA, Xdr: "xdr-a"}))
+	require.NoError(t, lc.Put(LedgerEntryResult{Key: keyB, Xdr: strings.Repeat("b", 4096)}))
+
+	// Make both entries old, then read A so it becomes the most recently used
+	old := time.Now().Add(-time.Hour)
+	for _, key := range []string{keyA, keyB} {
+		path, err := lc.path(key)
+		require.NoError(t, err)
+		require.NoError(t, os.Chtimes(path, old, old))
+	}
+	_, ok := lc.Get(keyA)
+	require.True(t, ok)
+
+	manager := cache.NewManager(root, cache.DefaultConfig())
+	size, err := manager.GetCacheSize()
+	require.NoError(t, err)
+
+	// Just over the limit: evicting the large, least recently used entry is enough
+	manager = cache.NewManager(root, cache.Config{MaxSizeBytes: size - 1})
+	status, err := manager.CleanLRU()
+	require.NoError(t, err)
+	assert.Equal(t, 1, status.FilesDeleted)
+
+	_, ok = lc.Get(keyA)
+	assert.True(t, ok, "recently used entry should survive eviction")
+	_, ok = lc.Get(keyB)
+	assert.False(t, ok, "least recently used entry should be evicted")
+}
+
+func TestParseCacheMode(t *testing.T) {
+	for _, name := range []string{"off", "read-through", "write-through", "offline"} {
+		mode, err := ParseCacheMode(name)
+		assert.NoError(t, err)
+		assert.Equal(t, CacheMode(name), mode)
+	}
+
+	_, err := ParseCacheMode("sometimes")
+	assert.Error(t, err)
+}
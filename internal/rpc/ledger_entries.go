@@ -0,0 +1,269 @@
+// Copyright (c) 2026 dotandev
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rpc
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/dotandev/hintents/internal/logger"
+	"github.com/dotandev/hintents/internal/telemetry"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const (
+	// DefaultLedgerEntriesBatchSize matches the number of keys Soroban RPC
+	// accepts in a single getLedgerEntries call
+	DefaultLedgerEntriesBatchSize = 200
+	// DefaultLedgerEntriesConcurrency is the number of batches fetched in parallel
+	DefaultLedgerEntriesConcurrency = 4
+)
+
+// RetryConfig controls exponential backoff for retryable RPC failures
+// (HTTP 429 and 5xx).
+type RetryConfig struct {
+	// MaxAttempts is the total number of attempts, including the first one
+	MaxAttempts int
+	// InitialBackoff is the delay before the first retry. It doubles on
+	// every further retry, up to MaxBackoff.
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+// DefaultRetryConfig is used when a client does not configure retries
+var DefaultRetryConfig = RetryConfig{
+	MaxAttempts:    4,
+	InitialBackoff: 500 * time.Millisecond,
+	MaxBackoff:     10 * time.Second,
+}
+
+// ServerError indicates that the RPC server answered with an unexpected HTTP status
+type ServerError struct {
+	StatusCode int
+	Message    string
+	// RetryAfter is the delay requested by the server's Retry-After header, if any
+	RetryAfter time.Duration
+}
+
+func (e *ServerError) Error() string {
+	return e.Message
+}
+
+// IsServerError checks if error is an unexpected HTTP status from the server
+func IsServerError(err error) bool {
+	_, ok := err.(*ServerError)
+	return ok
+}
+
+// fetchLedgerEntries requests keys from Soroban RPC, bypassing any cache, and
+// returns them with the oldest latestLedger among the responses.
+// Keys are split into batches that are fetched in parallel, each with its own
+// retries. The first batch that fails for good cancels the others.
+func (c *Client) fetchLedgerEntries(ctx context.Context, keys []string) ([]LedgerEntryResult, uint32, error) {
+	tracer := telemetry.GetTracer()
+	ctx, span := tracer.Start(ctx, "rpc_get_ledger_entries")
+	defer span.End()
+
+	batches := splitBatches(keys, c.ledgerEntriesBatchSize())
+	span.SetAttributes(
+		attribute.String("network", string(c.Network)),
+		attribute.Int("ledger_entries.requested", len(keys)),
+		attribute.Int("ledger_entries.batches", len(batches)),
+	)
+
+	logger.Logger.Debug("Fetching ledger entries", "count", len(keys), "batches", len(batches), "url", c.SorobanURL)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		entries  []LedgerEntryResult
+		latest   uint32
+		firstErr error
+		sem      = make(chan struct{}, c.ledgerEntriesConcurrency())
+	)
+
+	for i, batch := range batches {
+		wg.Add(1)
+		go func(i int, batch []string) {
+			defer wg.Done()
+
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				return
+			}
+
+			result, err := c.fetchLedgerEntriesBatch(ctx, batch)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					logger.Logger.Error("Failed to fetch ledger entries", "batch", i, "keys", len(batch), "error", err)
+					firstErr = err
+					cancel()
+				}
+				return
+			}
+			entries = append(entries, result.Entries...)
+			if ledger := uint32(result.LatestLedger); latest == 0 || ledger < latest {
+				latest = ledger
+			}
+		}(i, batch)
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		span.RecordError(firstErr)
+		return nil, 0, firstErr
+	}
+
+	span.SetAttributes(attribute.Int("ledger_entries.found", len(entries)))
+	logger.Logger.Info("Ledger entries fetched successfully", "found", len(entries), "requested", len(keys))
+
+	return entries, latest, nil
+}
+
+// fetchLedgerEntriesBatch performs one getLedgerEntries call, retrying with
+// exponential backoff on rate limits and server errors
+func (c *Client) fetchLedgerEntriesBatch(ctx context.Context, keys []string) (*GetLedgerEntriesResult, error) {
+	retry := c.retryConfig()
+
+	var err error
+	for attempt := 1; ; attempt++ {
+		var result GetLedgerEntriesResult
+		err = c.sorobanSource().call(ctx, "getLedgerEntries", []interface{}{keys}, &result)
+		if err == nil {
+			return &result, nil
+		}
+
+		delay, retryable := retryDelay(err, retry, attempt)
+		if !retryable || attempt >= retry.MaxAttempts {
+			return nil, err
+		}
+
+		logger.Logger.Warn("Retrying getLedgerEntries", "attempt", attempt, "delay", delay, "error", err)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, errors.Join(ctx.Err(), err)
+		case <-timer.C:
+		}
+	}
+}
+
+// retryDelay reports whether err is worth retrying and how long to wait first.
+// A Retry-After header from the server takes precedence over the backoff, but
+// is capped at MaxBackoff so that a server cannot stall a batch for hours.
+func retryDelay(err error, retry RetryConfig, attempt int) (time.Duration, bool) {
+	var retryAfter time.Duration
+	switch e := err.(type) {
+	case *RateLimitError:
+		retryAfter = e.RetryAfter
+	case *ServerError:
+		if e.StatusCode < http.StatusInternalServerError {
+			return 0, false
+		}
+		retryAfter = e.RetryAfter
+	default:
+		return 0, false
+	}
+
+	if retryAfter > 0 {
+		if retry.MaxBackoff > 0 && retryAfter > retry.MaxBackoff {
+			retryAfter = retry.MaxBackoff
+		}
+		return retryAfter, true
+	}
+	return backoff(retry, attempt), true
+}
+
+// backoff returns the delay before retry number attempt, with up to 50% jitter
+// so that parallel batches do not retry in lockstep
+func backoff(retry RetryConfig, attempt int) time.Duration {
+	delay := retry.InitialBackoff
+	for i := 1; i < attempt && delay < retry.MaxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > retry.MaxBackoff {
+		delay = retry.MaxBackoff
+	}
+	if delay <= 0 {
+		return 0
+	}
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+}
+
+// parseRetryAfter parses a Retry-After header given either in seconds or as an HTTP date
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(value); err == nil {
+		if secs < 0 {
+			return 0
+		}
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(value); err == nil && t.After(now) {
+		return t.Sub(now)
+	}
+	return 0
+}
+
+// splitBatches splits keys into consecutive chunks of at most size keys
+func splitBatches(keys []string, size int) [][]string {
+	var batches [][]string
+	for len(keys) > size {
+		batches = append(batches, keys[:size:size])
+		keys = keys[size:]
+	}
+	if len(keys) > 0 {
+		batches = append(batches, keys)
+	}
+	return batches
+}
+
+func (c *Client) ledgerEntriesBatchSize() int {
+	if c.LedgerEntriesBatchSize > 0 {
+		return c.LedgerEntriesBatchSize
+	}
+	return DefaultLedgerEntriesBatchSize
+}
+
+func (c *Client) ledgerEntriesConcurrency() int {
+	if c.LedgerEntriesConcurrency > 0 {
+		return c.LedgerEntriesConcurrency
+	}
+	return DefaultLedgerEntriesConcurrency
+}
+
+func (c *Client) retryConfig() RetryConfig {
+	if c.Retry.MaxAttempts > 0 {
+		return c.Retry
+	}
+	return DefaultRetryConfig
+}
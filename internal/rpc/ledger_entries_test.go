@@ -0,0 +1,258 @@
+// Copyright (c) 2026 dotandev
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var fastRetry = RetryConfig{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: 5 * time.Millisecond}
+
+// ledgerEntriesHandler echoes every requested key back as an entry
+func ledgerEntriesHandler(t *testing.T, w http.ResponseWriter, r *http.Request) []string {
+	var req struct {
+		Params [][]string `json:"params"`
+	}
+	require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+	keys := req.Params[0]
+
+	entries := make([]LedgerEntryResult, 0, len(keys))
+	for _, key := range keys {
+		entries = append(entries, LedgerEntryResult{Key: key, Xdr: "xdr-" + key})
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"result":  map[string]interface{}{"entries": entries, "latestLedger": 100},
+	})
+	return keys
+}
+
+func TestGetLedgerEntries_Batching(t *testing.T) {
+	var (
+		mu         sync.Mutex
+		batchSizes []int
+		inFlight   int32
+		maxFlight  int32
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			m := atomic.LoadInt32(&maxFlight)
+			if n <= m || atomic.CompareAndSwapInt32(&maxFlight, m, n) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+
+		keys := ledgerEntriesHandler(t, w, r)
+		mu.Lock()
+		batchSizes = append(batchSizes, len(keys))
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	keys := make([]string, 25)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("key-%02d", i)
+	}
+
+	client := &Client{SorobanURL: server.URL, LedgerEntriesBatchSize: 10, LedgerEntriesConcurrency: 2, Retry: fastRetry}
+	entries, err := client.GetLedgerEntries(context.Background(), keys)
+	require.NoError(t, err)
+
+	assert.Len(t, entries, 25)
+	assert.Equal(t, "xdr-key-24", entries["key-24"])
+	assert.ElementsMatch(t, []int{10, 10, 5}, batchSizes)
+	assert.LessOrEqual(t, atomic.LoadInt32(&maxFlight), int32(2))
+}
+
+func TestGetLedgerEntries_RetriesRateLimit(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch atomic.AddInt32(&calls, 1) {
+		case 1:
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+		case 2:
+			w.WriteHeader(http.StatusBadGateway)
+		default:
+			ledgerEntriesHandler(t, w, r)
+		}
+	}))
+	defer server.Close()
+
+	client := &Client{SorobanURL: server.URL, Retry: fastRetry}
+	entries, err := client.GetLedgerEntries(context.Background(), []string{"a"})
+	require.NoError(t, err)
+	assert.Equal(t, "xdr-a", entries["a"])
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+}
+
+func TestGetLedgerEntries_CapsRetryAfter(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.Header().Set("Retry-After", "3600")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		ledgerEntriesHandler(t, w, r)
+	}))
+	defer server.Close()
+
+	start := time.Now()
+	client := &Client{SorobanURL: server.URL, Retry: fastRetry}
+	entries, err := client.GetLedgerEntries(context.Background(), []string{"a"})
+	require.NoError(t, err)
+	assert.Equal(t, "xdr-a", entries["a"])
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
+
+func TestRetryDelay_CapsRetryAfter(t *testing.T) {
+	retry := RetryConfig{MaxAttempts: 3, InitialBackoff: time.Second, MaxBackoff: 10 * time.Second}
+
+	delay, ok := retryDelay(&RateLimitError{RetryAfter: time.Hour}, retry, 1)
+	assert.True(t, ok)
+	assert.Equal(t, 10*time.Second, delay)
+
+	delay, ok = retryDelay(&ServerError{StatusCode: http.StatusServiceUnavailable, RetryAfter: 3 * time.Second}, retry, 1)
+	assert.True(t, ok)
+	assert.Equal(t, 3*time.Second, delay)
+}
+
+func TestGetLedgerEntries_TypedErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		route     MockRoute
+		wantCalls int
+		checkFn   func(t *testing.T, err error)
+	}{
+		{
+			name:      "rate limit exhausted",
+			route:     MockRoute{StatusCode: http.StatusTooManyRequests, Headers: map[string]string{"Retry-After": "0"}},
+			wantCalls: 3,
+			checkFn: func(t *testing.T, err error) {
+				assert.True(t, IsRateLimitError(err))
+			},
+		},
+		{
+			name:      "server error exhausted",
+			route:     ServerErrorRoute(),
+			wantCalls: 3,
+			checkFn: func(t *testing.T, err error) {
+				require.True(t, IsServerError(err))
+				assert.Equal(t, http.StatusInternalServerError, err.(*ServerError).StatusCode)
+			},
+		},
+		{
+			name:      "client error is not retried",
+			route:     MockRoute{StatusCode: http.StatusUnauthorized},
+			wantCalls: 1,
+			checkFn: func(t *testing.T, err error) {
+				assert.True(t, IsServerError(err))
+				assert.Contains(t, err.Error(), "status 401")
+			},
+		},
+		{
+			name:      "rpc error is not retried",
+			route:     RPCErrorRoute(-32602, "invalid key"),
+			wantCalls: 1,
+			checkFn: func(t *testing.T, err error) {
+				assert.Contains(t, err.Error(), "invalid key")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := NewMockServer(nil)
+			defer server.Close()
+			server.AddRPCRoute("getLedgerEntries", tt.route)
+
+			client := &Client{SorobanURL: server.URL(), Retry: fastRetry}
+			_, err := client.GetLedgerEntries(context.Background(), []string{"a"})
+			require.Error(t, err)
+			tt.checkFn(t, err)
+			assert.Equal(t, tt.wantCalls, server.CallCount("getLedgerEntries"))
+		})
+	}
+}
+
+func TestGetLedgerEntries_UsesAuthTransport(t *testing.T) {
+	var auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		ledgerEntriesHandler(t, w, r)
+	}))
+	defer server.Close()
+
+	client := NewClientWithURL(server.URL, Testnet, "secret-token")
+	client.SorobanURL = server.URL
+	client.SorobanPool = NewEndpointPool([]string{server.URL})
+
+	_, err := client.GetLedgerEntries(context.Background(), []string{"a"})
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer secret-token", auth)
+}
+
+func TestGetLedgerEntries_HonoursContextDuringBackoff(t *testing.T) {
+	server := NewMockServer(nil)
+	defer server.Close()
+	server.AddRPCRoute("getLedgerEntries", RateLimitRoute()) // Retry-After: 60
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	// MaxBackoff allows the server's 60s, so only the context ends the wait
+	start := time.Now()
+	client := &Client{SorobanURL: server.URL(), Retry: RetryConfig{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: time.Minute}}
+	_, err := client.GetLedgerEntries(ctx, []string{"a"})
+	require.Error(t, err)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, 30*time.Second, parseRetryAfter("30", now))
+	assert.Equal(t, time.Duration(0), parseRetryAfter("", now))
+	assert.Equal(t, time.Duration(0), parseRetryAfter("soon", now))
+	assert.Equal(t, 90*time.Second, parseRetryAfter(now.Add(90*time.Second).Format(http.TimeFormat), now))
+}
+
+func TestBackoff(t *testing.T) {
+	retry := RetryConfig{MaxAttempts: 5, InitialBackoff: 100 * time.Millisecond, MaxBackoff: 300 * time.Millisecond}
+
+	for attempt, max := range map[int]time.Duration{1: 100, 2: 200, 3: 300, 4: 300} {
+		d := backoff(retry, attempt)
+		assert.GreaterOrEqual(t, d, max*time.Millisecond/2)
+		assert.LessOrEqual(t, d, max*time.Millisecond)
+	}
+}
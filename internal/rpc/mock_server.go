@@ -15,8 +15,10 @@
 package rpc
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
 	"net/http/httptest"
@@ -27,6 +29,7 @@ import (
 type MockServer struct {
 	server    *httptest.Server
 	routes    map[string]MockRoute
+	rpcRoutes map[string]MockRoute
 	mu        sync.RWMutex
 	callCount map[string]int
 }
@@ -42,6 +45,7 @@ type MockRoute struct {
 func NewMockServer(routes map[string]MockRoute) *MockServer {
 	ms := &MockServer{
 		routes:    make(map[string]MockRoute),
+		rpcRoutes: make(map[string]MockRoute),
 		callCount: make(map[string]int),
 	}
 
@@ -58,6 +62,10 @@ func NewMockServer(routes map[string]MockRoute) *MockServer {
 
 // handleRequest handles incoming HTTP requests and returns the configured response
 func (ms *MockServer) handleRequest(w http.ResponseWriter, r *http.Request) {
+	if ms.handleRPCRequest(w, r) {
+		return
+	}
+
 	ms.mu.Lock()
 	ms.callCount[r.RequestURI]++
 	ms.mu.Unlock()
@@ -81,7 +89,53 @@ func (ms *MockServer) handleRequest(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Set custom headers if provided
+	ms.writeRoute(w, route)
+}
+
+// handleRPCRequest serves JSON-RPC POST requests whose method has a registered
+// RPC route. It reports whether the request was handled.
+func (ms *MockServer) handleRPCRequest(w http.ResponseWriter, r *http.Request) bool {
+	if r.Method != http.MethodPost {
+		return false
+	}
+
+	ms.mu.RLock()
+	hasRPCRoutes := len(ms.rpcRoutes) > 0
+	ms.mu.RUnlock()
+	if !hasRPCRoutes {
+		return false
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return false
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	var req struct {
+		Method string `json:"method"`
+	}
+	if err := json.Unmarshal(body, &req); err != nil || req.Method == "" {
+		return false
+	}
+
+	ms.mu.Lock()
+	route, exists := ms.rpcRoutes[req.Method]
+	if exists {
+		ms.callCount[req.Method]++
+	}
+	ms.mu.Unlock()
+	if !exists {
+		return false
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	ms.writeRoute(w, route)
+	return true
+}
+
+// writeRoute writes the configured headers, status code and body of a route
+func (ms *MockServer) writeRoute(w http.ResponseWriter, route MockRoute) {
 	if route.Headers != nil {
 		for key, value := range route.Headers {
 			w.Header().Set(key, value)
@@ -125,7 +179,16 @@ func (ms *MockServer) RemoveRoute(path string) {
 	delete(ms.routes, path)
 }
 
-// CallCount returns the number of times a specific endpoint was called
+// AddRPCRoute adds or updates the response for a JSON-RPC method.
+// RPC routes match POST requests by the "method" field of the request body,
+// regardless of the request path.
+func (ms *MockServer) AddRPCRoute(method string, route MockRoute) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	ms.rpcRoutes[method] = route
+}
+
+// CallCount returns the number of times a specific endpoint or RPC method was called
 func (ms *MockServer) CallCount(path string) int {
 	ms.mu.RLock()
 	defer ms.mu.RUnlock()
@@ -203,6 +266,33 @@ func ServerErrorRoute() MockRoute {
 	}
 }
 
+// RPCSuccessRoute creates a route with a JSON-RPC 2.0 result envelope
+func RPCSuccessRoute(result interface{}) MockRoute {
+	return MockRoute{
+		StatusCode: http.StatusOK,
+		Body: map[string]interface{}{
+			"jsonrpc": "2.0",
+			"id":      1,
+			"result":  result,
+		},
+	}
+}
+
+// RPCErrorRoute creates a route with a JSON-RPC 2.0 error envelope
+func RPCErrorRoute(code int, message string) MockRoute {
+	return MockRoute{
+		StatusCode: http.StatusOK,
+		Body: map[string]interface{}{
+			"jsonrpc": "2.0",
+			"id":      1,
+			"error": map[string]interface{}{
+				"code":    code,
+				"message": message,
+			},
+		},
+	}
+}
+
 // SuccessRoute creates a route with a successful response
 func SuccessRoute(body interface{}) MockRoute {
 	return MockRoute{
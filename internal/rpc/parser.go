@@ -20,9 +20,19 @@ import (
 
 // parseTransactionResponse converts a Horizon transaction into a TransactionResponse
 func parseTransactionResponse(tx hProtocol.Transaction) *TransactionResponse {
+	status := TxStatusSuccess
+	if !tx.Successful {
+		status = TxStatusFailed
+	}
+
 	return &TransactionResponse{
-		EnvelopeXdr:   tx.EnvelopeXdr,
-		ResultXdr:     tx.ResultXdr,
-		ResultMetaXdr: tx.ResultMetaXdr,
+		Hash:            tx.Hash,
+		EnvelopeXdr:     tx.EnvelopeXdr,
+		ResultXdr:       tx.ResultXdr,
+		ResultMetaXdr:   tx.ResultMetaXdr,
+		LedgerSequence:  uint32(tx.Ledger),
+		LedgerCloseTime: tx.LedgerCloseTime,
+		Status:          status,
+		Source:          SourceHorizon,
 	}
 }
@@ -0,0 +1,369 @@
+// Copyright (c) 2026 dotandev
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dotandev/hintents/internal/logger"
+	"github.com/stellar/go/clients/horizonclient"
+)
+
+const (
+	// poolFailureThreshold is the number of consecutive failures after which
+	// an endpoint is considered unhealthy
+	poolFailureThreshold = 3
+	// poolMinScore is the health score below which an endpoint is considered unhealthy
+	poolMinScore = 0.5
+	// poolCooldown is how long an unhealthy endpoint is skipped before it is
+	// given another chance
+	poolCooldown = 30 * time.Second
+	// poolScoreWeight is the weight of the latest outcome in the health score
+	poolScoreWeight = 0.3
+	// poolLatencyWeight is the weight of the latest sample in the average latency
+	poolLatencyWeight = 0.3
+)
+
+// EndpointStats is a snapshot of the health of one endpoint in an EndpointPool
+type EndpointStats struct {
+	URL                 string        `json:"url"`
+	Requests            int           `json:"requests"`
+	Failures            int           `json:"failures"`
+	ConsecutiveFailures int           `json:"consecutive_failures"`
+	AvgLatency          time.Duration `json:"avg_latency_ns"`
+	Score               float64       `json:"score"`
+	Healthy             bool          `json:"healthy"`
+	LastError           string        `json:"last_error,omitempty"`
+	LastFailure         time.Time     `json:"last_failure,omitempty"`
+}
+
+type endpointState struct {
+	EndpointStats
+	priority int
+}
+
+// EndpointPool tracks latency and errors for an ordered list of equivalent
+// endpoints and hands them out healthiest first.
+//
+// Every endpoint starts with a score of 1. Each request moves the score towards
+// 1 on success and towards 0 on failure. An endpoint is unhealthy once its score
+// drops below 0.5 or it fails 3 times in a row; it is then tried only after every
+// healthy endpoint, until a 30 second cooldown lets it compete again.
+type EndpointPool struct {
+	mu        sync.Mutex
+	endpoints []*endpointState
+	now       func() time.Time
+}
+
+// NewEndpointPool creates a pool over urls, in priority order. Empty and
+// duplicate URLs are ignored.
+func NewEndpointPool(urls []string) *EndpointPool {
+	p := &EndpointPool{now: time.Now}
+	seen := make(map[string]bool)
+	for _, url := range urls {
+		if url == "" || seen[url] {
+			continue
+		}
+		seen[url] = true
+		p.endpoints = append(p.endpoints, &endpointState{
+			EndpointStats: EndpointStats{URL: url, Score: 1, Healthy: true},
+			priority:      len(p.endpoints),
+		})
+	}
+	return p
+}
+
+// Len returns the number of endpoints in the pool. A nil pool is empty.
+func (p *EndpointPool) Len() int {
+	if p == nil {
+		return 0
+	}
+	return len(p.endpoints)
+}
+
+// URLs returns the endpoints in the order they should be tried: healthy ones
+// in priority order, then unhealthy ones from the best score to the worst.
+func (p *EndpointPool) URLs() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	ordered := make([]*endpointState, len(p.endpoints))
+	copy(ordered, p.endpoints)
+
+	healthy := make(map[*endpointState]bool, len(ordered))
+	for _, e := range ordered {
+		healthy[e] = p.healthy(e)
+	}
+
+	sort.SliceStable(ordered, func(i, j int) bool {
+		a, b := ordered[i], ordered[j]
+		if healthy[a] != healthy[b] {
+			return healthy[a]
+		}
+		if !healthy[a] && a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		return a.priority < b.priority
+	})
+
+	urls := make([]string, len(ordered))
+	for i, e := range ordered {
+		urls[i] = e.URL
+	}
+	return urls
+}
+
+// RecordSuccess records a successful request to url that took latency
+func (p *EndpointPool) RecordSuccess(url string, latency time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	e := p.find(url)
+	if e == nil {
+		return
+	}
+	e.Requests++
+	e.ConsecutiveFailures = 0
+	e.Score += poolScoreWeight * (1 - e.Score)
+	e.recordLatency(latency)
+}
+
+// RecordFailure records a failed request to url
+func (p *EndpointPool) RecordFailure(url string, latency time.Duration, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	e := p.find(url)
+	if e == nil {
+		return
+	}
+	e.Requests++
+	e.Failures++
+	e.ConsecutiveFailures++
+	e.Score -= poolScoreWeight * e.Score
+	e.LastFailure = p.now()
+	if err != nil {
+		e.LastError = err.Error()
+	}
+	e.recordLatency(latency)
+}
+
+// Stats returns a snapshot of every endpoint, in priority order
+func (p *EndpointPool) Stats() []EndpointStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	stats := make([]EndpointStats, len(p.endpoints))
+	for i, e := range p.endpoints {
+		stats[i] = e.EndpointStats
+		stats[i].Healthy = p.healthy(e)
+	}
+	return stats
+}
+
+// Do calls fn with each endpoint in URLs order until one succeeds. Failures that
+// say something about the endpoint (transport errors, timeouts, rate limits and
+// unexpected HTTP statuses) move on to the next endpoint. Any other error is returned
+// straight away, as another endpoint would answer the same.
+// When every endpoint fails, the error of the last one is returned.
+func (p *EndpointPool) Do(ctx context.Context, fn func(url string) error) error {
+	var err error
+	for _, url := range p.URLs() {
+		start := p.now()
+		err = fn(url)
+		latency := p.now().Sub(start)
+
+		if ctx.Err() != nil {
+			return err
+		}
+		if err == nil || !isEndpointFailure(err) {
+			p.RecordSuccess(url, latency)
+			return err
+		}
+
+		p.RecordFailure(url, latency, err)
+		logger.Logger.Warn("RPC endpoint failed, trying next", "url", url, "error", err)
+	}
+	if err == nil {
+		return errors.New("no rpc endpoints configured")
+	}
+	return err
+}
+
+// doHorizon runs fn against the Horizon endpoints of pool, failing over between
+// them. Without pool endpoints, fn runs once against fallback. A pool endpoint
+// that fallback already serves uses fallback, so that its settings are kept;
+// other endpoints get a new client using httpClient.
+func doHorizon(ctx context.Context, pool *EndpointPool, httpClient *http.Client, fallback horizonclient.ClientInterface, fn func(horizonclient.ClientInterface) error) error {
+	if pool.Len() == 0 {
+		if fallback == nil {
+			return errors.New("horizon client is not configured")
+		}
+		return fn(fallback)
+	}
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return pool.Do(ctx, func(url string) error {
+		if servesURL(fallback, url) {
+			return fn(fallback)
+		}
+		return fn(&horizonclient.Client{HorizonURL: url, HTTP: httpClient})
+	})
+}
+
+// servesURL reports whether h is a Horizon client for url
+func servesURL(h horizonclient.ClientInterface, url string) bool {
+	c, ok := h.(*horizonclient.Client)
+	return ok && c != nil && strings.TrimSuffix(c.HorizonURL, "/") == strings.TrimSuffix(url, "/")
+}
+
+// isEndpointFailure reports whether err means the endpoint itself misbehaved,
+// as opposed to a valid answer the caller did not like. Soroban RPC answers
+// with HTTP 200 and a JSON-RPC error body, so any other status, such as a
+// 404 from a wrong path or a 403 from a proxy, is the endpoint's fault.
+// Horizon uses 4xx statuses for valid answers, like 404 for a missing
+// transaction.
+func isEndpointFailure(err error) bool {
+	switch e := err.(type) {
+	case *RateLimitError, *ServerError:
+		return true
+	case *horizonclient.Error:
+		status := e.Problem.Status
+		return status == http.StatusTooManyRequests || status >= http.StatusInternalServerError
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+	return errors.Is(err, context.DeadlineExceeded)
+}
+
+func (p *EndpointPool) healthy(e *endpointState) bool {
+	if e.ConsecutiveFailures < poolFailureThreshold && e.Score >= poolMinScore {
+		return true
+	}
+	return p.now().Sub(e.LastFailure) >= poolCooldown
+}
+
+func (p *EndpointPool) find(url string) *endpointState {
+	for _, e := range p.endpoints {
+		if e.URL == url {
+			return e
+		}
+	}
+	return nil
+}
+
+func (e *endpointState) recordLatency(latency time.Duration) {
+	if e.AvgLatency == 0 {
+		e.AvgLatency = latency
+		return
+	}
+	e.AvgLatency += time.Duration(poolLatencyWeight * float64(latency-e.AvgLatency))
+}
+
+// PoolStats reports the health of every endpoint a client can use
+type PoolStats struct {
+	Horizon []EndpointStats `json:"horizon"`
+	Soroban []EndpointStats `json:"soroban_rpc"`
+}
+
+// PoolStats returns a snapshot of the client's Horizon and Soroban RPC pools
+func (c *Client) PoolStats() PoolStats {
+	var stats PoolStats
+	if c.HorizonPool != nil {
+		stats.Horizon = c.HorizonPool.Stats()
+	}
+	if c.SorobanPool != nil {
+		stats.Soroban = c.SorobanPool.Stats()
+	}
+	return stats
+}
+
+// CheckEndpoints probes every endpoint of the client's pools rounds times and
+// records the outcome, without failing over. Horizon endpoints are probed with
+// a request to their root resource and Soroban RPC endpoints with getHealth.
+func (c *Client) CheckEndpoints(ctx context.Context, rounds int) {
+	httpClient := c.getHTTPClient()
+	for i := 0; i < rounds; i++ {
+		probePool(ctx, c.HorizonPool, func(url string) error {
+			return probeHorizon(ctx, httpClient, url)
+		})
+		probePool(ctx, c.SorobanPool, func(url string) error {
+			var health struct {
+				Status string `json:"status"`
+			}
+			if err := callJSONRPC(ctx, httpClient, url, "getHealth", nil, &health); err != nil {
+				return err
+			}
+			if health.Status != "healthy" {
+				return fmt.Errorf("soroban rpc reports status %q", health.Status)
+			}
+			return nil
+		})
+	}
+}
+
+func probePool(ctx context.Context, pool *EndpointPool, probe func(url string) error) {
+	if pool.Len() == 0 {
+		return
+	}
+	for _, url := range pool.URLs() {
+		if ctx.Err() != nil {
+			return
+		}
+		start := pool.now()
+		err := probe(url)
+		latency := pool.now().Sub(start)
+		if err != nil {
+			pool.RecordFailure(url, latency, err)
+		} else {
+			pool.RecordSuccess(url, latency)
+		}
+	}
+}
+
+func probeHorizon(ctx context.Context, httpClient *http.Client, url string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return &RateLimitError{Message: "rate limit exceeded, please try again later"}
+	case resp.StatusCode != http.StatusOK:
+		return &ServerError{
+			StatusCode: resp.StatusCode,
+			Message:    fmt.Sprintf("horizon error (status %d)", resp.StatusCode),
+		}
+	}
+	return nil
+}
@@ -0,0 +1,207 @@
+// Copyright (c) 2026 dotandev
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rpc
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stellar/go/clients/horizonclient"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNetworkConfig_Endpoints(t *testing.T) {
+	cfg := NetworkConfig{
+		HorizonURL:     "https://h1",
+		HorizonURLs:    []string{"https://h2", "https://h1", ""},
+		SorobanRPCURLs: []string{"https://s2"},
+	}
+
+	assert.Equal(t, []string{"https://h1", "https://h2"}, cfg.HorizonEndpoints())
+	assert.Equal(t, []string{"https://s2"}, cfg.SorobanEndpoints())
+}
+
+func TestEndpointPool_RotatesAwayFromUnhealthy(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	pool := NewEndpointPool([]string{"a", "b", "c"})
+	pool.now = func() time.Time { return now }
+
+	assert.Equal(t, []string{"a", "b", "c"}, pool.URLs())
+
+	for i := 0; i < poolFailureThreshold; i++ {
+		pool.RecordFailure("a", time.Millisecond, errors.New("boom"))
+	}
+	assert.Equal(t, []string{"b", "c", "a"}, pool.URLs())
+
+	stats := pool.Stats()
+	assert.False(t, stats[0].Healthy)
+	assert.Equal(t, 3, stats[0].Failures)
+	assert.Equal(t, "boom", stats[0].LastError)
+	assert.Less(t, stats[0].Score, poolMinScore)
+
+	// After the cooldown the primary competes again
+	now = now.Add(poolCooldown)
+	assert.Equal(t, []string{"a", "b", "c"}, pool.URLs())
+
+	pool.RecordSuccess("a", time.Millisecond)
+	assert.True(t, pool.Stats()[0].Healthy)
+	assert.Equal(t, 0, pool.Stats()[0].ConsecutiveFailures)
+}
+
+func TestEndpointPool_TracksLatency(t *testing.T) {
+	pool := NewEndpointPool([]string{"a"})
+	pool.RecordSuccess("a", 100*time.Millisecond)
+	pool.RecordSuccess("a", 200*time.Millisecond)
+
+	stats := pool.Stats()[0]
+	assert.Equal(t, 2, stats.Requests)
+	assert.Equal(t, 130*time.Millisecond, stats.AvgLatency)
+}
+
+func TestEndpointPool_Do(t *testing.T) {
+	t.Run("fails over on endpoint errors", func(t *testing.T) {
+		pool := NewEndpointPool([]string{"a", "b"})
+		var tried []string
+		err := pool.Do(context.Background(), func(url string) error {
+			tried = append(tried, url)
+			if url == "a" {
+				return &ServerError{StatusCode: http.StatusBadGateway, Message: "bad gateway"}
+			}
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"a", "b"}, tried)
+		assert.Equal(t, 1, pool.Stats()[0].Failures)
+		assert.Equal(t, 0, pool.Stats()[1].Failures)
+	})
+
+	t.Run("fails over on unexpected statuses", func(t *testing.T) {
+		pool := NewEndpointPool([]string{"a", "b"})
+		err := pool.Do(context.Background(), func(url string) error {
+			if url == "a" {
+				return &ServerError{StatusCode: http.StatusNotFound, Message: "soroban rpc error (status 404)"}
+			}
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 1, pool.Stats()[0].Failures)
+		assert.Contains(t, pool.Stats()[0].LastError, "status 404")
+	})
+
+	t.Run("returns answers without failing over", func(t *testing.T) {
+		pool := NewEndpointPool([]string{"a", "b"})
+		calls := 0
+		err := pool.Do(context.Background(), func(url string) error {
+			calls++
+			return &TransactionNotFoundError{Message: "not found"}
+		})
+		assert.True(t, IsTransactionNotFound(err))
+		assert.Equal(t, 1, calls)
+		assert.Equal(t, 0, pool.Stats()[0].Failures)
+	})
+
+	t.Run("returns last error when all fail", func(t *testing.T) {
+		pool := NewEndpointPool([]string{"a", "b"})
+		err := pool.Do(context.Background(), func(url string) error {
+			return &RateLimitError{Message: "rate limited by " + url}
+		})
+		require.Error(t, err)
+		assert.Equal(t, "rate limited by b", err.Error())
+	})
+}
+
+func TestDoHorizon_KeepsConfiguredClient(t *testing.T) {
+	configured := &horizonclient.Client{HorizonURL: "https://h1/", HTTP: &http.Client{Timeout: time.Second}}
+
+	var used []horizonclient.ClientInterface
+	err := doHorizon(context.Background(), NewEndpointPool([]string{"https://h1", "https://h2"}), nil, configured, func(h horizonclient.ClientInterface) error {
+		used = append(used, h)
+		return &RateLimitError{Message: "rate limited"}
+	})
+	require.Error(t, err)
+	require.Len(t, used, 2)
+
+	// The endpoint the configured client serves uses it as is
+	assert.Same(t, configured, used[0])
+	// Other endpoints get a client of their own
+	other, ok := used[1].(*horizonclient.Client)
+	require.True(t, ok)
+	assert.Equal(t, "https://h2", other.HorizonURL)
+}
+
+func TestGetLedgerEntries_FailsOverBetweenEndpoints(t *testing.T) {
+	bad := NewMockServer(nil)
+	defer bad.Close()
+	bad.AddRPCRoute("getLedgerEntries", ServerErrorRoute())
+
+	good := newLedgerEntriesServer(t, LedgerEntryResult{Key: "k", Xdr: "xdr-k"})
+
+	client := &Client{
+		SorobanURL:  bad.URL(),
+		SorobanPool: NewEndpointPool([]string{bad.URL(), good.URL()}),
+		Retry:       fastRetry,
+	}
+
+	for i := 0; i < 4; i++ {
+		entries, err := client.GetLedgerEntries(context.Background(), []string{"k"})
+		require.NoError(t, err)
+		assert.Equal(t, "xdr-k", entries["k"])
+	}
+
+	// Two failures push the score below poolMinScore, after which the bad
+	// endpoint is no longer tried first
+	assert.Equal(t, 2, bad.CallCount("getLedgerEntries"))
+	assert.Equal(t, 4, good.CallCount("getLedgerEntries"))
+
+	stats := client.PoolStats().Soroban
+	require.Len(t, stats, 2)
+	assert.False(t, stats[0].Healthy)
+	assert.True(t, stats[1].Healthy)
+}
+
+func TestCheckEndpoints(t *testing.T) {
+	soroban := NewMockServer(nil)
+	defer soroban.Close()
+	soroban.AddRPCRoute("getHealth", RPCSuccessRoute(map[string]interface{}{"status": "healthy"}))
+
+	horizon := NewMockServer(map[string]MockRoute{"/": SuccessRoute(map[string]string{})})
+	defer horizon.Close()
+
+	client, err := NewCustomClient(NetworkConfig{
+		Name:              "local",
+		HorizonURL:        horizon.URL() + "/",
+		HorizonURLs:       []string{"http://127.0.0.1:1/"},
+		NetworkPassphrase: "Local Network",
+		SorobanRPCURL:     soroban.URL(),
+	})
+	require.NoError(t, err)
+
+	client.CheckEndpoints(context.Background(), 2)
+	stats := client.PoolStats()
+
+	require.Len(t, stats.Horizon, 2)
+	assert.Equal(t, 2, stats.Horizon[0].Requests)
+	assert.Equal(t, 0, stats.Horizon[0].Failures)
+	assert.Equal(t, 2, stats.Horizon[1].Failures)
+	assert.NotEmpty(t, stats.Horizon[1].LastError)
+
+	require.Len(t, stats.Soroban, 1)
+	assert.Equal(t, 2, stats.Soroban[0].Requests)
+	assert.True(t, stats.Soroban[0].Healthy)
+}
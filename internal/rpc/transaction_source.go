@@ -0,0 +1,332 @@
+// Copyright (c) 2026 dotandev
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/stellar/go/clients/horizonclient"
+	hProtocol "github.com/stellar/go/protocols/horizon"
+)
+
+// Transaction source names reported in TransactionResponse.Source
+const (
+	SourceHorizon = "horizon"
+	SourceSoroban = "soroban-rpc"
+)
+
+// Soroban RPC getTransaction statuses
+const (
+	TxStatusSuccess  = "SUCCESS"
+	TxStatusFailed   = "FAILED"
+	TxStatusNotFound = "NOT_FOUND"
+)
+
+// TransactionSource is a backend capable of fetching a transaction by hash.
+// Client.GetTransaction tries each configured source in order and falls back
+// to the next one when a source fails.
+type TransactionSource interface {
+	Name() string
+	GetTransaction(ctx context.Context, hash string) (*TransactionResponse, error)
+}
+
+// TransactionNotFoundError indicates that a source does not know the transaction,
+// either because it never existed or because it fell outside the retention window.
+type TransactionNotFoundError struct {
+	Hash    string
+	Source  string
+	Message string
+}
+
+func (e *TransactionNotFoundError) Error() string {
+	return e.Message
+}
+
+// IsTransactionNotFound checks if error is a "transaction not found" error
+func IsTransactionNotFound(err error) bool {
+	_, ok := err.(*TransactionNotFoundError)
+	return ok
+}
+
+// HorizonTransactionSource fetches transactions through the Horizon API
+type HorizonTransactionSource struct {
+	Horizon horizonclient.ClientInterface
+	// Pool, when it has endpoints, replaces Horizon: every endpoint is tried
+	// healthiest first through a client built on HTTP.
+	Pool *EndpointPool
+	HTTP *http.Client
+}
+
+// NewHorizonTransactionSource creates a transaction source backed by Horizon
+func NewHorizonTransactionSource(horizon horizonclient.ClientInterface) *HorizonTransactionSource {
+	return &HorizonTransactionSource{Horizon: horizon}
+}
+
+// Name implements TransactionSource
+func (s *HorizonTransactionSource) Name() string {
+	return SourceHorizon
+}
+
+// GetTransaction implements TransactionSource
+func (s *HorizonTransactionSource) GetTransaction(ctx context.Context, hash string) (*TransactionResponse, error) {
+	var tx hProtocol.Transaction
+	err := doHorizon(ctx, s.Pool, s.HTTP, s.Horizon, func(h horizonclient.ClientInterface) error {
+		var err error
+		tx, err = h.TransactionDetail(hash)
+		return err
+	})
+	if err != nil {
+		if hErr, ok := err.(*horizonclient.Error); ok && hErr.Problem.Status == http.StatusNotFound {
+			return nil, &TransactionNotFoundError{
+				Hash:    hash,
+				Source:  SourceHorizon,
+				Message: fmt.Sprintf("transaction %s not found on horizon", hash),
+			}
+		}
+		return nil, err
+	}
+	return parseTransactionResponse(tx), nil
+}
+
+// SorobanTransactionSource fetches transactions through the Soroban RPC
+// getTransaction and getTransactions methods. Soroban RPC keeps its own
+// retention window and is the only option for RPC-only providers.
+type SorobanTransactionSource struct {
+	URL  string
+	HTTP *http.Client
+	// Pool, when it has endpoints, replaces URL: every endpoint is tried
+	// healthiest first.
+	Pool *EndpointPool
+}
+
+// NewSorobanTransactionSource creates a transaction source backed by Soroban RPC.
+// If httpClient is nil, http.DefaultClient is used.
+func NewSorobanTransactionSource(url string, httpClient *http.Client) *SorobanTransactionSource {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &SorobanTransactionSource{URL: url, HTTP: httpClient}
+}
+
+// Name implements TransactionSource
+func (s *SorobanTransactionSource) Name() string {
+	return SourceSoroban
+}
+
+type jsonRPCRequest struct {
+	Jsonrpc string      `json:"jsonrpc"`
+	ID      int         `json:"id"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params,omitempty"`
+}
+
+type jsonRPCError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type jsonRPCResponse struct {
+	Jsonrpc string          `json:"jsonrpc"`
+	ID      int             `json:"id"`
+	Result  json.RawMessage `json:"result"`
+	Error   *jsonRPCError   `json:"error,omitempty"`
+}
+
+// GetTransactionResult is the result of the Soroban RPC getTransaction method
+type GetTransactionResult struct {
+	Status                string `json:"status"`
+	LatestLedger          uint32 `json:"latestLedger"`
+	OldestLedger          uint32 `json:"oldestLedger"`
+	ApplicationOrder      int32  `json:"applicationOrder,omitempty"`
+	FeeBump               bool   `json:"feeBump,omitempty"`
+	EnvelopeXdr           string `json:"envelopeXdr,omitempty"`
+	ResultXdr             string `json:"resultXdr,omitempty"`
+	ResultMetaXdr         string `json:"resultMetaXdr,omitempty"`
+	Ledger                uint32 `json:"ledger,omitempty"`
+	LedgerCloseTime       int64  `json:"createdAt,string,omitempty"`
+	LatestLedgerCloseTime int64  `json:"latestLedgerCloseTime,string,omitempty"`
+	OldestLedgerCloseTime int64  `json:"oldestLedgerCloseTime,string,omitempty"`
+}
+
+// TransactionInfo is a single transaction returned by the Soroban RPC getTransactions method.
+// Unlike getTransaction, createdAt is encoded as a number here.
+type TransactionInfo struct {
+	Status           string `json:"status"`
+	TxHash           string `json:"txHash"`
+	ApplicationOrder int32  `json:"applicationOrder"`
+	FeeBump          bool   `json:"feeBump"`
+	EnvelopeXdr      string `json:"envelopeXdr"`
+	ResultXdr        string `json:"resultXdr"`
+	ResultMetaXdr    string `json:"resultMetaXdr"`
+	Ledger           uint32 `json:"ledger"`
+	LedgerCloseTime  int64  `json:"createdAt"`
+}
+
+// GetTransactionsResult is the result of the Soroban RPC getTransactions method
+type GetTransactionsResult struct {
+	Transactions []TransactionInfo `json:"transactions"`
+	LatestLedger uint32            `json:"latestLedger"`
+	OldestLedger uint32            `json:"oldestLedger"`
+	Cursor       string            `json:"cursor"`
+}
+
+// GetTransaction implements TransactionSource
+func (s *SorobanTransactionSource) GetTransaction(ctx context.Context, hash string) (*TransactionResponse, error) {
+	var result GetTransactionResult
+	if err := s.call(ctx, "getTransaction", map[string]string{"hash": hash}, &result); err != nil {
+		return nil, err
+	}
+
+	if result.Status == TxStatusNotFound {
+		return nil, &TransactionNotFoundError{
+			Hash:   hash,
+			Source: SourceSoroban,
+			Message: fmt.Sprintf("transaction %s not found on soroban rpc (retained ledgers %d-%d)",
+				hash, result.OldestLedger, result.LatestLedger),
+		}
+	}
+
+	return &TransactionResponse{
+		EnvelopeXdr:     result.EnvelopeXdr,
+		ResultXdr:       result.ResultXdr,
+		ResultMetaXdr:   result.ResultMetaXdr,
+		LedgerSequence:  result.Ledger,
+		LedgerCloseTime: unixTime(result.LedgerCloseTime),
+		Status:          result.Status,
+		Source:          SourceSoroban,
+	}, nil
+}
+
+// GetTransactions lists transactions starting at startLedger. When cursor is
+// non-empty it takes precedence over startLedger, as required by Soroban RPC.
+// The returned cursor can be passed back to fetch the next page.
+func (s *SorobanTransactionSource) GetTransactions(ctx context.Context, startLedger uint32, cursor string, limit uint) ([]*TransactionResponse, string, error) {
+	pagination := map[string]interface{}{}
+	if cursor != "" {
+		pagination["cursor"] = cursor
+	}
+	if limit > 0 {
+		pagination["limit"] = limit
+	}
+	params := map[string]interface{}{"pagination": pagination}
+	if cursor == "" {
+		params["startLedger"] = startLedger
+	}
+
+	var result GetTransactionsResult
+	if err := s.call(ctx, "getTransactions", params, &result); err != nil {
+		return nil, "", err
+	}
+
+	txs := make([]*TransactionResponse, 0, len(result.Transactions))
+	for _, info := range result.Transactions {
+		txs = append(txs, &TransactionResponse{
+			Hash:            info.TxHash,
+			EnvelopeXdr:     info.EnvelopeXdr,
+			ResultXdr:       info.ResultXdr,
+			ResultMetaXdr:   info.ResultMetaXdr,
+			LedgerSequence:  info.Ledger,
+			LedgerCloseTime: unixTime(info.LedgerCloseTime),
+			Status:          info.Status,
+			Source:          SourceSoroban,
+		})
+	}
+	return txs, result.Cursor, nil
+}
+
+// call performs a single JSON-RPC request and decodes the result into out
+func (s *SorobanTransactionSource) call(ctx context.Context, method string, params interface{}, out interface{}) error {
+	if s.Pool.Len() == 0 {
+		return callJSONRPC(ctx, s.HTTP, s.URL, method, params, out)
+	}
+	return s.Pool.Do(ctx, func(url string) error {
+		return callJSONRPC(ctx, s.HTTP, url, method, params, out)
+	})
+}
+
+// callJSONRPC performs a single JSON-RPC request against a Soroban RPC endpoint
+// and decodes the result into out. HTTP failures are reported as typed errors:
+// RateLimitError for 429 and ServerError for any other non-200 status.
+func callJSONRPC(ctx context.Context, httpClient *http.Client, url, method string, params interface{}, out interface{}) error {
+	if url == "" {
+		return fmt.Errorf("soroban rpc url is not configured")
+	}
+
+	bodyBytes, err := json.Marshal(jsonRPCRequest{
+		Jsonrpc: "2.0",
+		ID:      1,
+		Method:  method,
+		Params:  params,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewBuffer(bodyBytes))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return &RateLimitError{
+			Message:    "rate limit exceeded, please try again later",
+			RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+		}
+	}
+	if resp.StatusCode != http.StatusOK {
+		return &ServerError{
+			StatusCode: resp.StatusCode,
+			Message:    fmt.Sprintf("soroban rpc error (status %d)", resp.StatusCode),
+			RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+		}
+	}
+
+	respBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	var rpcResp jsonRPCResponse
+	if err := json.Unmarshal(respBytes, &rpcResp); err != nil {
+		return fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	if rpcResp.Error != nil {
+		return fmt.Errorf("rpc error: %s (code %d)", rpcResp.Error.Message, rpcResp.Error.Code)
+	}
+	if err := json.Unmarshal(rpcResp.Result, out); err != nil {
+		return fmt.Errorf("failed to unmarshal %s result: %w", method, err)
+	}
+	return nil
+}
+
+func unixTime(sec int64) time.Time {
+	if sec == 0 {
+		return time.Time{}
+	}
+	return time.Unix(sec, 0).UTC()
+}
@@ -0,0 +1,235 @@
+// Copyright (c) 2026 dotandev
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rpc
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stellar/go/clients/horizonclient"
+	hProtocol "github.com/stellar/go/protocols/horizon"
+	"github.com/stellar/go/support/render/problem"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func sorobanTxResult(status string) map[string]interface{} {
+	return map[string]interface{}{
+		"status":        status,
+		"latestLedger":  2000,
+		"oldestLedger":  1000,
+		"envelopeXdr":   "rpc-envelope-xdr",
+		"resultXdr":     "rpc-result-xdr",
+		"resultMetaXdr": "rpc-meta-xdr",
+		"ledger":        1500,
+		"createdAt":     "1700000000",
+	}
+}
+
+func TestSorobanTransactionSource_GetTransaction(t *testing.T) {
+	server := NewMockServer(nil)
+	defer server.Close()
+	server.AddRPCRoute("getTransaction", RPCSuccessRoute(sorobanTxResult(TxStatusFailed)))
+
+	source := NewSorobanTransactionSource(server.URL(), nil)
+	resp, err := source.GetTransaction(context.Background(), "abc123")
+	require.NoError(t, err)
+
+	assert.Equal(t, "rpc-envelope-xdr", resp.EnvelopeXdr)
+	assert.Equal(t, "rpc-result-xdr", resp.ResultXdr)
+	assert.Equal(t, "rpc-meta-xdr", resp.ResultMetaXdr)
+	assert.Equal(t, uint32(1500), resp.LedgerSequence)
+	assert.Equal(t, time.Unix(1700000000, 0).UTC(), resp.LedgerCloseTime)
+	assert.Equal(t, TxStatusFailed, resp.Status)
+	assert.Equal(t, SourceSoroban, resp.Source)
+	assert.Equal(t, 1, server.CallCount("getTransaction"))
+}
+
+func TestSorobanTransactionSource_NotFound(t *testing.T) {
+	server := NewMockServer(nil)
+	defer server.Close()
+	server.AddRPCRoute("getTransaction", RPCSuccessRoute(map[string]interface{}{
+		"status":       TxStatusNotFound,
+		"latestLedger": 2000,
+		"oldestLedger": 1000,
+	}))
+
+	source := NewSorobanTransactionSource(server.URL(), nil)
+	_, err := source.GetTransaction(context.Background(), "missing")
+	require.Error(t, err)
+	assert.True(t, IsTransactionNotFound(err))
+	assert.Contains(t, err.Error(), "1000-2000")
+}
+
+func TestSorobanTransactionSource_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		route   MockRoute
+		checkFn func(t *testing.T, err error)
+	}{
+		{
+			name:  "rpc error",
+			route: RPCErrorRoute(-32602, "invalid hash"),
+			checkFn: func(t *testing.T, err error) {
+				assert.Contains(t, err.Error(), "invalid hash")
+			},
+		},
+		{
+			name:  "rate limited",
+			route: RateLimitRoute(),
+			checkFn: func(t *testing.T, err error) {
+				assert.True(t, IsRateLimitError(err))
+			},
+		},
+		{
+			name:  "server error",
+			route: ServerErrorRoute(),
+			checkFn: func(t *testing.T, err error) {
+				assert.Contains(t, err.Error(), "status 500")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := NewMockServer(nil)
+			defer server.Close()
+			server.AddRPCRoute("getTransaction", tt.route)
+
+			_, err := NewSorobanTransactionSource(server.URL(), nil).GetTransaction(context.Background(), "abc")
+			require.Error(t, err)
+			tt.checkFn(t, err)
+		})
+	}
+}
+
+func TestSorobanTransactionSource_GetTransactions(t *testing.T) {
+	server := NewMockServer(nil)
+	defer server.Close()
+	server.AddRPCRoute("getTransactions", RPCSuccessRoute(map[string]interface{}{
+		"transactions": []map[string]interface{}{
+			{
+				"status":        TxStatusSuccess,
+				"txHash":        "hash1",
+				"envelopeXdr":   "env1",
+				"resultXdr":     "res1",
+				"resultMetaXdr": "meta1",
+				"ledger":        1500,
+				"createdAt":     1700000000,
+			},
+			{
+				"status":        TxStatusFailed,
+				"txHash":        "hash2",
+				"envelopeXdr":   "env2",
+				"resultXdr":     "res2",
+				"resultMetaXdr": "meta2",
+				"ledger":        1501,
+				"createdAt":     1700000005,
+			},
+		},
+		"latestLedger": 2000,
+		"oldestLedger": 1000,
+		"cursor":       "6442450944",
+	}))
+
+	txs, cursor, err := NewSorobanTransactionSource(server.URL(), nil).GetTransactions(context.Background(), 1500, "", 2)
+	require.NoError(t, err)
+	require.Len(t, txs, 2)
+	assert.Equal(t, "6442450944", cursor)
+	assert.Equal(t, "hash2", txs[1].Hash)
+	assert.Equal(t, uint32(1501), txs[1].LedgerSequence)
+	assert.Equal(t, time.Unix(1700000005, 0).UTC(), txs[1].LedgerCloseTime)
+	assert.Equal(t, TxStatusFailed, txs[1].Status)
+}
+
+func TestGetTransaction_FallsBackToSorobanRPC(t *testing.T) {
+	server := NewMockServer(nil)
+	defer server.Close()
+	server.AddRPCRoute("getTransaction", RPCSuccessRoute(sorobanTxResult(TxStatusSuccess)))
+
+	mock := &mockHorizonClient{
+		TransactionDetailFunc: func(hash string) (hProtocol.Transaction, error) {
+			return hProtocol.Transaction{}, &horizonclient.Error{
+				Problem: problem.P{Status: http.StatusNotFound, Title: "Resource Missing"},
+			}
+		},
+	}
+	client := &Client{Horizon: mock, Network: Testnet, SorobanURL: server.URL()}
+
+	resp, err := client.GetTransaction(context.Background(), "abc123")
+	require.NoError(t, err)
+	assert.Equal(t, SourceSoroban, resp.Source)
+	assert.Equal(t, "abc123", resp.Hash)
+	assert.Equal(t, "rpc-meta-xdr", resp.ResultMetaXdr)
+}
+
+func TestGetTransaction_FallsBackToHorizon(t *testing.T) {
+	server := NewMockServer(nil)
+	defer server.Close()
+	server.AddRPCRoute("getTransaction", ServerErrorRoute())
+
+	closeTime := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	mock := &mockHorizonClient{
+		TransactionDetailFunc: func(hash string) (hProtocol.Transaction, error) {
+			return hProtocol.Transaction{
+				Hash:            hash,
+				Successful:      false,
+				Ledger:          42,
+				LedgerCloseTime: closeTime,
+				EnvelopeXdr:     "envelope-xdr",
+				ResultXdr:       "result-xdr",
+				ResultMetaXdr:   "meta-xdr",
+			}, nil
+		},
+	}
+	client := &Client{Horizon: mock, Network: Testnet, SorobanURL: server.URL()}
+	client.TxSources = []TransactionSource{
+		NewSorobanTransactionSource(server.URL(), nil),
+		NewHorizonTransactionSource(mock),
+	}
+
+	resp, err := client.GetTransaction(context.Background(), "abc123")
+	require.NoError(t, err)
+	assert.Equal(t, SourceHorizon, resp.Source)
+	assert.Equal(t, "result-xdr", resp.ResultXdr)
+	assert.Equal(t, uint32(42), resp.LedgerSequence)
+	assert.Equal(t, closeTime, resp.LedgerCloseTime)
+	assert.Equal(t, TxStatusFailed, resp.Status)
+	assert.Equal(t, 1, server.CallCount("getTransaction"))
+}
+
+func TestGetTransaction_AllSourcesFail(t *testing.T) {
+	server := NewMockServer(nil)
+	defer server.Close()
+	server.AddRPCRoute("getTransaction", RPCSuccessRoute(map[string]interface{}{"status": TxStatusNotFound}))
+
+	mock := &mockHorizonClient{
+		TransactionDetailFunc: func(hash string) (hProtocol.Transaction, error) {
+			return hProtocol.Transaction{}, errors.New("connection refused")
+		},
+	}
+	client := &Client{Horizon: mock, Network: Testnet, SorobanURL: server.URL()}
+
+	_, err := client.GetTransaction(context.Background(), "abc123")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "horizon: connection refused")
+	assert.Contains(t, err.Error(), "soroban-rpc: transaction abc123 not found")
+
+	var notFound *TransactionNotFoundError
+	assert.True(t, errors.As(err, &notFound))
+}
@@ -20,6 +20,8 @@ import (
 	"math/big"
 	"strings"
 
+	"github.com/dotandev/hintents/internal/decoder"
+	"github.com/dotandev/hintents/internal/diagnostic"
 	"github.com/stellar/go/xdr"
 )
 
@@ -27,18 +29,18 @@ import (
 type Severity string
 
 const (
-	SeverityHigh     Severity = "HIGH"
-	SeverityMedium   Severity = "MEDIUM"
-	SeverityLow      Severity = "LOW"
-	SeverityInfo     Severity = "INFO"
+	SeverityHigh   Severity = "HIGH"
+	SeverityMedium Severity = "MEDIUM"
+	SeverityLow    Severity = "LOW"
+	SeverityInfo   Severity = "INFO"
 )
 
 // FindingType categorizes the security issue
 type FindingType string
 
 const (
-	FindingVerifiedRisk   FindingType = "VERIFIED_RISK"
-	FindingHeuristicWarn  FindingType = "HEURISTIC_WARNING"
+	FindingVerifiedRisk  FindingType = "VERIFIED_RISK"
+	FindingHeuristicWarn FindingType = "HEURISTIC_WARNING"
 )
 
 // Finding represents a security vulnerability or warning
@@ -63,7 +65,7 @@ func NewDetector() *Detector {
 }
 
 // Analyze performs security checks on transaction data
-func (d *Detector) Analyze(envelopeXdr, resultMetaXdr string, events []string, logs []string) []Finding {
+func (d *Detector) Analyze(envelopeXdr, resultMetaXdr string, events []diagnostic.Event, logs []string) []Finding {
 	d.findings = make([]Finding, 0)
 
 	// Decode envelope
@@ -121,18 +123,18 @@ func (d *Detector) checkContractValueTransfer(op xdr.Operation) {
 	if hostFn == nil {
 		return
 	}
-	
+
 	if hostFn.HostFunction.Type == xdr.HostFunctionTypeHostFunctionTypeInvokeContract {
 		invokeArgs := hostFn.HostFunction.InvokeContract
 		if invokeArgs == nil {
 			return
 		}
-		
+
 		// Look for amount parameters (common in transfer functions)
 		for _, arg := range invokeArgs.Args {
 			if arg.Type == xdr.ScValTypeScvI128 || arg.Type == xdr.ScValTypeScvU128 {
-				amount := extractAmount(arg)
-				if amount != nil && amount.Cmp(big.NewInt(100000000000000)) > 0 { // 10M tokens (assuming 7 decimals)
+				amount, ok := decoder.ScValInt(arg)
+				if ok && amount.Cmp(big.NewInt(100000000000000)) > 0 { // 10M tokens (assuming 7 decimals)
 					d.addFinding(Finding{
 						Type:        FindingHeuristicWarn,
 						Severity:    SeverityMedium,
@@ -147,9 +149,9 @@ func (d *Detector) checkContractValueTransfer(op xdr.Operation) {
 }
 
 // checkReentrancyPatterns detects potential reentrancy vulnerabilities
-func (d *Detector) checkReentrancyPatterns(envelope xdr.TransactionEnvelope, events []string) {
+func (d *Detector) checkReentrancyPatterns(envelope xdr.TransactionEnvelope, events []diagnostic.Event) {
 	ops := extractOperations(envelope)
-	
+
 	// Count contract invocations
 	invocationCount := 0
 	for _, op := range ops {
@@ -162,7 +164,8 @@ func (d *Detector) checkReentrancyPatterns(envelope xdr.TransactionEnvelope, eve
 	if invocationCount > 1 {
 		hasStateChange := false
 		for _, event := range events {
-			if strings.Contains(event, "contract_data") || strings.Contains(event, "write") {
+			name := event.Name()
+			if name == "contract_data" || strings.Contains(name, "write") {
 				hasStateChange = true
 				break
 			}
@@ -181,13 +184,27 @@ func (d *Detector) checkReentrancyPatterns(envelope xdr.TransactionEnvelope, eve
 }
 
 // checkIntegerOverflow detects potential integer overflow issues
-func (d *Detector) checkIntegerOverflow(events []string, logs []string) {
+func (d *Detector) checkIntegerOverflow(events []diagnostic.Event, logs []string) {
+	// The host reports arithmetic overflow as a value error in the arith domain
+	for _, event := range events {
+		if scErr, ok := event.ScError(); ok && scErr.Code != nil && *scErr.Code == xdr.ScErrorCodeScecArithDomain {
+			d.addFinding(Finding{
+				Type:        FindingVerifiedRisk,
+				Severity:    SeverityHigh,
+				Title:       "Integer Overflow/Underflow Detected",
+				Description: "Arithmetic operation failed, indicating potential overflow or underflow",
+				Evidence:    event.String(),
+			})
+			return
+		}
+	}
+
 	overflowKeywords := []string{"overflow", "underflow"}
 	arithmeticKeywords := []string{"checked_add", "checked_sub", "checked_mul", "checked_div", "arithmetic"}
-	
+
 	for _, log := range logs {
 		logLower := strings.ToLower(log)
-		
+
 		// Check for explicit overflow/underflow mentions
 		for _, keyword := range overflowKeywords {
 			if strings.Contains(logLower, keyword) {
@@ -201,7 +218,7 @@ func (d *Detector) checkIntegerOverflow(events []string, logs []string) {
 				return
 			}
 		}
-		
+
 		// Check for arithmetic operation failures
 		for _, keyword := range arithmeticKeywords {
 			if strings.Contains(logLower, keyword) && (strings.Contains(logLower, "fail") || strings.Contains(logLower, "error")) {
@@ -218,40 +235,49 @@ func (d *Detector) checkIntegerOverflow(events []string, logs []string) {
 	}
 }
 
-// checkSuspiciousEvents analyzes diagnostic events for suspicious patterns
-func (d *Detector) checkSuspiciousEvents(events []string) {
+// checkSuspiciousEvents analyzes diagnostic events for authorization
+// failures and traps raised by the host
+func (d *Detector) checkSuspiciousEvents(events []diagnostic.Event) {
 	for _, event := range events {
-		eventLower := strings.ToLower(event)
-		
-		// Check for authorization failures
-		if strings.Contains(eventLower, "auth") && (strings.Contains(eventLower, "fail") || strings.Contains(eventLower, "invalid")) {
+		scErr, ok := event.ScError()
+		if !ok {
+			continue
+		}
+
+		switch {
+		case scErr.Type == xdr.ScErrorTypeSceAuth:
 			d.addFinding(Finding{
 				Type:        FindingVerifiedRisk,
 				Severity:    SeverityHigh,
 				Title:       "Authorization Failure",
 				Description: "Contract authorization check failed",
-				Evidence:    event,
+				Evidence:    event.String(),
 			})
-		}
-
-		// Check for panic/trap events
-		if strings.Contains(eventLower, "panic") || strings.Contains(eventLower, "trap") {
+		case scErr.Type == xdr.ScErrorTypeSceWasmVm:
 			d.addFinding(Finding{
 				Type:        FindingVerifiedRisk,
 				Severity:    SeverityHigh,
 				Title:       "Contract Panic/Trap",
 				Description: "Contract execution panicked or trapped",
-				Evidence:    event,
+				Evidence:    event.String(),
 			})
 		}
 	}
 }
 
 // checkAuthorizationBypass detects potential authorization bypass attempts
-func (d *Detector) checkAuthorizationBypass(events []string, logs []string) {
+func (d *Detector) checkAuthorizationBypass(events []diagnostic.Event, logs []string) {
 	hasAuthCheck := false
 	hasPrivilegedOp := false
 
+	// Account contracts are called through __check_auth when a signature is
+	// verified
+	for _, event := range events {
+		if event.Kind == diagnostic.KindFnCall && event.Function() == "__check_auth" {
+			hasAuthCheck = true
+		}
+	}
+
 	for _, log := range logs {
 		logLower := strings.ToLower(log)
 		if strings.Contains(logLower, "require_auth") || strings.Contains(logLower, "check_auth") {
@@ -297,27 +323,3 @@ func extractOperations(envelope xdr.TransactionEnvelope) []xdr.Operation {
 	}
 	return nil
 }
-
-func extractAmount(val xdr.ScVal) *big.Int {
-	switch val.Type {
-	case xdr.ScValTypeScvI128:
-		parts := val.I128
-		if parts == nil {
-			return nil
-		}
-		amount := new(big.Int).SetInt64(int64(parts.Hi))
-		amount.Lsh(amount, 64)
-		amount.Add(amount, new(big.Int).SetUint64(uint64(parts.Lo)))
-		return amount
-	case xdr.ScValTypeScvU128:
-		parts := val.U128
-		if parts == nil {
-			return nil
-		}
-		amount := new(big.Int).SetUint64(uint64(parts.Hi))
-		amount.Lsh(amount, 64)
-		amount.Add(amount, new(big.Int).SetUint64(uint64(parts.Lo)))
-		return amount
-	}
-	return nil
-}
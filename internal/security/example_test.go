@@ -17,17 +17,26 @@ package security_test
 import (
 	"fmt"
 
+	"github.com/dotandev/hintents/internal/diagnostic"
 	"github.com/dotandev/hintents/internal/security"
+	"github.com/stellar/go/xdr"
 )
 
 // Example demonstrates basic usage of the security detector
 func Example() {
 	detector := security.NewDetector()
 
-	// Simulate a transaction with security issues
-	events := []string{
-		"ContractEvent: transfer initiated",
-		"PANIC: arithmetic overflow",
+	// Simulate a transaction with security issues: the contract trapped
+	transfer, errorTopic := xdr.ScSymbol("transfer"), xdr.ScSymbol("error")
+	code := xdr.ScErrorCodeScecInvalidAction
+	events := []diagnostic.Event{
+		diagnostic.New(diagnostic.TypeContract, "",
+			[]xdr.ScVal{{Type: xdr.ScValTypeScvSymbol, Sym: &transfer}}, xdr.ScVal{}),
+		diagnostic.New(diagnostic.TypeDiagnostic, "",
+			[]xdr.ScVal{
+				{Type: xdr.ScValTypeScvSymbol, Sym: &errorTopic},
+				{Type: xdr.ScValTypeScvError, Error: &xdr.ScError{Type: xdr.ScErrorTypeSceWasmVm, Code: &code}},
+			}, xdr.ScVal{}),
 	}
 
 	logs := []string{
@@ -37,11 +46,9 @@ func Example() {
 
 	findings := detector.Analyze("", "", events, logs)
 
-	fmt.Printf("Found %d security issues:
-", len(findings))
+	fmt.Printf("Found %d security issues:\n", len(findings))
 	for i, finding := range findings {
-		fmt.Printf("%d. [%s] %s - %s
-", i+1, finding.Type, finding.Severity, finding.Title)
+		fmt.Printf("%d. [%s] %s - %s\n", i+1, finding.Type, finding.Severity, finding.Title)
 	}
 
 	// Output:
@@ -19,6 +19,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/dotandev/hintents/internal/diagnostic"
 	"github.com/stellar/go/xdr"
 )
 
@@ -32,11 +33,10 @@ func TestDetector_FlawedContract(t *testing.T) {
 	// 3. Contract panic
 	// 4. Large value transfer
 
-	events := []string{
-		"ContractEvent: transfer initiated",
-		"PANIC: arithmetic overflow in balance calculation",
-		"ContractEvent: state_write attempted",
-		"Error: transaction aborted",
+	events := []diagnostic.Event{
+		contractEvent("transfer"),
+		errorEvent(xdr.ScErrorTypeSceWasmVm, xdr.ScErrorCodeScecInvalidAction),
+		contractEvent("state_write"),
 	}
 
 	logs := []string{
@@ -139,31 +139,31 @@ func TestDetector_FlawedContract(t *testing.T) {
 // TestDetector_TypeDistinction verifies clear distinction between risk types
 func TestDetector_TypeDistinction(t *testing.T) {
 	tests := []struct {
-		name           string
-		events         []string
-		logs           []string
-		expectVerified bool
+		name            string
+		events          []diagnostic.Event
+		logs            []string
+		expectVerified  bool
 		expectHeuristic bool
 	}{
 		{
-			name:           "Verified Risk - Overflow",
-			events:         []string{},
-			logs:           []string{"overflow detected"},
-			expectVerified: true,
+			name:            "Verified Risk - Overflow",
+			events:          nil,
+			logs:            []string{"overflow detected"},
+			expectVerified:  true,
 			expectHeuristic: false,
 		},
 		{
-			name:           "Heuristic Warning - Auth Pattern",
-			events:         []string{},
-			logs:           []string{"admin operation", "privileged access"},
-			expectVerified: false,
+			name:            "Heuristic Warning - Auth Pattern",
+			events:          nil,
+			logs:            []string{"admin operation", "privileged access"},
+			expectVerified:  false,
 			expectHeuristic: true,
 		},
 		{
-			name:           "Verified Risk - Auth Failure",
-			events:         []string{"auth check failed"},
-			logs:           []string{},
-			expectVerified: true,
+			name:            "Verified Risk - Auth Failure",
+			events:          []diagnostic.Event{errorEvent(xdr.ScErrorTypeSceAuth, xdr.ScErrorCodeScecInvalidAction)},
+			logs:            []string{},
+			expectVerified:  true,
 			expectHeuristic: false,
 		},
 	}
@@ -0,0 +1,75 @@
+// Copyright (c) 2026 dotandev
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package session
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/dotandev/hintents/internal/logger"
+	"github.com/dotandev/hintents/internal/trace"
+)
+
+// SaveBreakpoints stores the breakpoint set of a session, replacing the
+// previous one. The session does not need to be saved itself, so trace files
+// opened outside a debug session can keep their breakpoints too.
+func (s *Store) SaveBreakpoints(ctx context.Context, sessionID string, set *trace.BreakpointSet) error {
+	if sessionID == "" {
+		return fmt.Errorf("session ID is required")
+	}
+
+	data, err := json.Marshal(set)
+	if err != nil {
+		return fmt.Errorf("failed to marshal breakpoints: %w", err)
+	}
+
+	query := `
+	INSERT INTO breakpoints (session_id, updated_at, breakpoints_json)
+	VALUES (?, ?, ?)
+	ON CONFLICT(session_id) DO UPDATE SET
+		updated_at = excluded.updated_at,
+		breakpoints_json = excluded.breakpoints_json
+	`
+	if _, err := s.db.ExecContext(ctx, query, sessionID, time.Now(), string(data)); err != nil {
+		return fmt.Errorf("failed to save breakpoints: %w", err)
+	}
+
+	logger.Logger.Debug("Breakpoints saved", "session", sessionID, "count", len(set.Breakpoints))
+	return nil
+}
+
+// LoadBreakpoints returns the breakpoint set of a session, empty if none was
+// saved
+func (s *Store) LoadBreakpoints(ctx context.Context, sessionID string) (*trace.BreakpointSet, error) {
+	query := `SELECT breakpoints_json FROM breakpoints WHERE session_id = ?`
+
+	var data string
+	err := s.db.QueryRowContext(ctx, query, sessionID).Scan(&data)
+	if err == sql.ErrNoRows {
+		return trace.NewBreakpointSet(), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load breakpoints: %w", err)
+	}
+
+	set := trace.NewBreakpointSet()
+	if err := json.Unmarshal([]byte(data), set); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal breakpoints: %w", err)
+	}
+	return set, nil
+}
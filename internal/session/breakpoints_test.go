@@ -0,0 +1,65 @@
+// Copyright (c) 2026 dotandev
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package session
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dotandev/hintents/internal/trace"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore_Breakpoints(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	store, err := NewStore()
+	require.NoError(t, err)
+	defer store.Close()
+	ctx := context.Background()
+
+	empty, err := store.LoadBreakpoints(ctx, "abcd1234-1700000000")
+	require.NoError(t, err)
+	assert.Empty(t, empty.Breakpoints)
+
+	set := trace.NewBreakpointSet()
+	_, err = set.Add(trace.Breakpoint{Function: "transfer", OnError: true})
+	require.NoError(t, err)
+	_, err = set.Add(trace.Breakpoint{HostStateKey: "balance", Disabled: true})
+	require.NoError(t, err)
+	require.NoError(t, store.SaveBreakpoints(ctx, "abcd1234-1700000000", set))
+
+	loaded, err := store.LoadBreakpoints(ctx, "abcd1234-1700000000")
+	require.NoError(t, err)
+	assert.Equal(t, set, loaded)
+
+	require.NoError(t, set.Remove(1))
+	require.NoError(t, store.SaveBreakpoints(ctx, "abcd1234-1700000000", set))
+	loaded, err = store.LoadBreakpoints(ctx, "abcd1234-1700000000")
+	require.NoError(t, err)
+	assert.Len(t, loaded.Breakpoints, 1, "saving replaces the previous set")
+
+	other, err := store.LoadBreakpoints(ctx, "other")
+	require.NoError(t, err)
+	assert.Empty(t, other.Breakpoints, "breakpoints are kept per session")
+
+	assert.Error(t, store.SaveBreakpoints(ctx, "", set))
+
+	require.NoError(t, store.Cleanup(ctx, -time.Hour, 0))
+	loaded, err = store.LoadBreakpoints(ctx, "abcd1234-1700000000")
+	require.NoError(t, err)
+	assert.Empty(t, loaded.Breakpoints, "expired breakpoints are cleaned up")
+}
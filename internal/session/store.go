@@ -102,7 +102,7 @@ func NewStore() (*Store, error) {
 	return store, nil
 }
 
-// initSchema creates the sessions table if it doesn't exist
+// initSchema creates the sessions and breakpoints tables if they don't exist
 func (s *Store) initSchema() error {
 	query := `
 	CREATE TABLE IF NOT EXISTS sessions (
@@ -124,6 +124,12 @@ func (s *Store) initSchema() error {
 	
 	CREATE INDEX IF NOT EXISTS idx_last_access ON sessions(last_access_at);
 	CREATE INDEX IF NOT EXISTS idx_tx_hash ON sessions(tx_hash);
+
+	CREATE TABLE IF NOT EXISTS breakpoints (
+		session_id TEXT PRIMARY KEY,
+		updated_at TIMESTAMP NOT NULL,
+		breakpoints_json TEXT NOT NULL
+	);
 	`
 
 	if _, err := s.db.Exec(query); err != nil {
@@ -301,6 +307,10 @@ func (s *Store) Delete(ctx context.Context, sessionID string) error {
 		return fmt.Errorf("session not found: %s", sessionID)
 	}
 
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM breakpoints WHERE session_id = ?`, sessionID); err != nil {
+		return fmt.Errorf("failed to delete session breakpoints: %w", err)
+	}
+
 	logger.Logger.Debug("Session deleted", "id", sessionID)
 	return nil
 }
@@ -322,6 +332,12 @@ func (s *Store) Cleanup(ctx context.Context, ttl time.Duration, maxSessions int)
 		logger.Logger.Debug("Cleaned up expired sessions", "count", expiredCount)
 	}
 
+	// Breakpoints can outlive their session, so they expire on their own
+	deleteBreakpoints := `DELETE FROM breakpoints WHERE updated_at < ?`
+	if _, err := s.db.ExecContext(ctx, deleteBreakpoints, cutoff); err != nil {
+		return fmt.Errorf("failed to delete expired breakpoints: %w", err)
+	}
+
 	// Enforce max sessions limit
 	if maxSessions > 0 {
 		countQuery := `SELECT COUNT(*) FROM sessions`
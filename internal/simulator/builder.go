@@ -16,23 +16,62 @@ package simulator
 
 import (
 	"fmt"
+	"maps"
+
+	"github.com/stellar/go/xdr"
 )
 
+// SimulationMode describes what kind of execution a SimulationRequest asks for
+type SimulationMode int
+
+const (
+	// ModeNetworkReplay replays a transaction fetched from the network.
+	// It requires the envelope and result meta XDR.
+	ModeNetworkReplay SimulationMode = iota
+	// ModeLocalWasm runs a local WASM file against mock state.
+	// It requires a WASM path and takes no envelope or result meta.
+	ModeLocalWasm
+)
+
+// String returns the name of the mode
+func (m SimulationMode) String() string {
+	switch m {
+	case ModeNetworkReplay:
+		return "network replay"
+	case ModeLocalWasm:
+		return "local WASM"
+	default:
+		return fmt.Sprintf("SimulationMode(%d)", int(m))
+	}
+}
+
 // SimulationRequestBuilder provides a fluent interface for building SimulationRequest objects.
 // It uses the builder pattern to make request construction more readable and less error-prone.
 //
+// The mode is inferred from the fields that are set: a request with a WASM path
+// is a local WASM run, anything else is a network replay. Build validates the
+// request for its mode and checks that the envelope and result meta decode.
+//
 // Example usage:
 //
 //	req, err := NewSimulationRequestBuilder().
 //		WithEnvelopeXDR("AAAAAgAAAA...").
 //		WithResultMetaXDR("AAAAAQAAA...").
 //		WithLedgerEntry("key1", "value1").
+//		WithTimestamp(1700000000).
 //		Build()
 type SimulationRequestBuilder struct {
-	envelopeXdr   string
-	resultMetaXdr string
-	ledgerEntries map[string]string
-	errors        []string
+	envelopeXdr    string
+	resultMetaXdr  string
+	ledgerEntries  map[string]string
+	timestamp      int64
+	ledgerSequence uint32
+	wasmPath       string
+	mockArgs       *[]string
+	profile        bool
+	authTraceOpts  *AuthTraceOptions
+	customAuthCfg  map[string]interface{}
+	errors         []string
 }
 
 // NewSimulationRequestBuilder creates a new builder instance.
@@ -44,14 +83,14 @@ func NewSimulationRequestBuilder() *SimulationRequestBuilder {
 }
 
 // WithEnvelopeXDR sets the XDR encoded TransactionEnvelope.
-// This is a required field for simulation.
+// This is a required field for network replay.
 func (b *SimulationRequestBuilder) WithEnvelopeXDR(xdr string) *SimulationRequestBuilder {
 	b.envelopeXdr = xdr
 	return b
 }
 
 // WithResultMetaXDR sets the XDR encoded TransactionResultMeta.
-// This contains historical data needed for replay.
+// This contains historical data needed for network replay.
 func (b *SimulationRequestBuilder) WithResultMetaXDR(xdr string) *SimulationRequestBuilder {
 	b.resultMetaXdr = xdr
 	return b
@@ -73,7 +112,8 @@ func (b *SimulationRequestBuilder) WithLedgerEntry(key, value string) *Simulatio
 }
 
 // WithLedgerEntries sets multiple ledger entries at once.
-// This replaces any previously set ledger entries.
+// This replaces any previously set ledger entries. entries is copied, so that
+// later changes to it do not reach the request.
 func (b *SimulationRequestBuilder) WithLedgerEntries(entries map[string]string) *SimulationRequestBuilder {
 	if entries == nil {
 		b.ledgerEntries = make(map[string]string)
@@ -92,41 +132,159 @@ func (b *SimulationRequestBuilder) WithLedgerEntries(entries map[string]string)
 		}
 	}
 
-	b.ledgerEntries = entries
+	b.ledgerEntries = maps.Clone(entries)
 	return b
 }
 
+// WithTimestamp overrides the ledger header timestamp (Unix epoch seconds).
+// Zero keeps the timestamp of the original ledger.
+func (b *SimulationRequestBuilder) WithTimestamp(timestamp int64) *SimulationRequestBuilder {
+	if timestamp < 0 {
+		b.errors = append(b.errors, fmt.Sprintf("timestamp cannot be negative: %d", timestamp))
+		return b
+	}
+	b.timestamp = timestamp
+	return b
+}
+
+// WithLedgerSequence overrides the ledger sequence number.
+// Zero keeps the sequence of the original ledger.
+func (b *SimulationRequestBuilder) WithLedgerSequence(sequence uint32) *SimulationRequestBuilder {
+	b.ledgerSequence = sequence
+	return b
+}
+
+// WithWasmPath sets the local WASM file to execute and switches the request to
+// local WASM mode.
+func (b *SimulationRequestBuilder) WithWasmPath(path string) *SimulationRequestBuilder {
+	if path == "" {
+		b.errors = append(b.errors, "WASM path cannot be empty")
+		return b
+	}
+	b.wasmPath = path
+	return b
+}
+
+// WithMockArgs sets the arguments passed to the contract in local WASM mode.
+func (b *SimulationRequestBuilder) WithMockArgs(args []string) *SimulationRequestBuilder {
+	mockArgs := make([]string, len(args))
+	copy(mockArgs, args)
+	b.mockArgs = &mockArgs
+	return b
+}
+
+// WithProfile enables CPU and memory profiling in the simulator.
+func (b *SimulationRequestBuilder) WithProfile(enabled bool) *SimulationRequestBuilder {
+	b.profile = enabled
+	return b
+}
+
+// WithAuthTraceOptions enables authorization tracing with the given options.
+func (b *SimulationRequestBuilder) WithAuthTraceOptions(opts AuthTraceOptions) *SimulationRequestBuilder {
+	if opts.MaxEventDepth < 0 {
+		b.errors = append(b.errors, fmt.Sprintf("auth trace max event depth cannot be negative: %d", opts.MaxEventDepth))
+		return b
+	}
+	b.authTraceOpts = &opts
+	return b
+}
+
+// WithCustomAuthConfig sets the configuration passed to custom account
+// contracts during authorization.
+func (b *SimulationRequestBuilder) WithCustomAuthConfig(cfg map[string]interface{}) *SimulationRequestBuilder {
+	b.customAuthCfg = cfg
+	return b
+}
+
+// Mode returns the mode the request will be built for
+func (b *SimulationRequestBuilder) Mode() SimulationMode {
+	if b.wasmPath != "" {
+		return ModeLocalWasm
+	}
+	return ModeNetworkReplay
+}
+
 // Build constructs and validates the final SimulationRequest.
-// Returns an error if required fields are missing or validation fails.
+// Returns an error if required fields are missing, fields that do not apply to
+// the request's mode are set, or the envelope or result meta do not decode.
 func (b *SimulationRequestBuilder) Build() (*SimulationRequest, error) {
 	// Check for any errors collected during building
 	if len(b.errors) > 0 {
 		return nil, fmt.Errorf("validation errors: %v", b.errors)
 	}
 
-	// Validate required fields
-	if b.envelopeXdr == "" {
-		return nil, fmt.Errorf("envelope XDR is required")
-	}
-
-	if b.resultMetaXdr == "" {
-		return nil, fmt.Errorf("result meta XDR is required")
+	switch b.Mode() {
+	case ModeLocalWasm:
+		if err := b.validateLocalWasm(); err != nil {
+			return nil, err
+		}
+	default:
+		if err := b.validateNetworkReplay(); err != nil {
+			return nil, err
+		}
 	}
 
 	// Build the request
 	req := &SimulationRequest{
-		EnvelopeXdr:   b.envelopeXdr,
-		ResultMetaXdr: b.resultMetaXdr,
+		EnvelopeXdr:    b.envelopeXdr,
+		ResultMetaXdr:  b.resultMetaXdr,
+		Timestamp:      b.timestamp,
+		LedgerSequence: b.ledgerSequence,
+		MockArgs:       b.mockArgs,
+		Profile:        b.profile,
+		AuthTraceOpts:  b.authTraceOpts,
+		CustomAuthCfg:  b.customAuthCfg,
+	}
+	if b.wasmPath != "" {
+		wasmPath := b.wasmPath
+		req.WasmPath = &wasmPath
 	}
 
 	// Only set ledger entries if there are any
 	if len(b.ledgerEntries) > 0 {
-		req.LedgerEntries = b.ledgerEntries
+		req.LedgerEntries = maps.Clone(b.ledgerEntries)
 	}
 
 	return req, nil
 }
 
+func (b *SimulationRequestBuilder) validateNetworkReplay() error {
+	// Validate required fields
+	if b.envelopeXdr == "" {
+		return fmt.Errorf("envelope XDR is required")
+	}
+
+	if b.resultMetaXdr == "" {
+		return fmt.Errorf("result meta XDR is required")
+	}
+
+	if b.mockArgs != nil {
+		return fmt.Errorf("mock arguments require a WASM path")
+	}
+
+	var envelope xdr.TransactionEnvelope
+	if err := xdr.SafeUnmarshalBase64(b.envelopeXdr, &envelope); err != nil {
+		return fmt.Errorf("invalid envelope XDR: %w", err)
+	}
+
+	var meta xdr.TransactionResultMeta
+	if err := xdr.SafeUnmarshalBase64(b.resultMetaXdr, &meta); err != nil {
+		return fmt.Errorf("invalid result meta XDR: %w", err)
+	}
+
+	return nil
+}
+
+func (b *SimulationRequestBuilder) validateLocalWasm() error {
+	if b.envelopeXdr != "" {
+		return fmt.Errorf("envelope XDR cannot be combined with a WASM path")
+	}
+	if b.resultMetaXdr != "" {
+		return fmt.Errorf("result meta XDR cannot be combined with a WASM path")
+	}
+	return nil
+}
+
 // MustBuild is like Build but panics if there's an error.
 // Use this only when you're certain the request is valid (e.g., in tests with known good data).
 func (b *SimulationRequestBuilder) MustBuild() *SimulationRequest {
@@ -139,9 +297,6 @@ func (b *SimulationRequestBuilder) MustBuild() *SimulationRequest {
 
 // Reset clears all fields and errors, allowing the builder to be reused.
 func (b *SimulationRequestBuilder) Reset() *SimulationRequestBuilder {
-	b.envelopeXdr = ""
-	b.resultMetaXdr = ""
-	b.ledgerEntries = make(map[string]string)
-	b.errors = make([]string, 0)
+	*b = *NewSimulationRequestBuilder()
 	return b
 }
@@ -15,27 +15,36 @@
 package simulator
 
 import (
+	"strings"
 	"testing"
 )
 
+// Minimal valid XDR fixtures: a bump sequence transaction and an empty
+// successful result meta
+const (
+	testEnvelopeXDR   = "AAAAAgAAAABi/B0L0JGythwN1lY0aypo19NHxvLCyO5tBEcCVvwF9wAAAGQAAAAAAAAAAQAAAAAAAAAAAAAAAQAAAAAAAAALAAAAAAAAAAIAAAAAAAAAAA=="
+	testEnvelope2XDR  = "AAAAAgAAAABi/B0L0JGythwN1lY0aypo19NHxvLCyO5tBEcCVvwF9wAAAGQAAAAAAAAAAgAAAAAAAAAAAAAAAQAAAAAAAAALAAAAAAAAAAIAAAAAAAAAAA=="
+	testResultMetaXDR = "AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAZAAAAAAAAAAAAAAAAAAAAAAAAAABAAAAAAAAAAA="
+)
+
 func TestSimulationRequestBuilder_Basic(t *testing.T) {
 	builder := NewSimulationRequestBuilder()
 
 	req, err := builder.
-		WithEnvelopeXDR("envelope_xdr_data").
-		WithResultMetaXDR("result_meta_xdr_data").
+		WithEnvelopeXDR(testEnvelopeXDR).
+		WithResultMetaXDR(testResultMetaXDR).
 		Build()
 
 	if err != nil {
 		t.Fatalf("expected no error, got: %v", err)
 	}
 
-	if req.EnvelopeXdr != "envelope_xdr_data" {
-		t.Errorf("expected EnvelopeXdr to be 'envelope_xdr_data', got: %s", req.EnvelopeXdr)
+	if req.EnvelopeXdr != testEnvelopeXDR {
+		t.Errorf("expected EnvelopeXdr to be the test envelope, got: %s", req.EnvelopeXdr)
 	}
 
-	if req.ResultMetaXdr != "result_meta_xdr_data" {
-		t.Errorf("expected ResultMetaXdr to be 'result_meta_xdr_data', got: %s", req.ResultMetaXdr)
+	if req.ResultMetaXdr != testResultMetaXDR {
+		t.Errorf("expected ResultMetaXdr to be the test result meta, got: %s", req.ResultMetaXdr)
 	}
 
 	if req.LedgerEntries != nil {
@@ -47,8 +56,8 @@ func TestSimulationRequestBuilder_WithLedgerEntry(t *testing.T) {
 	builder := NewSimulationRequestBuilder()
 
 	req, err := builder.
-		WithEnvelopeXDR("envelope").
-		WithResultMetaXDR("result").
+		WithEnvelopeXDR(testEnvelopeXDR).
+		WithResultMetaXDR(testResultMetaXDR).
 		WithLedgerEntry("key1", "value1").
 		WithLedgerEntry("key2", "value2").
 		Build()
@@ -80,8 +89,8 @@ func TestSimulationRequestBuilder_WithLedgerEntries(t *testing.T) {
 	}
 
 	req, err := builder.
-		WithEnvelopeXDR("envelope").
-		WithResultMetaXDR("result").
+		WithEnvelopeXDR(testEnvelopeXDR).
+		WithResultMetaXDR(testResultMetaXDR).
 		WithLedgerEntries(entries).
 		Build()
 
@@ -102,11 +111,33 @@ func TestSimulationRequestBuilder_WithLedgerEntries(t *testing.T) {
 	}
 }
 
+func TestSimulationRequestBuilder_CopiesLedgerEntries(t *testing.T) {
+	entries := map[string]string{"key1": "value1"}
+	builder := NewSimulationRequestBuilder().
+		WithEnvelopeXDR(testEnvelopeXDR).
+		WithResultMetaXDR(testResultMetaXDR).
+		WithLedgerEntries(entries)
+
+	req, err := builder.Build()
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	// Neither the caller's map nor the builder may change a built request
+	entries["key1"] = "changed"
+	entries["key2"] = "value2"
+	builder.WithLedgerEntry("key3", "value3")
+
+	if len(req.LedgerEntries) != 1 || req.LedgerEntries["key1"] != "value1" {
+		t.Errorf("expected the built entries to be unchanged, got: %v", req.LedgerEntries)
+	}
+}
+
 func TestSimulationRequestBuilder_MissingEnvelopeXDR(t *testing.T) {
 	builder := NewSimulationRequestBuilder()
 
 	_, err := builder.
-		WithResultMetaXDR("result").
+		WithResultMetaXDR(testResultMetaXDR).
 		Build()
 
 	if err == nil {
@@ -123,7 +154,7 @@ func TestSimulationRequestBuilder_MissingResultMetaXDR(t *testing.T) {
 	builder := NewSimulationRequestBuilder()
 
 	_, err := builder.
-		WithEnvelopeXDR("envelope").
+		WithEnvelopeXDR(testEnvelopeXDR).
 		Build()
 
 	if err == nil {
@@ -140,8 +171,8 @@ func TestSimulationRequestBuilder_EmptyLedgerEntryKey(t *testing.T) {
 	builder := NewSimulationRequestBuilder()
 
 	_, err := builder.
-		WithEnvelopeXDR("envelope").
-		WithResultMetaXDR("result").
+		WithEnvelopeXDR(testEnvelopeXDR).
+		WithResultMetaXDR(testResultMetaXDR).
 		WithLedgerEntry("", "value").
 		Build()
 
@@ -154,8 +185,8 @@ func TestSimulationRequestBuilder_EmptyLedgerEntryValue(t *testing.T) {
 	builder := NewSimulationRequestBuilder()
 
 	_, err := builder.
-		WithEnvelopeXDR("envelope").
-		WithResultMetaXDR("result").
+		WithEnvelopeXDR(testEnvelopeXDR).
+		WithResultMetaXDR(testResultMetaXDR).
 		WithLedgerEntry("key", "").
 		Build()
 
@@ -169,8 +200,8 @@ func TestSimulationRequestBuilder_MustBuild_Success(t *testing.T) {
 
 	// Should not panic
 	req := builder.
-		WithEnvelopeXDR("envelope").
-		WithResultMetaXDR("result").
+		WithEnvelopeXDR(testEnvelopeXDR).
+		WithResultMetaXDR(testResultMetaXDR).
 		MustBuild()
 
 	if req == nil {
@@ -196,8 +227,8 @@ func TestSimulationRequestBuilder_Reset(t *testing.T) {
 
 	// Build first request
 	req1, err := builder.
-		WithEnvelopeXDR("envelope1").
-		WithResultMetaXDR("result1").
+		WithEnvelopeXDR(testEnvelopeXDR).
+		WithResultMetaXDR(testResultMetaXDR).
 		WithLedgerEntry("key1", "value1").
 		Build()
 
@@ -205,23 +236,23 @@ func TestSimulationRequestBuilder_Reset(t *testing.T) {
 		t.Fatalf("expected no error for first build, got: %v", err)
 	}
 
-	if req1.EnvelopeXdr != "envelope1" {
-		t.Errorf("expected first request EnvelopeXdr to be 'envelope1', got: %s", req1.EnvelopeXdr)
+	if req1.EnvelopeXdr != testEnvelopeXDR {
+		t.Errorf("expected first request EnvelopeXdr to be the first test envelope, got: %s", req1.EnvelopeXdr)
 	}
 
 	// Reset and build second request
 	req2, err := builder.
 		Reset().
-		WithEnvelopeXDR("envelope2").
-		WithResultMetaXDR("result2").
+		WithEnvelopeXDR(testEnvelope2XDR).
+		WithResultMetaXDR(testResultMetaXDR).
 		Build()
 
 	if err != nil {
 		t.Fatalf("expected no error for second build, got: %v", err)
 	}
 
-	if req2.EnvelopeXdr != "envelope2" {
-		t.Errorf("expected second request EnvelopeXdr to be 'envelope2', got: %s", req2.EnvelopeXdr)
+	if req2.EnvelopeXdr != testEnvelope2XDR {
+		t.Errorf("expected second request EnvelopeXdr to be the second test envelope, got: %s", req2.EnvelopeXdr)
 	}
 
 	if req2.LedgerEntries != nil {
@@ -234,13 +265,13 @@ func TestSimulationRequestBuilder_MethodChaining(t *testing.T) {
 	builder := NewSimulationRequestBuilder()
 
 	result := builder.
-		WithEnvelopeXDR("envelope").
-		WithResultMetaXDR("result").
+		WithEnvelopeXDR(testEnvelopeXDR).
+		WithResultMetaXDR(testResultMetaXDR).
 		WithLedgerEntry("key1", "value1").
 		WithLedgerEntries(map[string]string{"key2": "value2"}).
 		Reset().
-		WithEnvelopeXDR("envelope2").
-		WithResultMetaXDR("result2")
+		WithEnvelopeXDR(testEnvelope2XDR).
+		WithResultMetaXDR(testResultMetaXDR)
 
 	if result != builder {
 		t.Error("expected method chaining to return the same builder instance")
@@ -251,8 +282,8 @@ func TestSimulationRequestBuilder_NilLedgerEntries(t *testing.T) {
 	builder := NewSimulationRequestBuilder()
 
 	req, err := builder.
-		WithEnvelopeXDR("envelope").
-		WithResultMetaXDR("result").
+		WithEnvelopeXDR(testEnvelopeXDR).
+		WithResultMetaXDR(testResultMetaXDR).
 		WithLedgerEntries(nil).
 		Build()
 
@@ -264,3 +295,205 @@ func TestSimulationRequestBuilder_NilLedgerEntries(t *testing.T) {
 		t.Errorf("expected LedgerEntries to be nil, got: %v", req.LedgerEntries)
 	}
 }
+
+func TestSimulationRequestBuilder_AllFields(t *testing.T) {
+	authOpts := AuthTraceOptions{Enabled: true, CaptureSigDetails: true, MaxEventDepth: 50}
+	customAuth := map[string]interface{}{"threshold": 2}
+
+	req, err := NewSimulationRequestBuilder().
+		WithEnvelopeXDR(testEnvelopeXDR).
+		WithResultMetaXDR(testResultMetaXDR).
+		WithTimestamp(1700000000).
+		WithLedgerSequence(12345).
+		WithProfile(true).
+		WithAuthTraceOptions(authOpts).
+		WithCustomAuthConfig(customAuth).
+		Build()
+
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	if req.Timestamp != 1700000000 {
+		t.Errorf("expected Timestamp 1700000000, got: %d", req.Timestamp)
+	}
+	if req.LedgerSequence != 12345 {
+		t.Errorf("expected LedgerSequence 12345, got: %d", req.LedgerSequence)
+	}
+	if !req.Profile {
+		t.Error("expected Profile to be enabled")
+	}
+	if req.AuthTraceOpts == nil || *req.AuthTraceOpts != authOpts {
+		t.Errorf("expected AuthTraceOpts %+v, got: %+v", authOpts, req.AuthTraceOpts)
+	}
+	if req.CustomAuthCfg["threshold"] != 2 {
+		t.Errorf("expected CustomAuthCfg to be set, got: %v", req.CustomAuthCfg)
+	}
+	if req.WasmPath != nil || req.MockArgs != nil {
+		t.Errorf("expected no local WASM fields, got WasmPath=%v MockArgs=%v", req.WasmPath, req.MockArgs)
+	}
+}
+
+func TestSimulationRequestBuilder_LocalWasm(t *testing.T) {
+	args := []string{"hello", "42"}
+	builder := NewSimulationRequestBuilder().
+		WithWasmPath("./contract.wasm").
+		WithMockArgs(args)
+
+	if builder.Mode() != ModeLocalWasm {
+		t.Fatalf("expected local WASM mode, got: %s", builder.Mode())
+	}
+
+	req, err := builder.Build()
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	if req.WasmPath == nil || *req.WasmPath != "./contract.wasm" {
+		t.Errorf("expected WasmPath './contract.wasm', got: %v", req.WasmPath)
+	}
+	if req.MockArgs == nil || len(*req.MockArgs) != 2 {
+		t.Fatalf("expected 2 mock args, got: %v", req.MockArgs)
+	}
+
+	// The builder keeps its own copy of the arguments
+	args[0] = "changed"
+	if (*req.MockArgs)[0] != "hello" {
+		t.Errorf("expected mock args to be copied, got: %v", *req.MockArgs)
+	}
+}
+
+func TestSimulationRequestBuilder_LocalWasmEmptyArgs(t *testing.T) {
+	req, err := NewSimulationRequestBuilder().
+		WithWasmPath("./contract.wasm").
+		WithMockArgs(nil).
+		Build()
+
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if req.MockArgs == nil || len(*req.MockArgs) != 0 {
+		t.Errorf("expected empty mock args, got: %v", req.MockArgs)
+	}
+}
+
+func TestSimulationRequestBuilder_ModeValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		builder *SimulationRequestBuilder
+		wantErr string
+	}{
+		{
+			name: "mock args without WASM path",
+			builder: NewSimulationRequestBuilder().
+				WithEnvelopeXDR(testEnvelopeXDR).
+				WithResultMetaXDR(testResultMetaXDR).
+				WithMockArgs([]string{"a"}),
+			wantErr: "mock arguments require a WASM path",
+		},
+		{
+			name: "envelope with WASM path",
+			builder: NewSimulationRequestBuilder().
+				WithWasmPath("./contract.wasm").
+				WithEnvelopeXDR(testEnvelopeXDR),
+			wantErr: "envelope XDR cannot be combined with a WASM path",
+		},
+		{
+			name: "result meta with WASM path",
+			builder: NewSimulationRequestBuilder().
+				WithWasmPath("./contract.wasm").
+				WithResultMetaXDR(testResultMetaXDR),
+			wantErr: "result meta XDR cannot be combined with a WASM path",
+		},
+		{
+			name:    "empty WASM path",
+			builder: NewSimulationRequestBuilder().WithWasmPath(""),
+			wantErr: "validation errors: [WASM path cannot be empty]",
+		},
+		{
+			name: "negative timestamp",
+			builder: NewSimulationRequestBuilder().
+				WithEnvelopeXDR(testEnvelopeXDR).
+				WithResultMetaXDR(testResultMetaXDR).
+				WithTimestamp(-1),
+			wantErr: "validation errors: [timestamp cannot be negative: -1]",
+		},
+		{
+			name: "negative auth trace depth",
+			builder: NewSimulationRequestBuilder().
+				WithEnvelopeXDR(testEnvelopeXDR).
+				WithResultMetaXDR(testResultMetaXDR).
+				WithAuthTraceOptions(AuthTraceOptions{MaxEventDepth: -1}),
+			wantErr: "validation errors: [auth trace max event depth cannot be negative: -1]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.builder.Build()
+			if err == nil {
+				t.Fatalf("expected error %q, got nil", tt.wantErr)
+			}
+			if err.Error() != tt.wantErr {
+				t.Errorf("expected error %q, got: %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestSimulationRequestBuilder_InvalidXDR(t *testing.T) {
+	tests := []struct {
+		name       string
+		envelope   string
+		resultMeta string
+		wantPrefix string
+	}{
+		{"envelope is not base64", "not base64!", testResultMetaXDR, "invalid envelope XDR"},
+		{"envelope is not an envelope", testResultMetaXDR, testResultMetaXDR, "invalid envelope XDR"},
+		{"result meta is not base64", testEnvelopeXDR, "not base64!", "invalid result meta XDR"},
+		{"result meta is truncated", testEnvelopeXDR, "AAAAAA==", "invalid result meta XDR"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewSimulationRequestBuilder().
+				WithEnvelopeXDR(tt.envelope).
+				WithResultMetaXDR(tt.resultMeta).
+				Build()
+			if err == nil {
+				t.Fatal("expected error, got nil")
+			}
+			if !strings.HasPrefix(err.Error(), tt.wantPrefix) {
+				t.Errorf("expected error starting with %q, got: %v", tt.wantPrefix, err)
+			}
+		})
+	}
+}
+
+func TestSimulationRequestBuilder_ResetClearsAllFields(t *testing.T) {
+	builder := NewSimulationRequestBuilder().
+		WithWasmPath("./contract.wasm").
+		WithMockArgs([]string{"a"}).
+		WithTimestamp(10).
+		WithLedgerSequence(20).
+		WithProfile(true).
+		WithAuthTraceOptions(AuthTraceOptions{Enabled: true}).
+		WithCustomAuthConfig(map[string]interface{}{"k": "v"}).
+		Reset()
+
+	if builder.Mode() != ModeNetworkReplay {
+		t.Fatalf("expected network replay mode after reset, got: %s", builder.Mode())
+	}
+
+	req, err := builder.
+		WithEnvelopeXDR(testEnvelopeXDR).
+		WithResultMetaXDR(testResultMetaXDR).
+		Build()
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	if req.Timestamp != 0 || req.LedgerSequence != 0 || req.Profile || req.AuthTraceOpts != nil || req.CustomAuthCfg != nil {
+		t.Errorf("expected all optional fields to be cleared, got: %+v", req)
+	}
+}
@@ -20,43 +20,45 @@ import (
 	"github.com/dotandev/hintents/internal/simulator"
 )
 
+// Base64 XDR of a bump sequence transaction and of its result meta
+const (
+	exampleEnvelopeXDR   = "AAAAAgAAAABi/B0L0JGythwN1lY0aypo19NHxvLCyO5tBEcCVvwF9wAAAGQAAAAAAAAAAQAAAAAAAAAAAAAAAQAAAAAAAAALAAAAAAAAAAIAAAAAAAAAAA=="
+	exampleResultMetaXDR = "AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAZAAAAAAAAAAAAAAAAAAAAAAAAAABAAAAAAAAAAA="
+)
+
 // ExampleSimulationRequestBuilder demonstrates basic usage of the builder pattern.
 func ExampleSimulationRequestBuilder() {
 	// Create a simulation request using the builder
 	req, err := simulator.NewSimulationRequestBuilder().
-		WithEnvelopeXDR("AAAAAgAAAACE...").
-		WithResultMetaXDR("AAAAAQAAAAA...").
+		WithEnvelopeXDR(exampleEnvelopeXDR).
+		WithResultMetaXDR(exampleResultMetaXDR).
 		Build()
 
 	if err != nil {
-		fmt.Printf("Error: %v
-", err)
+		fmt.Printf("Error: %v\n", err)
 		return
 	}
 
-	fmt.Printf("Request created with envelope XDR length: %d
-", len(req.EnvelopeXdr))
-	// Output: Request created with envelope XDR length: 15
+	fmt.Printf("Request created with envelope XDR length: %d\n", len(req.EnvelopeXdr))
+	// Output: Request created with envelope XDR length: 120
 }
 
 // ExampleSimulationRequestBuilder_withLedgerEntries demonstrates adding ledger entries.
 func ExampleSimulationRequestBuilder_withLedgerEntries() {
 	// Create a simulation request with ledger entries
 	req, err := simulator.NewSimulationRequestBuilder().
-		WithEnvelopeXDR("AAAAAgAAAACE...").
-		WithResultMetaXDR("AAAAAQAAAAA...").
+		WithEnvelopeXDR(exampleEnvelopeXDR).
+		WithResultMetaXDR(exampleResultMetaXDR).
 		WithLedgerEntry("key1", "value1").
 		WithLedgerEntry("key2", "value2").
 		Build()
 
 	if err != nil {
-		fmt.Printf("Error: %v
-", err)
+		fmt.Printf("Error: %v\n", err)
 		return
 	}
 
-	fmt.Printf("Request created with %d ledger entries
-", len(req.LedgerEntries))
+	fmt.Printf("Request created with %d ledger entries\n", len(req.LedgerEntries))
 	// Output: Request created with 2 ledger entries
 }
 
@@ -69,19 +71,17 @@ func ExampleSimulationRequestBuilder_bulkLedgerEntries() {
 	}
 
 	req, err := simulator.NewSimulationRequestBuilder().
-		WithEnvelopeXDR("AAAAAgAAAACE...").
-		WithResultMetaXDR("AAAAAQAAAAA...").
+		WithEnvelopeXDR(exampleEnvelopeXDR).
+		WithResultMetaXDR(exampleResultMetaXDR).
 		WithLedgerEntries(entries).
 		Build()
 
 	if err != nil {
-		fmt.Printf("Error: %v
-", err)
+		fmt.Printf("Error: %v\n", err)
 		return
 	}
 
-	fmt.Printf("Request created with %d ledger entries
-", len(req.LedgerEntries))
+	fmt.Printf("Request created with %d ledger entries\n", len(req.LedgerEntries))
 	// Output: Request created with 3 ledger entries
 }
 
@@ -89,7 +89,7 @@ func ExampleSimulationRequestBuilder_bulkLedgerEntries() {
 func ExampleSimulationRequestBuilder_validation() {
 	// Try to build without required fields
 	_, err := simulator.NewSimulationRequestBuilder().
-		WithEnvelopeXDR("AAAAAgAAAACE...").
+		WithEnvelopeXDR(exampleEnvelopeXDR).
 		Build()
 
 	if err != nil {
@@ -98,29 +98,45 @@ func ExampleSimulationRequestBuilder_validation() {
 	// Output: Validation error: result meta XDR is required
 }
 
+// ExampleSimulationRequestBuilder_localWasm demonstrates a local WASM run with
+// an overridden ledger timestamp.
+func ExampleSimulationRequestBuilder_localWasm() {
+	req, err := simulator.NewSimulationRequestBuilder().
+		WithWasmPath("./contract.wasm").
+		WithMockArgs([]string{"transfer", "100"}).
+		WithTimestamp(1700000000).
+		Build()
+
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return
+	}
+
+	fmt.Printf("Running %s with %d args at %d\n", *req.WasmPath, len(*req.MockArgs), req.Timestamp)
+	// Output: Running ./contract.wasm with 2 args at 1700000000
+}
+
 // ExampleSimulationRequestBuilder_reuse demonstrates builder reuse with Reset().
 func ExampleSimulationRequestBuilder_reuse() {
 	builder := simulator.NewSimulationRequestBuilder()
 
-	// Build first request
+	// Build a network replay request
 	req1, _ := builder.
-		WithEnvelopeXDR("envelope1").
-		WithResultMetaXDR("result1").
+		WithEnvelopeXDR(exampleEnvelopeXDR).
+		WithResultMetaXDR(exampleResultMetaXDR).
 		Build()
 
-	fmt.Printf("First request envelope: %s
-", req1.EnvelopeXdr)
+	fmt.Printf("First request has envelope: %t\n", req1.EnvelopeXdr != "")
 
-	// Reset and build second request
+	// Reset and build a local WASM request
 	req2, _ := builder.
 		Reset().
-		WithEnvelopeXDR("envelope2").
-		WithResultMetaXDR("result2").
+		WithWasmPath("./contract.wasm").
+		WithMockArgs([]string{"hello"}).
 		Build()
 
-	fmt.Printf("Second request envelope: %s
-", req2.EnvelopeXdr)
+	fmt.Printf("Second request runs: %s %v\n", *req2.WasmPath, *req2.MockArgs)
 	// Output:
-	// First request envelope: envelope1
-	// Second request envelope: envelope2
+	// First request has envelope: true
+	// Second request runs: ./contract.wasm [hello]
 }
@@ -14,15 +14,19 @@
 
 package simulator
 
+import "context"
+
 // RunnerInterface defines the contract for simulator execution
 type RunnerInterface interface {
 	Run(req *SimulationRequest) (*SimulationResponse, error)
+	// RunContext is like Run but stops the simulation when ctx is cancelled
+	RunContext(ctx context.Context, req *SimulationRequest) (*SimulationResponse, error)
 }
 
 // NewRunnerInterface creates a RunnerInterface implementation
 // This allows for easy swapping between real and mock implementations
 func NewRunnerInterface() (RunnerInterface, error) {
-	return NewRunner()
+	return NewRunner("", false)
 }
 
 // ExampleUsage of how commands can accept the interface
@@ -15,15 +15,17 @@
 package simulator
 
 import (
+	"context"
 	"testing"
 
+	"github.com/dotandev/hintents/internal/diagnostic"
 	"github.com/stretchr/testify/assert"
 )
 
 func TestRunnerInterface_CompileTimeCheck(t *testing.T) {
 	// Verify Runner implements RunnerInterface at compile time
 	var _ RunnerInterface = (*Runner)(nil)
-	
+
 	// This test ensures the interface contract is maintained
 	assert.True(t, true, "Runner implements RunnerInterface")
 }
@@ -31,7 +33,7 @@ func TestRunnerInterface_CompileTimeCheck(t *testing.T) {
 func TestNewRunnerInterface(t *testing.T) {
 	// Test the factory function
 	runner, err := NewRunnerInterface()
-	
+
 	// Note: This will fail in the current environment due to missing binary
 	// but the interface structure is correct
 	if err != nil {
@@ -47,14 +49,14 @@ func TestNewRunnerInterface(t *testing.T) {
 func TestExampleUsage(t *testing.T) {
 	// Create a mock implementation for testing
 	mockRunner := &mockRunnerForTest{}
-	
+
 	req := &SimulationRequest{
 		EnvelopeXdr:   "test-envelope",
 		ResultMetaXdr: "test-meta",
 	}
-	
+
 	resp, err := ExampleUsage(mockRunner, req)
-	
+
 	assert.NoError(t, err)
 	assert.NotNil(t, resp)
 	assert.Equal(t, "success", resp.Status)
@@ -63,9 +65,13 @@ func TestExampleUsage(t *testing.T) {
 // Simple mock for testing the interface
 type mockRunnerForTest struct{}
 
+func (m *mockRunnerForTest) RunContext(ctx context.Context, req *SimulationRequest) (*SimulationResponse, error) {
+	return m.Run(req)
+}
+
 func (m *mockRunnerForTest) Run(req *SimulationRequest) (*SimulationResponse, error) {
 	return &SimulationResponse{
 		Status: "success",
-		Events: []string{"mock-event"},
+		Events: []diagnostic.Event{{Type: diagnostic.TypeContract, Kind: diagnostic.KindContract}},
 	}, nil
 }
@@ -0,0 +1,91 @@
+// Copyright (c) 2026 dotandev
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package simulator
+
+import (
+	"bytes"
+	"sync"
+	"time"
+)
+
+// Limits bounds the resources a single simulator run may use.
+// A zero field means no limit.
+type Limits struct {
+	// Timeout is the wall-clock time after which the simulator is killed
+	Timeout time.Duration
+	// MaxStdoutBytes caps the size of the JSON response read from the simulator
+	MaxStdoutBytes int64
+	// MaxStderrBytes caps the size of the diagnostics read from the simulator
+	MaxStderrBytes int64
+	// MaxMemoryBytes caps the simulator's address space (RLIMIT_AS). Linux only.
+	MaxMemoryBytes uint64
+	// MaxCPUTime caps the simulator's CPU time (RLIMIT_CPU), rounded up to
+	// whole seconds. Linux only.
+	MaxCPUTime time.Duration
+}
+
+// DefaultLimits are the limits used by NewRunner. They only guard against a
+// hung or runaway simulator; memory and CPU are not capped by default.
+var DefaultLimits = Limits{
+	Timeout:        5 * time.Minute,
+	MaxStdoutBytes: 256 << 20,
+	MaxStderrBytes: 16 << 20,
+}
+
+// waitDelay bounds how long Wait blocks on output pipes after the simulator
+// was killed, in case it left children holding them open
+const waitDelay = 2 * time.Second
+
+// cappedBuffer collects process output up to limit bytes. Once the limit is
+// exceeded, further output is discarded and onExceed is called once.
+type cappedBuffer struct {
+	mu       sync.Mutex
+	buf      bytes.Buffer
+	limit    int64
+	exceeded bool
+	onExceed func()
+}
+
+func newCappedBuffer(limit int64, onExceed func()) *cappedBuffer {
+	return &cappedBuffer{limit: limit, onExceed: onExceed}
+}
+
+func (b *cappedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.exceeded {
+		return len(p), nil
+	}
+	if b.limit > 0 && int64(b.buf.Len()+len(p)) > b.limit {
+		b.buf.Write(p[:b.limit-int64(b.buf.Len())])
+		b.exceeded = true
+		b.onExceed()
+		return len(p), nil
+	}
+	return b.buf.Write(p)
+}
+
+func (b *cappedBuffer) Bytes() []byte {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Bytes()
+}
+
+func (b *cappedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
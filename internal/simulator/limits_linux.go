@@ -0,0 +1,129 @@
+// Copyright (c) 2026 dotandev
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package simulator
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/dotandev/hintents/internal/errors"
+	"golang.org/x/sys/unix"
+)
+
+// rlimitShimEnv carries the limits to the rlimit shim, as
+// "<address space bytes>:<cpu seconds>"
+const rlimitShimEnv = "ERST_SIMULATOR_RLIMITS"
+
+// A process started by limitCommand is erst itself acting as the rlimit
+// shim: it sets the limits and execs the simulator before anything else
+func init() {
+	if spec, ok := os.LookupEnv(rlimitShimEnv); ok {
+		err := execWithLimits(spec, os.Args[1:])
+		fmt.Fprintf(os.Stderr, "failed to start simulator with resource limits: %v\n", err)
+		os.Exit(127)
+	}
+}
+
+// limitCommand makes cmd start the simulator through the rlimit shim when
+// limits caps memory or CPU time. The shim, a re-executed erst, sets the
+// rlimits on itself and then execs the simulator, so the limits are in place
+// before the simulator runs its first instruction.
+func limitCommand(cmd *exec.Cmd, limits Limits) error {
+	if limits.MaxMemoryBytes == 0 && limits.MaxCPUTime == 0 {
+		return nil
+	}
+	if cmd.Err != nil {
+		// Start reports the missing binary
+		return nil
+	}
+	self, err := os.Executable()
+	if err != nil {
+		return err
+	}
+
+	env := cmd.Env
+	if env == nil {
+		env = os.Environ()
+	}
+	cmd.Env = append(env, fmt.Sprintf("%s=%d:%d", rlimitShimEnv, limits.MaxMemoryBytes, cpuSeconds(limits.MaxCPUTime)))
+	cmd.Args = append([]string{self, cmd.Path}, cmd.Args[1:]...)
+	cmd.Path = self
+	return nil
+}
+
+// execWithLimits sets the rlimits of spec on the current process and replaces
+// it with args. It only returns on failure.
+func execWithLimits(spec string, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("no simulator to run")
+	}
+	var memory, cpu uint64
+	if _, err := fmt.Sscanf(spec, "%d:%d", &memory, &cpu); err != nil {
+		return fmt.Errorf("invalid limits %q: %w", spec, err)
+	}
+
+	if memory > 0 {
+		if err := unix.Setrlimit(unix.RLIMIT_AS, &unix.Rlimit{Cur: memory, Max: memory}); err != nil {
+			return fmt.Errorf("failed to set memory limit: %w", err)
+		}
+	}
+	if cpu > 0 {
+		// SIGXCPU at the soft limit, SIGKILL one second later
+		if err := unix.Setrlimit(unix.RLIMIT_CPU, &unix.Rlimit{Cur: cpu, Max: cpu + 1}); err != nil {
+			return fmt.Errorf("failed to set cpu time limit: %w", err)
+		}
+	}
+
+	env := make([]string, 0, len(os.Environ()))
+	for _, kv := range os.Environ() {
+		if !strings.HasPrefix(kv, rlimitShimEnv+"=") {
+			env = append(env, kv)
+		}
+	}
+	return syscall.Exec(args[0], args, env)
+}
+
+// cpuSeconds rounds a CPU time limit up to the whole seconds of RLIMIT_CPU
+func cpuSeconds(d time.Duration) uint64 {
+	return uint64((d + time.Second - 1) / time.Second)
+}
+
+// resourceLimitError reports whether a failed simulator run was stopped by
+// one of the rlimits set by limitCommand
+func resourceLimitError(state *os.ProcessState, limits Limits, stderr string) error {
+	if state == nil {
+		return nil
+	}
+	status, ok := state.Sys().(syscall.WaitStatus)
+	if !ok {
+		return nil
+	}
+
+	if limits.MaxCPUTime > 0 && status.Signaled() &&
+		(status.Signal() == syscall.SIGXCPU || status.Signal() == syscall.SIGKILL) {
+		return errors.WrapSimulatorResourceLimit("cpu time limit of "+limits.MaxCPUTime.String()+" reached", stderr)
+	}
+	// Rust aborts with "memory allocation of N bytes failed" when RLIMIT_AS is hit
+	if limits.MaxMemoryBytes > 0 && strings.Contains(stderr, "memory allocation of") {
+		return errors.WrapSimulatorResourceLimit("memory limit reached", stderr)
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+// Copyright (c) 2026 dotandev
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package simulator
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunContext_CPULimit(t *testing.T) {
+	runner := fakeSimulator(t, `while :; do :; done`)
+	runner.Limits.MaxCPUTime = time.Second
+
+	start := time.Now()
+	_, err := runner.RunContext(context.Background(), newTestRequest())
+	require.Error(t, err)
+	assert.ErrorIs(t, err, errors.ErrSimulatorResourceLimit)
+	assert.Contains(t, err.Error(), "cpu time")
+	assert.Less(t, time.Since(start), 30*time.Second)
+}
+
+func TestRunContext_LimitsApplyBeforeStart(t *testing.T) {
+	// The script reads its limits before doing anything else
+	runner := fakeSimulator(t, `limits="$(ulimit -t) $(ulimit -v)"; read -r line; respond "$line" "{\"events\":[],\"logs\":[\"$limits\"]}"`)
+	runner.Limits.MaxCPUTime = 1500 * time.Millisecond
+	runner.Limits.MaxMemoryBytes = 1 << 30
+
+	resp, err := runner.RunContext(context.Background(), newTestRequest())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"2 1048576"}, resp.Logs)
+}
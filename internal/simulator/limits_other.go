@@ -0,0 +1,42 @@
+// Copyright (c) 2026 dotandev
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !linux
+
+package simulator
+
+import (
+	"os"
+	"os/exec"
+	"sync"
+
+	"github.com/dotandev/hintents/internal/logger"
+)
+
+var warnLimitsOnce sync.Once
+
+// limitCommand is a no-op outside Linux: memory and CPU caps rely on
+// setrlimit(2). Timeouts and output caps still apply.
+func limitCommand(cmd *exec.Cmd, limits Limits) error {
+	if limits.MaxMemoryBytes > 0 || limits.MaxCPUTime > 0 {
+		warnLimitsOnce.Do(func() {
+			logger.Logger.Warn("Simulator memory and CPU limits are only enforced on Linux")
+		})
+	}
+	return nil
+}
+
+func resourceLimitError(state *os.ProcessState, limits Limits, stderr string) error {
+	return nil
+}
@@ -14,6 +14,8 @@
 
 package simulator
 
+import "context"
+
 type MockRunner struct {
 	RunFunc func(req *SimulationRequest) (*SimulationResponse, error)
 }
@@ -25,6 +27,14 @@ func (m *MockRunner) Run(req *SimulationRequest) (*SimulationResponse, error) {
 	return &SimulationResponse{Status: "success"}, nil
 }
 
+// RunContext returns ctx's error if it is already done, and calls Run otherwise
+func (m *MockRunner) RunContext(ctx context.Context, req *SimulationRequest) (*SimulationResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return m.Run(req)
+}
+
 func NewMockRunner(fn func(req *SimulationRequest) (*SimulationResponse, error)) *MockRunner {
 	return &MockRunner{RunFunc: fn}
 }
@@ -34,7 +44,6 @@ func NewDefaultMockRunner() *MockRunner {
 		RunFunc: func(req *SimulationRequest) (*SimulationResponse, error) {
 			return &SimulationResponse{
 				Status: "success",
-				Events: []string{},
 				Logs:   []string{},
 			}, nil
 		},
@@ -17,6 +17,8 @@ package simulator
 import (
 	"errors"
 	"testing"
+
+	"github.com/dotandev/hintents/internal/diagnostic"
 )
 
 func TestMockRunnerDefault(t *testing.T) {
@@ -58,7 +60,7 @@ func TestMockRunnerCustomResponse(t *testing.T) {
 	expectedResp := &SimulationResponse{
 		Status: "failed",
 		Error:  "test error",
-		Events: []string{"event1", "event2"},
+		Events: []diagnostic.Event{{Kind: diagnostic.KindFnCall}, {Kind: diagnostic.KindFnReturn}},
 	}
 	mock := NewMockRunner(func(req *SimulationRequest) (*SimulationResponse, error) {
 		return expectedResp, nil
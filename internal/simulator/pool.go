@@ -0,0 +1,561 @@
+// Copyright (c) 2026 dotandev
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package simulator
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"sync"
+	"time"
+
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/dotandev/hintents/internal/logger"
+)
+
+const (
+	// serveFlag starts erst-sim as a long-lived worker reading one JSON
+	// request per line from stdin and writing one JSON response per line
+	serveFlag = "--serve"
+	// workerStderrTail is how much of a worker's most recent stderr is kept
+	// for error messages
+	workerStderrTail = 64 << 10
+)
+
+var errPoolClosed = fmt.Errorf("simulator pool is closed")
+
+// PoolStats is a snapshot of the activity of a Pool
+type PoolStats struct {
+	// Size is the number of worker slots
+	Size int `json:"size"`
+	// Alive is the number of running worker processes
+	Alive int `json:"alive"`
+	// Busy is the number of workers currently serving a request
+	Busy int `json:"busy"`
+	// Waiting is the number of requests queued for a free worker
+	Waiting int `json:"waiting"`
+	// Requests is the number of requests served, including failed ones
+	Requests int64 `json:"requests"`
+	// Failures is the number of requests that returned an error
+	Failures int64 `json:"failures"`
+	// Restarts is the number of workers started to replace a dead one
+	Restarts int64 `json:"restarts"`
+	// AvgLatency is the mean time spent on a worker per request
+	AvgLatency time.Duration `json:"avg_latency_ns"`
+}
+
+// Pool is a RunnerInterface that keeps a fixed number of erst-sim processes
+// alive and sends them requests over stdin/stdout, one JSON document per
+// line. Each request carries a request_id that the simulator echoes back.
+//
+// A worker serves one request at a time. Workers that crash, hang past the
+// timeout or answer out of protocol are killed and replaced on next use, so a
+// single bad transaction never takes the pool down.
+//
+// Limits apply per request, except MaxMemoryBytes which bounds each worker
+// process. MaxCPUTime is not applied since CPU time accumulates over the
+// lifetime of a worker; use Timeout instead.
+type Pool struct {
+	binaryPath string
+	limits     Limits
+
+	// slots hands out the index of an idle worker
+	slots   chan int
+	closing chan struct{}
+
+	mu        sync.Mutex
+	workers   []*worker
+	closed    bool
+	busy      int
+	waiting   int
+	requests  int64
+	failures  int64
+	restarts  int64
+	totalTime time.Duration
+}
+
+// NewPool starts size long-lived workers running the runner's simulator
+// binary with the runner's limits. It fails if a worker cannot be started,
+// for instance when the binary predates the --serve mode.
+func NewPool(runner *Runner, size int) (*Pool, error) {
+	if size < 1 {
+		return nil, fmt.Errorf("simulator pool size must be at least 1, got %d", size)
+	}
+
+	p := &Pool{
+		binaryPath: runner.BinaryPath,
+		limits:     runner.Limits,
+		slots:      make(chan int, size),
+		closing:    make(chan struct{}),
+		workers:    make([]*worker, size),
+	}
+	for i := 0; i < size; i++ {
+		w, err := startWorker(p.binaryPath, p.limits)
+		if err != nil {
+			_ = p.Close()
+			return nil, err
+		}
+		p.workers[i] = w
+		p.slots <- i
+	}
+
+	logger.Logger.Debug("Simulator pool started", "binary", p.binaryPath, "size", size)
+	return p, nil
+}
+
+// Run executes the simulation on a pooled worker
+func (p *Pool) Run(req *SimulationRequest) (*SimulationResponse, error) {
+	return p.RunContext(context.Background(), req)
+}
+
+// RunContext executes the simulation on the next free worker, waiting for one
+// if all are busy. The worker is killed and later restarted when ctx is
+// cancelled or the timeout expires mid-request.
+func (p *Pool) RunContext(ctx context.Context, req *SimulationRequest) (*SimulationResponse, error) {
+	parent := ctx
+	if p.limits.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeoutCause(ctx, p.limits.Timeout, errors.WrapSimulationTimeout(p.limits.Timeout))
+		defer cancel()
+	}
+
+	slot, err := p.acquire(ctx)
+	if err != nil {
+		if parent.Err() != nil {
+			return nil, errors.WrapSimulationCancelled(context.Cause(parent))
+		}
+		if ctx.Err() != nil {
+			return nil, context.Cause(ctx)
+		}
+		return nil, err
+	}
+
+	p.mu.Lock()
+	w := p.workers[slot]
+	p.mu.Unlock()
+	id := nextRequestID()
+
+	start := time.Now()
+	resp, err := w.do(ctx, id, req)
+	elapsed := time.Since(start)
+
+	if err != nil && !w.alive() {
+		// Keep the dead worker in its slot, acquire replaces it
+		logger.Logger.Warn("Simulator worker stopped", "request_id", id, "error", err)
+	}
+	p.release(slot, elapsed, err != nil || (resp != nil && resp.Status == "error"))
+
+	if err != nil {
+		if parent.Err() != nil {
+			logger.Logger.Error("Simulator run cancelled", "request_id", id, "error", context.Cause(parent))
+			return nil, errors.WrapSimulationCancelled(context.Cause(parent))
+		}
+		if ctx.Err() != nil {
+			logger.Logger.Error("Simulator run aborted", "request_id", id, "error", context.Cause(ctx))
+			return nil, context.Cause(ctx)
+		}
+		return nil, err
+	}
+
+	if resp.Status == "error" {
+		logger.Logger.Error("Simulation logic error", "request_id", id, "error", resp.Error)
+		return nil, fmt.Errorf("simulation error: %s", resp.Error)
+	}
+	return resp, nil
+}
+
+// Stats returns a snapshot of the pool activity
+func (p *Pool) Stats() PoolStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	stats := PoolStats{
+		Size:     len(p.workers),
+		Busy:     p.busy,
+		Waiting:  p.waiting,
+		Requests: p.requests,
+		Failures: p.failures,
+		Restarts: p.restarts,
+	}
+	for _, w := range p.workers {
+		if w != nil && w.alive() {
+			stats.Alive++
+		}
+	}
+	if p.requests > 0 {
+		stats.AvgLatency = p.totalTime / time.Duration(p.requests)
+	}
+	return stats
+}
+
+// Close stops every worker once its current request is done. Requests made
+// after Close fail.
+func (p *Pool) Close() error {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return nil
+	}
+	p.closed = true
+	close(p.closing)
+	p.mu.Unlock()
+
+	for i := 0; i < cap(p.slots); i++ {
+		// Slots not yet filled by NewPool hold no worker
+		p.mu.Lock()
+		started := p.workers[i] != nil
+		p.mu.Unlock()
+		if !started {
+			continue
+		}
+
+		slot := <-p.slots
+		p.mu.Lock()
+		w := p.workers[slot]
+		p.mu.Unlock()
+		w.stop()
+	}
+	return nil
+}
+
+// acquire waits for an idle worker and restarts it if it died
+func (p *Pool) acquire(ctx context.Context) (int, error) {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return 0, errPoolClosed
+	}
+	p.waiting++
+	p.mu.Unlock()
+
+	var slot int
+	select {
+	case slot = <-p.slots:
+	case <-p.closing:
+		p.mu.Lock()
+		p.waiting--
+		p.mu.Unlock()
+		return 0, errPoolClosed
+	case <-ctx.Done():
+		p.mu.Lock()
+		p.waiting--
+		p.mu.Unlock()
+		return 0, ctx.Err()
+	}
+
+	p.mu.Lock()
+	p.waiting--
+	w := p.workers[slot]
+	p.mu.Unlock()
+
+	if !w.alive() {
+		restarted, err := startWorker(p.binaryPath, p.limits)
+		if err != nil {
+			p.slots <- slot
+			return 0, fmt.Errorf("failed to restart simulator worker: %w", err)
+		}
+		logger.Logger.Info("Simulator worker restarted", "slot", slot)
+		p.mu.Lock()
+		p.workers[slot] = restarted
+		p.restarts++
+		p.mu.Unlock()
+	}
+
+	p.mu.Lock()
+	p.busy++
+	p.mu.Unlock()
+	return slot, nil
+}
+
+func (p *Pool) release(slot int, elapsed time.Duration, failed bool) {
+	p.mu.Lock()
+	p.busy--
+	p.requests++
+	p.totalTime += elapsed
+	if failed {
+		p.failures++
+	}
+	p.mu.Unlock()
+
+	p.slots <- slot
+}
+
+// -------------------- Workers --------------------
+
+type worker struct {
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	stderr *tailBuffer
+	limits Limits
+	// version is the protocol version negotiated at startup
+	version string
+
+	// lines delivers stdout lines, it is closed when stdout ends
+	lines    chan []byte
+	readErr  error
+	readDone chan struct{}
+	// exited is closed once the process has been reaped
+	exited  chan struct{}
+	waitErr error
+
+	killOnce sync.Once
+	killed   chan struct{}
+}
+
+func startWorker(binaryPath string, limits Limits) (*worker, error) {
+	cmd := exec.Command(binaryPath, serveFlag)
+	cmd.WaitDelay = waitDelay
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create simulator stdin: %w", err)
+	}
+	stdoutR, stdoutW := io.Pipe()
+	cmd.Stdout = stdoutW
+	w := &worker{
+		cmd:      cmd,
+		stdin:    stdin,
+		stderr:   newTailBuffer(workerStderrTail),
+		limits:   limits,
+		lines:    make(chan []byte, 1),
+		readDone: make(chan struct{}),
+		exited:   make(chan struct{}),
+		killed:   make(chan struct{}),
+	}
+	// RLIMIT_CPU would accumulate over every request the worker serves
+	w.limits.MaxCPUTime = 0
+	cmd.Stderr = w.stderr
+
+	if err := limitCommand(cmd, w.limits); err != nil {
+		return nil, fmt.Errorf("failed to apply simulator resource limits: %w", err)
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start simulator: %w", err)
+	}
+
+	go w.readLoop(stdoutR, limits.MaxStdoutBytes)
+	go func() {
+		w.waitErr = cmd.Wait()
+		_ = stdoutW.Close()
+		close(w.exited)
+	}()
+
+	if err := w.handshake(binaryPath); err != nil {
+		w.kill()
+		<-w.exited
+		return nil, err
+	}
+	return w, nil
+}
+
+// handshake waits for the protocol handshake erst-sim prints first in
+// --serve mode and negotiates the protocol version
+func (w *worker) handshake(binaryPath string) error {
+	timer := time.NewTimer(handshakeTimeout)
+	defer timer.Stop()
+
+	select {
+	case line, ok := <-w.lines:
+		if !ok {
+			<-w.exited
+			return fmt.Errorf("simulator exited during startup: %w", w.failure())
+		}
+		version, err := negotiateProtocol(binaryPath, line)
+		if err != nil {
+			return err
+		}
+		w.version = version
+		return nil
+	case <-timer.C:
+		// Binaries without --serve support wait for stdin to close instead
+		return errors.WrapSimulatorProtocol(fmt.Sprintf(
+			"%s did not answer the protocol handshake within %s, it may not support %s",
+			binaryPath, handshakeTimeout, serveFlag))
+	}
+}
+
+// do sends one request and waits for its response. Any error other than a
+// response with status "error" or an invalid request leaves the worker killed.
+func (w *worker) do(ctx context.Context, id string, req *SimulationRequest) (*SimulationResponse, error) {
+	line, err := encodeRequest(req, w.version, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// A worker that stops reading blocks the write once the pipe is full, so
+	// the write is bounded by ctx like the response
+	written := make(chan error, 1)
+	go func() {
+		_, err := w.stdin.Write(line)
+		written <- err
+	}()
+	select {
+	case err := <-written:
+		if err != nil {
+			w.kill()
+			<-w.exited
+			return nil, fmt.Errorf("failed to send request to simulator: %w", w.failure())
+		}
+	case <-ctx.Done():
+		// Killing the worker closes the pipe and ends the write
+		w.kill()
+		return nil, context.Cause(ctx)
+	}
+
+	select {
+	case out, ok := <-w.lines:
+		if !ok {
+			w.kill()
+			<-w.exited
+			return nil, fmt.Errorf("simulator worker exited: %w", w.failure())
+		}
+		resp, err := decodeResponse(out, w.version, id)
+		if err != nil {
+			// The worker is out of sync, later answers cannot be trusted
+			w.kill()
+			return nil, err
+		}
+		return resp, nil
+	case <-ctx.Done():
+		// A simulation cannot be interrupted, drop the worker instead
+		w.kill()
+		return nil, context.Cause(ctx)
+	}
+}
+
+// readLoop splits stdout into lines of at most limit bytes
+func (w *worker) readLoop(r io.Reader, limit int64) {
+	defer close(w.readDone)
+	defer close(w.lines)
+
+	reader := bufio.NewReader(r)
+	for {
+		line, err := readLine(reader, limit)
+		if err != nil {
+			if err != io.EOF {
+				w.readErr = err
+				w.kill()
+			}
+			// Keep draining so the process can be reaped
+			_, _ = io.Copy(io.Discard, reader)
+			return
+		}
+
+		select {
+		case w.lines <- line:
+		case <-w.killed:
+			_, _ = io.Copy(io.Discard, reader)
+			return
+		}
+	}
+}
+
+func readLine(r *bufio.Reader, limit int64) ([]byte, error) {
+	var line []byte
+	for {
+		chunk, err := r.ReadSlice('\n')
+		line = append(line, chunk...)
+		if limit > 0 && int64(len(line)) > limit {
+			return nil, errors.WrapSimulatorOutputLimit("stdout", limit)
+		}
+		switch err {
+		case nil:
+			return bytes.TrimRight(line, "\r\n"), nil
+		case bufio.ErrBufferFull:
+			continue
+		default:
+			if err == io.EOF && len(line) > 0 {
+				return line, nil
+			}
+			return nil, err
+		}
+	}
+}
+
+func (w *worker) alive() bool {
+	select {
+	case <-w.killed:
+		return false
+	case <-w.exited:
+		return false
+	default:
+		return true
+	}
+}
+
+func (w *worker) kill() {
+	w.killOnce.Do(func() {
+		close(w.killed)
+		_ = w.cmd.Process.Kill()
+	})
+}
+
+// stop closes stdin so the worker exits on its own, killing it if it does not
+func (w *worker) stop() {
+	_ = w.stdin.Close()
+	select {
+	case <-w.exited:
+	case <-time.After(waitDelay):
+		w.kill()
+		<-w.exited
+	}
+}
+
+// failure describes why a worker stopped. It must only be called once the
+// worker has exited.
+func (w *worker) failure() error {
+	<-w.readDone
+	if w.readErr != nil {
+		return w.readErr
+	}
+	if limitErr := resourceLimitError(w.cmd.ProcessState, w.limits, w.stderr.String()); limitErr != nil {
+		return limitErr
+	}
+	if w.waitErr != nil {
+		return fmt.Errorf("%w, stderr: %s", w.waitErr, w.stderr.String())
+	}
+	return fmt.Errorf("unexpected end of output, stderr: %s", w.stderr.String())
+}
+
+// tailBuffer keeps the last size bytes written to it
+type tailBuffer struct {
+	mu   sync.Mutex
+	buf  []byte
+	size int
+}
+
+func newTailBuffer(size int) *tailBuffer {
+	return &tailBuffer{size: size}
+}
+
+func (b *tailBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.buf = append(b.buf, p...)
+	if len(b.buf) > b.size {
+		b.buf = append(b.buf[:0], b.buf[len(b.buf)-b.size:]...)
+	}
+	return len(p), nil
+}
+
+func (b *tailBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return string(b.buf)
+}
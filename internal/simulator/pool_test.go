@@ -0,0 +1,211 @@
+// Copyright (c) 2026 dotandev
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package simulator
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeServeScript stands in for erst-sim --serve. It answers every line with
+// its PID as the only log, and misbehaves on request when the envelope is
+// "crash", "hang", "badid" or "fail".
+const fakeServeScript = `echo "$handshake"
+while IFS= read -r line; do
+  case "$line" in
+    *'"xdr":"crash"'*) echo "worker crashed" >&2; exit 101 ;;
+    *'"xdr":"hang"'*) exec sleep 30 ;;
+    *'"xdr":"badid"'*) respond '"request_id":"other"' '{}'; continue ;;
+    *'"xdr":"fail"'*)
+      id=$(printf '%s' "$line" | sed 's/.*"request_id":"\([^"]*\)".*/\1/')
+      echo "{\"version\":\"1.0\",\"request_id\":\"$id\",\"success\":false,\"error\":{\"code\":\"simulation_failed\",\"message\":\"bad tx\"}}"
+      continue ;;
+  esac
+  respond "$line" "{\"logs\":[\"$$\"]}"
+done`
+
+func poolRequest(envelope string) *SimulationRequest {
+	return &SimulationRequest{EnvelopeXdr: envelope, ResultMetaXdr: testResultMetaXDR}
+}
+
+func newTestPool(t *testing.T, size int) *Pool {
+	t.Helper()
+	pool, err := NewPool(fakeSimulator(t, fakeServeScript), size)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = pool.Close() })
+	return pool
+}
+
+func TestPool_ReusesWorkers(t *testing.T) {
+	pool := newTestPool(t, 1)
+
+	first, err := pool.Run(poolRequest("ok"))
+	require.NoError(t, err)
+	second, err := pool.RunContext(context.Background(), poolRequest("ok"))
+	require.NoError(t, err)
+
+	assert.Equal(t, "success", second.Status)
+	assert.Equal(t, first.Logs, second.Logs, "both requests should be served by the same process")
+
+	stats := pool.Stats()
+	assert.Equal(t, 1, stats.Size)
+	assert.Equal(t, 1, stats.Alive)
+	assert.Equal(t, int64(2), stats.Requests)
+	assert.Equal(t, int64(0), stats.Failures)
+	assert.Equal(t, int64(0), stats.Restarts)
+}
+
+func TestPool_SimulationError(t *testing.T) {
+	pool := newTestPool(t, 1)
+
+	_, err := pool.Run(poolRequest("fail"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "bad tx")
+
+	// Logic errors do not cost a worker
+	_, err = pool.Run(poolRequest("ok"))
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), pool.Stats().Restarts)
+	assert.Equal(t, int64(1), pool.Stats().Failures)
+}
+
+func TestPool_RestartsCrashedWorker(t *testing.T) {
+	pool := newTestPool(t, 1)
+
+	before, err := pool.Run(poolRequest("ok"))
+	require.NoError(t, err)
+
+	_, err = pool.Run(poolRequest("crash"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "worker crashed")
+
+	after, err := pool.Run(poolRequest("ok"))
+	require.NoError(t, err)
+	assert.NotEqual(t, before.Logs, after.Logs)
+
+	stats := pool.Stats()
+	assert.Equal(t, int64(1), stats.Restarts)
+	assert.Equal(t, int64(1), stats.Failures)
+	assert.Equal(t, 1, stats.Alive)
+}
+
+func TestPool_Timeout(t *testing.T) {
+	runner := fakeSimulator(t, fakeServeScript)
+	runner.Limits.Timeout = 100 * time.Millisecond
+	pool, err := NewPool(runner, 1)
+	require.NoError(t, err)
+	defer pool.Close()
+
+	start := time.Now()
+	_, err = pool.Run(poolRequest("hang"))
+	require.Error(t, err)
+	assert.ErrorIs(t, err, errors.ErrSimulationTimeout)
+	assert.Less(t, time.Since(start), 10*time.Second)
+
+	_, err = pool.Run(poolRequest("ok"))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), pool.Stats().Restarts)
+}
+
+func TestPool_TimeoutWhileSending(t *testing.T) {
+	// The worker never reads its requests, so a large one fills the pipe
+	runner := fakeSimulator(t, `echo "$handshake"; exec sleep 30`)
+	runner.Limits.Timeout = 100 * time.Millisecond
+	pool, err := NewPool(runner, 1)
+	require.NoError(t, err)
+	defer pool.Close()
+
+	start := time.Now()
+	_, err = pool.Run(poolRequest(strings.Repeat("A", 1<<20)))
+	require.Error(t, err)
+	assert.ErrorIs(t, err, errors.ErrSimulationTimeout)
+	assert.Less(t, time.Since(start), 10*time.Second)
+	assert.Equal(t, 0, pool.Stats().Alive)
+}
+
+func TestPool_Cancellation(t *testing.T) {
+	pool := newTestPool(t, 1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(100*time.Millisecond, cancel)
+
+	_, err := pool.RunContext(ctx, poolRequest("hang"))
+	require.Error(t, err)
+	assert.ErrorIs(t, err, errors.ErrSimulationCancelled)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestPool_RejectsMismatchedRequestID(t *testing.T) {
+	pool := newTestPool(t, 1)
+
+	_, err := pool.Run(poolRequest("badid"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `"other"`)
+
+	_, err = pool.Run(poolRequest("ok"))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), pool.Stats().Restarts)
+}
+
+func TestPool_Concurrent(t *testing.T) {
+	pool := newTestPool(t, 2)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 8)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := pool.Run(poolRequest("ok"))
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		assert.NoError(t, err)
+	}
+
+	stats := pool.Stats()
+	assert.Equal(t, int64(8), stats.Requests)
+	assert.Equal(t, 0, stats.Busy)
+	assert.Equal(t, 0, stats.Waiting)
+}
+
+func TestPool_RequiresHandshake(t *testing.T) {
+	// A simulator without --serve support answers nothing but a response
+	runner := fakeSimulator(t, `echo '{"status":"success"}'`)
+
+	_, err := NewPool(runner, 1)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, errors.ErrSimulatorProtocol)
+}
+
+func TestPool_Close(t *testing.T) {
+	pool := newTestPool(t, 2)
+	require.NoError(t, pool.Close())
+	require.NoError(t, pool.Close())
+
+	_, err := pool.Run(poolRequest("ok"))
+	assert.ErrorIs(t, err, errPoolClosed)
+	assert.Equal(t, 0, pool.Stats().Alive)
+}
@@ -0,0 +1,163 @@
+// Copyright (c) 2026 dotandev
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package simulator
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/dotandev/hintents/internal/authtrace"
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/dotandev/hintents/internal/ipc"
+)
+
+const (
+	// handshakeFlag makes erst-sim print its handshake and exit
+	handshakeFlag = "--handshake"
+	// handshakeTimeout bounds how long the simulator may take to announce
+	// its protocol versions
+	handshakeTimeout = 10 * time.Second
+	// handshakeMaxBytes caps the output read while waiting for a handshake
+	handshakeMaxBytes = 64 << 10
+)
+
+var requestSeq atomic.Uint64
+
+// nextRequestID returns a request ID unique within this process
+func nextRequestID() string {
+	return "sim-" + strconv.FormatUint(requestSeq.Add(1), 10)
+}
+
+// negotiateProtocol checks the first line printed by the simulator against
+// the handshake schema and picks the protocol version to speak
+func negotiateProtocol(binaryPath string, line []byte) (string, error) {
+	line = bytes.TrimSpace(line)
+	if err := ipc.ValidateHandshake(line); err != nil {
+		return "", errors.WrapSimulatorProtocol(fmt.Sprintf(
+			"%s did not answer the protocol handshake, it is probably older than erst (got %q)",
+			binaryPath, truncateOutput(line)))
+	}
+
+	handshake, err := ipc.UnmarshalHandshake(line)
+	if err != nil {
+		return "", errors.WrapSimulatorProtocol(fmt.Sprintf("invalid handshake: %v", err))
+	}
+	return ipc.Negotiate(handshake.ProtocolVersions)
+}
+
+// encodeRequest frames req as a protocol request line, checked against the
+// request schema
+func encodeRequest(req *SimulationRequest, version, id string) ([]byte, error) {
+	wire := ipc.SimulationRequestSchema{
+		Version:          version,
+		RequestID:        id,
+		Xdr:              req.EnvelopeXdr,
+		ResultMetaXdr:    req.ResultMetaXdr,
+		LedgerEntries:    req.LedgerEntries,
+		Timestamp:        req.Timestamp,
+		LedgerSequence:   req.LedgerSequence,
+		WasmPath:         req.WasmPath,
+		MockArgs:         req.MockArgs,
+		Profile:          req.Profile,
+		CustomAuthConfig: req.CustomAuthCfg,
+	}
+	if opts := req.AuthTraceOpts; opts != nil {
+		wire.AuthTraceOpts = &ipc.AuthTraceOptions{
+			Enabled:              opts.Enabled,
+			TraceCustomContracts: opts.TraceCustomContracts,
+			CaptureSigDetails:    opts.CaptureSigDetails,
+			MaxEventDepth:        opts.MaxEventDepth,
+		}
+	}
+
+	data, err := wire.Marshal()
+	if err != nil {
+		return nil, errors.WrapMarshalFailed(err)
+	}
+	if err := ipc.ValidateRequest(data); err != nil {
+		return nil, fmt.Errorf("invalid simulation request: %w", err)
+	}
+	return append(data, '\n'), nil
+}
+
+// decodeResponse checks a protocol response line against the response schema
+// and the pending request. Simulation failures are returned as a response
+// with status "error"; protocol violations as an error.
+func decodeResponse(line []byte, version, id string) (*SimulationResponse, error) {
+	line = bytes.TrimSpace(line)
+	if err := ipc.ValidateResponse(line); err != nil {
+		return nil, errors.WrapSimulatorProtocol(fmt.Sprintf("invalid response to protocol %s: %v", version, err))
+	}
+
+	wire, err := ipc.UnmarshalSimulationResponseSchema(line)
+	if err != nil {
+		return nil, errors.WrapUnmarshalFailed(err, truncateOutput(line))
+	}
+	if wire.RequestID != id {
+		return nil, errors.WrapSimulatorProtocol(fmt.Sprintf("simulator answered request %q while %q was pending", wire.RequestID, id))
+	}
+	if wire.Version != version {
+		return nil, errors.WrapSimulatorProtocol(fmt.Sprintf("simulator answered with protocol %s instead of %s", wire.Version, version))
+	}
+
+	if !wire.Success {
+		if wire.Error.Code == ipc.ErrorCodeUnsupportedVersion {
+			return nil, errors.WrapSimulatorProtocol(wire.Error.Message)
+		}
+		return &SimulationResponse{Status: "error", Error: wire.Error.Message}, nil
+	}
+
+	result := wire.Result
+	resp := &SimulationResponse{
+		Status:     "success",
+		Logs:       result.Logs,
+		Flamegraph: result.Flamegraph,
+	}
+	if err := unmarshalOptional(result.Events, &resp.Events); err != nil {
+		return nil, err
+	}
+	if err := unmarshalOptional(result.SecurityViolations, &resp.SecurityViolations); err != nil {
+		return nil, err
+	}
+	if len(result.AuthTrace) > 0 {
+		resp.AuthTrace = &authtrace.AuthTrace{}
+		if err := unmarshalOptional(result.AuthTrace, resp.AuthTrace); err != nil {
+			return nil, err
+		}
+	}
+	return resp, nil
+}
+
+func unmarshalOptional(raw json.RawMessage, v interface{}) error {
+	if len(raw) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(raw, v); err != nil {
+		return errors.WrapUnmarshalFailed(err, truncateOutput(raw))
+	}
+	return nil
+}
+
+func truncateOutput(out []byte) string {
+	const max = 200
+	if len(out) > max {
+		return string(out[:max]) + "..."
+	}
+	return string(out)
+}
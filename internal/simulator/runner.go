@@ -16,12 +16,14 @@ package simulator
 
 import (
 	"bytes"
-	"encoding/json"
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sync"
 
+	"github.com/dotandev/hintents/internal/errors"
 	"github.com/dotandev/hintents/internal/logger"
 )
 
@@ -29,6 +31,11 @@ import (
 type Runner struct {
 	BinaryPath string
 	Debug      bool
+	// Limits bounds every simulator run. NewRunner sets DefaultLimits.
+	Limits Limits
+
+	protocolMu sync.Mutex
+	protocol   string
 }
 
 // NewRunner creates a new simulator runner.
@@ -55,6 +62,7 @@ func NewRunner(simPathOverride string, debug bool) (*Runner, error) {
 	return &Runner{
 		BinaryPath: path,
 		Debug:      debug,
+		Limits:     DefaultLimits,
 	}, nil
 }
 
@@ -132,43 +140,139 @@ func abs(path string) string {
 
 // Run executes the simulation with the given request
 func (r *Runner) Run(req *SimulationRequest) (*SimulationResponse, error) {
+	return r.RunContext(context.Background(), req)
+}
+
+// Protocol returns the protocol version negotiated with the simulator binary.
+// The handshake runs on first use and its outcome is cached once successful.
+func (r *Runner) Protocol(ctx context.Context) (string, error) {
+	r.protocolMu.Lock()
+	defer r.protocolMu.Unlock()
+
+	if r.protocol != "" {
+		return r.protocol, nil
+	}
+
+	parent := ctx
+	ctx, cancel := context.WithTimeout(ctx, handshakeTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, r.BinaryPath, handshakeFlag)
+	cmd.WaitDelay = waitDelay
+	stdout := newCappedBuffer(handshakeMaxBytes, func() {})
+	stderr := newCappedBuffer(handshakeMaxBytes, func() {})
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+
+	runErr := cmd.Run()
+	if parent.Err() != nil {
+		return "", errors.WrapSimulationCancelled(context.Cause(parent))
+	}
+	if ctx.Err() != nil {
+		return "", errors.WrapSimulatorProtocol(fmt.Sprintf(
+			"%s did not answer the protocol handshake within %s", r.BinaryPath, handshakeTimeout))
+	}
+
+	line, _, _ := bytes.Cut(stdout.Bytes(), []byte("\n"))
+	version, err := negotiateProtocol(r.BinaryPath, line)
+	if err != nil {
+		if runErr != nil {
+			return "", fmt.Errorf("%w (%v, stderr: %s)", err, runErr, stderr.String())
+		}
+		return "", err
+	}
+
+	logger.Logger.Debug("Simulator protocol negotiated", "binary", r.BinaryPath, "version", version)
+	r.protocol = version
+	return version, nil
+}
+
+// RunContext executes the simulation with the given request. The simulator
+// process is killed when ctx is cancelled or the runner's timeout expires, and
+// when its output grows past the configured caps.
+func (r *Runner) RunContext(ctx context.Context, req *SimulationRequest) (*SimulationResponse, error) {
 	logger.Logger.Debug("Starting simulation", "binary", r.BinaryPath)
 
+	version, err := r.Protocol(ctx)
+	if err != nil {
+		logger.Logger.Error("Simulator handshake failed", "error", err)
+		return nil, err
+	}
+
 	// Serialize Request
-	inputBytes, err := json.Marshal(req)
+	id := nextRequestID()
+	inputBytes, err := encodeRequest(req, version, id)
 	if err != nil {
 		logger.Logger.Error("Failed to marshal simulation request", "error", err)
-		return nil, fmt.Errorf("failed to marshal request: %w", err)
+		return nil, err
 	}
 
+	parent := ctx
+	limits := r.Limits
+	if limits.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeoutCause(ctx, limits.Timeout, errors.WrapSimulationTimeout(limits.Timeout))
+		defer cancel()
+	}
+	ctx, abort := context.WithCancelCause(ctx)
+	defer abort(nil)
+
 	// Prepare Command
-	cmd := exec.Command(r.BinaryPath)
+	cmd := exec.CommandContext(ctx, r.BinaryPath)
 	cmd.Stdin = bytes.NewReader(inputBytes)
+	cmd.WaitDelay = waitDelay
 
-	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
+	stdout := newCappedBuffer(limits.MaxStdoutBytes, func() {
+		abort(errors.WrapSimulatorOutputLimit("stdout", limits.MaxStdoutBytes))
+	})
+	stderr := newCappedBuffer(limits.MaxStderrBytes, func() {
+		abort(errors.WrapSimulatorOutputLimit("stderr", limits.MaxStderrBytes))
+	})
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+
+	if err := limitCommand(cmd, limits); err != nil {
+		return nil, fmt.Errorf("failed to apply simulator resource limits: %w", err)
+	}
 
-	logger.Logger.Info("Executing simulator binary")
+	logger.Logger.Info("Executing simulator binary", "request_id", id)
 
-	if err := cmd.Run(); err != nil {
+	if err := cmd.Start(); err != nil {
+		logger.Logger.Error("Failed to start simulator", "error", err)
+		return nil, fmt.Errorf("failed to start simulator: %w", err)
+	}
+
+	err = cmd.Wait()
+	if parent.Err() != nil {
+		logger.Logger.Error("Simulator run cancelled", "error", context.Cause(parent))
+		return nil, errors.WrapSimulationCancelled(context.Cause(parent))
+	}
+	if ctx.Err() != nil {
+		// Timeout or output cap, see the causes set above
+		logger.Logger.Error("Simulator run aborted", "error", context.Cause(ctx))
+		return nil, context.Cause(ctx)
+	}
+	if err != nil {
 		logger.Logger.Error(
 			"Simulator execution failed",
 			"error", err,
 			"stderr", stderr.String(),
 		)
+		if limitErr := resourceLimitError(cmd.ProcessState, limits, stderr.String()); limitErr != nil {
+			return nil, limitErr
+		}
 		return nil, fmt.Errorf("simulator execution failed: %w, stderr: %s", err, stderr.String())
 	}
 
 	// Deserialize Response
-	var resp SimulationResponse
-	if err := json.Unmarshal(stdout.Bytes(), &resp); err != nil {
+	resp, err := decodeResponse(stdout.Bytes(), version, id)
+	if err != nil {
 		logger.Logger.Error(
-			"Failed to unmarshal simulation response",
+			"Failed to decode simulation response",
 			"error", err,
 			"output", stdout.String(),
 		)
-		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+		return nil, err
 	}
 
 	if resp.Status == "error" {
@@ -178,5 +282,5 @@ func (r *Runner) Run(req *SimulationRequest) (*SimulationResponse, error) {
 
 	logger.Logger.Info("Simulation completed successfully")
 
-	return &resp, nil
+	return resp, nil
 }
@@ -0,0 +1,179 @@
+// Copyright (c) 2026 dotandev
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package simulator
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/dotandev/hintents/internal/diagnostic"
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSimulatorPreamble answers the protocol handshake and defines respond,
+// which answers the request line $1 with the result object $2
+const fakeSimulatorPreamble = `handshake='{"protocol_versions":["1.0"],"simulator_version":"test"}'
+if [ "$1" = "--handshake" ]; then echo "$handshake"; exit 0; fi
+respond() {
+  id=$(printf '%s' "$1" | sed 's/.*"request_id":"\([^"]*\)".*/\1/')
+  echo "{\"version\":\"1.0\",\"request_id\":\"$id\",\"success\":true,\"result\":$2}"
+}`
+
+// fakeSimulator writes a shell script standing in for erst-sim
+func fakeSimulator(t *testing.T, script string) *Runner {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("fake simulator scripts need a POSIX shell")
+	}
+	path := filepath.Join(t.TempDir(), "erst-sim")
+	content := "#!/bin/sh\n" + fakeSimulatorPreamble + "\n" + script + "\n"
+	require.NoError(t, os.WriteFile(path, []byte(content), 0755))
+	return &Runner{BinaryPath: path, Limits: DefaultLimits}
+}
+
+// testFnCallEvent is a fn_call diagnostic event without arguments
+const testFnCallEvent = `{"type":"diagnostic","contract_id":"CDLZFC3SYJYDZT7K67VZ75HPJVIEUVNIXF47ZG2FB2RMQQVU2HHGCYSC","topics":["AAAADwAAAAdmbl9jYWxsAA=="],"data":"AAAAAQ==","in_successful_contract_call":true,"call_depth":1}`
+
+func newTestRequest() *SimulationRequest {
+	return &SimulationRequest{EnvelopeXdr: testEnvelopeXDR, ResultMetaXdr: testResultMetaXDR}
+}
+
+func TestRunContext_Success(t *testing.T) {
+	runner := fakeSimulator(t, `read -r line; respond "$line" '{"events":[`+testFnCallEvent+`],"logs":["l1"]}'`)
+
+	resp, err := runner.RunContext(context.Background(), newTestRequest())
+	require.NoError(t, err)
+	assert.Equal(t, "success", resp.Status)
+	assert.Equal(t, []string{"l1"}, resp.Logs)
+	require.Len(t, resp.Events, 1)
+	assert.Equal(t, diagnostic.KindFnCall, resp.Events[0].Kind)
+	assert.Equal(t, "CDLZFC3SYJYDZT7K67VZ75HPJVIEUVNIXF47ZG2FB2RMQQVU2HHGCYSC", resp.Events[0].ContractID)
+	assert.Equal(t, 1, resp.Events[0].CallDepth)
+}
+
+func TestRunContext_SimulationError(t *testing.T) {
+	runner := fakeSimulator(t, `read -r line
+id=$(printf '%s' "$line" | sed 's/.*"request_id":"\([^"]*\)".*/\1/')
+echo "{\"version\":\"1.0\",\"request_id\":\"$id\",\"success\":false,\"error\":{\"code\":\"simulation_failed\",\"message\":\"bad tx\"}}"`)
+
+	_, err := runner.Run(newTestRequest())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "simulation error: bad tx")
+}
+
+func TestRunContext_ProtocolMismatch(t *testing.T) {
+	t.Run("unsupported version", func(t *testing.T) {
+		runner := fakeSimulator(t, "")
+		require.NoError(t, os.WriteFile(runner.BinaryPath, []byte("#!/bin/sh\necho '{\"protocol_versions\":[\"0.9\",\"2.0\"]}'\n"), 0755))
+
+		_, err := runner.Run(newTestRequest())
+		require.Error(t, err)
+		assert.ErrorIs(t, err, errors.ErrSimulatorProtocol)
+		assert.Contains(t, err.Error(), "0.9, 2.0")
+	})
+
+	t.Run("legacy simulator", func(t *testing.T) {
+		runner := fakeSimulator(t, "")
+		require.NoError(t, os.WriteFile(runner.BinaryPath, []byte("#!/bin/sh\ncat > /dev/null; echo '{\"status\":\"success\"}'\n"), 0755))
+
+		_, err := runner.Run(newTestRequest())
+		require.Error(t, err)
+		assert.ErrorIs(t, err, errors.ErrSimulatorProtocol)
+		assert.Contains(t, err.Error(), "older than erst")
+	})
+
+	t.Run("invalid response", func(t *testing.T) {
+		runner := fakeSimulator(t, `cat > /dev/null; echo '{"status":"success"}'`)
+
+		_, err := runner.Run(newTestRequest())
+		require.Error(t, err)
+		assert.ErrorIs(t, err, errors.ErrSimulatorProtocol)
+		assert.Contains(t, err.Error(), "request_id")
+	})
+}
+
+func TestRunContext_RejectsInvalidRequest(t *testing.T) {
+	runner := fakeSimulator(t, `read -r line; respond "$line" '{}'`)
+
+	_, err := runner.Run(&SimulationRequest{EnvelopeXdr: "not base64!"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid simulation request")
+}
+
+func TestRunContext_ReportsStderr(t *testing.T) {
+	runner := fakeSimulator(t, `echo boom >&2; exit 3`)
+
+	_, err := runner.Run(newTestRequest())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "boom")
+}
+
+func TestRunContext_Timeout(t *testing.T) {
+	runner := fakeSimulator(t, `exec sleep 30`)
+	runner.Limits.Timeout = 100 * time.Millisecond
+
+	start := time.Now()
+	_, err := runner.RunContext(context.Background(), newTestRequest())
+	require.Error(t, err)
+	assert.ErrorIs(t, err, errors.ErrSimulationTimeout)
+	assert.Less(t, time.Since(start), 10*time.Second)
+}
+
+func TestRunContext_Cancellation(t *testing.T) {
+	runner := fakeSimulator(t, `exec sleep 30`)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(100*time.Millisecond, cancel)
+
+	start := time.Now()
+	_, err := runner.RunContext(ctx, newTestRequest())
+	require.Error(t, err)
+	assert.ErrorIs(t, err, errors.ErrSimulationCancelled)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Less(t, time.Since(start), 10*time.Second)
+}
+
+func TestRunContext_OutputLimit(t *testing.T) {
+	runner := fakeSimulator(t, `exec yes`)
+	runner.Limits.MaxStdoutBytes = 1024
+
+	_, err := runner.RunContext(context.Background(), newTestRequest())
+	require.Error(t, err)
+	assert.ErrorIs(t, err, errors.ErrSimulatorOutputLimit)
+	assert.Contains(t, err.Error(), "stdout")
+}
+
+func TestCappedBuffer(t *testing.T) {
+	calls := 0
+	buf := newCappedBuffer(5, func() { calls++ })
+
+	n, err := buf.Write([]byte("abc"))
+	require.NoError(t, err)
+	assert.Equal(t, 3, n)
+
+	n, err = buf.Write([]byte("defg"))
+	require.NoError(t, err)
+	assert.Equal(t, 4, n)
+	_, _ = buf.Write([]byte("h"))
+
+	assert.Equal(t, "abcde", buf.String())
+	assert.Equal(t, 1, calls)
+}
@@ -21,6 +21,7 @@ import (
 	"time"
 
 	"github.com/dotandev/hintents/internal/authtrace"
+	"github.com/dotandev/hintents/internal/diagnostic"
 	_ "modernc.org/sqlite"
 )
 
@@ -52,13 +53,6 @@ type AuthTraceOptions struct {
 	MaxEventDepth        int  `json:"max_event_depth,omitempty"`
 }
 
-type CategorizedEvent struct {
-	EventType  string   `json:"event_type"`
-	ContractID *string  `json:"contract_id,omitempty"`
-	Topics     []string `json:"topics"`
-	Data       string   `json:"data"`
-}
-
 type SecurityViolation struct {
 	Type        string                 `json:"type"`
 	Severity    string                 `json:"severity"`
@@ -70,8 +64,7 @@ type SecurityViolation struct {
 type SimulationResponse struct {
 	Status             string               `json:"status"` // "success" or "error"
 	Error              string               `json:"error,omitempty"`
-	Events             []string             `json:"events,omitempty"`
-	CategorizedEvents  []CategorizedEvent   `json:"categorized_events,omitempty"`
+	Events             []diagnostic.Event   `json:"events,omitempty"`
 	Logs               []string             `json:"logs,omitempty"`
 	SecurityViolations []SecurityViolation  `json:"security_violations,omitempty"`
 	Flamegraph         string               `json:"flamegraph,omitempty"` // SVG flamegraph
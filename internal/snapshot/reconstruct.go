@@ -0,0 +1,345 @@
+// Copyright (c) 2026 dotandev
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snapshot
+
+import (
+	"encoding/base64"
+	"fmt"
+	"sort"
+
+	"github.com/stellar/go/xdr"
+)
+
+// Reconstruction is the ledger state a transaction saw right before it was
+// applied, rebuilt from its TransactionResultMeta.
+type Reconstruction struct {
+	// Entries maps base64 LedgerKey XDR to the base64 LedgerEntry XDR the
+	// transaction observed before execution.
+	Entries map[string]string
+	// Absent lists keys that did not exist before execution: keys the
+	// transaction created, and keys the fee phase removed.
+	Absent []string
+	// Unreconstructed lists keys the meta touches without recording a
+	// "before" image. Callers have to fall back to another source for these.
+	Unreconstructed []string
+}
+
+// Apply overlays the reconstruction on top of current, typically the output of
+// GetLedgerEntries. Reconstructed entries replace the current value, absent
+// entries are dropped and unreconstructed entries are left as they are.
+// current is not modified.
+func (r *Reconstruction) Apply(current map[string]string) map[string]string {
+	out := make(map[string]string, len(current)+len(r.Entries))
+	for k, v := range current {
+		out[k] = v
+	}
+	for k, v := range r.Entries {
+		out[k] = v
+	}
+	for _, k := range r.Absent {
+		delete(out, k)
+	}
+	return out
+}
+
+// LedgerChanges returns the ledger entry changes recorded in meta, split into
+// the fee processing phase and the apply phase. Apply phase changes are in
+// application order: transaction-level changes before the operations, then
+// each operation, then transaction-level changes after the operations.
+func LedgerChanges(meta xdr.TransactionResultMeta) (fee, apply xdr.LedgerEntryChanges) {
+	before, operations, after := applyPhases(meta.TxApplyProcessing)
+	apply = append(apply, before...)
+	apply = append(apply, operations...)
+	apply = append(apply, after...)
+	return meta.FeeProcessing, apply
+}
+
+// applyPhases splits the apply phase changes of tm into the transaction-level
+// changes before the operations, the changes of the operations and the
+// transaction-level changes after them
+func applyPhases(tm xdr.TransactionMeta) (before, operations, after xdr.LedgerEntryChanges) {
+	var ops []xdr.OperationMeta
+	switch tm.V {
+	case 0:
+		if tm.Operations != nil {
+			ops = *tm.Operations
+		}
+	case 1:
+		if v1 := tm.V1; v1 != nil {
+			before, ops = v1.TxChanges, v1.Operations
+		}
+	case 2:
+		if v2 := tm.V2; v2 != nil {
+			before, ops, after = v2.TxChangesBefore, v2.Operations, v2.TxChangesAfter
+		}
+	case 3:
+		if v3 := tm.V3; v3 != nil {
+			before, ops, after = v3.TxChangesBefore, v3.Operations, v3.TxChangesAfter
+		}
+	case 4:
+		if v4 := tm.V4; v4 != nil {
+			before = v4.TxChangesBefore
+			for _, op := range v4.Operations {
+				operations = append(operations, op.Changes...)
+			}
+			return before, operations, v4.TxChangesAfter
+		}
+	}
+	for _, op := range ops {
+		operations = append(operations, op.Changes...)
+	}
+	return before, operations, after
+}
+
+// Writes returns the ledger entries the operations of a base64
+// TransactionResultMeta left behind, as base64 LedgerKey XDR to base64
+// LedgerEntry XDR, "" for a removed entry. Fee processing and the
+// transaction-level changes around the operations, such as the sequence
+// number bump and the fee refund, are left out.
+func Writes(metaXdr string) (map[string]string, error) {
+	var meta xdr.TransactionResultMeta
+	if err := xdr.SafeUnmarshalBase64(metaXdr, &meta); err != nil {
+		return nil, fmt.Errorf("failed to decode transaction meta: %w", err)
+	}
+
+	_, operations, _ := applyPhases(meta.TxApplyProcessing)
+	writes := make(map[string]string)
+	for i := range operations {
+		change := &operations[i]
+		if change.Type == xdr.LedgerEntryChangeTypeLedgerEntryState {
+			continue
+		}
+		key, err := changeKey(change)
+		if err != nil {
+			return nil, err
+		}
+		entry := changeEntry(change)
+		if entry == nil {
+			writes[key] = ""
+			continue
+		}
+		b64, err := xdr.MarshalBase64(*entry)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode ledger entry: %w", err)
+		}
+		writes[key] = b64
+	}
+	return writes, nil
+}
+
+// ReconstructFromMeta rebuilds pre-execution state from a base64
+// TransactionResultMeta.
+//
+// Fees for a whole ledger are charged before any transaction is applied, so
+// fee processing changes are rolled forward: their final value is the state the
+// transaction started from. Apply phase changes are rolled back: the first
+// change seen for a key decides its pre-execution value.
+//   - State and Restored carry the entry as it was before the change
+//   - Created means the entry did not exist yet
+//   - Updated or Removed without a preceding State image cannot be rolled
+//     back, and the key is reported in Unreconstructed
+func ReconstructFromMeta(metaXdr string) (*Reconstruction, error) {
+	var meta xdr.TransactionResultMeta
+	if err := xdr.SafeUnmarshalBase64(metaXdr, &meta); err != nil {
+		return nil, fmt.Errorf("failed to decode transaction meta: %w", err)
+	}
+	return Reconstruct(meta)
+}
+
+// ReconstructTransaction is ReconstructFromMeta for a transaction whose
+// Soroban footprint may read or write keys its meta does not record, such as
+// every key of a failed transaction, whose apply changes are rolled back.
+// Footprint keys the meta does not cover are reported in Unreconstructed.
+func ReconstructTransaction(envelopeXdr, metaXdr string) (*Reconstruction, error) {
+	footprint, err := FootprintKeys(envelopeXdr)
+	if err != nil {
+		return nil, err
+	}
+	r, err := ReconstructFromMeta(metaXdr)
+	if err != nil {
+		return nil, err
+	}
+
+	covered := make(map[string]struct{}, len(r.Entries)+len(r.Absent)+len(r.Unreconstructed))
+	for key := range r.Entries {
+		covered[key] = struct{}{}
+	}
+	for _, keys := range [][]string{r.Absent, r.Unreconstructed} {
+		for _, key := range keys {
+			covered[key] = struct{}{}
+		}
+	}
+	for _, key := range footprint {
+		if _, ok := covered[key]; !ok {
+			covered[key] = struct{}{}
+			r.Unreconstructed = append(r.Unreconstructed, key)
+		}
+	}
+	sort.Strings(r.Unreconstructed)
+	return r, nil
+}
+
+// FootprintKeys returns the base64 LedgerKey XDR of the read-only and
+// read-write footprint of a base64 TransactionEnvelope, nil when the
+// transaction carries no SorobanTransactionData
+func FootprintKeys(envelopeXdr string) ([]string, error) {
+	var env xdr.TransactionEnvelope
+	if err := xdr.SafeUnmarshalBase64(envelopeXdr, &env); err != nil {
+		return nil, fmt.Errorf("failed to decode transaction envelope: %w", err)
+	}
+
+	var ext xdr.TransactionExt
+	switch env.Type {
+	case xdr.EnvelopeTypeEnvelopeTypeTx:
+		ext = env.V1.Tx.Ext
+	case xdr.EnvelopeTypeEnvelopeTypeTxFeeBump:
+		if inner := env.FeeBump.Tx.InnerTx; inner.V1 != nil {
+			ext = inner.V1.Tx.Ext
+		}
+	}
+	data, ok := ext.GetSorobanData()
+	if !ok {
+		return nil, nil
+	}
+
+	footprint := data.Resources.Footprint
+	keys := make([]string, 0, len(footprint.ReadOnly)+len(footprint.ReadWrite))
+	for _, key := range append(append([]xdr.LedgerKey{}, footprint.ReadOnly...), footprint.ReadWrite...) {
+		b64, err := xdr.MarshalBase64(key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode ledger key: %w", err)
+		}
+		keys = append(keys, b64)
+	}
+	return keys, nil
+}
+
+// Reconstruct is ReconstructFromMeta for already decoded meta.
+func Reconstruct(meta xdr.TransactionResultMeta) (*Reconstruction, error) {
+	fee, apply := LedgerChanges(meta)
+
+	// Roll fee processing forward. A nil value means the entry was removed.
+	afterFees := make(map[string]*xdr.LedgerEntry)
+	var feeOrder []string
+	for i := range fee {
+		change := &fee[i]
+		key, err := changeKey(change)
+		if err != nil {
+			return nil, err
+		}
+		if _, seen := afterFees[key]; !seen {
+			feeOrder = append(feeOrder, key)
+		}
+		afterFees[key] = changeEntry(change)
+	}
+
+	var (
+		values          = make(map[string]*xdr.LedgerEntry)
+		absent          []string
+		unreconstructed []string
+		seen            = make(map[string]struct{})
+	)
+
+	// Roll the apply phase back: only the first change per key matters
+	for i := range apply {
+		change := &apply[i]
+		key, err := changeKey(change)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+
+		switch change.Type {
+		case xdr.LedgerEntryChangeTypeLedgerEntryState:
+			values[key] = change.State
+		case xdr.LedgerEntryChangeTypeLedgerEntryRestored:
+			values[key] = change.Restored
+		case xdr.LedgerEntryChangeTypeLedgerEntryCreated:
+			absent = append(absent, key)
+		default:
+			if entry, ok := afterFees[key]; ok {
+				if entry == nil {
+					absent = append(absent, key)
+				} else {
+					values[key] = entry
+				}
+				continue
+			}
+			unreconstructed = append(unreconstructed, key)
+		}
+	}
+
+	// Keys only touched by fee processing keep their post-fee value
+	for _, key := range feeOrder {
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		if entry := afterFees[key]; entry != nil {
+			values[key] = entry
+		} else {
+			absent = append(absent, key)
+		}
+	}
+
+	entries := make(map[string]string, len(values))
+	for key, entry := range values {
+		b64, err := xdr.MarshalBase64(*entry)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode ledger entry: %w", err)
+		}
+		entries[key] = b64
+	}
+
+	sort.Strings(absent)
+	sort.Strings(unreconstructed)
+
+	return &Reconstruction{
+		Entries:         entries,
+		Absent:          absent,
+		Unreconstructed: unreconstructed,
+	}, nil
+}
+
+// changeKey returns the base64 LedgerKey XDR a change applies to
+func changeKey(change *xdr.LedgerEntryChange) (string, error) {
+	key, err := change.LedgerKey()
+	if err != nil {
+		return "", fmt.Errorf("failed to derive ledger key: %w", err)
+	}
+	b, err := key.MarshalBinary()
+	if err != nil {
+		return "", fmt.Errorf("failed to encode ledger key: %w", err)
+	}
+	return base64.StdEncoding.EncodeToString(b), nil
+}
+
+// changeEntry returns the entry a change leaves behind, or nil if it removed it
+func changeEntry(change *xdr.LedgerEntryChange) *xdr.LedgerEntry {
+	switch change.Type {
+	case xdr.LedgerEntryChangeTypeLedgerEntryCreated:
+		return change.Created
+	case xdr.LedgerEntryChangeTypeLedgerEntryUpdated:
+		return change.Updated
+	case xdr.LedgerEntryChangeTypeLedgerEntryState:
+		return change.State
+	case xdr.LedgerEntryChangeTypeLedgerEntryRestored:
+		return change.Restored
+	default:
+		return nil
+	}
+}
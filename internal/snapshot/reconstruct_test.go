@@ -0,0 +1,295 @@
+// Copyright (c) 2026 dotandev
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snapshot
+
+import (
+	"testing"
+
+	"github.com/stellar/go/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	accountA = "GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H"
+	accountB = "GCRRSYF5JBFPXHN5DCG65A4J3MUYE53QMQ4XMXZ3CNKWFJIJJTGMH6MZ"
+	accountC = "GAHK7EEG2WWHVKDNT4CEQFZGKF2LGDSW2IVM4S5DP42RBW3K6BTODB4A"
+	accountD = "GBUQWP3BOUZX34TOND2QV7QQ7K7VJTG6VSE7WMLBTMDJLLAW7YKGU6EP"
+)
+
+func accountEntry(address string, balance xdr.Int64, lastModified xdr.Uint32) xdr.LedgerEntry {
+	return xdr.LedgerEntry{
+		LastModifiedLedgerSeq: lastModified,
+		Data: xdr.LedgerEntryData{
+			Type: xdr.LedgerEntryTypeAccount,
+			Account: &xdr.AccountEntry{
+				AccountId: xdr.MustAddress(address),
+				Balance:   balance,
+			},
+		},
+	}
+}
+
+func state(e xdr.LedgerEntry) xdr.LedgerEntryChange {
+	return xdr.LedgerEntryChange{Type: xdr.LedgerEntryChangeTypeLedgerEntryState, State: &e}
+}
+
+func updated(e xdr.LedgerEntry) xdr.LedgerEntryChange {
+	return xdr.LedgerEntryChange{Type: xdr.LedgerEntryChangeTypeLedgerEntryUpdated, Updated: &e}
+}
+
+func created(e xdr.LedgerEntry) xdr.LedgerEntryChange {
+	return xdr.LedgerEntryChange{Type: xdr.LedgerEntryChangeTypeLedgerEntryCreated, Created: &e}
+}
+
+func keyOf(t *testing.T, e xdr.LedgerEntry) string {
+	t.Helper()
+	k, err := e.LedgerKey()
+	require.NoError(t, err)
+	b64, err := xdr.MarshalBase64(k)
+	require.NoError(t, err)
+	return b64
+}
+
+func entryOf(t *testing.T, e xdr.LedgerEntry) string {
+	t.Helper()
+	b64, err := xdr.MarshalBase64(e)
+	require.NoError(t, err)
+	return b64
+}
+
+func TestReconstruct_RollsBackApplyChanges(t *testing.T) {
+	feeBefore := accountEntry(accountA, 1000, 10)
+	feeAfter := accountEntry(accountA, 900, 20)
+	seqBumped := accountEntry(accountA, 900, 20)
+	before := accountEntry(accountB, 50, 5)
+	newAccount := accountEntry(accountC, 10, 20)
+	feeOnly := accountEntry(accountD, 70, 20)
+
+	meta := xdr.TransactionResultMeta{
+		Result: xdr.TransactionResultPair{
+			Result: xdr.TransactionResult{
+				Result: xdr.TransactionResultResult{
+					Code:    xdr.TransactionResultCodeTxSuccess,
+					Results: &[]xdr.OperationResult{},
+				},
+			},
+		},
+		FeeProcessing: xdr.LedgerEntryChanges{
+			state(feeBefore), updated(feeAfter),
+			state(accountEntry(accountD, 80, 3)), updated(feeOnly),
+		},
+		TxApplyProcessing: xdr.TransactionMeta{
+			V: 3,
+			V3: &xdr.TransactionMetaV3{
+				// Account A is bumped without a State image: the post-fee value is used
+				TxChangesBefore: xdr.LedgerEntryChanges{updated(seqBumped)},
+				Operations: []xdr.OperationMeta{{
+					Changes: xdr.LedgerEntryChanges{
+						state(before), updated(accountEntry(accountB, 40, 20)),
+						created(newAccount),
+						updated(accountEntry(accountB, 30, 20)),
+					},
+				}},
+			},
+		},
+	}
+	metaB64, err := xdr.MarshalBase64(meta)
+	require.NoError(t, err)
+
+	r, err := ReconstructFromMeta(metaB64)
+	require.NoError(t, err)
+
+	assert.Equal(t, entryOf(t, feeAfter), r.Entries[keyOf(t, feeAfter)])
+	assert.Equal(t, entryOf(t, before), r.Entries[keyOf(t, before)])
+	assert.Equal(t, entryOf(t, feeOnly), r.Entries[keyOf(t, feeOnly)])
+	assert.Equal(t, []string{keyOf(t, newAccount)}, r.Absent)
+	assert.Empty(t, r.Unreconstructed)
+}
+
+func TestReconstruct_ReportsUnreconstructedKeys(t *testing.T) {
+	entry := accountEntry(accountA, 100, 20)
+	removed := accountEntry(accountB, 0, 0)
+	removedKey, err := removed.LedgerKey()
+	require.NoError(t, err)
+
+	meta := xdr.TransactionResultMeta{
+		TxApplyProcessing: xdr.TransactionMeta{
+			V: 1,
+			V1: &xdr.TransactionMetaV1{
+				TxChanges: xdr.LedgerEntryChanges{updated(entry)},
+				Operations: []xdr.OperationMeta{{
+					Changes: xdr.LedgerEntryChanges{
+						{Type: xdr.LedgerEntryChangeTypeLedgerEntryRemoved, Removed: &removedKey},
+					},
+				}},
+			},
+		},
+	}
+
+	r, err := Reconstruct(meta)
+	require.NoError(t, err)
+
+	removedB64, err := xdr.MarshalBase64(removedKey)
+	require.NoError(t, err)
+
+	assert.Empty(t, r.Entries)
+	assert.ElementsMatch(t, []string{keyOf(t, entry), removedB64}, r.Unreconstructed)
+}
+
+func TestReconstruction_Apply(t *testing.T) {
+	r := &Reconstruction{
+		Entries: map[string]string{"a": "a-before"},
+		Absent:  []string{"b"},
+	}
+	current := map[string]string{"a": "a-now", "b": "b-now", "c": "c-now"}
+
+	got := r.Apply(current)
+
+	assert.Equal(t, map[string]string{"a": "a-before", "c": "c-now"}, got)
+	assert.Equal(t, "a-now", current["a"], "input must not be modified")
+}
+
+func TestWrites(t *testing.T) {
+	seqBumped := accountEntry(accountA, 900, 20)
+	changed := accountEntry(accountB, 30, 20)
+	newAccount := accountEntry(accountC, 10, 20)
+	removed := accountEntry(accountD, 0, 0)
+	removedKey, err := removed.LedgerKey()
+	require.NoError(t, err)
+
+	meta := xdr.TransactionResultMeta{
+		Result: xdr.TransactionResultPair{
+			Result: xdr.TransactionResult{
+				Result: xdr.TransactionResultResult{
+					Code:    xdr.TransactionResultCodeTxSuccess,
+					Results: &[]xdr.OperationResult{},
+				},
+			},
+		},
+		TxApplyProcessing: xdr.TransactionMeta{
+			V: 3,
+			V3: &xdr.TransactionMetaV3{
+				TxChangesBefore: xdr.LedgerEntryChanges{updated(seqBumped)},
+				Operations: []xdr.OperationMeta{{
+					Changes: xdr.LedgerEntryChanges{
+						state(accountEntry(accountB, 50, 5)), updated(accountEntry(accountB, 40, 20)),
+						created(newAccount),
+						updated(changed),
+						{Type: xdr.LedgerEntryChangeTypeLedgerEntryRemoved, Removed: &removedKey},
+					},
+				}},
+			},
+		},
+	}
+	metaB64, err := xdr.MarshalBase64(meta)
+	require.NoError(t, err)
+
+	writes, err := Writes(metaB64)
+	require.NoError(t, err)
+
+	removedB64, err := xdr.MarshalBase64(removedKey)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		keyOf(t, changed):    entryOf(t, changed),
+		keyOf(t, newAccount): entryOf(t, newAccount),
+		removedB64:           "",
+	}, writes, "the last change of each key, without transaction-level changes")
+
+	_, err = Writes("not-xdr")
+	assert.Error(t, err)
+}
+
+func TestReconstructFromMeta_InvalidXdr(t *testing.T) {
+	_, err := ReconstructFromMeta("not-base64!")
+	assert.Error(t, err)
+}
+
+func footprintEnvelope(t *testing.T, readOnly, readWrite []xdr.LedgerKey) string {
+	t.Helper()
+	b64, err := xdr.MarshalBase64(xdr.TransactionEnvelope{
+		Type: xdr.EnvelopeTypeEnvelopeTypeTx,
+		V1: &xdr.TransactionV1Envelope{
+			Tx: xdr.Transaction{
+				SourceAccount: xdr.MustMuxedAddress(accountA),
+				Ext: xdr.TransactionExt{
+					V: 1,
+					SorobanData: &xdr.SorobanTransactionData{
+						Resources: xdr.SorobanResources{
+							Footprint: xdr.LedgerFootprint{ReadOnly: readOnly, ReadWrite: readWrite},
+						},
+					},
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+	return b64
+}
+
+func TestReconstructTransaction_ReportsFootprint(t *testing.T) {
+	feeBefore := accountEntry(accountA, 1000, 10)
+	feeAfter := accountEntry(accountA, 900, 20)
+	readOnlyEntry, readWriteEntry := accountEntry(accountB, 0, 0), accountEntry(accountC, 0, 0)
+	readOnly, err := readOnlyEntry.LedgerKey()
+	require.NoError(t, err)
+	readWrite, err := readWriteEntry.LedgerKey()
+	require.NoError(t, err)
+	feeKey, err := feeAfter.LedgerKey()
+	require.NoError(t, err)
+
+	// A failed transaction: only the fee is recorded, the apply changes are
+	// rolled back
+	metaB64, err := xdr.MarshalBase64(xdr.TransactionResultMeta{
+		Result: xdr.TransactionResultPair{
+			Result: xdr.TransactionResult{
+				Result: xdr.TransactionResultResult{
+					Code:    xdr.TransactionResultCodeTxFailed,
+					Results: &[]xdr.OperationResult{},
+				},
+			},
+		},
+		FeeProcessing:     xdr.LedgerEntryChanges{state(feeBefore), updated(feeAfter)},
+		TxApplyProcessing: xdr.TransactionMeta{V: 3, V3: &xdr.TransactionMetaV3{}},
+	})
+	require.NoError(t, err)
+	envelope := footprintEnvelope(t, []xdr.LedgerKey{readOnly}, []xdr.LedgerKey{readWrite, feeKey})
+
+	footprint, err := FootprintKeys(envelope)
+	require.NoError(t, err)
+	readOnlyB64, err := xdr.MarshalBase64(readOnly)
+	require.NoError(t, err)
+	readWriteB64, err := xdr.MarshalBase64(readWrite)
+	require.NoError(t, err)
+	assert.Equal(t, []string{readOnlyB64, readWriteB64, keyOf(t, feeAfter)}, footprint)
+
+	r, err := ReconstructTransaction(envelope, metaB64)
+	require.NoError(t, err)
+	assert.Equal(t, entryOf(t, feeAfter), r.Entries[keyOf(t, feeAfter)])
+	// Footprint keys missing from the meta replay their current value
+	assert.ElementsMatch(t, []string{readOnlyB64, readWriteB64}, r.Unreconstructed)
+}
+
+func TestFootprintKeys_ClassicTransaction(t *testing.T) {
+	b64, err := xdr.MarshalBase64(xdr.TransactionEnvelope{
+		Type: xdr.EnvelopeTypeEnvelopeTypeTx,
+		V1:   &xdr.TransactionV1Envelope{Tx: xdr.Transaction{SourceAccount: xdr.MustMuxedAddress(accountA)}},
+	})
+	require.NoError(t, err)
+
+	keys, err := FootprintKeys(b64)
+	require.NoError(t, err)
+	assert.Empty(t, keys)
+}
@@ -28,18 +28,19 @@ import (
 // TestRegression_{{.TestName}} is a regression test for transaction {{.TxHash}}
 func TestRegression_{{.TestName}}(t *testing.T) {
 	// Create simulation request with captured transaction data
-	req := &simulator.SimulationRequest{
-		EnvelopeXdr:   "{{.EnvelopeXdr}}",
-		ResultMetaXdr: "{{.ResultMetaXdr}}",
-		LedgerEntries: map[string]string{
+	req, err := simulator.NewSimulationRequestBuilder().
+		WithEnvelopeXDR("{{.EnvelopeXdr}}").
+		WithResultMetaXDR("{{.ResultMetaXdr}}").
+		WithLedgerEntries(map[string]string{
 {{- range .LedgerEntries}}
 			"{{.Key}}": "{{.Value}}",
 {{- end}}
-		},
-	}
+		}).
+		Build()
+	require.NoError(t, err, "Invalid simulation request")
 
 	// Create simulator runner
-	runner, err := simulator.NewRunner()
+	runner, err := simulator.NewRunner("", false)
 	require.NoError(t, err, "Failed to create simulator runner")
 
 	// Run simulation
@@ -21,6 +21,7 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/dotandev/hintents/internal/decoder"
 	"github.com/stellar/go/strkey"
 	"github.com/stellar/go/xdr"
 )
@@ -227,7 +228,7 @@ func extractSACTransfersAndMints(resultMetaXdrB64 string) ([]Transfer, error) {
 			if !ok {
 				continue
 			}
-			amt, ok := scValAmount(body.Data)
+			amt, ok := decoder.ScValInt(body.Data)
 			if !ok || amt.Sign() < 0 {
 				continue
 			}
@@ -247,7 +248,7 @@ func extractSACTransfersAndMints(resultMetaXdrB64 string) ([]Transfer, error) {
 			if !ok {
 				continue
 			}
-			amt, ok := scValAmount(body.Data)
+			amt, ok := decoder.ScValInt(body.Data)
 			if !ok || amt.Sign() < 0 {
 				continue
 			}
@@ -296,47 +297,6 @@ func scValAddressString(v xdr.ScVal) (string, bool) {
 	return s, true
 }
 
-func scValAmount(v xdr.ScVal) (*big.Int, bool) {
-	switch v.Type {
-	case xdr.ScValTypeScvU64:
-		if v.U64 == nil {
-			return nil, false
-		}
-		return new(big.Int).SetUint64(uint64(*v.U64)), true
-	case xdr.ScValTypeScvI64:
-		if v.I64 == nil {
-			return nil, false
-		}
-		return new(big.Int).SetInt64(int64(*v.I64)), true
-	case xdr.ScValTypeScvU128:
-		if v.U128 == nil {
-			return nil, false
-		}
-		return bigIntFromU128(*v.U128), true
-	case xdr.ScValTypeScvI128:
-		if v.I128 == nil {
-			return nil, false
-		}
-		return bigIntFromI128(*v.I128), true
-	default:
-		return nil, false
-	}
-}
-
-func bigIntFromU128(p xdr.UInt128Parts) *big.Int {
-	hi := new(big.Int).SetUint64(uint64(p.Hi))
-	lo := new(big.Int).SetUint64(uint64(p.Lo))
-	hi.Lsh(hi, 64)
-	return hi.Or(hi, lo)
-}
-
-func bigIntFromI128(p xdr.Int128Parts) *big.Int {
-	hi := new(big.Int).SetInt64(int64(p.Hi))
-	lo := new(big.Int).SetUint64(uint64(p.Lo))
-	hi.Lsh(hi, 64)
-	return hi.Or(hi, lo)
-}
-
 func muxedAccountToAddress(a xdr.MuxedAccount) (string, error) {
 	ma := a
 	return (&ma).GetAddress()
@@ -0,0 +1,277 @@
+// Copyright (c) 2026 dotandev
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Breakpoint stops Continue and ReverseContinue on the steps matching all of
+// its conditions
+type Breakpoint struct {
+	ID int `json:"id"`
+	// ContractID matches the steps of contracts whose ID starts with it
+	ContractID string `json:"contract_id,omitempty"`
+	// Function matches the steps calling this function
+	Function string `json:"function,omitempty"`
+	// OnError matches the steps that failed
+	OnError bool `json:"on_error,omitempty"`
+	// HostStateKey and MemoryKey match the steps changing the value of the key
+	HostStateKey string `json:"host_state_key,omitempty"`
+	MemoryKey    string `json:"memory_key,omitempty"`
+	Disabled     bool   `json:"disabled,omitempty"`
+}
+
+// ParseBreakpoint parses the conditions of a breakpoint, separated by spaces:
+//
+//	contract=<id prefix> function=<name> error host=<key> memory=<key>
+//
+// A bare word is a contract ID when it looks like one, and a function name
+// otherwise, so "break transfer" stops on every call to transfer.
+func ParseBreakpoint(spec string) (Breakpoint, error) {
+	var bp Breakpoint
+	for _, token := range strings.Fields(spec) {
+		name, value, hasValue := strings.Cut(token, "=")
+		switch {
+		case !hasValue && name == "error":
+			bp.OnError = true
+		case !hasValue && isContractID(name):
+			bp.ContractID = name
+		case !hasValue:
+			bp.Function = name
+		case value == "":
+			return Breakpoint{}, fmt.Errorf("breakpoint condition %q has no value", token)
+		case name == "contract":
+			bp.ContractID = value
+		case name == "function" || name == "fn":
+			bp.Function = value
+		case name == "host":
+			bp.HostStateKey = value
+		case name == "memory":
+			bp.MemoryKey = value
+		default:
+			return Breakpoint{}, fmt.Errorf("unknown breakpoint condition %q, expected contract, function, error, host or memory", name)
+		}
+	}
+	if err := bp.validate(); err != nil {
+		return Breakpoint{}, err
+	}
+	return bp, nil
+}
+
+func isContractID(s string) bool {
+	return len(s) == 56 && s[0] == 'C'
+}
+
+func (b Breakpoint) validate() error {
+	if b.ContractID == "" && b.Function == "" && !b.OnError && b.HostStateKey == "" && b.MemoryKey == "" {
+		return fmt.Errorf("breakpoint has no condition")
+	}
+	return nil
+}
+
+// String renders the conditions in the syntax ParseBreakpoint reads
+func (b Breakpoint) String() string {
+	var conditions []string
+	if b.ContractID != "" {
+		conditions = append(conditions, "contract="+b.ContractID)
+	}
+	if b.Function != "" {
+		conditions = append(conditions, "function="+b.Function)
+	}
+	if b.OnError {
+		conditions = append(conditions, "error")
+	}
+	if b.HostStateKey != "" {
+		conditions = append(conditions, "host="+b.HostStateKey)
+	}
+	if b.MemoryKey != "" {
+		conditions = append(conditions, "memory="+b.MemoryKey)
+	}
+	return strings.Join(conditions, " ")
+}
+
+// Matches reports whether the given step of t meets every condition. It
+// fails if the state of the step cannot be loaded from a lazy trace.
+func (b Breakpoint) Matches(t *ExecutionTrace, step int) (bool, error) {
+	if step < 0 || step >= len(t.States) {
+		return false, nil
+	}
+	state := &t.States[step]
+
+	if b.ContractID != "" && !strings.HasPrefix(state.ContractID, b.ContractID) {
+		return false, nil
+	}
+	if b.Function != "" && state.Function != b.Function {
+		return false, nil
+	}
+	if b.OnError && state.Error == "" {
+		return false, nil
+	}
+	if b.HostStateKey != "" {
+		if changed, err := keyChanged(t, step, b.HostStateKey, false); err != nil || !changed {
+			return false, err
+		}
+	}
+	if b.MemoryKey != "" {
+		if changed, err := keyChanged(t, step, b.MemoryKey, true); err != nil || !changed {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// keyChanged reports whether step sets key to a value different from the one
+// it had before. States only record the entries they change, so the previous
+// value comes from the state reconstructed at the step before, which costs at
+// most one snapshot interval.
+func keyChanged(t *ExecutionTrace, step int, key string, inMemory bool) (bool, error) {
+	hostState, memory, err := t.stepChanges(step)
+	if err != nil {
+		return false, err
+	}
+	entries := hostState
+	if inMemory {
+		entries = memory
+	}
+	value, ok := entries[key]
+	if !ok {
+		return false, nil
+	}
+	if step == 0 {
+		return true, nil
+	}
+
+	previous, err := t.ReconstructStateAt(step - 1)
+	if err != nil {
+		return false, err
+	}
+	entries = previous.HostState
+	if inMemory {
+		entries = previous.Memory
+	}
+	before, ok := entries[key]
+	return !ok || !reflect.DeepEqual(before, value), nil
+}
+
+// BreakpointSet is the list of breakpoints of a debugging session
+type BreakpointSet struct {
+	Breakpoints []Breakpoint `json:"breakpoints"`
+}
+
+// NewBreakpointSet creates an empty breakpoint set
+func NewBreakpointSet() *BreakpointSet {
+	return &BreakpointSet{Breakpoints: make([]Breakpoint, 0)}
+}
+
+// Add validates bp and adds it to the set with the next free ID
+func (s *BreakpointSet) Add(bp Breakpoint) (Breakpoint, error) {
+	if err := bp.validate(); err != nil {
+		return Breakpoint{}, err
+	}
+	bp.ID = 1
+	for _, existing := range s.Breakpoints {
+		bp.ID = max(bp.ID, existing.ID+1)
+	}
+	s.Breakpoints = append(s.Breakpoints, bp)
+	return bp, nil
+}
+
+// Remove deletes the breakpoint with the given ID
+func (s *BreakpointSet) Remove(id int) error {
+	for i, bp := range s.Breakpoints {
+		if bp.ID == id {
+			s.Breakpoints = append(s.Breakpoints[:i], s.Breakpoints[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("no breakpoint %d", id)
+}
+
+// SetEnabled enables or disables the breakpoint with the given ID
+func (s *BreakpointSet) SetEnabled(id int, enabled bool) error {
+	for i := range s.Breakpoints {
+		if s.Breakpoints[i].ID == id {
+			s.Breakpoints[i].Disabled = !enabled
+			return nil
+		}
+	}
+	return fmt.Errorf("no breakpoint %d", id)
+}
+
+// Find returns the breakpoint with the same conditions as bp, if any
+func (s *BreakpointSet) Find(bp Breakpoint) (Breakpoint, bool) {
+	for _, existing := range s.Breakpoints {
+		if existing.String() == bp.String() {
+			return existing, true
+		}
+	}
+	return Breakpoint{}, false
+}
+
+// Match returns the first enabled breakpoint matching the given step of t
+func (s *BreakpointSet) Match(t *ExecutionTrace, step int) (Breakpoint, bool, error) {
+	if s == nil {
+		return Breakpoint{}, false, nil
+	}
+	for _, bp := range s.Breakpoints {
+		if bp.Disabled {
+			continue
+		}
+		ok, err := bp.Matches(t, step)
+		if err != nil {
+			return Breakpoint{}, false, fmt.Errorf("breakpoint %d at step %d: %w", bp.ID, step, err)
+		}
+		if ok {
+			return bp, true, nil
+		}
+	}
+	return Breakpoint{}, false, nil
+}
+
+// Continue steps forward until a step matches one of the breakpoints, and
+// returns that breakpoint. Without a match it stops on the last step. If the
+// state of a step cannot be loaded, it stops on that step with the error.
+func (t *ExecutionTrace) Continue(breakpoints *BreakpointSet) (*ExecutionState, *Breakpoint, error) {
+	if t.CurrentStep >= len(t.States)-1 {
+		return nil, nil, fmt.Errorf("already at the last step")
+	}
+	return t.runTo(breakpoints, 1)
+}
+
+// ReverseContinue steps backward until a step matches one of the breakpoints,
+// and returns that breakpoint. Without a match it stops on the first step.
+func (t *ExecutionTrace) ReverseContinue(breakpoints *BreakpointSet) (*ExecutionState, *Breakpoint, error) {
+	if t.CurrentStep <= 0 {
+		return nil, nil, fmt.Errorf("already at the first step")
+	}
+	return t.runTo(breakpoints, -1)
+}
+
+func (t *ExecutionTrace) runTo(breakpoints *BreakpointSet, direction int) (*ExecutionState, *Breakpoint, error) {
+	for step := t.CurrentStep + direction; step >= 0 && step < len(t.States); step += direction {
+		t.CurrentStep = step
+		bp, ok, err := breakpoints.Match(t, step)
+		if err != nil {
+			return nil, nil, err
+		}
+		if ok {
+			return &t.States[step], &bp, nil
+		}
+	}
+	return &t.States[t.CurrentStep], nil, nil
+}
@@ -0,0 +1,157 @@
+// Copyright (c) 2026 dotandev
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseBreakpoint(t *testing.T) {
+	tests := []struct {
+		spec string
+		want Breakpoint
+	}{
+		{"transfer", Breakpoint{Function: "transfer"}},
+		{testContractID, Breakpoint{ContractID: testContractID}},
+		{"contract=CDLZ function=burn error", Breakpoint{ContractID: "CDLZ", Function: "burn", OnError: true}},
+		{"fn=mint host=balance memory=tmp", Breakpoint{Function: "mint", HostStateKey: "balance", MemoryKey: "tmp"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			bp, err := ParseBreakpoint(tt.spec)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, bp)
+
+			reparsed, err := ParseBreakpoint(bp.String())
+			require.NoError(t, err)
+			assert.Equal(t, bp, reparsed, "String should read back")
+		})
+	}
+
+	for _, spec := range []string{"", "   ", "host=", "line=12"} {
+		_, err := ParseBreakpoint(spec)
+		assert.Error(t, err, spec)
+	}
+}
+
+func TestBreakpoint_Matches(t *testing.T) {
+	trace := viewerTestTrace()
+	stops := func(bp Breakpoint) []int {
+		var steps []int
+		for step := range trace.States {
+			ok, err := bp.Matches(trace, step)
+			require.NoError(t, err)
+			if ok {
+				steps = append(steps, step)
+			}
+		}
+		return steps
+	}
+
+	assert.Equal(t, []int{0, 1, 3}, stops(Breakpoint{ContractID: "CDLZ"}))
+	assert.Equal(t, []int{1}, stops(Breakpoint{Function: "get_balance"}))
+	assert.Equal(t, []int{3}, stops(Breakpoint{OnError: true}))
+	assert.Empty(t, stops(Breakpoint{Function: "transfer", OnError: true}), "conditions are combined")
+
+	// Step 3 writes balance again, but with the value it already had
+	assert.Equal(t, []int{0, 2}, stops(Breakpoint{HostStateKey: "balance"}))
+	assert.Equal(t, []int{2}, stops(Breakpoint{HostStateKey: "nonce"}))
+	assert.Equal(t, []int{1}, stops(Breakpoint{MemoryKey: "account"}))
+	ok, err := Breakpoint{Function: "transfer"}.Matches(trace, 10)
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestBreakpointSet(t *testing.T) {
+	set := NewBreakpointSet()
+
+	first, err := set.Add(Breakpoint{Function: "transfer"})
+	require.NoError(t, err)
+	second, err := set.Add(Breakpoint{OnError: true})
+	require.NoError(t, err)
+	assert.Equal(t, 1, first.ID)
+	assert.Equal(t, 2, second.ID)
+
+	_, err = set.Add(Breakpoint{})
+	assert.Error(t, err)
+
+	found, ok := set.Find(Breakpoint{Function: "transfer"})
+	assert.True(t, ok)
+	assert.Equal(t, first, found)
+
+	require.NoError(t, set.Remove(1))
+	assert.Error(t, set.Remove(1))
+	third, err := set.Add(Breakpoint{MemoryKey: "tmp"})
+	require.NoError(t, err)
+	assert.Equal(t, 3, third.ID, "IDs are not reused while higher ones exist")
+
+	require.NoError(t, set.SetEnabled(2, false))
+	assert.Error(t, set.SetEnabled(9, true))
+	_, ok, err = set.Match(viewerTestTrace(), 3)
+	require.NoError(t, err)
+	assert.False(t, ok, "disabled breakpoints do not match")
+
+	data, err := json.Marshal(set)
+	require.NoError(t, err)
+	loaded := NewBreakpointSet()
+	require.NoError(t, json.Unmarshal(data, loaded))
+	assert.Equal(t, set, loaded)
+}
+
+func TestExecutionTrace_Continue(t *testing.T) {
+	trace := viewerTestTrace()
+	set := NewBreakpointSet()
+	_, err := set.Add(Breakpoint{HostStateKey: "balance"})
+	require.NoError(t, err)
+
+	state, bp, err := trace.Continue(set)
+	require.NoError(t, err)
+	require.NotNil(t, bp)
+	assert.Equal(t, 1, bp.ID)
+	assert.Equal(t, 2, trace.CurrentStep)
+	assert.Equal(t, "storage_write", state.Operation)
+
+	state, bp, err = trace.Continue(set)
+	require.NoError(t, err)
+	assert.Nil(t, bp, "no breakpoint after step 2")
+	assert.Equal(t, 3, trace.CurrentStep)
+	assert.Equal(t, "burn", state.Function)
+
+	_, _, err = trace.Continue(set)
+	assert.Error(t, err)
+
+	_, bp, err = trace.ReverseContinue(set)
+	require.NoError(t, err)
+	require.NotNil(t, bp)
+	assert.Equal(t, 2, trace.CurrentStep)
+
+	_, bp, err = trace.ReverseContinue(set)
+	require.NoError(t, err)
+	require.NotNil(t, bp)
+	assert.Equal(t, 0, trace.CurrentStep)
+
+	_, _, err = trace.ReverseContinue(set)
+	assert.Error(t, err)
+
+	_, bp, err = trace.Continue(nil)
+	require.NoError(t, err)
+	assert.Nil(t, bp)
+	assert.Equal(t, 3, trace.CurrentStep, "without breakpoints continue runs to the end")
+}
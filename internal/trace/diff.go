@@ -0,0 +1,425 @@
+// Copyright (c) 2026 dotandev
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// maxAlignCells bounds the table used to align two lists of calls. Longer
+// lists that differ in the middle are aligned position by position instead.
+const maxAlignCells = 1 << 22
+
+// DiffKind tells how a call or a host state entry differs between two
+// executions
+type DiffKind string
+
+const (
+	// DiffSame is a call found in both executions with the same fields
+	DiffSame DiffKind = "same"
+	// DiffChanged is a call found in both executions whose fields differ
+	DiffChanged DiffKind = "changed"
+	// DiffAdded is only found in the right execution
+	DiffAdded DiffKind = "added"
+	// DiffRemoved is only found in the left execution
+	DiffRemoved DiffKind = "removed"
+)
+
+// DiffCall is a call, or any other step, of one of the executions
+type DiffCall struct {
+	// Step is the step of an ExecutionTrace, -1 for the nodes of a TraceNode tree
+	Step        int      `json:"step"`
+	Type        string   `json:"type"`
+	ContractID  string   `json:"contract_id,omitempty"`
+	Function    string   `json:"function,omitempty"`
+	Args        []string `json:"args,omitempty"`
+	ReturnValue string   `json:"return_value,omitempty"`
+	Error       string   `json:"error,omitempty"`
+	Data        string   `json:"data,omitempty"`
+	Failed      bool     `json:"failed,omitempty"`
+}
+
+// FieldDiff is a field of a call that differs between the executions, e.g.
+// "args[1]" or "return"
+type FieldDiff struct {
+	Field string `json:"field"`
+	Left  string `json:"left"`
+	Right string `json:"right"`
+}
+
+// StateDelta is a host state entry that differs between the executions
+type StateDelta struct {
+	Key   string      `json:"key"`
+	Kind  DiffKind    `json:"kind"`
+	Left  interface{} `json:"left,omitempty"`
+	Right interface{} `json:"right,omitempty"`
+}
+
+// CallDiff pairs a call of the left execution with the call of the right one
+// it was aligned with. Left is nil for added calls, Right for removed ones.
+type CallDiff struct {
+	Kind   DiffKind    `json:"kind"`
+	Left   *DiffCall   `json:"left,omitempty"`
+	Right  *DiffCall   `json:"right,omitempty"`
+	Fields []FieldDiff `json:"fields,omitempty"`
+	// HostState compares the host state entries each side wrote at this step
+	HostState []StateDelta `json:"host_state,omitempty"`
+	Children  []*CallDiff  `json:"children,omitempty"`
+}
+
+// TraceDiff is the structural diff of two executions
+type TraceDiff struct {
+	// Left and Right name the executions, e.g. the trace files they came from
+	Left  string      `json:"left"`
+	Right string      `json:"right"`
+	Calls []*CallDiff `json:"calls"`
+	// FirstDivergence is the first call, in execution order, that differs
+	FirstDivergence *CallDiff `json:"first_divergence,omitempty"`
+	// HostState compares the host state at the end of each execution
+	HostState []StateDelta `json:"host_state,omitempty"`
+}
+
+// Equal reports whether the executions made the same calls and ended with the
+// same host state
+func (d *TraceDiff) Equal() bool {
+	return d.FirstDivergence == nil && len(d.HostState) == 0
+}
+
+// ToJSON serializes the diff
+func (d *TraceDiff) ToJSON() ([]byte, error) {
+	return json.MarshalIndent(d, "", "  ")
+}
+
+// Differs reports whether the call, or any call nested in it, differs
+func (c *CallDiff) Differs() bool {
+	if c.Kind != DiffSame {
+		return true
+	}
+	for _, child := range c.Children {
+		if child.Differs() {
+			return true
+		}
+	}
+	return false
+}
+
+// DiffTrees compares the calls nested in two trace trees, such as the ones
+// built by ParseSimulationResponse. The roots themselves are not compared.
+func DiffTrees(left, right *TraceNode) *TraceDiff {
+	d := &differ{}
+	diff := &TraceDiff{Calls: d.diffChildren(left, right)}
+	diff.FirstDivergence = firstDivergence(diff.Calls)
+	return diff
+}
+
+// DiffTraces compares two execution traces. Steps are nested by call depth,
+// as in the viewer, and aligned call by call; the host state written by
+// aligned steps and the host state at the end are compared too.
+func DiffTraces(left, right *ExecutionTrace) (*TraceDiff, error) {
+	leftRoot, leftNodes := buildStepTree(left)
+	rightRoot, rightNodes := buildStepTree(right)
+	d := &differ{
+		left:  diffSide{trace: left, steps: stepIndex(leftNodes)},
+		right: diffSide{trace: right, steps: stepIndex(rightNodes)},
+	}
+
+	diff := &TraceDiff{Calls: d.diffChildren(leftRoot, rightRoot)}
+	if d.err != nil {
+		return nil, d.err
+	}
+	diff.FirstDivergence = firstDivergence(diff.Calls)
+
+	leftState, err := finalHostState(left)
+	if err != nil {
+		return nil, err
+	}
+	rightState, err := finalHostState(right)
+	if err != nil {
+		return nil, err
+	}
+	diff.HostState = stateDeltas(leftState, rightState)
+	return diff, nil
+}
+
+// diffSide is one of the executions compared by a differ. trace is nil when
+// comparing trees.
+type diffSide struct {
+	trace *ExecutionTrace
+	steps map[*TraceNode]int
+}
+
+func (s diffSide) step(node *TraceNode) int {
+	if step, ok := s.steps[node]; ok {
+		return step
+	}
+	return -1
+}
+
+type differ struct {
+	left, right diffSide
+	err         error
+}
+
+// diffChildren aligns the children of two nodes. Children with the same type,
+// contract and function are paired along their longest common subsequence,
+// the others are added or removed.
+func (d *differ) diffChildren(left, right *TraceNode) []*CallDiff {
+	var a, b []*TraceNode
+	if left != nil {
+		a = left.Children
+	}
+	if right != nil {
+		b = right.Children
+	}
+
+	var diffs []*CallDiff
+	for _, pair := range alignNodes(a, b) {
+		switch {
+		case pair[0] < 0:
+			diffs = append(diffs, d.added(b[pair[1]]))
+		case pair[1] < 0:
+			diffs = append(diffs, d.removed(a[pair[0]]))
+		default:
+			diffs = append(diffs, d.paired(a[pair[0]], b[pair[1]]))
+		}
+	}
+	return diffs
+}
+
+func (d *differ) paired(left, right *TraceNode) *CallDiff {
+	diff := &CallDiff{
+		Kind:  DiffSame,
+		Left:  newDiffCall(left, d.left.step(left)),
+		Right: newDiffCall(right, d.right.step(right)),
+	}
+	diff.Fields = diffFields(diff.Left, diff.Right)
+	diff.HostState = d.hostStateDeltas(diff.Left.Step, diff.Right.Step)
+	if len(diff.Fields) > 0 || len(diff.HostState) > 0 {
+		diff.Kind = DiffChanged
+	}
+	diff.Children = d.diffChildren(left, right)
+	return diff
+}
+
+func (d *differ) added(node *TraceNode) *CallDiff {
+	diff := &CallDiff{Kind: DiffAdded, Right: newDiffCall(node, d.right.step(node))}
+	for _, child := range node.Children {
+		diff.Children = append(diff.Children, d.added(child))
+	}
+	return diff
+}
+
+func (d *differ) removed(node *TraceNode) *CallDiff {
+	diff := &CallDiff{Kind: DiffRemoved, Left: newDiffCall(node, d.left.step(node))}
+	for _, child := range node.Children {
+		diff.Children = append(diff.Children, d.removed(child))
+	}
+	return diff
+}
+
+// hostStateDeltas compares the host state entries written at two aligned steps
+func (d *differ) hostStateDeltas(leftStep, rightStep int) []StateDelta {
+	if d.err != nil || d.left.trace == nil || d.right.trace == nil || leftStep < 0 || rightStep < 0 {
+		return nil
+	}
+	leftState, _, err := d.left.trace.stepChanges(leftStep)
+	if err != nil {
+		d.err = err
+		return nil
+	}
+	rightState, _, err := d.right.trace.stepChanges(rightStep)
+	if err != nil {
+		d.err = err
+		return nil
+	}
+	return stateDeltas(leftState, rightState)
+}
+
+func newDiffCall(node *TraceNode, step int) *DiffCall {
+	return &DiffCall{
+		Step:        step,
+		Type:        node.Type,
+		ContractID:  node.ContractID,
+		Function:    node.Function,
+		Args:        node.Args,
+		ReturnValue: node.ReturnValue,
+		Error:       node.Error,
+		Data:        node.EventData,
+		Failed:      node.Failed,
+	}
+}
+
+// diffFields lists the fields that differ between two aligned calls
+func diffFields(left, right *DiffCall) []FieldDiff {
+	var fields []FieldDiff
+	compare := func(field, l, r string) {
+		if l != r {
+			fields = append(fields, FieldDiff{Field: field, Left: l, Right: r})
+		}
+	}
+
+	compare("contract", left.ContractID, right.ContractID)
+	compare("function", left.Function, right.Function)
+	for i := 0; i < max(len(left.Args), len(right.Args)); i++ {
+		var l, r string
+		if i < len(left.Args) {
+			l = left.Args[i]
+		}
+		if i < len(right.Args) {
+			r = right.Args[i]
+		}
+		compare(fmt.Sprintf("args[%d]", i), l, r)
+	}
+	compare("return", left.ReturnValue, right.ReturnValue)
+	compare("error", left.Error, right.Error)
+	compare("data", left.Data, right.Data)
+	compare("failed", strconv.FormatBool(left.Failed), strconv.FormatBool(right.Failed))
+	return fields
+}
+
+// stateDeltas lists the entries that differ between two host states
+func stateDeltas(left, right map[string]interface{}) []StateDelta {
+	var deltas []StateDelta
+	for _, c := range diffStates(left, right) {
+		switch c.Kind {
+		case added:
+			deltas = append(deltas, StateDelta{Key: c.Key, Kind: DiffAdded, Right: c.After})
+		case removed:
+			deltas = append(deltas, StateDelta{Key: c.Key, Kind: DiffRemoved, Left: c.Before})
+		case changed:
+			deltas = append(deltas, StateDelta{Key: c.Key, Kind: DiffChanged, Left: c.Before, Right: c.After})
+		}
+	}
+	return deltas
+}
+
+func finalHostState(t *ExecutionTrace) (map[string]interface{}, error) {
+	if len(t.States) == 0 {
+		return nil, nil
+	}
+	state, err := t.ReconstructStateAt(len(t.States) - 1)
+	if err != nil {
+		return nil, err
+	}
+	return state.HostState, nil
+}
+
+// firstDivergence returns the first call, in execution order, that differs
+func firstDivergence(calls []*CallDiff) *CallDiff {
+	for _, call := range calls {
+		if call.Kind != DiffSame {
+			return call
+		}
+		if child := firstDivergence(call.Children); child != nil {
+			return child
+		}
+	}
+	return nil
+}
+
+func stepIndex(nodes []*TraceNode) map[*TraceNode]int {
+	steps := make(map[*TraceNode]int, len(nodes))
+	for step, node := range nodes {
+		steps[node] = step
+	}
+	return steps
+}
+
+// alignKey identifies the calls that can be paired
+func alignKey(node *TraceNode) string {
+	return node.Type + "\x00" + node.ContractID + "\x00" + node.Function
+}
+
+// alignNodes pairs the indexes of a and b. A pair with -1 on one side is a node
+// of the other side only.
+func alignNodes(a, b []*TraceNode) [][2]int {
+	var pairs [][2]int
+
+	// Executions mostly agree: pair the common prefix and suffix directly and
+	// only align what lies between them
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && alignKey(a[prefix]) == alignKey(b[prefix]) {
+		pairs = append(pairs, [2]int{prefix, prefix})
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix &&
+		alignKey(a[len(a)-1-suffix]) == alignKey(b[len(b)-1-suffix]) {
+		suffix++
+	}
+
+	pairs = append(pairs, alignMiddle(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix], prefix)...)
+
+	for i := suffix; i > 0; i-- {
+		pairs = append(pairs, [2]int{len(a) - i, len(b) - i})
+	}
+	return pairs
+}
+
+// alignMiddle aligns a and b, whose indexes start at offset, along their
+// longest common subsequence of keys
+func alignMiddle(a, b []*TraceNode, offset int) [][2]int {
+	n, m := len(a), len(b)
+	var pairs [][2]int
+	if n*m > maxAlignCells {
+		for i := 0; i < max(n, m); i++ {
+			switch {
+			case i >= n:
+				pairs = append(pairs, [2]int{-1, offset + i})
+			case i >= m:
+				pairs = append(pairs, [2]int{offset + i, -1})
+			case alignKey(a[i]) == alignKey(b[i]):
+				pairs = append(pairs, [2]int{offset + i, offset + i})
+			default:
+				pairs = append(pairs, [2]int{offset + i, -1}, [2]int{-1, offset + i})
+			}
+		}
+		return pairs
+	}
+
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:]
+	lcs := make([][]int, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if alignKey(a[i]) == alignKey(b[j]) {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < n || j < m {
+		switch {
+		case i < n && j < m && alignKey(a[i]) == alignKey(b[j]):
+			pairs = append(pairs, [2]int{offset + i, offset + j})
+			i++
+			j++
+		case j == m || (i < n && lcs[i+1][j] >= lcs[i][j+1]):
+			pairs = append(pairs, [2]int{offset + i, -1})
+			i++
+		default:
+			pairs = append(pairs, [2]int{-1, offset + j})
+			j++
+		}
+	}
+	return pairs
+}
@@ -0,0 +1,203 @@
+// Copyright (c) 2026 dotandev
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Render formats the diff for a terminal:
+//
+//	First divergence  burn(10) CDLZ…CYSC  step 4 ↔ 4
+//	    args[0]: 10 → 20
+//
+//	Calls
+//	    transfer(alice, 100) CDLZ…CYSC
+//	  ~   burn(10) CDLZ…CYSC  step 4 ↔ 4
+//	  -   mint(5) CDLZ…CYSC  step 5
+//	  +   approve() CDLZ…CYSC  step 5
+//	    ⋯ 12 identical call(s)
+//
+// Calls that are the same on both sides are dimmed, and runs of them away
+// from any difference are folded.
+func (d *TraceDiff) Render() string {
+	lines := []string{titleStyle.Render("erst trace diff") + "  " + d.Left + dimStyle.Render(" ↔ ") + d.Right, ""}
+
+	if d.Equal() {
+		lines = append(lines, addedStyle.Render("No differences"))
+		return strings.Join(lines, "\n") + "\n"
+	}
+
+	if first := d.FirstDivergence; first != nil {
+		lines = append(lines, titleStyle.Render("First divergence")+"  "+callText(first)+stepText(first))
+		lines = append(lines, detailLines(first, 2)...)
+		lines = append(lines, "")
+	}
+
+	lines = append(lines, titleStyle.Render("Calls"))
+	lines = appendCallLines(lines, d.Calls, 0)
+
+	if len(d.HostState) > 0 {
+		lines = append(lines, "", titleStyle.Render("Host state at the end"))
+		for _, delta := range d.HostState {
+			lines = append(lines, "  "+deltaLine(delta, ""))
+		}
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func appendCallLines(lines []string, calls []*CallDiff, depth int) []string {
+	differs := make([]bool, len(calls))
+	for i, call := range calls {
+		differs[i] = call.Differs()
+	}
+	indent := strings.Repeat("  ", depth)
+
+	var folded []*CallDiff
+	fold := func() {
+		if len(folded) == 1 {
+			lines = append(lines, sameLine(folded[0], indent))
+		} else if len(folded) > 1 {
+			n := 0
+			for _, call := range folded {
+				n += countCalls(call)
+			}
+			lines = append(lines, dimStyle.Render(fmt.Sprintf("    %s⋯ %d identical call(s)", indent, n)))
+		}
+		folded = folded[:0]
+	}
+
+	for i, call := range calls {
+		// Keep one identical call on each side of a difference for context
+		near := differs[i] || (i > 0 && differs[i-1]) || (i+1 < len(calls) && differs[i+1])
+		if !near {
+			folded = append(folded, call)
+			continue
+		}
+		fold()
+
+		text := callText(call) + stepText(call)
+		switch call.Kind {
+		case DiffAdded:
+			lines = append(lines, addedStyle.Render("  + ")+indent+addedStyle.Render(text))
+		case DiffRemoved:
+			lines = append(lines, removedStyle.Render("  - ")+indent+removedStyle.Render(text))
+		case DiffChanged:
+			lines = append(lines, changedStyle.Render("  ~ ")+indent+changedStyle.Render(text))
+			lines = append(lines, detailLines(call, depth+3)...)
+		default:
+			if !differs[i] {
+				lines = append(lines, sameLine(call, indent))
+				continue
+			}
+			lines = append(lines, "    "+indent+text)
+		}
+		lines = appendCallLines(lines, call.Children, depth+1)
+	}
+	fold()
+	return lines
+}
+
+// sameLine renders a call that is the same on both sides, with the number of
+// calls nested in it
+func sameLine(call *CallDiff, indent string) string {
+	text := callText(call) + stepText(call)
+	if nested := countCalls(call) - 1; nested > 0 {
+		text += fmt.Sprintf(" (+%d nested)", nested)
+	}
+	return dimStyle.Render("    " + indent + text)
+}
+
+// detailLines lists the fields and host state entries of a changed call
+func detailLines(call *CallDiff, depth int) []string {
+	indent := strings.Repeat("  ", depth)
+	var lines []string
+	for _, field := range call.Fields {
+		lines = append(lines, indent+changedStyle.Render(fmt.Sprintf("%s: %s → %s", field.Field, orNone(field.Left), orNone(field.Right))))
+	}
+	for _, delta := range call.HostState {
+		lines = append(lines, indent+deltaLine(delta, "host "))
+	}
+	return lines
+}
+
+func deltaLine(delta StateDelta, prefix string) string {
+	switch delta.Kind {
+	case DiffAdded:
+		return addedStyle.Render(fmt.Sprintf("+ %s%s: %v", prefix, delta.Key, delta.Right))
+	case DiffRemoved:
+		return removedStyle.Render(fmt.Sprintf("- %s%s: %v", prefix, delta.Key, delta.Left))
+	default:
+		return changedStyle.Render(fmt.Sprintf("~ %s%s: %v → %v", prefix, delta.Key, delta.Left, delta.Right))
+	}
+}
+
+// callText renders a call as "function(args) → return CDLZ…CYSC: error", from
+// the left side unless the call was added
+func callText(diff *CallDiff) string {
+	call := diff.Left
+	if call == nil {
+		call = diff.Right
+	}
+
+	var sb strings.Builder
+	switch {
+	case call.Function != "":
+		sb.WriteString(call.Function + "(" + strings.Join(call.Args, ", ") + ")")
+		if call.ReturnValue != "" {
+			sb.WriteString(" → " + call.ReturnValue)
+		}
+	case call.Data != "":
+		sb.WriteString(call.Data)
+	default:
+		sb.WriteString(call.Type)
+	}
+	if call.ContractID != "" {
+		sb.WriteString(" " + shortContractID(call.ContractID))
+	}
+	if call.Error != "" {
+		sb.WriteString(": " + call.Error)
+	}
+	return sb.String()
+}
+
+// stepText renders the steps of a call of an ExecutionTrace, "  step 4 ↔ 5"
+func stepText(diff *CallDiff) string {
+	switch {
+	case diff.Left != nil && diff.Right != nil && diff.Left.Step >= 0:
+		return fmt.Sprintf("  step %d ↔ %d", diff.Left.Step, diff.Right.Step)
+	case diff.Left != nil && diff.Left.Step >= 0:
+		return fmt.Sprintf("  step %d", diff.Left.Step)
+	case diff.Right != nil && diff.Right.Step >= 0:
+		return fmt.Sprintf("  step %d", diff.Right.Step)
+	}
+	return ""
+}
+
+func countCalls(call *CallDiff) int {
+	n := 1
+	for _, child := range call.Children {
+		n += countCalls(child)
+	}
+	return n
+}
+
+func orNone(value string) string {
+	if value == "" {
+		return "(none)"
+	}
+	return value
+}
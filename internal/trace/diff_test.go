@@ -0,0 +1,196 @@
+// Copyright (c) 2026 dotandev
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/dotandev/hintents/internal/diagnostic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// diffTestTrace builds a transfer that reads and writes a balance
+func diffTestTrace(amount int, states ...ExecutionState) *ExecutionTrace {
+	trace := NewExecutionTrace("diff-tx", 2)
+	trace.AddState(ExecutionState{Operation: "contract_call", ContractID: testContractID, Function: "transfer", Arguments: []interface{}{"alice", amount}})
+	trace.AddState(ExecutionState{Operation: "contract_call", Depth: 1, ContractID: testContractID, Function: "get_balance", ReturnValue: 500})
+	for _, state := range states {
+		trace.AddState(state)
+	}
+	trace.AddState(ExecutionState{Operation: "storage_write", Depth: 1, HostState: map[string]interface{}{"balance": 500 - amount}})
+	return trace
+}
+
+func TestDiffTraces_Equal(t *testing.T) {
+	diff, err := DiffTraces(diffTestTrace(100), diffTestTrace(100))
+	require.NoError(t, err)
+
+	assert.True(t, diff.Equal())
+	assert.Nil(t, diff.FirstDivergence)
+	require.Len(t, diff.Calls, 1)
+	assert.Equal(t, DiffSame, diff.Calls[0].Kind)
+	assert.False(t, diff.Calls[0].Differs())
+	assert.Contains(t, diff.Render(), "No differences")
+}
+
+func TestDiffTraces_ChangedArgument(t *testing.T) {
+	diff, err := DiffTraces(diffTestTrace(100), diffTestTrace(150))
+	require.NoError(t, err)
+
+	assert.False(t, diff.Equal())
+	first := diff.FirstDivergence
+	require.NotNil(t, first)
+	assert.Equal(t, DiffChanged, first.Kind)
+	assert.Equal(t, "transfer", first.Left.Function)
+	assert.Equal(t, 0, first.Left.Step)
+	assert.Equal(t, []FieldDiff{{Field: "args[1]", Left: "100", Right: "150"}}, first.Fields)
+
+	// The write of each side is aligned, and differs in the value written
+	write := diff.Calls[0].Children[1]
+	assert.Equal(t, DiffChanged, write.Kind)
+	assert.Equal(t, []StateDelta{{Key: "balance", Kind: DiffChanged, Left: 400, Right: 350}}, write.HostState)
+	assert.Equal(t, []StateDelta{{Key: "balance", Kind: DiffChanged, Left: 400, Right: 350}}, diff.HostState)
+}
+
+func TestDiffTraces_InsertedCall(t *testing.T) {
+	burn := ExecutionState{Operation: "contract_call", Depth: 1, ContractID: testContractID, Function: "burn", Error: "insufficient balance"}
+	diff, err := DiffTraces(diffTestTrace(100), diffTestTrace(100, burn))
+	require.NoError(t, err)
+
+	children := diff.Calls[0].Children
+	require.Len(t, children, 3)
+	assert.Equal(t, DiffSame, children[0].Kind)
+	assert.Equal(t, DiffAdded, children[1].Kind)
+	assert.Nil(t, children[1].Left)
+	assert.Equal(t, 2, children[1].Right.Step)
+	// The write after the inserted call is still paired with its counterpart
+	assert.Equal(t, DiffSame, children[2].Kind)
+	assert.Equal(t, 2, children[2].Left.Step)
+	assert.Equal(t, 3, children[2].Right.Step)
+
+	assert.Same(t, children[1], diff.FirstDivergence)
+	assert.Empty(t, diff.HostState)
+
+	out := diff.Render()
+	assert.Contains(t, out, "+ ")
+	assert.Contains(t, out, "burn() CDLZ…CYSC: insufficient balance")
+}
+
+func TestDiffTraces_RemovedCall(t *testing.T) {
+	extra := ExecutionState{Operation: "contract_call", Depth: 1, ContractID: testContractID, Function: "log"}
+	diff, err := DiffTraces(diffTestTrace(100, extra), diffTestTrace(100))
+	require.NoError(t, err)
+
+	require.NotNil(t, diff.FirstDivergence)
+	assert.Equal(t, DiffRemoved, diff.FirstDivergence.Kind)
+	assert.Equal(t, "log", diff.FirstDivergence.Left.Function)
+	assert.Nil(t, diff.FirstDivergence.Right)
+}
+
+func TestDiffTraces_FoldsIdenticalCalls(t *testing.T) {
+	build := func(last string) *ExecutionTrace {
+		trace := NewExecutionTrace("fold-tx", 10)
+		for i := 0; i < 20; i++ {
+			trace.AddState(ExecutionState{Operation: "contract_call", Function: fmt.Sprintf("step%d", i)})
+		}
+		trace.AddState(ExecutionState{Operation: "contract_call", Function: "finish", ReturnValue: last})
+		return trace
+	}
+	diff, err := DiffTraces(build("ok"), build("failed"))
+	require.NoError(t, err)
+
+	out := diff.Render()
+	assert.Contains(t, out, "⋯ 19 identical call(s)")
+	assert.Contains(t, out, "step19()")
+	assert.NotContains(t, out, "step18()")
+	assert.Contains(t, out, "return: ok → failed")
+}
+
+func TestDiffTrees_SimulationResponses(t *testing.T) {
+	response := func(amount uint32, ret uint32) *SimulationResponse {
+		return &SimulationResponse{
+			Status: "success",
+			Events: []diagnostic.Event{
+				fnCallEvent("", testContractID, "transfer", u32(amount)),
+				fnCallEvent(testContractID, testContractID, "get_balance"),
+				fnReturnEvent(testContractID, "get_balance", u32(500)),
+				fnReturnEvent(testContractID, "transfer", u32(ret)),
+			},
+		}
+	}
+	left, err := ParseSimulationResponse(response(100, 1))
+	require.NoError(t, err)
+	right, err := ParseSimulationResponse(response(100, 0))
+	require.NoError(t, err)
+
+	diff := DiffTrees(left, right)
+
+	require.NotNil(t, diff.FirstDivergence)
+	assert.Equal(t, "transfer", diff.FirstDivergence.Left.Function)
+	assert.Equal(t, -1, diff.FirstDivergence.Left.Step)
+	assert.Equal(t, []FieldDiff{{Field: "return", Left: "1", Right: "0"}}, diff.FirstDivergence.Fields)
+	assert.Equal(t, DiffSame, diff.Calls[0].Children[0].Kind)
+
+	same := DiffTrees(left, left)
+	assert.True(t, same.Equal())
+}
+
+func TestTraceDiff_ToJSON(t *testing.T) {
+	diff, err := DiffTraces(diffTestTrace(100), diffTestTrace(150))
+	require.NoError(t, err)
+	diff.Left, diff.Right = "a.json", "b.json"
+
+	data, err := diff.ToJSON()
+	require.NoError(t, err)
+
+	var decoded map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, "a.json", decoded["left"])
+	first := decoded["first_divergence"].(map[string]interface{})
+	assert.Equal(t, "changed", first["kind"])
+	assert.Equal(t, "args[1]", first["fields"].([]interface{})[0].(map[string]interface{})["field"])
+}
+
+func TestAlignNodes(t *testing.T) {
+	nodes := func(names ...string) []*TraceNode {
+		var out []*TraceNode
+		for _, name := range names {
+			node := NewTraceNode(name, "contract_call")
+			node.Function = name
+			out = append(out, node)
+		}
+		return out
+	}
+
+	pairs := alignNodes(nodes("a", "b", "c", "d"), nodes("a", "x", "c", "d", "e"))
+	assert.Equal(t, [][2]int{{0, 0}, {1, -1}, {-1, 1}, {2, 2}, {3, 3}, {-1, 4}}, pairs)
+
+	assert.Empty(t, alignNodes(nil, nil))
+	assert.Equal(t, [][2]int{{-1, 0}}, alignNodes(nil, nodes("a")))
+}
+
+func TestDiffTraces_LazyStream(t *testing.T) {
+	loaded, err := OpenTrace(writeStreamFile(t, diffTestTrace(100), 2))
+	require.NoError(t, err)
+	defer loaded.Close()
+
+	diff, err := DiffTraces(loaded, diffTestTrace(150))
+	require.NoError(t, err)
+	// Values read back from the file are JSON numbers
+	assert.Equal(t, []StateDelta{{Key: "balance", Kind: DiffChanged, Left: float64(400), Right: 350}}, diff.HostState)
+}
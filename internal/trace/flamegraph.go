@@ -0,0 +1,191 @@
+// Copyright (c) 2026 dotandev
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/dotandev/hintents/internal/diagnostic"
+)
+
+// Weight is the cost flamegraph frames are sized by
+type Weight string
+
+const (
+	// WeightCPU sizes frames by the CPU instructions metered while they ran
+	WeightCPU Weight = "cpu"
+	// WeightMemory sizes frames by the memory bytes metered while they ran
+	WeightMemory Weight = "memory"
+	// WeightSteps gives every step a weight of one
+	WeightSteps Weight = "steps"
+)
+
+// Unit is the unit the weight is counted in
+func (w Weight) Unit() string {
+	switch w {
+	case WeightCPU:
+		return "instructions"
+	case WeightMemory:
+		return "bytes"
+	}
+	return "steps"
+}
+
+// FoldedStacks is a profile in the collapsed-stack ("folded") format: every
+// stack of frames, outermost first and separated by ";", with the weight spent
+// in its innermost frame
+type FoldedStacks struct {
+	Weight Weight
+	Stacks map[string]uint64
+}
+
+// FoldStacks profiles the calls of a trace. Steps are nested by call depth, as
+// in the viewer; every step with a function is a frame, and steps without one,
+// such as storage accesses and returns, count towards the frame they ran in. A step costs
+// the resources metered until the next step.
+//
+// An empty weight picks WeightCPU when the trace recorded CPU instructions and
+// WeightSteps otherwise.
+func FoldStacks(t *ExecutionTrace, weight Weight) (*FoldedStacks, error) {
+	cpu := func(s *ExecutionState) uint64 { return s.CPUInstructions }
+	mem := func(s *ExecutionState) uint64 { return s.MemoryBytes }
+
+	var clock []uint64
+	var recorded bool
+	switch weight {
+	case "":
+		if clock, recorded = meterClock(t, cpu); recorded {
+			weight = WeightCPU
+		} else {
+			weight, clock = WeightSteps, stepClock(t)
+		}
+	case WeightCPU:
+		if clock, recorded = meterClock(t, cpu); !recorded {
+			return nil, fmt.Errorf("trace has no CPU instruction counts, use the %s weight", WeightSteps)
+		}
+	case WeightMemory:
+		if clock, recorded = meterClock(t, mem); !recorded {
+			return nil, fmt.Errorf("trace has no memory byte counts, use the %s weight", WeightSteps)
+		}
+	case WeightSteps:
+		clock = stepClock(t)
+	default:
+		return nil, fmt.Errorf("unknown weight %q, use %s, %s or %s", weight, WeightCPU, WeightMemory, WeightSteps)
+	}
+
+	folded := &FoldedStacks{Weight: weight, Stacks: make(map[string]uint64)}
+	root, nodes := buildStepTree(t)
+	steps := stepIndex(nodes)
+
+	var visit func(node *TraceNode, stack []string)
+	visit = func(node *TraceNode, stack []string) {
+		if step, ok := steps[node]; ok {
+			state := &t.States[step]
+			if len(stack) == 0 || (state.Function != "" && state.Operation != string(diagnostic.KindFnReturn)) {
+				stack = append(stack[:len(stack):len(stack)], frameName(state))
+			}
+			if cost := clock[step+1] - clock[step]; cost > 0 {
+				folded.Stacks[strings.Join(stack, ";")] += cost
+			}
+		}
+		for _, child := range node.Children {
+			visit(child, stack)
+		}
+	}
+	visit(root, nil)
+	return folded, nil
+}
+
+// frameName names the frame of a step "function [CDLZ…CYSC]"
+func frameName(state *ExecutionState) string {
+	name := state.Function
+	if name == "" {
+		name = state.Operation
+	}
+	if state.ContractID != "" {
+		name += " [" + shortContractID(state.ContractID) + "]"
+	}
+	return strings.ReplaceAll(name, ";", ":")
+}
+
+// Total is the weight of the whole profile
+func (f *FoldedStacks) Total() uint64 {
+	var total uint64
+	for _, weight := range f.Stacks {
+		total += weight
+	}
+	return total
+}
+
+// Write writes one "stack weight" line per stack, sorted by stack, as read by
+// flamegraph.pl and most profilers
+func (f *FoldedStacks) Write(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	for _, stack := range sortedKeys(f.Stacks) {
+		if _, err := fmt.Fprintf(bw, "%s %d\n", stack, f.Stacks[stack]); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// FoldedDiff compares two profiles stack by stack
+type FoldedDiff struct {
+	Weight Weight
+	// Stacks holds the weight of each stack in the base and the current
+	// profile, 0 where it is missing
+	Stacks map[string][2]uint64
+}
+
+// DiffStacks compares a profile with the base it changed from
+func DiffStacks(base, current *FoldedStacks) (*FoldedDiff, error) {
+	if base.Weight != current.Weight {
+		return nil, fmt.Errorf("cannot compare a %s profile with a %s one", base.Weight, current.Weight)
+	}
+	diff := &FoldedDiff{Weight: current.Weight, Stacks: make(map[string][2]uint64)}
+	for stack, weight := range base.Stacks {
+		diff.Stacks[stack] = [2]uint64{weight, 0}
+	}
+	for stack, weight := range current.Stacks {
+		diff.Stacks[stack] = [2]uint64{diff.Stacks[stack][0], weight}
+	}
+	return diff, nil
+}
+
+// Write writes one "stack base current" line per stack, the input of
+// difffolded.pl style differential flamegraphs
+func (d *FoldedDiff) Write(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	for _, stack := range sortedKeys(d.Stacks) {
+		weights := d.Stacks[stack]
+		if _, err := fmt.Fprintf(bw, "%s %d %d\n", stack, weights[0], weights[1]); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
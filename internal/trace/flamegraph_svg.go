@@ -0,0 +1,223 @@
+// Copyright (c) 2026 dotandev
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace
+
+import (
+	"bufio"
+	"fmt"
+	"hash/fnv"
+	"html"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Layout of the rendered flamegraphs, in pixels
+const (
+	flameWidth       = 1200
+	flameFrameHeight = 16
+	flamePadSide     = 10
+	flamePadTop      = 40
+	flamePadBottom   = 10
+	flameCharWidth   = 7
+	// flameMinWidth hides frames too narrow to see
+	flameMinWidth = 0.1
+)
+
+// flameFrame is a frame of a flamegraph with the weight of every stack through
+// it, in the base and the current profile
+type flameFrame struct {
+	name     string
+	base     uint64
+	value    uint64
+	children []*flameFrame
+	byName   map[string]*flameFrame
+}
+
+func (f *flameFrame) child(name string) *flameFrame {
+	if c, ok := f.byName[name]; ok {
+		return c
+	}
+	c := &flameFrame{name: name, byName: make(map[string]*flameFrame)}
+	f.byName[name] = c
+	f.children = append(f.children, c)
+	return c
+}
+
+// buildFlameTree merges stacks into frames under an "all" root, with children
+// sorted by name as flamegraph.pl does
+func buildFlameTree(stacks map[string][2]uint64) *flameFrame {
+	root := &flameFrame{name: "all", byName: make(map[string]*flameFrame)}
+	for stack, weights := range stacks {
+		frame := root
+		frame.base += weights[0]
+		frame.value += weights[1]
+		for _, name := range strings.Split(stack, ";") {
+			frame = frame.child(name)
+			frame.base += weights[0]
+			frame.value += weights[1]
+		}
+	}
+
+	var sortFrames func(*flameFrame)
+	sortFrames = func(f *flameFrame) {
+		sort.Slice(f.children, func(i, j int) bool { return f.children[i].name < f.children[j].name })
+		for _, c := range f.children {
+			sortFrames(c)
+		}
+	}
+	sortFrames(root)
+	return root
+}
+
+// WriteSVG renders the profile as an SVG flamegraph. Every frame is as wide as
+// the weight spent in it and in the frames it called.
+func (f *FoldedStacks) WriteSVG(w io.Writer, title string) error {
+	stacks := make(map[string][2]uint64, len(f.Stacks))
+	for stack, weight := range f.Stacks {
+		stacks[stack] = [2]uint64{0, weight}
+	}
+	return writeFlamegraph(w, title, f.Weight, buildFlameTree(stacks), false)
+}
+
+// WriteSVG renders the comparison as a differential flamegraph: frames are
+// sized by the current profile, and colored red where they grew and blue
+// where they shrank from the base, the more the larger the change.
+func (d *FoldedDiff) WriteSVG(w io.Writer, title string) error {
+	return writeFlamegraph(w, title, d.Weight, buildFlameTree(d.Stacks), true)
+}
+
+func writeFlamegraph(w io.Writer, title string, weight Weight, root *flameFrame, diff bool) error {
+	depth := flameDepth(root)
+	height := flamePadTop + depth*flameFrameHeight + flamePadBottom
+	total := max(root.value, 1)
+	scale := float64(flameWidth-2*flamePadSide) / float64(total)
+
+	var maxDelta uint64
+	if diff {
+		maxDelta = flameMaxDelta(root)
+	}
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, `<?xml version="1.0" standalone="no"?>
+<svg version="1.1" width="%d" height="%d" viewBox="0 0 %d %d" xmlns="http://www.w3.org/2000/svg">
+<rect x="0" y="0" width="%d" height="%d" fill="#f8f8f8"/>
+<text x="%d" y="24" font-family="Verdana" font-size="17" text-anchor="middle">%s</text>
+<g font-family="Verdana" font-size="12">
+`, flameWidth, height, flameWidth, height, flameWidth, height, flameWidth/2, html.EscapeString(title))
+
+	var draw func(f *flameFrame, level int, x float64)
+	draw = func(f *flameFrame, level int, x float64) {
+		width := float64(f.value) * scale
+		if width < flameMinWidth {
+			return
+		}
+		y := height - flamePadBottom - (level+1)*flameFrameHeight
+
+		var tip, fill string
+		if diff {
+			tip = fmt.Sprintf("%s (%d → %d %s, %s)", f.name, f.base, f.value, weight.Unit(), percentChange(f.base, f.value))
+			fill = diffColor(f.base, f.value, maxDelta)
+		} else {
+			tip = fmt.Sprintf("%s (%d %s, %.2f%%)", f.name, f.value, weight.Unit(), 100*float64(f.value)/float64(total))
+			fill = frameColor(f.name)
+		}
+		fmt.Fprintf(bw, "<g><title>%s</title><rect x=\"%.1f\" y=\"%d\" width=\"%.1f\" height=\"%d\" fill=\"%s\" rx=\"2\"/>",
+			html.EscapeString(tip), x, y, width, flameFrameHeight-1, fill)
+		if label := fitLabel(f.name, width); label != "" {
+			fmt.Fprintf(bw, "<text x=\"%.1f\" y=\"%d\">%s</text>", x+3, y+flameFrameHeight-4, html.EscapeString(label))
+		}
+		bw.WriteString("</g>\n")
+
+		for _, c := range f.children {
+			draw(c, level+1, x)
+			x += float64(c.value) * scale
+		}
+	}
+	draw(root, 0, flamePadSide)
+
+	bw.WriteString("</g>\n</svg>\n")
+	return bw.Flush()
+}
+
+func flameDepth(f *flameFrame) int {
+	depth := 0
+	for _, c := range f.children {
+		depth = max(depth, flameDepth(c))
+	}
+	return depth + 1
+}
+
+func flameMaxDelta(f *flameFrame) uint64 {
+	delta := absDelta(f.base, f.value)
+	for _, c := range f.children {
+		delta = max(delta, flameMaxDelta(c))
+	}
+	return delta
+}
+
+func absDelta(a, b uint64) uint64 {
+	if a > b {
+		return a - b
+	}
+	return b - a
+}
+
+// fitLabel truncates a frame name to the width of its frame, or drops it when
+// not even a few characters fit
+func fitLabel(name string, width float64) string {
+	chars := int(width-6) / flameCharWidth
+	if chars < 3 {
+		return ""
+	}
+	runes := []rune(name)
+	if len(runes) <= chars {
+		return name
+	}
+	return string(runes[:chars-2]) + ".."
+}
+
+// frameColor picks a warm color for a frame, the same for every frame of a name
+func frameColor(name string) string {
+	h := fnv.New32a()
+	h.Write([]byte(name))
+	v := h.Sum32()
+	return fmt.Sprintf("rgb(%d,%d,%d)", 205+v%50, (v>>8)%230, (v>>16)%55)
+}
+
+// diffColor shades a frame from white, unchanged, to red when it grew or blue
+// when it shrank by the largest change in the graph
+func diffColor(base, value, maxDelta uint64) string {
+	if base == value || maxDelta == 0 {
+		return "rgb(250,250,250)"
+	}
+	shade := 250 - int(210*float64(absDelta(base, value))/float64(maxDelta))
+	if value > base {
+		return fmt.Sprintf("rgb(255,%d,%d)", shade, shade)
+	}
+	return fmt.Sprintf("rgb(%d,%d,255)", shade, shade)
+}
+
+func percentChange(base, value uint64) string {
+	switch {
+	case base == value:
+		return "unchanged"
+	case base == 0:
+		return "new"
+	case value == 0:
+		return "removed"
+	}
+	return fmt.Sprintf("%+.2f%%", 100*(float64(value)-float64(base))/float64(base))
+}
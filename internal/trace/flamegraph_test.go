@@ -0,0 +1,154 @@
+// Copyright (c) 2026 dotandev
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	transferFrame   = "transfer [CDLZ…CYSC]"
+	getBalanceFrame = "get_balance [CDLZ…CYSC]"
+	burnFrame       = "burn [CDLZ…CYSC]"
+)
+
+func TestFoldStacks_Steps(t *testing.T) {
+	folded, err := FoldStacks(exportTestTrace(), WeightSteps)
+	require.NoError(t, err)
+
+	assert.Equal(t, WeightSteps, folded.Weight)
+	assert.Equal(t, map[string]uint64{
+		transferFrame: 1,
+		// The return counts towards the call it returns from
+		transferFrame + ";" + getBalanceFrame: 2,
+		transferFrame + ";" + burnFrame:       1,
+		// A step outside any call is a frame of its own
+		"log": 1,
+	}, folded.Stacks)
+	assert.Equal(t, uint64(5), folded.Total())
+}
+
+func TestFoldStacks_CPU(t *testing.T) {
+	folded, err := FoldStacks(exportTestTrace(), "")
+	require.NoError(t, err)
+
+	// The CPU weight is picked when the trace metered instructions, and the
+	// last step costs nothing as there is no reading after it
+	assert.Equal(t, WeightCPU, folded.Weight)
+	assert.Equal(t, map[string]uint64{
+		transferFrame:                         500,
+		transferFrame + ";" + getBalanceFrame: 3000,
+		transferFrame + ";" + burnFrame:       4500,
+	}, folded.Stacks)
+}
+
+func TestFoldStacks_Memory(t *testing.T) {
+	trace := NewExecutionTrace("memory", 10)
+	trace.AddState(ExecutionState{Operation: "fn_call", Function: "deposit", MemoryBytes: 64})
+	trace.AddState(ExecutionState{Operation: "storage_write", Depth: 1, MemoryBytes: 1024})
+	trace.AddState(ExecutionState{Operation: "fn_return", Depth: 1, Function: "deposit", MemoryBytes: 4096})
+
+	folded, err := FoldStacks(trace, WeightMemory)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]uint64{"deposit": 4032}, folded.Stacks)
+	assert.Equal(t, "bytes", folded.Weight.Unit())
+}
+
+func TestFoldStacks_Errors(t *testing.T) {
+	trace := NewExecutionTrace("unmetered", 10)
+	trace.AddState(ExecutionState{Operation: "fn_call", Function: "a"})
+
+	_, err := FoldStacks(trace, WeightCPU)
+	assert.ErrorContains(t, err, "no CPU instruction counts")
+	_, err = FoldStacks(trace, WeightMemory)
+	assert.ErrorContains(t, err, "no memory byte counts")
+	_, err = FoldStacks(trace, "wallclock")
+	assert.ErrorContains(t, err, "unknown weight")
+
+	folded, err := FoldStacks(trace, "")
+	require.NoError(t, err)
+	assert.Equal(t, WeightSteps, folded.Weight)
+}
+
+func TestFoldedStacks_Write(t *testing.T) {
+	folded, err := FoldStacks(exportTestTrace(), WeightSteps)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, folded.Write(&buf))
+	assert.Equal(t, "log 1\n"+
+		transferFrame+" 1\n"+
+		transferFrame+";"+burnFrame+" 1\n"+
+		transferFrame+";"+getBalanceFrame+" 2\n", buf.String())
+}
+
+func TestDiffStacks(t *testing.T) {
+	base := &FoldedStacks{Weight: WeightCPU, Stacks: map[string]uint64{"a": 100, "a;b": 50, "a;c": 10}}
+	current := &FoldedStacks{Weight: WeightCPU, Stacks: map[string]uint64{"a": 100, "a;b": 80, "a;d": 5}}
+
+	diff, err := DiffStacks(base, current)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, diff.Write(&buf))
+	assert.Equal(t, "a 100 100\na;b 50 80\na;c 10 0\na;d 0 5\n", buf.String())
+
+	_, err = DiffStacks(base, &FoldedStacks{Weight: WeightSteps})
+	assert.ErrorContains(t, err, "cannot compare")
+}
+
+func TestFoldedStacks_WriteSVG(t *testing.T) {
+	folded, err := FoldStacks(exportTestTrace(), WeightCPU)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, folded.WriteSVG(&buf, "transfer <export-tx>"))
+	svg := buf.String()
+
+	assert.True(t, strings.HasPrefix(svg, "<?xml"))
+	assert.Contains(t, svg, "transfer &lt;export-tx&gt;")
+	assert.Contains(t, svg, "<title>all (8000 instructions, 100.00%)</title>")
+	assert.Contains(t, svg, "<title>"+getBalanceFrame+" (3000 instructions, 37.50%)</title>")
+	// all, transfer, burn and get_balance
+	assert.Equal(t, 4, strings.Count(svg, "<g><title>"))
+}
+
+func TestFoldedDiff_WriteSVG(t *testing.T) {
+	base := &FoldedStacks{Weight: WeightSteps, Stacks: map[string]uint64{"a;b": 4, "a;c": 4}}
+	current := &FoldedStacks{Weight: WeightSteps, Stacks: map[string]uint64{"a;b": 8, "a;c": 2}}
+	diff, err := DiffStacks(base, current)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, diff.WriteSVG(&buf, "diff"))
+	svg := buf.String()
+
+	assert.Contains(t, svg, "<title>b (4 → 8 steps, +100.00%)</title>")
+	assert.Contains(t, svg, "<title>c (4 → 2 steps, -50.00%)</title>")
+	// b grew the most and is the reddest, c shrank and is blue
+	assert.Contains(t, svg, `fill="rgb(255,40,40)"`)
+	assert.Contains(t, svg, `fill="rgb(145,145,255)"`)
+}
+
+func TestFitLabel(t *testing.T) {
+	assert.Equal(t, "transfer", fitLabel("transfer", 100))
+	assert.Equal(t, "tran..", fitLabel("transfer", 48))
+	assert.Equal(t, "", fitLabel("transfer", 10))
+}
@@ -17,6 +17,7 @@ package trace
 import (
 	"encoding/json"
 	"fmt"
+	"sort"
 	"time"
 )
 
@@ -25,6 +26,7 @@ type ExecutionState struct {
 	Step        int                    `json:"step"`
 	Timestamp   time.Time              `json:"timestamp"`
 	Operation   string                 `json:"operation"`
+	Depth       int                    `json:"depth,omitempty"` // Call depth, 0 for top-level steps
 	ContractID  string                 `json:"contract_id,omitempty"`
 	Function    string                 `json:"function,omitempty"`
 	Arguments   []interface{}          `json:"arguments,omitempty"`
@@ -32,6 +34,10 @@ type ExecutionState struct {
 	Error       string                 `json:"error,omitempty"`
 	HostState   map[string]interface{} `json:"host_state,omitempty"`
 	Memory      map[string]interface{} `json:"memory,omitempty"`
+	// CPUInstructions and MemoryBytes are the resources the host had metered
+	// when the step ran, 0 when the producer of the trace did not record them
+	CPUInstructions uint64 `json:"cpu_instructions,omitempty"`
+	MemoryBytes     uint64 `json:"memory_bytes,omitempty"`
 }
 
 // StateSnapshot represents a complete state snapshot for efficient reconstruction
@@ -43,7 +49,10 @@ type StateSnapshot struct {
 	CallStack []string               `json:"call_stack"`
 }
 
-// ExecutionTrace manages the complete execution trace with bi-directional navigation
+// ExecutionTrace manages the complete execution trace with bi-directional navigation.
+// States only record the host state and memory entries their step changes;
+// snapshots hold the complete state every SnapshotInterval steps, so any
+// step is rebuilt from the closest snapshot before it.
 type ExecutionTrace struct {
 	TransactionHash  string           `json:"transaction_hash"`
 	StartTime        time.Time        `json:"start_time"`
@@ -52,6 +61,64 @@ type ExecutionTrace struct {
 	Snapshots        []StateSnapshot  `json:"snapshots"`
 	CurrentStep      int              `json:"current_step"`
 	SnapshotInterval int              `json:"snapshot_interval"`
+	// SnapshotBudget caps the number of host state and memory entries held by
+	// all snapshots together, 0 for no limit. See SetSnapshotBudget.
+	SnapshotBudget int `json:"snapshot_budget,omitempty"`
+
+	// head is the state after the last added step, kept up to date by AddState
+	head *headState
+	// snapshotEntries counts the entries held by Snapshots
+	snapshotEntries int
+
+	// loader reads the host state and memory of the first loadedSteps steps,
+	// and of the snapshots without them, when the trace is read lazily from a
+	// stream file
+	loader      stateLoader
+	loadedSteps int
+}
+
+// stateLoader reads the state changes a lazily read trace leaves on disk
+type stateLoader interface {
+	stepChanges(step int) (hostState, memory map[string]interface{}, err error)
+	snapshotState(step int) (hostState, memory map[string]interface{}, err error)
+	Close() error
+}
+
+// headState accumulates the changes of the steps added so far
+type headState struct {
+	steps     int
+	hostState map[string]interface{}
+	memory    map[string]interface{}
+	callStack []string
+}
+
+func newHeadState() *headState {
+	return &headState{
+		hostState: make(map[string]interface{}),
+		memory:    make(map[string]interface{}),
+	}
+}
+
+// apply adds the changes of the next step
+func (h *headState) apply(state *ExecutionState, hostState, memory map[string]interface{}) {
+	applyChanges(h.hostState, hostState)
+	applyChanges(h.memory, memory)
+	if state.Function != "" {
+		depth := min(max(state.Depth, 0), len(h.callStack))
+		h.callStack = append(h.callStack[:depth], fmt.Sprintf("%s::%s", state.ContractID, state.Function))
+	}
+	h.steps++
+}
+
+// snapshot deep copies the accumulated state
+func (h *headState) snapshot(step int, timestamp time.Time) StateSnapshot {
+	return StateSnapshot{
+		Step:      step,
+		Timestamp: timestamp,
+		HostState: deepCopyMap(h.hostState),
+		Memory:    deepCopyMap(h.memory),
+		CallStack: append([]string(nil), h.callStack...),
+	}
 }
 
 // NewExecutionTrace creates a new execution trace
@@ -70,70 +137,137 @@ func NewExecutionTrace(txHash string, snapshotInterval int) *ExecutionTrace {
 	}
 }
 
-// AddState adds a new execution state and creates snapshots as needed
+// AddState adds a new execution state and creates snapshots as needed. It only
+// applies the changes of the new state, so building a trace is linear in the
+// number of changes, plus the cost of the snapshots.
 func (t *ExecutionTrace) AddState(state ExecutionState) {
 	state.Step = len(t.States)
 	state.Timestamp = time.Now()
+	// The trace keeps its own copy, callers may reuse their maps
+	state.HostState = copyEntries(state.HostState)
+	state.Memory = copyEntries(state.Memory)
 	t.States = append(t.States, state)
 
-	// Create snapshot at intervals
-	if state.Step%t.SnapshotInterval == 0 {
-		// Reconstruct the complete state up to this point for the snapshot
-		reconstructed, err := t.reconstructStateUpTo(state.Step)
-		if err == nil {
-			snapshot := StateSnapshot{
-				Step:      state.Step,
-				Timestamp: state.Timestamp,
-				HostState: copyMap(reconstructed.HostState),
-				Memory:    copyMap(reconstructed.Memory),
-				CallStack: t.getCurrentCallStack(),
+	// Snapshots only speed up reconstruction, one that cannot be built
+	// because a lazily read step failed to load is skipped
+	head, err := t.advanceHead()
+	if err != nil || state.Step%t.SnapshotInterval != 0 {
+		return
+	}
+	t.Snapshots = append(t.Snapshots, head.snapshot(state.Step, state.Timestamp))
+	t.snapshotEntries += len(head.hostState) + len(head.memory)
+	t.enforceSnapshotBudget()
+}
+
+// advanceHead applies the states added since the last call to the head state.
+// A trace loaded from a file has no head yet, it starts from the last snapshot.
+func (t *ExecutionTrace) advanceHead() (*headState, error) {
+	if t.head == nil || t.head.steps > len(t.States) {
+		head := newHeadState()
+		if i := t.snapshotIndex(len(t.States) - 1); i >= 0 {
+			hostState, memory, err := t.snapshotState(i)
+			if err != nil {
+				return nil, err
 			}
-			t.Snapshots = append(t.Snapshots, snapshot)
+			head.steps = t.Snapshots[i].Step + 1
+			head.hostState = deepCopyMap(hostState)
+			head.memory = deepCopyMap(memory)
+			head.callStack = append([]string(nil), t.Snapshots[i].CallStack...)
+		}
+		t.head = head
+		t.snapshotEntries = 0
+		for i := range t.Snapshots {
+			t.snapshotEntries += len(t.Snapshots[i].HostState) + len(t.Snapshots[i].Memory)
+		}
+	}
+
+	for t.head.steps < len(t.States) {
+		step := t.head.steps
+		hostState, memory, err := t.stepChanges(step)
+		if err != nil {
+			t.head = nil
+			return nil, err
 		}
+		t.head.apply(&t.States[step], hostState, memory)
 	}
+	return t.head, nil
 }
 
-// reconstructStateUpTo is a helper that reconstructs state without using snapshots
-func (t *ExecutionTrace) reconstructStateUpTo(step int) (*ExecutionState, error) {
-	if step < 0 || step >= len(t.States) {
-		return nil, fmt.Errorf("step %d out of range", step)
+// stepChanges returns the host state and memory entries set by step
+func (t *ExecutionTrace) stepChanges(step int) (map[string]interface{}, map[string]interface{}, error) {
+	if step < t.loadedSteps {
+		return t.loader.stepChanges(step)
 	}
+	return t.States[step].HostState, t.States[step].Memory, nil
+}
 
-	reconstructedState := &ExecutionState{
-		Step:      step,
-		HostState: make(map[string]interface{}),
-		Memory:    make(map[string]interface{}),
+// snapshotState returns the host state and memory held by the i-th snapshot
+func (t *ExecutionTrace) snapshotState(i int) (map[string]interface{}, map[string]interface{}, error) {
+	snapshot := &t.Snapshots[i]
+	if t.loader != nil && snapshot.HostState == nil && snapshot.Memory == nil {
+		return t.loader.snapshotState(snapshot.Step)
 	}
+	return snapshot.HostState, snapshot.Memory, nil
+}
 
-	// Apply all state changes from 0 to step (inclusive)
-	for i := 0; i <= step; i++ {
-		state := &t.States[i]
-
-		// Update metadata from target step
-		if i == step {
-			reconstructedState.Timestamp = state.Timestamp
-			reconstructedState.Operation = state.Operation
-			reconstructedState.ContractID = state.ContractID
-			reconstructedState.Function = state.Function
-			reconstructedState.Arguments = state.Arguments
-			reconstructedState.ReturnValue = state.ReturnValue
-			reconstructedState.Error = state.Error
-		}
+// Close releases the file a lazily read trace is read from. The trace can
+// still be navigated, but states can no longer be reconstructed.
+func (t *ExecutionTrace) Close() error {
+	if t.loader == nil {
+		return nil
+	}
+	return t.loader.Close()
+}
 
-		// Accumulate state changes
-		if state.HostState != nil {
-			for k, v := range state.HostState {
-				reconstructedState.HostState[k] = v
-			}
+// loadAll reads every lazily read state into memory
+func (t *ExecutionTrace) loadAll() error {
+	if t.loader == nil {
+		return nil
+	}
+	for step := 0; step < t.loadedSteps; step++ {
+		hostState, memory, err := t.loader.stepChanges(step)
+		if err != nil {
+			return err
 		}
-		if state.Memory != nil {
-			for k, v := range state.Memory {
-				reconstructedState.Memory[k] = v
-			}
+		t.States[step].HostState, t.States[step].Memory = copyEntries(hostState), copyEntries(memory)
+	}
+	for i := range t.Snapshots {
+		hostState, memory, err := t.snapshotState(i)
+		if err != nil {
+			return err
 		}
+		t.Snapshots[i].HostState, t.Snapshots[i].Memory = deepCopyMap(hostState), deepCopyMap(memory)
 	}
+	t.loader, t.loadedSteps = nil, 0
+	t.head = nil
+	return nil
+}
 
-	return reconstructedState, nil
+// SetSnapshotBudget caps the number of host state and memory entries held by
+// all snapshots together, 0 for no limit. Over budget, every other snapshot is
+// dropped and the snapshot interval doubles, which keeps memory bounded at
+// the cost of replaying more steps to reconstruct a state.
+func (t *ExecutionTrace) SetSnapshotBudget(entries int) {
+	t.SnapshotBudget = max(entries, 0)
+	if _, err := t.advanceHead(); err == nil {
+		t.enforceSnapshotBudget()
+	}
+}
+
+func (t *ExecutionTrace) enforceSnapshotBudget() {
+	for t.SnapshotBudget > 0 && t.snapshotEntries > t.SnapshotBudget && len(t.Snapshots) > 1 {
+		t.SnapshotInterval *= 2
+		kept := t.Snapshots[:0]
+		t.snapshotEntries = 0
+		for _, snapshot := range t.Snapshots {
+			if snapshot.Step%t.SnapshotInterval == 0 {
+				kept = append(kept, snapshot)
+				t.snapshotEntries += len(snapshot.HostState) + len(snapshot.Memory)
+			}
+		}
+		clear(t.Snapshots[len(kept):])
+		t.Snapshots = kept
+	}
 }
 
 // StepForward moves to the next execution step
@@ -175,67 +309,69 @@ func (t *ExecutionTrace) GetCurrentState() (*ExecutionState, error) {
 	return &t.States[t.CurrentStep], nil
 }
 
-// ReconstructStateAt reconstructs the complete state at a given step
+// ReconstructStateAt reconstructs the complete state at a given step. The
+// closest snapshot is found by binary search, and at most SnapshotInterval
+// steps are replayed on top of it. The returned state shares no maps or
+// slices with the trace.
 func (t *ExecutionTrace) ReconstructStateAt(step int) (*ExecutionState, error) {
 	if step < 0 || step >= len(t.States) {
 		return nil, fmt.Errorf("step %d out of range", step)
 	}
 
-	// Find the nearest snapshot before or at the target step
-	var baseSnapshot *StateSnapshot
-	for i := len(t.Snapshots) - 1; i >= 0; i-- {
-		if t.Snapshots[i].Step <= step {
-			baseSnapshot = &t.Snapshots[i]
-			break
-		}
+	target := &t.States[step]
+	reconstructedState := &ExecutionState{
+		Step:        step,
+		Timestamp:   target.Timestamp,
+		Operation:   target.Operation,
+		Depth:       target.Depth,
+		ContractID:  target.ContractID,
+		Function:    target.Function,
+		ReturnValue: deepCopyValue(target.ReturnValue),
+		Error:       target.Error,
+		HostState:   make(map[string]interface{}),
+		Memory:      make(map[string]interface{}),
+
+		CPUInstructions: target.CPUInstructions,
+		MemoryBytes:     target.MemoryBytes,
 	}
 
-	// Start with empty state
-	reconstructedState := &ExecutionState{
-		Step:      step,
-		HostState: make(map[string]interface{}),
-		Memory:    make(map[string]interface{}),
+	if target.Arguments != nil {
+		reconstructedState.Arguments = deepCopyValue(target.Arguments).([]interface{})
 	}
 
-	// Start from snapshot or beginning
+	// Start from the closest snapshot, or from the beginning
 	startStep := 0
-	if baseSnapshot != nil {
-		startStep = baseSnapshot.Step
-		reconstructedState.HostState = copyMap(baseSnapshot.HostState)
-		reconstructedState.Memory = copyMap(baseSnapshot.Memory)
+	if i := t.snapshotIndex(step); i >= 0 {
+		hostState, memory, err := t.snapshotState(i)
+		if err != nil {
+			return nil, err
+		}
+		startStep = t.Snapshots[i].Step + 1
+		reconstructedState.HostState = deepCopyMap(hostState)
+		reconstructedState.Memory = deepCopyMap(memory)
 	}
 
-	// Apply state changes from start to target step (inclusive)
+	// Apply the changes of the following steps up to the target (inclusive)
 	for i := startStep; i <= step; i++ {
-		state := &t.States[i]
-
-		// Update metadata from target step
-		if i == step {
-			reconstructedState.Timestamp = state.Timestamp
-			reconstructedState.Operation = state.Operation
-			reconstructedState.ContractID = state.ContractID
-			reconstructedState.Function = state.Function
-			reconstructedState.Arguments = state.Arguments
-			reconstructedState.ReturnValue = state.ReturnValue
-			reconstructedState.Error = state.Error
-		}
-
-		// Accumulate state changes from all steps up to target
-		if state.HostState != nil {
-			for k, v := range state.HostState {
-				reconstructedState.HostState[k] = v
-			}
-		}
-		if state.Memory != nil {
-			for k, v := range state.Memory {
-				reconstructedState.Memory[k] = v
-			}
+		hostState, memory, err := t.stepChanges(i)
+		if err != nil {
+			return nil, err
 		}
+		applyChanges(reconstructedState.HostState, copyEntries(hostState))
+		applyChanges(reconstructedState.Memory, copyEntries(memory))
 	}
 
 	return reconstructedState, nil
 }
 
+// snapshotIndex returns the index of the last snapshot at or before step, or
+// -1 if there is none
+func (t *ExecutionTrace) snapshotIndex(step int) int {
+	return sort.Search(len(t.Snapshots), func(i int) bool {
+		return t.Snapshots[i].Step > step
+	}) - 1
+}
+
 // GetNavigationInfo returns information about navigation possibilities
 func (t *ExecutionTrace) GetNavigationInfo() map[string]interface{} {
 	return map[string]interface{}{
@@ -247,8 +383,12 @@ func (t *ExecutionTrace) GetNavigationInfo() map[string]interface{} {
 	}
 }
 
-// ToJSON serializes the trace to JSON
+// ToJSON serializes the trace to JSON. A lazily read trace is loaded into
+// memory first.
 func (t *ExecutionTrace) ToJSON() ([]byte, error) {
+	if err := t.loadAll(); err != nil {
+		return nil, err
+	}
 	return json.MarshalIndent(t, "", "  ")
 }
 
@@ -261,29 +401,41 @@ func FromJSON(data []byte) (*ExecutionTrace, error) {
 
 // Helper functions
 
-func copyMap(original map[string]interface{}) map[string]interface{} {
+func applyChanges(state, changes map[string]interface{}) {
+	for k, v := range changes {
+		state[k] = v
+	}
+}
+
+// copyEntries deep copies the changes of a state, keeping nil as nil
+func copyEntries(original map[string]interface{}) map[string]interface{} {
 	if original == nil {
-		return make(map[string]interface{})
+		return nil
 	}
+	return deepCopyMap(original)
+}
 
-	copy := make(map[string]interface{})
+// deepCopyMap copies a state map along with the maps and slices nested in its
+// values, the shapes JSON decoding produces
+func deepCopyMap(original map[string]interface{}) map[string]interface{} {
+	copied := make(map[string]interface{}, len(original))
 	for k, v := range original {
-		copy[k] = v
+		copied[k] = deepCopyValue(v)
 	}
-	return copy
+	return copied
 }
 
-func (t *ExecutionTrace) getCurrentCallStack() []string {
-	// Extract call stack from current states
-	var stack []string
-	for i := 0; i <= t.CurrentStep && i < len(t.States); i++ {
-		state := &t.States[i]
-		if state.Function != "" {
-			entry := fmt.Sprintf("%s::%s", state.ContractID, state.Function)
-			if len(stack) == 0 || stack[len(stack)-1] != entry {
-				stack = append(stack, entry)
-			}
+func deepCopyValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		return deepCopyMap(v)
+	case []interface{}:
+		copied := make([]interface{}, len(v))
+		for i, item := range v {
+			copied[i] = deepCopyValue(item)
 		}
+		return copied
+	default:
+		return v
 	}
-	return stack
 }
@@ -0,0 +1,93 @@
+// Copyright (c) 2026 dotandev
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace
+
+import (
+	"fmt"
+	"testing"
+)
+
+// benchmarkStates builds n steps that each touch a couple of the 1000 host
+// state entries and a scratch memory entry
+func benchmarkStates(n int) []ExecutionState {
+	states := make([]ExecutionState, n)
+	for i := range states {
+		balance := fmt.Sprintf("balance_%d", i%1000)
+		states[i] = ExecutionState{
+			Operation: "host_fn",
+			HostState: map[string]interface{}{
+				balance:  i,
+				"ledger": map[string]interface{}{"sequence": i},
+			},
+			Memory: map[string]interface{}{"scratch": i},
+		}
+	}
+	return states
+}
+
+func benchmarkTrace(n, interval int) *ExecutionTrace {
+	trace := NewExecutionTrace("bench", interval)
+	for _, state := range benchmarkStates(n) {
+		trace.AddState(state)
+	}
+	return trace
+}
+
+func BenchmarkAddState100k(b *testing.B) {
+	states := benchmarkStates(100_000)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		trace := NewExecutionTrace("bench", 1000)
+		for _, state := range states {
+			trace.AddState(state)
+		}
+	}
+}
+
+func BenchmarkAddState100kWithBudget(b *testing.B) {
+	states := benchmarkStates(100_000)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		trace := NewExecutionTrace("bench", 100)
+		trace.SetSnapshotBudget(20_000)
+		for _, state := range states {
+			trace.AddState(state)
+		}
+	}
+}
+
+func BenchmarkReconstructStateAt100k(b *testing.B) {
+	trace := benchmarkTrace(100_000, 1000)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := trace.ReconstructStateAt((i * 7919) % len(trace.States)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkReconstructStateAtSequential(b *testing.B) {
+	trace := benchmarkTrace(10_000, 10)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := trace.ReconstructStateAt(i % len(trace.States)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
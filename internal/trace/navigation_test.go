@@ -15,6 +15,8 @@
 package trace
 
 import (
+	"fmt"
+	"reflect"
 	"testing"
 )
 
@@ -289,3 +291,135 @@ func TestExecutionTrace_StateReconstruction(t *testing.T) {
 		t.Errorf("Expected var3=true at step 4, got %v", reconstructed.Memory["var3"])
 	}
 }
+
+func TestExecutionTrace_ReconstructMatchesReplay(t *testing.T) {
+	trace := NewExecutionTrace("test-tx-hash", 4)
+	for i := 0; i < 50; i++ {
+		state := ExecutionState{Operation: "step", HostState: map[string]interface{}{fmt.Sprintf("key%d", i%7): i}}
+		if i%3 == 0 {
+			state.Memory = map[string]interface{}{"last": i}
+		}
+		trace.AddState(state)
+	}
+
+	hostState := map[string]interface{}{}
+	memory := map[string]interface{}{}
+	for step, state := range trace.States {
+		for k, v := range state.HostState {
+			hostState[k] = v
+		}
+		for k, v := range state.Memory {
+			memory[k] = v
+		}
+
+		reconstructed, err := trace.ReconstructStateAt(step)
+		if err != nil {
+			t.Fatalf("ReconstructStateAt(%d) failed: %v", step, err)
+		}
+		if !reflect.DeepEqual(reconstructed.HostState, hostState) {
+			t.Errorf("step %d: host state %v, want %v", step, reconstructed.HostState, hostState)
+		}
+		if !reflect.DeepEqual(reconstructed.Memory, memory) {
+			t.Errorf("step %d: memory %v, want %v", step, reconstructed.Memory, memory)
+		}
+	}
+
+	if _, err := trace.ReconstructStateAt(50); err == nil {
+		t.Error("Expected error for a step out of range")
+	}
+}
+
+func TestExecutionTrace_NoAliasing(t *testing.T) {
+	trace := NewExecutionTrace("test-tx-hash", 1)
+	balances := map[string]interface{}{"alice": 100}
+	hostState := map[string]interface{}{"balances": balances}
+	trace.AddState(ExecutionState{Operation: "init", HostState: hostState, Arguments: []interface{}{[]interface{}{"alice"}}})
+
+	// Changing the caller's maps after the fact does not change the trace
+	balances["alice"] = 1
+	hostState["other"] = true
+
+	reconstructed, err := trace.ReconstructStateAt(0)
+	if err != nil {
+		t.Fatalf("ReconstructStateAt failed: %v", err)
+	}
+	got := reconstructed.HostState["balances"].(map[string]interface{})
+	if got["alice"] != 100 || len(reconstructed.HostState) != 1 {
+		t.Fatalf("Expected the state as added, got %v", reconstructed.HostState)
+	}
+
+	// Neither does changing a reconstructed state
+	got["alice"] = 2
+	reconstructed.Arguments[0].([]interface{})[0] = "bob"
+	again, _ := trace.ReconstructStateAt(0)
+	if again.HostState["balances"].(map[string]interface{})["alice"] != 100 {
+		t.Error("Reconstructed states should not alias the snapshots")
+	}
+	if trace.States[0].Arguments[0].([]interface{})[0] != "alice" {
+		t.Error("Reconstructed states should not alias the arguments")
+	}
+}
+
+func TestExecutionTrace_SnapshotBudget(t *testing.T) {
+	trace := NewExecutionTrace("test-tx-hash", 2)
+	trace.SetSnapshotBudget(12)
+	for i := 0; i < 20; i++ {
+		trace.AddState(ExecutionState{Operation: "step", HostState: map[string]interface{}{fmt.Sprintf("key%d", i): i}})
+	}
+
+	entries := 0
+	for _, snapshot := range trace.Snapshots {
+		entries += len(snapshot.HostState) + len(snapshot.Memory)
+		if snapshot.Step%trace.SnapshotInterval != 0 {
+			t.Errorf("Snapshot at step %d is off the interval %d", snapshot.Step, trace.SnapshotInterval)
+		}
+	}
+	if entries > 12 {
+		t.Errorf("Snapshots hold %d entries, over the budget of 12", entries)
+	}
+	if trace.SnapshotInterval <= 2 {
+		t.Errorf("Expected the snapshot interval to grow, got %d", trace.SnapshotInterval)
+	}
+
+	reconstructed, err := trace.ReconstructStateAt(19)
+	if err != nil {
+		t.Fatalf("ReconstructStateAt failed: %v", err)
+	}
+	if len(reconstructed.HostState) != 20 {
+		t.Errorf("Expected 20 host state entries, got %d", len(reconstructed.HostState))
+	}
+}
+
+func TestExecutionTrace_AddStateAfterJSON(t *testing.T) {
+	original := NewExecutionTrace("test-tx-hash", 2)
+	for i := 0; i < 5; i++ {
+		original.AddState(ExecutionState{Operation: "step", Function: "f", Depth: i % 2, Memory: map[string]interface{}{"i": i}})
+	}
+	data, err := original.ToJSON()
+	if err != nil {
+		t.Fatalf("ToJSON failed: %v", err)
+	}
+	restored, err := FromJSON(data)
+	if err != nil {
+		t.Fatalf("FromJSON failed: %v", err)
+	}
+
+	restored.AddState(ExecutionState{Operation: "step", HostState: map[string]interface{}{"done": true}})
+
+	reconstructed, err := restored.ReconstructStateAt(5)
+	if err != nil {
+		t.Fatalf("ReconstructStateAt failed: %v", err)
+	}
+	if reconstructed.Memory["i"] != float64(4) || reconstructed.HostState["done"] != true {
+		t.Errorf("Expected the restored state to carry over, got %v %v", reconstructed.Memory, reconstructed.HostState)
+	}
+
+	restored.AddState(ExecutionState{Operation: "step"})
+	snapshot := restored.Snapshots[len(restored.Snapshots)-1]
+	if snapshot.Step != 6 || snapshot.HostState["done"] != true || snapshot.Memory["i"] != float64(4) {
+		t.Errorf("Expected a complete snapshot at step 6, got %+v", snapshot)
+	}
+	if !reflect.DeepEqual(snapshot.CallStack, []string{"::f"}) {
+		t.Errorf("Expected the call stack of the last call, got %v", snapshot.CallStack)
+	}
+}
@@ -1,17 +1,24 @@
 package trace
 
+import "strings"
+
 // TraceNode represents a single node in the execution trace tree
 type TraceNode struct {
-	ID         string       // Unique identifier for this node
-	Type       string       // Type of event: "contract_call", "host_fn", "error", "event"
-	ContractID string       // Contract ID if applicable
-	Function   string       // Function name being called
-	Error      string       // Error message if this is an error node
-	EventData  string       // Event data/payload
-	Depth      int          // Depth in the call tree (0 = root)
-	Children   []*TraceNode // Child nodes in the execution tree
-	Parent     *TraceNode   // Parent node (nil for root)
-	Expanded   bool         // Whether this node is expanded in the UI
+	ID          string       // Unique identifier for this node
+	Type        string       // Type of event: "contract_call", "host_fn", "error", "event"
+	ContractID  string       // Contract ID if applicable
+	Function    string       // Function name being called
+	Error       string       // Error message if this is an error node
+	EventData   string       // Event data/payload
+	Args        []string     // Decoded arguments of a contract call
+	ReturnValue string       // Decoded return value of a contract call that returned
+	Failed      bool         // Whether the contract call failed and was rolled back
+	CodeOffsets []uint64     // WASM code offsets of the frames running when the event was emitted, innermost first
+	Source      []string     // Source locations of CodeOffsets, from the contract's DWARF debug info
+	Depth       int          // Depth in the call tree (0 = root)
+	Children    []*TraceNode // Child nodes in the execution tree
+	Parent      *TraceNode   // Parent node (nil for root)
+	Expanded    bool         // Whether this node is expanded in the UI
 }
 
 // NewTraceNode creates a new trace node
@@ -31,6 +38,11 @@ func (n *TraceNode) AddChild(child *TraceNode) {
 	n.Children = append(n.Children, child)
 }
 
+// ArgsText returns the arguments of a contract call separated by commas
+func (n *TraceNode) ArgsText() string {
+	return strings.Join(n.Args, ", ")
+}
+
 // IsLeaf returns true if this node has no children
 func (n *TraceNode) IsLeaf() bool {
 	return len(n.Children) == 0
@@ -3,14 +3,37 @@ package trace
 import (
 	"fmt"
 	"strings"
+
+	"github.com/dotandev/hintents/internal/decoder"
+	"github.com/dotandev/hintents/internal/diagnostic"
+	"github.com/stellar/go/xdr"
 )
 
 // SimulationResponse represents a simulation response (to avoid import cycle)
 type SimulationResponse struct {
 	Status string
 	Error  string
-	Events []string
+	Events []diagnostic.Event
 	Logs   []string
+	// Contracts decodes calls and errors with the interfaces of the contracts,
+	// when they are known
+	Contracts ContractDecoder
+	// LedgerEntries are the ledger entries the transaction started from, and
+	// LedgerWrites the ones it left behind, "" for a removed entry, both as
+	// base64 LedgerKey XDR to base64 LedgerEntry XDR. They give the host
+	// state of the steps.
+	LedgerEntries map[string]string
+	LedgerWrites  map[string]string
+}
+
+// ContractDecoder decodes the calls and errors of contracts with their
+// interfaces, see contractspec
+type ContractDecoder interface {
+	// FormatArgs renders the arguments of a call by name, nil when the
+	// function is unknown
+	FormatArgs(contractID, function string, args []xdr.ScVal) []string
+	// ErrorName names a contract error code, "InsufficientAllowance"
+	ErrorName(contractID string, code uint32) (string, bool)
 }
 
 // ParseSimulationResponse converts a simulation response into a trace tree
@@ -29,11 +52,8 @@ func ParseSimulationResponse(resp *SimulationResponse) (*TraceNode, error) {
 		root.AddChild(errorNode)
 	}
 
-	// Parse events
-	for i, event := range resp.Events {
-		eventNode := parseEvent(fmt.Sprintf("event-%d", i), event)
-		root.AddChild(eventNode)
-	}
+	// Nest events under the contract calls that emitted them
+	buildCallTree(root, resp.Events, resp.Contracts)
 
 	// Parse logs
 	for i, log := range resp.Logs {
@@ -45,43 +65,228 @@ func ParseSimulationResponse(resp *SimulationResponse) (*TraceNode, error) {
 	return root, nil
 }
 
-// parseEvent parses a single event string into a trace node
-func parseEvent(id, event string) *TraceNode {
+// buildCallTree adds events to root as a tree of contract calls. A fn_call
+// opens a frame and its fn_return closes it; every other event is added to the
+// innermost open frame. The host emits no fn_return for a call that fails, so a
+// failed frame is closed by the first event of its caller, and frames still
+// open at the end are marked failed.
+func buildCallTree(root *TraceNode, events []diagnostic.Event, contracts ContractDecoder) {
+	stack := []*TraceNode{root}
+	for i, event := range events {
+		if event.InSuccessfulContractCall {
+			for len(stack) > 1 && stack[len(stack)-1].Failed {
+				stack = stack[:len(stack)-1]
+			}
+		}
+		frame := stack[len(stack)-1]
+
+		switch event.Kind {
+		case diagnostic.KindFnCall:
+			call := parseCall(fmt.Sprintf("call-%d", i), event, contracts)
+			frame.AddChild(call)
+			stack = append(stack, call)
+			continue
+		case diagnostic.KindFnReturn:
+			if frame != root {
+				frame.ReturnValue = decoder.FormatScVal(event.Data)
+				stack = stack[:len(stack)-1]
+				continue
+			}
+		}
+
+		frame.AddChild(parseEvent(fmt.Sprintf("event-%d", i), event, contracts))
+	}
+
+	for _, frame := range stack[1:] {
+		frame.Failed = true
+	}
+}
+
+// parseCall converts a fn_call event into a contract call node
+func parseCall(id string, event diagnostic.Event, contracts ContractDecoder) *TraceNode {
+	node := NewTraceNode(id, "contract_call")
+	node.ContractID = event.CalledContract()
+	node.Function = event.Function()
+	node.Failed = !event.InSuccessfulContractCall
+	node.CodeOffsets = event.WasmBacktrace
+	node.Args = callArgs(event, contracts)
+	return node
+}
+
+// parseEvent converts a single diagnostic event into a trace node
+func parseEvent(id string, event diagnostic.Event, contracts ContractDecoder) *TraceNode {
 	node := NewTraceNode(id, "event")
-	node.EventData = event
-
-	// Try to extract contract ID and function from event
-	// This is a simple parser - adjust based on actual event format
-	if strings.Contains(event, "contract:") {
-		parts := strings.Split(event, "contract:")
-		if len(parts) > 1 {
-			contractPart := strings.TrimSpace(parts[1])
-			contractID := strings.Fields(contractPart)[0]
-			node.ContractID = contractID
+	node.EventData = event.String()
+	node.ContractID = event.ContractID
+	node.Function = event.Function()
+	node.CodeOffsets = event.WasmBacktrace
+
+	if event.Kind == diagnostic.KindError {
+		node.Type = "error"
+		node.Error = errorMessage(event, contracts)
+	}
+
+	return node
+}
+
+// callArgs renders the arguments of a fn_call event, by name when the
+// interface of the called contract is known
+func callArgs(event diagnostic.Event, contracts ContractDecoder) []string {
+	if contracts != nil {
+		if args := contracts.FormatArgs(event.CalledContract(), event.Function(), event.Args()); args != nil {
+			return args
 		}
 	}
+	var args []string
+	for _, arg := range event.Args() {
+		args = append(args, decoder.FormatScVal(arg))
+	}
+	return args
+}
 
-	if strings.Contains(event, "fn:") {
-		parts := strings.Split(event, "fn:")
-		if len(parts) > 1 {
-			fnPart := strings.TrimSpace(parts[1])
-			function := strings.Fields(fnPart)[0]
-			node.Function = function
+// errorMessage renders the error of an error event with the message the host
+// or contract attached to it, e.g. "Error(Auth, InvalidAction): invalid signature".
+// Contract errors are named after the contract's interface when it is known.
+func errorMessage(event diagnostic.Event, contracts ContractDecoder) string {
+	var msg string
+	if scErr, ok := event.ScError(); ok {
+		msg = decoder.FormatScError(scErr)
+		if contracts != nil && scErr.Type == xdr.ScErrorTypeSceContract && scErr.ContractCode != nil {
+			if name, ok := contracts.ErrorName(event.ContractID, uint32(*scErr.ContractCode)); ok {
+				msg = name
+			}
 		}
 	}
+	if text, ok := diagnostic.Text(event.Data); ok {
+		if msg == "" {
+			return text
+		}
+		return msg + ": " + text
+	}
+	if msg == "" {
+		return event.String()
+	}
+	return msg
+}
+
+// ExecutionStates converts the events of a simulation response into execution
+// steps, one per event, in the order they were emitted. A fn_call is a step of
+// the caller, and the events of the call are nested one level below it.
+func ExecutionStates(resp *SimulationResponse) []ExecutionState {
+	if resp == nil {
+		return nil
+	}
 
-	if strings.Contains(event, "error") || strings.Contains(event, "Error") {
-		node.Type = "error"
-		// Extract error message
-		if strings.Contains(event, ":") {
-			parts := strings.SplitN(event, ":", 2)
-			if len(parts) > 1 {
-				node.Error = strings.TrimSpace(parts[1])
+	contracts := resp.Contracts
+	states := make([]ExecutionState, 0, len(resp.Events))
+	for _, event := range resp.Events {
+		state := ExecutionState{
+			Operation:  string(event.Kind),
+			Depth:      event.CallDepth,
+			ContractID: event.ContractID,
+			Function:   event.Function(),
+
+			CPUInstructions: event.CPUInstructions,
+			MemoryBytes:     event.MemoryBytes,
+		}
+
+		switch event.Kind {
+		case diagnostic.KindFnCall:
+			state.Depth = max(event.CallDepth-1, 0)
+			state.ContractID = event.CalledContract()
+			for _, arg := range callArgs(event, contracts) {
+				state.Arguments = append(state.Arguments, arg)
+			}
+			if !event.InSuccessfulContractCall {
+				state.Error = "call failed"
 			}
+		case diagnostic.KindFnReturn:
+			state.ReturnValue = decoder.FormatScVal(event.Data)
+		case diagnostic.KindError:
+			state.Error = errorMessage(event, contracts)
+		default:
+			for _, topic := range event.Topics {
+				state.Arguments = append(state.Arguments, decoder.FormatScVal(topic))
+			}
+			state.ReturnValue = decoder.FormatScVal(event.Data)
 		}
+
+		states = append(states, state)
 	}
+	addHostState(states, resp.LedgerEntries, resp.LedgerWrites)
+	return states
+}
 
-	return node
+// addHostState sets the ledger entries a transaction started from on its
+// first step, and the entries it wrote on the steps that wrote them. The
+// simulator does not report when a write happens, so the writes to the data
+// of a contract are set on the last return of that contract, and any other
+// write on the last step. Keys are the ledger keys as FormatLedgerKey renders
+// them, without spaces so they can be used in breakpoints.
+func addHostState(states []ExecutionState, entries, writes map[string]string) {
+	if len(states) == 0 || len(entries)+len(writes) == 0 {
+		return
+	}
+
+	initial := make(map[string]interface{}, len(entries))
+	for keyXdr, entryXdr := range entries {
+		if key, value, ok := hostStateEntry(keyXdr, entryXdr); ok {
+			initial[key] = value
+		}
+	}
+	if len(initial) > 0 {
+		states[0].HostState = initial
+	}
+
+	for keyXdr, entryXdr := range writes {
+		if entries[keyXdr] == entryXdr {
+			continue
+		}
+		key, value, ok := hostStateEntry(keyXdr, entryXdr)
+		if !ok {
+			continue
+		}
+		state := &states[writeStep(states, keyXdr)]
+		if state.HostState == nil {
+			state.HostState = make(map[string]interface{})
+		}
+		state.HostState[key] = value
+	}
+}
+
+// hostStateEntry decodes a ledger entry into a host state key and value, nil
+// for an entry that does not exist
+func hostStateEntry(keyXdr, entryXdr string) (string, interface{}, bool) {
+	var key xdr.LedgerKey
+	if err := xdr.SafeUnmarshalBase64(keyXdr, &key); err != nil {
+		return "", nil, false
+	}
+	name := strings.ReplaceAll(decoder.FormatLedgerKey(key), " ", "")
+	if entryXdr == "" {
+		return name, nil, true
+	}
+	var entry xdr.LedgerEntry
+	if err := xdr.SafeUnmarshalBase64(entryXdr, &entry); err != nil {
+		return "", nil, false
+	}
+	return name, decoder.FormatLedgerEntry(entry.Data), true
+}
+
+// writeStep returns the step a write to the given key is recorded on
+func writeStep(states []ExecutionState, keyXdr string) int {
+	var key xdr.LedgerKey
+	if err := xdr.SafeUnmarshalBase64(keyXdr, &key); err == nil {
+		if data, ok := key.GetContractData(); ok {
+			if contractID, err := data.Contract.String(); err == nil {
+				for step := len(states) - 1; step >= 0; step-- {
+					if states[step].Operation == string(diagnostic.KindFnReturn) && states[step].ContractID == contractID {
+						return step
+					}
+				}
+			}
+		}
+	}
+	return len(states) - 1
 }
 
 // CreateMockTrace creates a mock trace tree for testing
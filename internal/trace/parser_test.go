@@ -3,16 +3,60 @@ package trace
 import (
 	"testing"
 
+	"github.com/dotandev/hintents/internal/diagnostic"
+	"github.com/stellar/go/strkey"
+	"github.com/stellar/go/xdr"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
+const testContractID = "CDLZFC3SYJYDZT7K67VZ75HPJVIEUVNIXF47ZG2FB2RMQQVU2HHGCYSC"
+
+func symbol(s string) xdr.ScVal {
+	sym := xdr.ScSymbol(s)
+	return xdr.ScVal{Type: xdr.ScValTypeScvSymbol, Sym: &sym}
+}
+
+func u32(n uint32) xdr.ScVal {
+	v := xdr.Uint32(n)
+	return xdr.ScVal{Type: xdr.ScValTypeScvU32, U32: &v}
+}
+
+// fnCallEvent is the event the host emits when calling function on contractID
+func fnCallEvent(caller, contractID, function string, args ...xdr.ScVal) diagnostic.Event {
+	id := xdr.ScBytes(strkey.MustDecode(strkey.VersionByteContract, contractID))
+	vec := &xdr.ScVec{}
+	*vec = args
+	return diagnostic.New(diagnostic.TypeDiagnostic, caller,
+		[]xdr.ScVal{symbol("fn_call"), {Type: xdr.ScValTypeScvBytes, Bytes: &id}, symbol(function)},
+		xdr.ScVal{Type: xdr.ScValTypeScvVec, Vec: &vec})
+}
+
+// fnReturnEvent is the event the host emits when function returns value
+func fnReturnEvent(contractID, function string, value xdr.ScVal) diagnostic.Event {
+	return diagnostic.New(diagnostic.TypeDiagnostic, contractID, []xdr.ScVal{symbol("fn_return"), symbol(function)}, value)
+}
+
+// failed marks an event as emitted by a call that failed
+func failed(event diagnostic.Event) diagnostic.Event {
+	event.InSuccessfulContractCall = false
+	return event
+}
+
+func errorEvent(contractID string, code xdr.ScErrorCode, msg string) diagnostic.Event {
+	scErr := xdr.ScVal{Type: xdr.ScValTypeScvError, Error: &xdr.ScError{Type: xdr.ScErrorTypeSceContext, Code: &code}}
+	text := xdr.ScString(msg)
+	return diagnostic.New(diagnostic.TypeDiagnostic, contractID,
+		[]xdr.ScVal{symbol("error"), scErr}, xdr.ScVal{Type: xdr.ScValTypeScvString, Str: &text})
+}
+
 func TestParseSimulationResponse_Success(t *testing.T) {
 	resp := &SimulationResponse{
 		Status: "success",
-		Events: []string{
-			"contract: CDLZFC3 fn: transfer",
-			"event: Transfer completed",
+		Events: []diagnostic.Event{
+			fnCallEvent("", testContractID, "transfer", u32(100)),
+			diagnostic.New(diagnostic.TypeContract, testContractID, []xdr.ScVal{symbol("transfer")}, symbol("completed")),
+			fnReturnEvent(testContractID, "transfer", xdr.ScVal{Type: xdr.ScValTypeScvVoid}),
 		},
 		Logs: []string{
 			"Debug: Starting transfer",
@@ -28,15 +72,132 @@ func TestParseSimulationResponse_Success(t *testing.T) {
 	assert.Equal(t, "simulation", root.Type)
 	assert.Contains(t, root.EventData, "success")
 
-	// Should have 2 event nodes + 2 log nodes = 4 children
-	assert.Equal(t, 4, len(root.Children))
+	// Should have 1 call node + 2 log nodes = 3 children
+	require.Equal(t, 3, len(root.Children))
+
+	call := root.Children[0]
+	assert.Equal(t, "contract_call", call.Type)
+	assert.Equal(t, testContractID, call.ContractID)
+	assert.Equal(t, "transfer", call.Function)
+	assert.Equal(t, []string{"100"}, call.Args)
+	assert.Equal(t, "(void)", call.ReturnValue)
+	assert.False(t, call.Failed)
+	require.Len(t, call.Children, 1)
+	assert.Equal(t, "event", call.Children[0].Type)
+	assert.Equal(t, 2, call.Children[0].Depth)
+}
+
+func TestParseSimulationResponse_NestedCalls(t *testing.T) {
+	const (
+		router = "CA3D5KRYM6CB7OWQ6TWYRR3Z4T7GNZLKERYNZGGA5SOAOPIFY6YQGAXE"
+		pool   = "CDLZFC3SYJYDZT7K67VZ75HPJVIEUVNIXF47ZG2FB2RMQQVU2HHGCYSC"
+	)
+	resp := &SimulationResponse{
+		Status: "success",
+		Events: []diagnostic.Event{
+			fnCallEvent("", router, "swap", u32(1), u32(2)),
+			fnCallEvent(router, pool, "get_reserves"),
+			fnReturnEvent(pool, "get_reserves", u32(500)),
+			fnCallEvent(router, pool, "swap", u32(1)),
+			fnReturnEvent(pool, "swap", u32(9)),
+			fnReturnEvent(router, "swap", u32(9)),
+		},
+	}
+
+	root, err := ParseSimulationResponse(resp)
+	require.NoError(t, err)
+
+	require.Len(t, root.Children, 1)
+	swap := root.Children[0]
+	assert.Equal(t, router, swap.ContractID)
+	assert.Equal(t, []string{"1", "2"}, swap.Args)
+	assert.Equal(t, "9", swap.ReturnValue)
+
+	require.Len(t, swap.Children, 2)
+	assert.Equal(t, "get_reserves", swap.Children[0].Function)
+	assert.Empty(t, swap.Children[0].Args)
+	assert.Equal(t, "500", swap.Children[0].ReturnValue)
+	assert.Equal(t, "swap", swap.Children[1].Function)
+	assert.Equal(t, pool, swap.Children[1].ContractID)
+	assert.Equal(t, swap, swap.Children[1].Parent)
+	assert.Equal(t, 2, swap.Children[1].Depth)
+}
+
+func TestParseSimulationResponse_FailedFrame(t *testing.T) {
+	const callee = "CA3D5KRYM6CB7OWQ6TWYRR3Z4T7GNZLKERYNZGGA5SOAOPIFY6YQGAXE"
+	resp := &SimulationResponse{
+		Status: "success",
+		Events: []diagnostic.Event{
+			fnCallEvent("", testContractID, "try_pay"),
+			failed(fnCallEvent(testContractID, callee, "pay", u32(7))),
+			failed(errorEvent(callee, xdr.ScErrorCodeScecInvalidAction, "insufficient balance")),
+			// the caller recovers and goes on
+			diagnostic.New(diagnostic.TypeDiagnostic, testContractID, []xdr.ScVal{symbol("log")}, symbol("fallback")),
+			fnReturnEvent(testContractID, "try_pay", u32(0)),
+		},
+	}
+
+	root, err := ParseSimulationResponse(resp)
+	require.NoError(t, err)
+
+	require.Len(t, root.Children, 1)
+	outer := root.Children[0]
+	assert.False(t, outer.Failed)
+	assert.Equal(t, "0", outer.ReturnValue)
+	require.Len(t, outer.Children, 2)
+
+	pay := outer.Children[0]
+	assert.True(t, pay.Failed)
+	assert.Empty(t, pay.ReturnValue)
+	require.Len(t, pay.Children, 1)
+	assert.Equal(t, "error", pay.Children[0].Type)
+	assert.Contains(t, pay.Children[0].Error, "insufficient balance")
+
+	assert.Equal(t, "event", outer.Children[1].Type)
+	assert.Contains(t, outer.Children[1].EventData, "fallback")
+}
+
+func TestParseSimulationResponse_UnreturnedCall(t *testing.T) {
+	const callee = "CA3D5KRYM6CB7OWQ6TWYRR3Z4T7GNZLKERYNZGGA5SOAOPIFY6YQGAXE"
+	resp := &SimulationResponse{
+		Status: "success",
+		Events: []diagnostic.Event{
+			failed(fnCallEvent("", testContractID, "transfer")),
+			failed(fnCallEvent(testContractID, callee, "balance")),
+			failed(fnReturnEvent(callee, "balance", u32(5))),
+			failed(errorEvent(testContractID, xdr.ScErrorCodeScecArithDomain, "overflow")),
+		},
+	}
+
+	root, err := ParseSimulationResponse(resp)
+	require.NoError(t, err)
+
+	require.Len(t, root.Children, 1)
+	transfer := root.Children[0]
+	assert.True(t, transfer.Failed)
+	require.Len(t, transfer.Children, 2)
+	assert.Equal(t, "5", transfer.Children[0].ReturnValue)
+	assert.Equal(t, "error", transfer.Children[1].Type)
+}
+
+func TestParseSimulationResponse_UnmatchedReturn(t *testing.T) {
+	resp := &SimulationResponse{
+		Status: "success",
+		Events: []diagnostic.Event{fnReturnEvent(testContractID, "transfer", u32(1))},
+	}
+
+	root, err := ParseSimulationResponse(resp)
+	require.NoError(t, err)
+
+	require.Len(t, root.Children, 1)
+	assert.Equal(t, "event", root.Children[0].Type)
+	assert.Equal(t, "transfer", root.Children[0].Function)
 }
 
 func TestParseSimulationResponse_WithError(t *testing.T) {
 	resp := &SimulationResponse{
 		Status: "error",
 		Error:  "Contract execution failed",
-		Events: []string{},
 		Logs:   []string{},
 	}
 
@@ -59,34 +220,152 @@ func TestParseSimulationResponse_Nil(t *testing.T) {
 	assert.Contains(t, err.Error(), "nil")
 }
 
-func TestParseEvent_ContractID(t *testing.T) {
-	event := "contract: CDLZFC3SYJYDZT7K67VZ75HPJVIEUVNIXF47ZG2FB2RMQQVU2HHGCYSC fn: transfer"
+func TestExecutionStates(t *testing.T) {
+	atDepth := func(event diagnostic.Event, depth int) diagnostic.Event {
+		event.CallDepth = depth
+		return event
+	}
+	resp := &SimulationResponse{
+		Events: []diagnostic.Event{
+			atDepth(fnCallEvent("", testContractID, "transfer", u32(100)), 1),
+			atDepth(diagnostic.New(diagnostic.TypeContract, testContractID, []xdr.ScVal{symbol("transfer")}, symbol("completed")), 1),
+			atDepth(errorEvent(testContractID, xdr.ScErrorCodeScecInvalidAction, "denied"), 1),
+			atDepth(fnReturnEvent(testContractID, "transfer", u32(1)), 1),
+		},
+	}
+
+	states := ExecutionStates(resp)
+
+	require.Len(t, states, 4)
+	assert.Equal(t, "fn_call", states[0].Operation)
+	assert.Equal(t, 0, states[0].Depth)
+	assert.Equal(t, testContractID, states[0].ContractID)
+	assert.Equal(t, "transfer", states[0].Function)
+	assert.Equal(t, []interface{}{"100"}, states[0].Arguments)
+
+	assert.Equal(t, "contract", states[1].Operation)
+	assert.Equal(t, 1, states[1].Depth)
+	assert.Equal(t, "completed", states[1].ReturnValue)
+
+	assert.Equal(t, "error", states[2].Operation)
+	assert.Contains(t, states[2].Error, "denied")
 
-	node := parseEvent("test-1", event)
+	assert.Equal(t, "fn_return", states[3].Operation)
+	assert.Equal(t, 1, states[3].Depth)
+	assert.Equal(t, "1", states[3].ReturnValue)
+
+	assert.Nil(t, ExecutionStates(nil))
+}
+
+func TestExecutionStates_HostState(t *testing.T) {
+	const (
+		account = "GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H"
+		other   = "GCRRSYF5JBFPXHN5DCG65A4J3MUYE53QMQ4XMXZ3CNKWFJIJJTGMH6MZ"
+	)
+	accountEntry := func(balance xdr.Int64) xdr.LedgerEntry {
+		return xdr.LedgerEntry{Data: xdr.LedgerEntryData{
+			Type:    xdr.LedgerEntryTypeAccount,
+			Account: &xdr.AccountEntry{AccountId: xdr.MustAddress(account), Balance: balance},
+		}}
+	}
+	encode := func(v interface{}) string {
+		b64, err := xdr.MarshalBase64(v)
+		require.NoError(t, err)
+		return b64
+	}
+	before := accountEntry(10000000)
+	key, err := before.LedgerKey()
+	require.NoError(t, err)
+	removed := xdr.LedgerKey{Type: xdr.LedgerEntryTypeAccount, Account: &xdr.LedgerKeyAccount{AccountId: xdr.MustAddress(other)}}
+
+	states := ExecutionStates(&SimulationResponse{
+		Events: []diagnostic.Event{
+			fnCallEvent("", testContractID, "pay"),
+			fnReturnEvent(testContractID, "pay", u32(1)),
+		},
+		LedgerEntries: map[string]string{encode(key): encode(before), encode(removed): encode(accountEntry(1))},
+		LedgerWrites:  map[string]string{encode(key): encode(accountEntry(5000000)), encode(removed): ""},
+	})
+	require.Len(t, states, 2)
+
+	name := "Account(" + account + ")"
+	assert.Equal(t, "balance 1.0000000, seq 0", states[0].HostState[name])
+	assert.Equal(t, map[string]interface{}{
+		name:                     "balance 0.5000000, seq 0",
+		"Account(" + other + ")": nil,
+	}, states[1].HostState, "writes outside contract data are set on the last step")
+}
+
+func TestParseEvent_ContractID(t *testing.T) {
+	node := parseEvent("test-1", fnReturnEvent(testContractID, "transfer", u32(1)), nil)
 
 	assert.Equal(t, "test-1", node.ID)
 	assert.Equal(t, "event", node.Type)
-	assert.Equal(t, "CDLZFC3SYJYDZT7K67VZ75HPJVIEUVNIXF47ZG2FB2RMQQVU2HHGCYSC", node.ContractID)
+	assert.Equal(t, testContractID, node.ContractID)
 	assert.Equal(t, "transfer", node.Function)
 }
 
 func TestParseEvent_Error(t *testing.T) {
-	event := "error: Insufficient balance"
+	code := xdr.ScErrorCodeScecMissingValue
+	scErr := xdr.ScVal{Type: xdr.ScValTypeScvError, Error: &xdr.ScError{Type: xdr.ScErrorTypeSceStorage, Code: &code}}
+	msg := xdr.ScString("Insufficient balance")
+	event := diagnostic.New(diagnostic.TypeDiagnostic, testContractID,
+		[]xdr.ScVal{symbol("error"), scErr}, xdr.ScVal{Type: xdr.ScValTypeScvString, Str: &msg})
 
-	node := parseEvent("test-1", event)
+	node := parseEvent("test-1", event, nil)
 
 	assert.Equal(t, "error", node.Type)
-	assert.Equal(t, "Insufficient balance", node.Error)
+	assert.Equal(t, "Error(Storage, MissingValue): Insufficient balance", node.Error)
+}
+
+// tokenInterface names the arguments of transfer and error #7 of any contract
+type tokenInterface struct{}
+
+func (tokenInterface) FormatArgs(_, function string, args []xdr.ScVal) []string {
+	if function != "transfer" || len(args) != 1 {
+		return nil
+	}
+	return []string{"amount: 100"}
+}
+
+func (tokenInterface) ErrorName(_ string, code uint32) (string, bool) {
+	return "InsufficientAllowance", code == 7
+}
+
+func TestParseSimulationResponse_Contracts(t *testing.T) {
+	code := xdr.Uint32(7)
+	scErr := xdr.ScVal{Type: xdr.ScValTypeScvError, Error: &xdr.ScError{Type: xdr.ScErrorTypeSceContract, ContractCode: &code}}
+	resp := &SimulationResponse{
+		Status: "error",
+		Events: []diagnostic.Event{
+			failed(fnCallEvent("", testContractID, "transfer", u32(100))),
+			failed(diagnostic.New(diagnostic.TypeDiagnostic, testContractID, []xdr.ScVal{symbol("error"), scErr}, scErr)),
+			fnCallEvent("", testContractID, "burn", u32(5)),
+		},
+		Contracts: tokenInterface{},
+	}
+
+	root, err := ParseSimulationResponse(resp)
+	require.NoError(t, err)
+	require.Len(t, root.Children, 2)
+	assert.Equal(t, []string{"amount: 100"}, root.Children[0].Args)
+	assert.Equal(t, "InsufficientAllowance", root.Children[0].Children[0].Error)
+	assert.Equal(t, []string{"5"}, root.Children[1].Args)
+
+	states := ExecutionStates(resp)
+	assert.Equal(t, []interface{}{"amount: 100"}, states[0].Arguments)
+	assert.Equal(t, "InsufficientAllowance", states[1].Error)
 }
 
 func TestParseEvent_Simple(t *testing.T) {
-	event := "Simple event message"
+	event := diagnostic.New(diagnostic.TypeContract, "", []xdr.ScVal{symbol("ping")}, xdr.ScVal{})
 
-	node := parseEvent("test-1", event)
+	node := parseEvent("test-1", event, nil)
 
 	assert.Equal(t, "test-1", node.ID)
 	assert.Equal(t, "event", node.Type)
-	assert.Equal(t, event, node.EventData)
+	assert.Equal(t, event.String(), node.EventData)
+	assert.Empty(t, node.Function)
 }
 
 func TestCreateMockTrace(t *testing.T) {
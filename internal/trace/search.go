@@ -24,7 +24,7 @@ type TraceNodeMatch struct {
 type MatchRange struct {
 	Start int    // Start position of match
 	End   int    // End position of match
-	Field string // Which field matched: "contractID", "function", "error", "event"
+	Field string // Which field matched: "contractID", "function", "args", "return", "error", "event"
 }
 
 // NewSearchEngine creates a new search engine
@@ -88,6 +88,14 @@ func (s *SearchEngine) searchNode(node *TraceNode, index int) TraceNodeMatch {
 		match.MatchRanges = append(match.MatchRanges, ranges...)
 	}
 
+	// Search in call arguments and return value
+	if ranges := s.findInString(node.ArgsText(), "args"); len(ranges) > 0 {
+		match.MatchRanges = append(match.MatchRanges, ranges...)
+	}
+	if ranges := s.findInString(node.ReturnValue, "return"); len(ranges) > 0 {
+		match.MatchRanges = append(match.MatchRanges, ranges...)
+	}
+
 	// Search in error message
 	if ranges := s.findInString(node.Error, "error"); len(ranges) > 0 {
 		match.MatchRanges = append(match.MatchRanges, ranges...)
@@ -212,6 +220,10 @@ func (s *SearchEngine) HighlightMatches(node *TraceNode, field string) []MatchRa
 		text = node.ContractID
 	case "function":
 		text = node.Function
+	case "args":
+		text = node.ArgsText()
+	case "return":
+		text = node.ReturnValue
 	case "error":
 		text = node.Error
 	case "event":
@@ -172,12 +172,17 @@ func TestSearchEngine_SearchAllFields(t *testing.T) {
 		{ID: "3", Error: "match_here"},
 		{ID: "4", EventData: "match_here"},
 		{ID: "5", Type: "match_here"},
+		{ID: "6", Args: []string{"1", "match_here"}},
+		{ID: "7", ReturnValue: "match_here"},
 	}
 
 	engine.SetQuery("match_here")
 	matches := engine.Search(nodes)
 
-	assert.Equal(t, 5, len(matches))
+	assert.Equal(t, 7, len(matches))
+	assert.Equal(t, "args", matches[5].MatchRanges[0].Field)
+	assert.Equal(t, 3, matches[5].MatchRanges[0].Start)
+	assert.Equal(t, "return", matches[6].MatchRanges[0].Field)
 }
 
 func TestSearchEngine_HighlightMatches(t *testing.T) {
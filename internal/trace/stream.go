@@ -0,0 +1,478 @@
+// Copyright (c) 2026 dotandev
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace
+
+// The stream format stores a trace as a sequence of frames, written as the
+// steps are produced, so neither writing nor reading needs the whole trace in
+// memory:
+//
+//	magic   "ERSTTRC1"
+//	frame   header: transaction hash, start time, snapshot interval
+//	frame   steps of chunk 0: the steps without their host state and memory
+//	frame   changes of chunk 0: the host state and memory entries of each step
+//	frame   snapshot: the complete state at a step
+//	...
+//	frame   index: the offsets of every chunk and snapshot
+//	footer  offset of the index frame (uint64), magic
+//
+// A frame is a kind byte, the payload length (uint32), the gzipped JSON
+// payload and its CRC-32. Readers seek to the footer to find the index; when
+// the writer did not finish, they rebuild it by scanning the frames.
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"time"
+)
+
+const (
+	streamMagic = "ERSTTRC1"
+	// StreamFileExtension is the extension of stream trace files
+	StreamFileExtension = ".etrace"
+	// DefaultStreamChunkSize is the number of steps per chunk
+	DefaultStreamChunkSize = 1024
+
+	frameHeaderSize = 5 // kind + payload length
+	frameTrailSize  = 4 // CRC-32 of the payload
+	footerSize      = 8 + len(streamMagic)
+)
+
+// Frame kinds
+const (
+	frameHeader   byte = 'H'
+	frameSteps    byte = 'S'
+	frameChanges  byte = 'C'
+	frameSnapshot byte = 'P'
+	frameIndex    byte = 'I'
+)
+
+// streamHeader describes the trace, it is repeated in the index
+type streamHeader struct {
+	TransactionHash  string    `json:"transaction_hash"`
+	StartTime        time.Time `json:"start_time"`
+	EndTime          time.Time `json:"end_time,omitempty"`
+	SnapshotInterval int       `json:"snapshot_interval"`
+}
+
+// streamIndex locates the chunks and snapshots of a stream file
+type streamIndex struct {
+	streamHeader
+	Steps     int             `json:"steps"`
+	Chunks    []chunkEntry    `json:"chunks"`
+	Snapshots []snapshotEntry `json:"snapshots"`
+}
+
+type chunkEntry struct {
+	FirstStep int   `json:"first_step"`
+	Steps     int   `json:"steps"`
+	States    int64 `json:"states_offset"`
+	Changes   int64 `json:"changes_offset"`
+}
+
+type snapshotEntry struct {
+	Step      int       `json:"step"`
+	Timestamp time.Time `json:"timestamp"`
+	CallStack []string  `json:"call_stack,omitempty"`
+	Offset    int64     `json:"offset"`
+}
+
+// stepsPayload is the payload of a steps frame
+type stepsPayload struct {
+	FirstStep int              `json:"first_step"`
+	States    []ExecutionState `json:"states"`
+}
+
+// stepChanges is the host state and memory set by one step
+type stepChanges struct {
+	HostState map[string]interface{} `json:"host_state,omitempty"`
+	Memory    map[string]interface{} `json:"memory,omitempty"`
+}
+
+// StreamOptions configures a StreamWriter
+type StreamOptions struct {
+	// StartTime defaults to the time the writer is created
+	StartTime time.Time
+	// SnapshotInterval defaults to 10 steps
+	SnapshotInterval int
+	// ChunkSize defaults to DefaultStreamChunkSize steps
+	ChunkSize int
+}
+
+// StreamWriter writes a trace in the stream format one step at a time. It
+// only holds the current chunk and the accumulated state in memory.
+type StreamWriter struct {
+	w      *bufio.Writer
+	closer io.Closer
+	offset int64
+
+	header    streamHeader
+	chunkSize int
+	index     streamIndex
+	pending   []ExecutionState
+	head      *headState
+	closed    bool
+}
+
+// CreateStreamFile creates a stream trace file at path
+func CreateStreamFile(path, txHash string, opts StreamOptions) (*StreamWriter, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create trace file: %w", err)
+	}
+	w, err := NewStreamWriter(f, txHash, opts)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	w.closer = f
+	return w, nil
+}
+
+// NewStreamWriter starts a stream trace on w
+func NewStreamWriter(w io.Writer, txHash string, opts StreamOptions) (*StreamWriter, error) {
+	if opts.StartTime.IsZero() {
+		opts.StartTime = time.Now()
+	}
+	if opts.SnapshotInterval <= 0 {
+		opts.SnapshotInterval = 10
+	}
+	if opts.ChunkSize <= 0 {
+		opts.ChunkSize = DefaultStreamChunkSize
+	}
+
+	sw := &StreamWriter{
+		w: bufio.NewWriter(w),
+		header: streamHeader{
+			TransactionHash:  txHash,
+			StartTime:        opts.StartTime,
+			SnapshotInterval: opts.SnapshotInterval,
+		},
+		chunkSize: opts.ChunkSize,
+		head:      newHeadState(),
+	}
+	if _, err := sw.w.WriteString(streamMagic); err != nil {
+		return nil, fmt.Errorf("failed to write trace file: %w", err)
+	}
+	sw.offset = int64(len(streamMagic))
+	if _, err := sw.writeFrame(frameHeader, sw.header); err != nil {
+		return nil, err
+	}
+	return sw, nil
+}
+
+// AddState appends the next step, like ExecutionTrace.AddState. A timestamp
+// already set on the state is kept.
+func (sw *StreamWriter) AddState(state ExecutionState) error {
+	if sw.closed {
+		return fmt.Errorf("trace file already closed")
+	}
+
+	state.Step = sw.head.steps
+	if state.Timestamp.IsZero() {
+		state.Timestamp = time.Now()
+	}
+	state.HostState = copyEntries(state.HostState)
+	state.Memory = copyEntries(state.Memory)
+	sw.head.apply(&state, state.HostState, state.Memory)
+	sw.pending = append(sw.pending, state)
+
+	if state.Step%sw.header.SnapshotInterval == 0 {
+		snapshot := sw.head.snapshot(state.Step, state.Timestamp)
+		offset, err := sw.writeFrame(frameSnapshot, snapshot)
+		if err != nil {
+			return err
+		}
+		sw.index.Snapshots = append(sw.index.Snapshots, snapshotEntry{
+			Step:      snapshot.Step,
+			Timestamp: snapshot.Timestamp,
+			CallStack: snapshot.CallStack,
+			Offset:    offset,
+		})
+	}
+
+	if len(sw.pending) >= sw.chunkSize {
+		return sw.flushChunk()
+	}
+	return nil
+}
+
+// Steps returns the number of steps written so far
+func (sw *StreamWriter) Steps() int {
+	return sw.head.steps
+}
+
+// SetEndTime records when the traced execution ended. Close uses the current
+// time otherwise.
+func (sw *StreamWriter) SetEndTime(end time.Time) {
+	sw.header.EndTime = end
+}
+
+// Close writes the last chunk and the index, and closes the file created by
+// CreateStreamFile
+func (sw *StreamWriter) Close() error {
+	if sw.closed {
+		return nil
+	}
+	sw.closed = true
+
+	err := sw.finish()
+	if sw.closer != nil {
+		if closeErr := sw.closer.Close(); err == nil && closeErr != nil {
+			err = fmt.Errorf("failed to close trace file: %w", closeErr)
+		}
+	}
+	return err
+}
+
+func (sw *StreamWriter) finish() error {
+	if err := sw.flushChunk(); err != nil {
+		return err
+	}
+	if sw.header.EndTime.IsZero() {
+		sw.header.EndTime = time.Now()
+	}
+	sw.index.streamHeader = sw.header
+	sw.index.Steps = sw.head.steps
+
+	indexOffset, err := sw.writeFrame(frameIndex, sw.index)
+	if err != nil {
+		return err
+	}
+	var footer [footerSize]byte
+	binary.LittleEndian.PutUint64(footer[:8], uint64(indexOffset))
+	copy(footer[8:], streamMagic)
+	if _, err := sw.w.Write(footer[:]); err != nil {
+		return fmt.Errorf("failed to write trace file: %w", err)
+	}
+	if err := sw.w.Flush(); err != nil {
+		return fmt.Errorf("failed to write trace file: %w", err)
+	}
+	return nil
+}
+
+// flushChunk writes the pending steps as a steps frame and a changes frame
+func (sw *StreamWriter) flushChunk() error {
+	if len(sw.pending) == 0 {
+		return nil
+	}
+
+	steps := stepsPayload{FirstStep: sw.pending[0].Step, States: make([]ExecutionState, len(sw.pending))}
+	changes := make([]stepChanges, len(sw.pending))
+	for i, state := range sw.pending {
+		changes[i] = stepChanges{HostState: state.HostState, Memory: state.Memory}
+		state.HostState, state.Memory = nil, nil
+		steps.States[i] = state
+	}
+
+	statesOffset, err := sw.writeFrame(frameSteps, steps)
+	if err != nil {
+		return err
+	}
+	changesOffset, err := sw.writeFrame(frameChanges, changes)
+	if err != nil {
+		return err
+	}
+	sw.index.Chunks = append(sw.index.Chunks, chunkEntry{
+		FirstStep: steps.FirstStep,
+		Steps:     len(sw.pending),
+		States:    statesOffset,
+		Changes:   changesOffset,
+	})
+
+	clear(sw.pending)
+	sw.pending = sw.pending[:0]
+	// Completed chunks reach the disk, so an unfinished file can be recovered
+	if err := sw.w.Flush(); err != nil {
+		return fmt.Errorf("failed to write trace file: %w", err)
+	}
+	return nil
+}
+
+// writeFrame encodes v as a frame and returns its offset
+func (sw *StreamWriter) writeFrame(kind byte, v interface{}) (int64, error) {
+	var payload bytes.Buffer
+	zw := gzip.NewWriter(&payload)
+	if err := json.NewEncoder(zw).Encode(v); err != nil {
+		return 0, fmt.Errorf("failed to encode trace frame: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return 0, fmt.Errorf("failed to compress trace frame: %w", err)
+	}
+
+	var head [frameHeaderSize]byte
+	head[0] = kind
+	binary.LittleEndian.PutUint32(head[1:], uint32(payload.Len()))
+	var trail [frameTrailSize]byte
+	binary.LittleEndian.PutUint32(trail[:], crc32.ChecksumIEEE(payload.Bytes()))
+
+	offset := sw.offset
+	for _, part := range [][]byte{head[:], payload.Bytes(), trail[:]} {
+		if _, err := sw.w.Write(part); err != nil {
+			return 0, fmt.Errorf("failed to write trace file: %w", err)
+		}
+	}
+	sw.offset += int64(frameHeaderSize + payload.Len() + frameTrailSize)
+	return offset, nil
+}
+
+// WriteStream writes an in-memory trace in the stream format
+func WriteStream(w io.Writer, t *ExecutionTrace, chunkSize int) error {
+	if err := t.loadAll(); err != nil {
+		return err
+	}
+	sw, err := NewStreamWriter(w, t.TransactionHash, StreamOptions{
+		StartTime:        t.StartTime,
+		SnapshotInterval: t.SnapshotInterval,
+		ChunkSize:        chunkSize,
+	})
+	if err != nil {
+		return err
+	}
+	for _, state := range t.States {
+		if err := sw.AddState(state); err != nil {
+			return err
+		}
+	}
+	sw.SetEndTime(t.EndTime)
+	return sw.Close()
+}
+
+// ConvertJSONToStream converts a trace in the JSON format of ToJSON to the
+// stream format. The steps are decoded one at a time, so the JSON trace is
+// never held in memory. Snapshots are rebuilt rather than copied.
+func ConvertJSONToStream(r io.Reader, w io.Writer, opts StreamOptions) error {
+	dec := json.NewDecoder(r)
+	if err := expectDelim(dec, '{'); err != nil {
+		return err
+	}
+
+	var header streamHeader
+	var sw *StreamWriter
+	start := func() error {
+		if sw != nil {
+			return nil
+		}
+		if opts.StartTime.IsZero() {
+			opts.StartTime = header.StartTime
+		}
+		var err error
+		sw, err = NewStreamWriter(w, header.TransactionHash, opts)
+		return err
+	}
+
+	for dec.More() {
+		token, err := dec.Token()
+		if err != nil {
+			return fmt.Errorf("failed to read JSON trace: %w", err)
+		}
+		switch token {
+		case "transaction_hash":
+			err = dec.Decode(&header.TransactionHash)
+		case "start_time":
+			err = dec.Decode(&header.StartTime)
+		case "end_time":
+			err = dec.Decode(&header.EndTime)
+		case "states":
+			if err := start(); err != nil {
+				return err
+			}
+			err = decodeStates(dec, sw)
+		default:
+			err = skipValue(dec)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read JSON trace: %w", err)
+		}
+	}
+	if err := expectDelim(dec, '}'); err != nil {
+		return err
+	}
+
+	if err := start(); err != nil {
+		return err
+	}
+	// The header frame is written when the steps start, fields that come
+	// later in the JSON still reach the index
+	if sw.header.TransactionHash == "" {
+		sw.header.TransactionHash = header.TransactionHash
+	}
+	if !header.EndTime.IsZero() {
+		sw.SetEndTime(header.EndTime)
+	}
+	return sw.Close()
+}
+
+func decodeStates(dec *json.Decoder, sw *StreamWriter) error {
+	token, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if token == nil {
+		return nil // "states": null
+	}
+	if token != json.Delim('[') {
+		return fmt.Errorf("states should be an array")
+	}
+	for dec.More() {
+		var state ExecutionState
+		if err := dec.Decode(&state); err != nil {
+			return err
+		}
+		if err := sw.AddState(state); err != nil {
+			return err
+		}
+	}
+	_, err = dec.Token()
+	return err
+}
+
+func expectDelim(dec *json.Decoder, delim json.Delim) error {
+	token, err := dec.Token()
+	if err != nil {
+		return fmt.Errorf("failed to read JSON trace: %w", err)
+	}
+	if token != delim {
+		return fmt.Errorf("failed to read JSON trace: expected %q, got %v", delim, token)
+	}
+	return nil
+}
+
+// skipValue consumes the next JSON value token by token, so large values are
+// not decoded
+func skipValue(dec *json.Decoder) error {
+	depth := 0
+	for {
+		token, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		switch token {
+		case json.Delim('{'), json.Delim('['):
+			depth++
+		case json.Delim('}'), json.Delim(']'):
+			depth--
+		}
+		if depth == 0 {
+			return nil
+		}
+	}
+}
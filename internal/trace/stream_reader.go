@@ -0,0 +1,401 @@
+// Copyright (c) 2026 dotandev
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"sort"
+	"sync"
+
+	"github.com/dotandev/hintents/internal/errors"
+)
+
+// Number of decoded chunks and snapshots a StreamReader keeps in memory
+const (
+	streamChunkCacheSize    = 8
+	streamSnapshotCacheSize = 2
+)
+
+// StreamReader reads a stream trace file lazily: the index when opened, the
+// steps when Trace is called, and the host state and memory of a chunk or
+// snapshot only when a state is reconstructed from it
+type StreamReader struct {
+	r      io.ReaderAt
+	size   int64
+	closer io.Closer
+	index  streamIndex
+	// Recovered is set when the file was not finished and its index was
+	// rebuilt from the frames that were completely written
+	Recovered bool
+
+	mu        sync.Mutex
+	chunks    cache[[]stepChanges]
+	snapshots cache[stepChanges]
+}
+
+// OpenStreamFile opens a stream trace file
+func OpenStreamFile(path string) (*StreamReader, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open trace file: %w", err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("failed to open trace file: %w", err)
+	}
+	r, err := NewStreamReader(f, info.Size())
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	r.closer = f
+	return r, nil
+}
+
+// NewStreamReader reads the index of the stream trace held by r
+func NewStreamReader(r io.ReaderAt, size int64) (*StreamReader, error) {
+	sr := &StreamReader{
+		r:         r,
+		size:      size,
+		chunks:    newCache[[]stepChanges](streamChunkCacheSize),
+		snapshots: newCache[stepChanges](streamSnapshotCacheSize),
+	}
+
+	magic := make([]byte, len(streamMagic))
+	if _, err := r.ReadAt(magic, 0); err != nil || string(magic) != streamMagic {
+		return nil, errors.WrapTraceFileCorrupt("not a stream trace file")
+	}
+
+	if err := sr.readIndex(); err != nil {
+		if err := sr.recoverIndex(); err != nil {
+			return nil, err
+		}
+		sr.Recovered = true
+	}
+	return sr, nil
+}
+
+// IsStreamFile reports whether the file at path is in the stream format
+func IsStreamFile(path string) (bool, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+
+	magic := make([]byte, len(streamMagic))
+	if _, err := io.ReadFull(f, magic); err != nil {
+		return false, nil
+	}
+	return string(magic) == streamMagic, nil
+}
+
+// Steps returns the number of steps in the trace
+func (sr *StreamReader) Steps() int {
+	return sr.index.Steps
+}
+
+// Close closes the file opened by OpenStreamFile
+func (sr *StreamReader) Close() error {
+	if sr.closer == nil {
+		return nil
+	}
+	return sr.closer.Close()
+}
+
+// Trace returns the trace with its steps in memory, but not their host state
+// and memory, which are read from the file when needed. The trace owns the
+// reader: closing the trace closes it.
+func (sr *StreamReader) Trace() (*ExecutionTrace, error) {
+	t := &ExecutionTrace{
+		TransactionHash:  sr.index.TransactionHash,
+		StartTime:        sr.index.StartTime,
+		EndTime:          sr.index.EndTime,
+		States:           make([]ExecutionState, 0, sr.index.Steps),
+		Snapshots:        make([]StateSnapshot, 0, len(sr.index.Snapshots)),
+		SnapshotInterval: sr.index.SnapshotInterval,
+		loader:           sr,
+		loadedSteps:      sr.index.Steps,
+	}
+	if t.SnapshotInterval <= 0 {
+		t.SnapshotInterval = 10
+	}
+
+	for _, chunk := range sr.index.Chunks {
+		var steps stepsPayload
+		if _, err := sr.readFrame(chunk.States, frameSteps, &steps); err != nil {
+			return nil, err
+		}
+		if steps.FirstStep != len(t.States) || len(steps.States) != chunk.Steps {
+			return nil, errors.WrapTraceFileCorrupt(fmt.Sprintf("chunk at offset %d does not follow step %d", chunk.States, len(t.States)))
+		}
+		t.States = append(t.States, steps.States...)
+	}
+	for _, snapshot := range sr.index.Snapshots {
+		t.Snapshots = append(t.Snapshots, StateSnapshot{
+			Step:      snapshot.Step,
+			Timestamp: snapshot.Timestamp,
+			CallStack: snapshot.CallStack,
+		})
+	}
+	return t, nil
+}
+
+// stepChanges implements stateLoader
+func (sr *StreamReader) stepChanges(step int) (map[string]interface{}, map[string]interface{}, error) {
+	i := sort.Search(len(sr.index.Chunks), func(i int) bool {
+		chunk := sr.index.Chunks[i]
+		return chunk.FirstStep+chunk.Steps > step
+	})
+	if i == len(sr.index.Chunks) || step < sr.index.Chunks[i].FirstStep {
+		return nil, nil, fmt.Errorf("step %d out of range", step)
+	}
+	chunk := sr.index.Chunks[i]
+
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+	changes, ok := sr.chunks.get(chunk.Changes)
+	if !ok {
+		if _, err := sr.readFrame(chunk.Changes, frameChanges, &changes); err != nil {
+			return nil, nil, err
+		}
+		if len(changes) != chunk.Steps {
+			return nil, nil, errors.WrapTraceFileCorrupt(fmt.Sprintf("chunk at offset %d has %d changes for %d steps", chunk.Changes, len(changes), chunk.Steps))
+		}
+		sr.chunks.put(chunk.Changes, changes)
+	}
+	c := changes[step-chunk.FirstStep]
+	return c.HostState, c.Memory, nil
+}
+
+// snapshotState implements stateLoader
+func (sr *StreamReader) snapshotState(step int) (map[string]interface{}, map[string]interface{}, error) {
+	i := sort.Search(len(sr.index.Snapshots), func(i int) bool {
+		return sr.index.Snapshots[i].Step >= step
+	})
+	if i == len(sr.index.Snapshots) || sr.index.Snapshots[i].Step != step {
+		return nil, nil, fmt.Errorf("no snapshot at step %d", step)
+	}
+	offset := sr.index.Snapshots[i].Offset
+
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+	state, ok := sr.snapshots.get(offset)
+	if !ok {
+		var snapshot StateSnapshot
+		if _, err := sr.readFrame(offset, frameSnapshot, &snapshot); err != nil {
+			return nil, nil, err
+		}
+		state = stepChanges{HostState: snapshot.HostState, Memory: snapshot.Memory}
+		sr.snapshots.put(offset, state)
+	}
+	return state.HostState, state.Memory, nil
+}
+
+// readIndex reads the index the footer points to
+func (sr *StreamReader) readIndex() error {
+	if sr.size < int64(len(streamMagic)+footerSize) {
+		return errors.WrapTraceFileCorrupt("file too short")
+	}
+	footer := make([]byte, footerSize)
+	if _, err := sr.r.ReadAt(footer, sr.size-int64(footerSize)); err != nil {
+		return fmt.Errorf("failed to read trace file: %w", err)
+	}
+	if string(footer[8:]) != streamMagic {
+		return errors.WrapTraceFileCorrupt("missing footer")
+	}
+	offset := int64(binary.LittleEndian.Uint64(footer[:8]))
+	_, err := sr.readFrame(offset, frameIndex, &sr.index)
+	return err
+}
+
+// recoverIndex rebuilds the index from the frames written before the writer
+// stopped. Snapshots of steps that were not written are dropped.
+func (sr *StreamReader) recoverIndex() error {
+	sr.index = streamIndex{}
+	offset := int64(len(streamMagic))
+	var pending *chunkEntry
+	for {
+		kind, payload, next, err := sr.frameAt(offset)
+		if err != nil {
+			break
+		}
+		switch kind {
+		case frameHeader:
+			err = decodePayload(payload, &sr.index.streamHeader)
+		case frameSteps:
+			var steps stepsPayload
+			err = decodePayload(payload, &steps)
+			pending = &chunkEntry{FirstStep: steps.FirstStep, Steps: len(steps.States), States: offset}
+		case frameChanges:
+			if pending != nil {
+				pending.Changes = offset
+				sr.index.Chunks = append(sr.index.Chunks, *pending)
+				sr.index.Steps = pending.FirstStep + pending.Steps
+				pending = nil
+			}
+		case frameSnapshot:
+			var snapshot StateSnapshot
+			err = decodePayload(payload, &snapshot)
+			sr.index.Snapshots = append(sr.index.Snapshots, snapshotEntry{
+				Step:      snapshot.Step,
+				Timestamp: snapshot.Timestamp,
+				CallStack: snapshot.CallStack,
+				Offset:    offset,
+			})
+		}
+		if err != nil {
+			break
+		}
+		offset = next
+	}
+
+	if sr.index.SnapshotInterval == 0 {
+		return errors.WrapTraceFileCorrupt("no header frame")
+	}
+	kept := sr.index.Snapshots[:0]
+	for _, snapshot := range sr.index.Snapshots {
+		if snapshot.Step < sr.index.Steps {
+			kept = append(kept, snapshot)
+		}
+	}
+	sr.index.Snapshots = kept
+	return nil
+}
+
+// readFrame reads the frame at offset, checks its kind and decodes its
+// payload into v
+func (sr *StreamReader) readFrame(offset int64, kind byte, v interface{}) (int64, error) {
+	got, payload, next, err := sr.frameAt(offset)
+	if err != nil {
+		return 0, err
+	}
+	if got != kind {
+		return 0, errors.WrapTraceFileCorrupt(fmt.Sprintf("expected frame %q at offset %d, found %q", kind, offset, got))
+	}
+	if err := decodePayload(payload, v); err != nil {
+		return 0, errors.WrapTraceFileCorrupt(fmt.Sprintf("frame at offset %d: %v", offset, err))
+	}
+	return next, nil
+}
+
+// frameAt reads the frame at offset and checks its checksum
+func (sr *StreamReader) frameAt(offset int64) (byte, []byte, int64, error) {
+	head := make([]byte, frameHeaderSize)
+	if _, err := sr.r.ReadAt(head, offset); err != nil {
+		return 0, nil, 0, errors.WrapTraceFileCorrupt(fmt.Sprintf("truncated frame at offset %d", offset))
+	}
+	length := int64(binary.LittleEndian.Uint32(head[1:]))
+	next := offset + frameHeaderSize + length + frameTrailSize
+	if next > sr.size {
+		return 0, nil, 0, errors.WrapTraceFileCorrupt(fmt.Sprintf("truncated frame at offset %d", offset))
+	}
+
+	body := make([]byte, length+frameTrailSize)
+	if _, err := sr.r.ReadAt(body, offset+frameHeaderSize); err != nil {
+		return 0, nil, 0, fmt.Errorf("failed to read trace file: %w", err)
+	}
+	payload := body[:length]
+	if crc32.ChecksumIEEE(payload) != binary.LittleEndian.Uint32(body[length:]) {
+		return 0, nil, 0, errors.WrapTraceFileCorrupt(fmt.Sprintf("bad checksum at offset %d", offset))
+	}
+	return head[0], payload, next, nil
+}
+
+func decodePayload(payload []byte, v interface{}) error {
+	zr, err := gzip.NewReader(bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	defer zr.Close()
+	return json.NewDecoder(zr).Decode(v)
+}
+
+// cache keeps the most recently used values read from a stream file
+type cache[V any] struct {
+	size   int
+	values map[int64]V
+	// order lists the offsets from the least to the most recently used
+	order []int64
+}
+
+func newCache[V any](size int) cache[V] {
+	return cache[V]{size: size, values: make(map[int64]V, size)}
+}
+
+func (c *cache[V]) get(offset int64) (V, bool) {
+	v, ok := c.values[offset]
+	if ok {
+		c.touch(offset)
+	}
+	return v, ok
+}
+
+func (c *cache[V]) put(offset int64, v V) {
+	if len(c.order) == c.size {
+		delete(c.values, c.order[0])
+		c.order = c.order[1:]
+	}
+	c.values[offset] = v
+	c.order = append(c.order, offset)
+}
+
+func (c *cache[V]) touch(offset int64) {
+	for i, o := range c.order {
+		if o == offset {
+			c.order = append(append(c.order[:i:i], c.order[i+1:]...), offset)
+			return
+		}
+	}
+}
+
+// OpenTrace loads a trace file in either format. A stream file is read
+// lazily and stays open until the trace is closed.
+func OpenTrace(path string) (*ExecutionTrace, error) {
+	stream, err := IsStreamFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read trace file: %w", err)
+	}
+	if stream {
+		sr, err := OpenStreamFile(path)
+		if err != nil {
+			return nil, err
+		}
+		t, err := sr.Trace()
+		if err != nil {
+			sr.Close()
+			return nil, err
+		}
+		return t, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read trace file: %w", err)
+	}
+	t, err := FromJSON(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse trace file: %w", err)
+	}
+	return t, nil
+}
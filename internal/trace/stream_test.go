@@ -0,0 +1,245 @@
+// Copyright (c) 2026 dotandev
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// streamTestTrace builds a trace whose values survive a JSON round trip
+func streamTestTrace(steps int) *ExecutionTrace {
+	trace := NewExecutionTrace("stream-tx", 4)
+	start := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	trace.StartTime = start
+	for i := 0; i < steps; i++ {
+		state := ExecutionState{
+			Timestamp:  start.Add(time.Duration(i) * time.Millisecond),
+			Operation:  "contract_call",
+			Depth:      i % 3,
+			ContractID: fmt.Sprintf("C%d", i%5),
+			Function:   fmt.Sprintf("fn%d", i%7),
+			Arguments:  []interface{}{float64(i), "arg"},
+			HostState: map[string]interface{}{
+				"balance":                 float64(1000 - i),
+				fmt.Sprintf("key%d", i%9): map[string]interface{}{"step": float64(i)},
+			},
+		}
+		if i%4 == 0 {
+			state.Memory = map[string]interface{}{"scratch": []interface{}{float64(i), "x"}}
+		}
+		if i%11 == 10 {
+			state.Error = "failed"
+		}
+		trace.AddState(state)
+	}
+	return trace
+}
+
+func writeStreamFile(t *testing.T, trace *ExecutionTrace, chunkSize int) string {
+	t.Helper()
+	var buf bytes.Buffer
+	require.NoError(t, WriteStream(&buf, trace, chunkSize))
+	path := filepath.Join(t.TempDir(), "trace"+StreamFileExtension)
+	require.NoError(t, os.WriteFile(path, buf.Bytes(), 0644))
+	return path
+}
+
+func assertSameStates(t *testing.T, want, got *ExecutionTrace) {
+	t.Helper()
+	require.Len(t, got.States, len(want.States))
+	for step := range want.States {
+		expected, err := want.ReconstructStateAt(step)
+		require.NoError(t, err)
+		actual, err := got.ReconstructStateAt(step)
+		require.NoError(t, err)
+		// Timestamps lose their location when encoded
+		require.True(t, expected.Timestamp.Equal(actual.Timestamp), "step %d", step)
+		expected.Timestamp, actual.Timestamp = time.Time{}, time.Time{}
+		require.Equal(t, expected, actual, "step %d", step)
+	}
+}
+
+func TestStream_RoundTrip(t *testing.T) {
+	original := streamTestTrace(100)
+	path := writeStreamFile(t, original, 16)
+
+	sr, err := OpenStreamFile(path)
+	require.NoError(t, err)
+	assert.False(t, sr.Recovered)
+	assert.Equal(t, 100, sr.Steps())
+
+	loaded, err := sr.Trace()
+	require.NoError(t, err)
+	defer loaded.Close()
+
+	assert.Equal(t, original.TransactionHash, loaded.TransactionHash)
+	assert.True(t, original.StartTime.Equal(loaded.StartTime))
+	assert.Equal(t, original.SnapshotInterval, loaded.SnapshotInterval)
+	assertSameStates(t, original, loaded)
+}
+
+func TestStream_LoadsLazily(t *testing.T) {
+	path := writeStreamFile(t, streamTestTrace(50), 8)
+
+	loaded, err := OpenTrace(path)
+	require.NoError(t, err)
+	defer loaded.Close()
+
+	// Only the steps are in memory, their state is read on demand
+	for _, state := range loaded.States {
+		assert.Nil(t, state.HostState)
+		assert.Nil(t, state.Memory)
+	}
+	for _, snapshot := range loaded.Snapshots {
+		assert.Nil(t, snapshot.HostState)
+	}
+
+	state, err := loaded.ReconstructStateAt(49)
+	require.NoError(t, err)
+	assert.Equal(t, float64(951), state.HostState["balance"])
+	assert.Equal(t, "fn0", state.Function)
+}
+
+func TestStream_BreakpointOnLazyTrace(t *testing.T) {
+	original := streamTestTrace(30)
+	loaded, err := OpenTrace(writeStreamFile(t, original, 4))
+	require.NoError(t, err)
+	defer loaded.Close()
+
+	set := NewBreakpointSet()
+	_, err = set.Add(Breakpoint{HostStateKey: "key3"})
+	require.NoError(t, err)
+
+	want, _, err := original.Continue(set)
+	require.NoError(t, err)
+	got, bp, err := loaded.Continue(set)
+	require.NoError(t, err)
+	require.NotNil(t, bp)
+	assert.Equal(t, want.Step, got.Step)
+}
+
+func TestStream_BreakpointLoadError(t *testing.T) {
+	loaded, err := OpenTrace(writeStreamFile(t, streamTestTrace(30), 4))
+	require.NoError(t, err)
+	require.NoError(t, loaded.Close())
+
+	set := NewBreakpointSet()
+	_, err = set.Add(Breakpoint{HostStateKey: "key3"})
+	require.NoError(t, err)
+
+	_, _, err = loaded.Continue(set)
+	assert.Error(t, err, "states that cannot be loaded are not treated as unchanged")
+}
+
+func TestStream_RecoversUnfinishedFile(t *testing.T) {
+	var buf bytes.Buffer
+	sw, err := NewStreamWriter(&buf, "partial-tx", StreamOptions{SnapshotInterval: 4, ChunkSize: 8})
+	require.NoError(t, err)
+	for i := 0; i < 20; i++ {
+		require.NoError(t, sw.AddState(ExecutionState{
+			Operation: "step",
+			HostState: map[string]interface{}{"counter": float64(i)},
+		}))
+	}
+	// Two chunks of 8 steps were flushed, the last 4 steps were never
+	// written because the writer was not closed
+	require.NoError(t, sw.w.Flush())
+
+	sr, err := NewStreamReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+	assert.True(t, sr.Recovered)
+	assert.Equal(t, 16, sr.Steps())
+
+	loaded, err := sr.Trace()
+	require.NoError(t, err)
+	assert.Equal(t, "partial-tx", loaded.TransactionHash)
+	state, err := loaded.ReconstructStateAt(15)
+	require.NoError(t, err)
+	assert.Equal(t, float64(15), state.HostState["counter"])
+}
+
+func TestStream_DetectsCorruption(t *testing.T) {
+	path := writeStreamFile(t, streamTestTrace(20), 4)
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	sr, err := NewStreamReader(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+
+	// Flip a byte in the payload of the first steps frame
+	data[sr.index.Chunks[0].States+frameHeaderSize+2] ^= 0xff
+
+	sr, err = NewStreamReader(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+	_, err = sr.Trace()
+	require.Error(t, err)
+	assert.ErrorIs(t, err, errors.ErrTraceFileCorrupt)
+
+	_, err = NewStreamReader(bytes.NewReader([]byte("{\"states\":[]}")), 13)
+	assert.ErrorIs(t, err, errors.ErrTraceFileCorrupt)
+}
+
+func TestConvertJSONToStream(t *testing.T) {
+	original := streamTestTrace(40)
+	original.EndTime = original.StartTime.Add(time.Second)
+	data, err := original.ToJSON()
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+	require.NoError(t, ConvertJSONToStream(bytes.NewReader(data), &out, StreamOptions{ChunkSize: 16}))
+
+	sr, err := NewStreamReader(bytes.NewReader(out.Bytes()), int64(out.Len()))
+	require.NoError(t, err)
+	loaded, err := sr.Trace()
+	require.NoError(t, err)
+
+	assert.Equal(t, "stream-tx", loaded.TransactionHash)
+	assert.True(t, original.StartTime.Equal(loaded.StartTime))
+	assert.True(t, original.EndTime.Equal(loaded.EndTime))
+	assertSameStates(t, original, loaded)
+}
+
+func TestOpenTrace_BothFormats(t *testing.T) {
+	original := streamTestTrace(12)
+	data, err := original.ToJSON()
+	require.NoError(t, err)
+	jsonPath := filepath.Join(t.TempDir(), "trace.json")
+	require.NoError(t, os.WriteFile(jsonPath, data, 0644))
+
+	fromJSON, err := OpenTrace(jsonPath)
+	require.NoError(t, err)
+	defer fromJSON.Close()
+	fromStream, err := OpenTrace(writeStreamFile(t, original, 5))
+	require.NoError(t, err)
+	defer fromStream.Close()
+
+	assertSameStates(t, fromJSON, fromStream)
+
+	// A lazy trace is written out completely
+	again, err := fromStream.ToJSON()
+	require.NoError(t, err)
+	reloaded, err := FromJSON(again)
+	require.NoError(t, err)
+	assertSameStates(t, fromJSON, reloaded)
+}
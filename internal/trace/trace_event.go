@@ -0,0 +1,271 @@
+// Copyright (c) 2026 dotandev
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/dotandev/hintents/internal/diagnostic"
+)
+
+// Timeline is what the time axis of an exported trace counts
+type Timeline string
+
+const (
+	// TimelineSteps gives every step one unit of time
+	TimelineSteps Timeline = "steps"
+	// TimelineCPU places every step at the CPU instructions metered when it
+	// ran, so a call lasts as long as the instructions it consumed
+	TimelineCPU Timeline = "cpu"
+)
+
+// Process and thread the exported events are attributed to
+const (
+	traceEventPid = 1
+	traceEventTid = 1
+)
+
+// TraceEvent is an event of the Chrome Trace Event Format, read by Perfetto
+// and chrome://tracing
+type TraceEvent struct {
+	Name string `json:"name"`
+	Cat  string `json:"cat,omitempty"`
+	// Ph is the phase: "X" for a complete event, "C" for a counter and "M"
+	// for metadata
+	Ph   string                 `json:"ph"`
+	Ts   uint64                 `json:"ts"`
+	Dur  *uint64                `json:"dur,omitempty"`
+	Pid  int                    `json:"pid"`
+	Tid  int                    `json:"tid"`
+	Args map[string]interface{} `json:"args,omitempty"`
+	// Cname is one of the colors reserved by chrome://tracing
+	Cname string `json:"cname,omitempty"`
+}
+
+// TraceEventFile is a trace in the JSON object form of the Trace Event Format
+type TraceEventFile struct {
+	TraceEvents     []TraceEvent           `json:"traceEvents"`
+	DisplayTimeUnit string                 `json:"displayTimeUnit,omitempty"`
+	OtherData       map[string]interface{} `json:"otherData,omitempty"`
+}
+
+// Write writes the trace as JSON
+func (f *TraceEventFile) Write(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(f)
+}
+
+// ExportTraceEvents converts an execution trace to the Trace Event Format,
+// with one complete event per step that spans the steps nested in it. Steps
+// are nested by call depth, as in the viewer. core_metrics steps are exported
+// as counters.
+func ExportTraceEvents(t *ExecutionTrace, timeline Timeline) (*TraceEventFile, error) {
+	var clock []uint64
+	switch timeline {
+	case TimelineSteps, "":
+		timeline = TimelineSteps
+		clock = stepClock(t)
+	case TimelineCPU:
+		var recorded bool
+		clock, recorded = meterClock(t, func(s *ExecutionState) uint64 { return s.CPUInstructions })
+		if !recorded {
+			return nil, fmt.Errorf("trace has no CPU instruction counts, use the %s timeline", TimelineSteps)
+		}
+	default:
+		return nil, fmt.Errorf("unknown timeline %q, use %s or %s", timeline, TimelineSteps, TimelineCPU)
+	}
+
+	root, nodes := buildStepTree(t)
+	steps := stepIndex(nodes)
+	file := newTraceEventFile(fmt.Sprintf("erst %s", t.TransactionHash), timeline)
+	file.OtherData["transaction_hash"] = t.TransactionHash
+
+	var visit func(node *TraceNode) int
+	// visit adds the event of node, before the events nested in it, and
+	// returns the position after its subtree
+	visit = func(node *TraceNode) int {
+		step, isStep := steps[node]
+		var state *ExecutionState
+		if isStep {
+			state = &t.States[step]
+			if state.Operation == string(diagnostic.KindCoreMetrics) {
+				if counter, ok := metricEvent(state, clock[step]); ok {
+					file.TraceEvents = append(file.TraceEvents, counter)
+				}
+			}
+		}
+		i := len(file.TraceEvents)
+		file.TraceEvents = append(file.TraceEvents, TraceEvent{})
+
+		from := 0
+		if isStep {
+			from = step + 1
+		}
+		to := from
+		for _, child := range node.Children {
+			to = visit(child)
+		}
+
+		if !isStep {
+			file.TraceEvents[i] = completeEvent(node, clock[0], clock[to], nil)
+			return to
+		}
+		args := map[string]interface{}{"step": step}
+		if state.CPUInstructions > 0 {
+			args["cpu_instructions"] = state.CPUInstructions
+		}
+		file.TraceEvents[i] = completeEvent(node, clock[step], clock[to], args)
+		return to
+	}
+	visit(root)
+	return file, nil
+}
+
+// ExportTreeTraceEvents converts a trace tree, such as the one built by
+// ParseSimulationResponse, to the Trace Event Format. Trees carry no resource
+// counts, so every node takes one unit of time in depth-first order.
+func ExportTreeTraceEvents(root *TraceNode) *TraceEventFile {
+	file := newTraceEventFile("erst", TimelineSteps)
+
+	var visit func(node *TraceNode, from uint64) uint64
+	visit = func(node *TraceNode, from uint64) uint64 {
+		i := len(file.TraceEvents)
+		file.TraceEvents = append(file.TraceEvents, TraceEvent{})
+		to := from + 1
+		for _, child := range node.Children {
+			to = visit(child, to)
+		}
+		file.TraceEvents[i] = completeEvent(node, from, to, nil)
+		return to
+	}
+	if root != nil {
+		visit(root, 0)
+	}
+	return file
+}
+
+// stepClock gives every step one unit of time: clock[i] is the time at which
+// step i starts, clock[len(States)] the end of the trace
+func stepClock(t *ExecutionTrace) []uint64 {
+	clock := make([]uint64, len(t.States)+1)
+	for i := range clock {
+		clock[i] = uint64(i)
+	}
+	return clock
+}
+
+// meterClock places every step at a meter reading, such as the CPU
+// instructions metered when it ran. Steps without a reading start when the
+// previous one did, and the trace ends at the last reading. It reports whether
+// any step had a reading.
+func meterClock(t *ExecutionTrace, reading func(*ExecutionState) uint64) ([]uint64, bool) {
+	clock := make([]uint64, len(t.States)+1)
+	recorded := false
+	for i := range t.States {
+		clock[i] = reading(&t.States[i])
+		recorded = recorded || clock[i] > 0
+		if i > 0 {
+			clock[i] = max(clock[i], clock[i-1])
+		}
+	}
+	if n := len(t.States); n > 0 {
+		clock[n] = clock[n-1]
+	}
+	return clock, recorded
+}
+
+func newTraceEventFile(processName string, timeline Timeline) *TraceEventFile {
+	return &TraceEventFile{
+		TraceEvents: []TraceEvent{
+			{Name: "process_name", Ph: "M", Pid: traceEventPid, Tid: traceEventTid, Args: map[string]interface{}{"name": processName}},
+			{Name: "thread_name", Ph: "M", Pid: traceEventPid, Tid: traceEventTid, Args: map[string]interface{}{"name": "calls"}},
+		},
+		OtherData: map[string]interface{}{"timeline": string(timeline)},
+	}
+}
+
+// completeEvent is the "X" event of a call frame from ts to end, carrying its
+// decoded arguments, return value and error
+func completeEvent(node *TraceNode, ts, end uint64, args map[string]interface{}) TraceEvent {
+	if args == nil {
+		args = map[string]interface{}{}
+	}
+	if node.ContractID != "" {
+		args["contract"] = node.ContractID
+	}
+	if len(node.Args) > 0 {
+		args["args"] = node.Args
+	}
+	if node.ReturnValue != "" {
+		args["return"] = node.ReturnValue
+	}
+	if node.Error != "" {
+		args["error"] = node.Error
+	}
+	if node.EventData != "" && node.Function == "" {
+		args["data"] = node.EventData
+	}
+
+	dur := end - ts
+	event := TraceEvent{
+		Name: eventName(node),
+		Cat:  node.Type,
+		Ph:   "X",
+		Ts:   ts,
+		Dur:  &dur,
+		Pid:  traceEventPid,
+		Tid:  traceEventTid,
+		Args: args,
+	}
+	if node.Failed {
+		event.Cname = "terrible"
+	}
+	return event
+}
+
+func eventName(node *TraceNode) string {
+	switch {
+	case node.Function != "":
+		return node.Function
+	case node.Type != "":
+		return node.Type
+	}
+	return node.ID
+}
+
+// metricEvent turns a core_metrics step, whose arguments are the topics
+// ["core_metrics", metric] and whose return value is the count, into a counter
+func metricEvent(state *ExecutionState, ts uint64) (TraceEvent, bool) {
+	if len(state.Arguments) < 2 {
+		return TraceEvent{}, false
+	}
+	value, err := strconv.ParseUint(fmt.Sprint(state.ReturnValue), 10, 64)
+	if err != nil {
+		return TraceEvent{}, false
+	}
+	name := fmt.Sprint(state.Arguments[1])
+	return TraceEvent{
+		Name: name,
+		Ph:   "C",
+		Ts:   ts,
+		Pid:  traceEventPid,
+		Tid:  traceEventTid,
+		Args: map[string]interface{}{name: value},
+	}, true
+}
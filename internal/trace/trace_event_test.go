@@ -0,0 +1,190 @@
+// Copyright (c) 2026 dotandev
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/dotandev/hintents/internal/diagnostic"
+	"github.com/stellar/go/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// completeEvents returns the "X" events of f by name
+func completeEvents(f *TraceEventFile) map[string]TraceEvent {
+	events := make(map[string]TraceEvent)
+	for _, event := range f.TraceEvents {
+		if event.Ph == "X" {
+			events[event.Name] = event
+		}
+	}
+	return events
+}
+
+func exportTestTrace() *ExecutionTrace {
+	trace := NewExecutionTrace("export-tx", 10)
+	trace.AddState(ExecutionState{Operation: "fn_call", ContractID: testContractID, Function: "transfer", Arguments: []interface{}{"alice", "100"}, CPUInstructions: 1000})
+	trace.AddState(ExecutionState{Operation: "fn_call", Depth: 1, ContractID: testContractID, Function: "get_balance", CPUInstructions: 1500})
+	trace.AddState(ExecutionState{Operation: "fn_return", Depth: 2, ReturnValue: "500", CPUInstructions: 4000})
+	trace.AddState(ExecutionState{Operation: "fn_call", Depth: 1, ContractID: testContractID, Function: "burn", Error: "insufficient balance", CPUInstructions: 4500})
+	trace.AddState(ExecutionState{Operation: "log", CPUInstructions: 9000})
+	return trace
+}
+
+func TestExportTraceEvents_Steps(t *testing.T) {
+	file, err := ExportTraceEvents(exportTestTrace(), TimelineSteps)
+	require.NoError(t, err)
+
+	events := completeEvents(file)
+	// The transaction spans every step, a call the steps nested in it
+	assert.Equal(t, uint64(0), events["transaction"].Ts)
+	assert.Equal(t, uint64(5), *events["transaction"].Dur)
+	assert.Equal(t, uint64(0), events["transfer"].Ts)
+	assert.Equal(t, uint64(4), *events["transfer"].Dur)
+	assert.Equal(t, uint64(1), events["get_balance"].Ts)
+	assert.Equal(t, uint64(2), *events["get_balance"].Dur)
+	assert.Equal(t, uint64(4), events["log"].Ts)
+	assert.Equal(t, uint64(1), *events["log"].Dur)
+
+	transfer := events["transfer"]
+	assert.Equal(t, "fn_call", transfer.Cat)
+	assert.Equal(t, []string{"alice", "100"}, transfer.Args["args"])
+	assert.Equal(t, testContractID, transfer.Args["contract"])
+	assert.Equal(t, 0, transfer.Args["step"])
+
+	burn := events["burn"]
+	assert.Equal(t, "insufficient balance", burn.Args["error"])
+	assert.Equal(t, "terrible", burn.Cname)
+
+	// Parents come before the events nested in them
+	var names []string
+	for _, event := range file.TraceEvents {
+		if event.Ph == "X" {
+			names = append(names, event.Name)
+		}
+	}
+	assert.Equal(t, []string{"transaction", "transfer", "get_balance", "fn_return", "burn", "log"}, names)
+	assert.Equal(t, "steps", file.OtherData["timeline"])
+}
+
+func TestExportTraceEvents_CPU(t *testing.T) {
+	file, err := ExportTraceEvents(exportTestTrace(), TimelineCPU)
+	require.NoError(t, err)
+
+	events := completeEvents(file)
+	assert.Equal(t, uint64(1000), events["transfer"].Ts)
+	assert.Equal(t, uint64(8000), *events["transfer"].Dur)
+	assert.Equal(t, uint64(1500), events["get_balance"].Ts)
+	assert.Equal(t, uint64(3000), *events["get_balance"].Dur)
+	assert.Equal(t, uint64(1500), events["get_balance"].Args["cpu_instructions"])
+
+	// Steps without a count do not move the clock back
+	trace := NewExecutionTrace("partial", 10)
+	trace.AddState(ExecutionState{Operation: "a", CPUInstructions: 100})
+	trace.AddState(ExecutionState{Operation: "b"})
+	trace.AddState(ExecutionState{Operation: "c", CPUInstructions: 300})
+	file, err = ExportTraceEvents(trace, TimelineCPU)
+	require.NoError(t, err)
+	events = completeEvents(file)
+	assert.Equal(t, uint64(100), events["b"].Ts)
+	assert.Equal(t, uint64(200), *events["b"].Dur)
+}
+
+func TestExportTraceEvents_Errors(t *testing.T) {
+	trace := NewExecutionTrace("no-cpu", 10)
+	trace.AddState(ExecutionState{Operation: "a"})
+
+	_, err := ExportTraceEvents(trace, TimelineCPU)
+	assert.ErrorContains(t, err, "no CPU instruction counts")
+
+	_, err = ExportTraceEvents(trace, "wallclock")
+	assert.ErrorContains(t, err, "unknown timeline")
+}
+
+func TestExportTraceEvents_CoreMetrics(t *testing.T) {
+	cpu := xdr.Uint64(123456)
+	metrics := diagnostic.New(diagnostic.TypeDiagnostic, "",
+		[]xdr.ScVal{symbol("core_metrics"), symbol("cpu_insn")},
+		xdr.ScVal{Type: xdr.ScValTypeScvU64, U64: &cpu})
+
+	trace := NewExecutionTrace("metrics", 10)
+	for _, state := range ExecutionStates(&SimulationResponse{Events: []diagnostic.Event{
+		fnCallEvent("", testContractID, "transfer", u32(1)),
+		metrics,
+	}}) {
+		trace.AddState(state)
+	}
+
+	file, err := ExportTraceEvents(trace, TimelineSteps)
+	require.NoError(t, err)
+
+	var counters []TraceEvent
+	for _, event := range file.TraceEvents {
+		if event.Ph == "C" {
+			counters = append(counters, event)
+		}
+	}
+	require.Len(t, counters, 1)
+	assert.Equal(t, "cpu_insn", counters[0].Name)
+	assert.Equal(t, uint64(1), counters[0].Ts)
+	assert.Equal(t, uint64(123456), counters[0].Args["cpu_insn"])
+}
+
+func TestExportTreeTraceEvents(t *testing.T) {
+	root, err := ParseSimulationResponse(&SimulationResponse{
+		Status: "success",
+		Events: []diagnostic.Event{
+			fnCallEvent("", testContractID, "transfer", u32(100)),
+			fnCallEvent(testContractID, testContractID, "get_balance"),
+			fnReturnEvent(testContractID, "get_balance", u32(500)),
+			fnReturnEvent(testContractID, "transfer", u32(1)),
+		},
+	})
+	require.NoError(t, err)
+
+	file := ExportTreeTraceEvents(root)
+
+	events := completeEvents(file)
+	assert.Equal(t, uint64(3), *events["simulation"].Dur)
+	assert.Equal(t, uint64(1), events["transfer"].Ts)
+	assert.Equal(t, uint64(2), *events["transfer"].Dur)
+	assert.Equal(t, "1", events["transfer"].Args["return"])
+	assert.Equal(t, "500", events["get_balance"].Args["return"])
+}
+
+func TestTraceEventFile_Write(t *testing.T) {
+	file, err := ExportTraceEvents(exportTestTrace(), TimelineSteps)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, file.Write(&buf))
+
+	var decoded struct {
+		TraceEvents []map[string]interface{} `json:"traceEvents"`
+	}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	require.NotEmpty(t, decoded.TraceEvents)
+	assert.Equal(t, "M", decoded.TraceEvents[0]["ph"])
+	for _, event := range decoded.TraceEvents {
+		if event["ph"] == "X" {
+			assert.Contains(t, event, "dur")
+		} else {
+			assert.NotContains(t, event, "dur")
+		}
+	}
+}
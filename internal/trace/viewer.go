@@ -15,371 +15,533 @@
 package trace
 
 import (
-	"bufio"
 	"fmt"
+	"io"
 	"os"
 	"strconv"
 	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
 )
 
-// InteractiveViewer provides a terminal-based interactive trace navigation interface
+// InteractiveViewer is a full-screen terminal UI over an execution trace. It
+// shows the call tree next to the state of the selected step, and supports
+// incremental search across the tree and breakpoints.
 type InteractiveViewer struct {
-	trace  *ExecutionTrace
-	reader *bufio.Reader
+	trace       *ExecutionTrace
+	input       io.Reader
+	output      io.Writer
+	breakpoints *BreakpointSet
+	save        func(*BreakpointSet) error
 }
 
 // NewInteractiveViewer creates a new interactive trace viewer
 func NewInteractiveViewer(trace *ExecutionTrace) *InteractiveViewer {
 	return &InteractiveViewer{
-		trace:  trace,
-		reader: bufio.NewReader(os.Stdin),
+		trace:       trace,
+		input:       os.Stdin,
+		output:      os.Stdout,
+		breakpoints: NewBreakpointSet(),
 	}
 }
 
-// Start begins the interactive trace viewing session
+// UseBreakpoints starts the viewer with the given breakpoints. save, when not
+// nil, is called with the set every time the user changes it.
+func (v *InteractiveViewer) UseBreakpoints(breakpoints *BreakpointSet, save func(*BreakpointSet) error) {
+	v.breakpoints = breakpoints
+	v.save = save
+}
+
+// Start runs the viewer until the user quits
 func (v *InteractiveViewer) Start() error {
-	fmt.Println("🔍 ERST Interactive Trace Viewer")
-	fmt.Println("=================================")
-	fmt.Printf("Transaction: %s
-", v.trace.TransactionHash)
-	fmt.Printf("Total Steps: %d
-
-", len(v.trace.States))
-
-	v.showHelp()
-	v.displayCurrentState()
-
-	for {
-		fmt.Print("
-> ")
-		input, err := v.reader.ReadString('
-')
-		if err != nil {
-			return fmt.Errorf("failed to read input: %w", err)
-		}
+	m := newViewerModel(v.trace)
+	m.breakpoints = v.breakpoints
+	m.save = v.save
+	program := tea.NewProgram(m,
+		tea.WithAltScreen(),
+		tea.WithInput(v.input),
+		tea.WithOutput(v.output),
+	)
+	if _, err := program.Run(); err != nil {
+		return fmt.Errorf("trace viewer failed: %w", err)
+	}
+	return nil
+}
 
-		command := strings.TrimSpace(input)
-		if command == "" {
-			continue
-		}
+// viewerMode tells whether keys drive navigation or edit the search query or
+// a command
+type viewerMode int
 
-		if v.handleCommand(command) {
-			break // Exit requested
+const (
+	modeBrowse viewerMode = iota
+	modeSearch
+	modeCommand
+)
+
+// Default screen size, until the terminal reports its own
+const (
+	defaultViewerWidth  = 100
+	defaultViewerHeight = 30
+)
+
+// viewerModel is the bubbletea model behind InteractiveViewer
+type viewerModel struct {
+	trace *ExecutionTrace
+	root  *TraceNode
+	// nodes holds the tree node of every step, steps the reverse mapping
+	nodes []*TraceNode
+	steps map[*TraceNode]int
+
+	search  *SearchEngine
+	visible []*TraceNode
+	cursor  int
+	offset  int
+
+	breakpoints *BreakpointSet
+	save        func(*BreakpointSet) error
+
+	width   int
+	height  int
+	mode    viewerMode
+	query   string
+	command string
+	status  string
+	// message is a status that is not an error
+	message         string
+	help            bool
+	showBreakpoints bool
+}
+
+func newViewerModel(trace *ExecutionTrace) *viewerModel {
+	m := &viewerModel{
+		trace:       trace,
+		search:      NewSearchEngine(),
+		breakpoints: NewBreakpointSet(),
+		width:       defaultViewerWidth,
+		height:      defaultViewerHeight,
+	}
+	m.root, m.nodes = buildStepTree(trace)
+	m.steps = make(map[*TraceNode]int, len(m.nodes))
+	for step, node := range m.nodes {
+		m.steps[node] = step
+	}
+	m.refresh()
+	if len(m.nodes) > 0 {
+		m.selectStep(trace.CurrentStep)
+	}
+	return m
+}
+
+// buildStepTree turns the steps of a trace into a call tree. A step is nested
+// under the last step one level up, so steps without a depth are siblings.
+func buildStepTree(trace *ExecutionTrace) (*TraceNode, []*TraceNode) {
+	root := NewTraceNode("root", "transaction")
+	root.EventData = fmt.Sprintf("Transaction: %s", trace.TransactionHash)
+
+	nodes := make([]*TraceNode, len(trace.States))
+	parents := []*TraceNode{root}
+	for i := range trace.States {
+		state := &trace.States[i]
+
+		node := NewTraceNode(fmt.Sprintf("step-%d", i), state.Operation)
+		node.ContractID = state.ContractID
+		node.Function = state.Function
+		node.Error = state.Error
+		node.Failed = state.Error != ""
+		for _, arg := range state.Arguments {
+			node.Args = append(node.Args, fmt.Sprint(arg))
+		}
+		if state.ReturnValue != nil {
+			node.ReturnValue = fmt.Sprint(state.ReturnValue)
 		}
+
+		depth := min(max(state.Depth, 0), len(parents)-1)
+		parents = parents[:depth+1]
+		parents[depth].AddChild(node)
+		parents = append(parents, node)
+		nodes[i] = node
 	}
+	return root, nodes
+}
 
+// Init implements tea.Model
+func (m *viewerModel) Init() tea.Cmd {
 	return nil
 }
 
-// handleCommand processes user commands and returns true if exit is requested
-func (v *InteractiveViewer) handleCommand(command string) bool {
-	parts := strings.Fields(command)
-	if len(parts) == 0 {
-		return false
-	}
-
-	cmd := strings.ToLower(parts[0])
-
-	switch cmd {
-	case "n", "next", "forward":
-		v.stepForward()
-	case "p", "prev", "back", "backward":
-		v.stepBackward()
-	case "j", "jump":
-		if len(parts) > 1 {
-			v.jumpToStep(parts[1])
-		} else {
-			fmt.Println("Usage: jump <step_number>")
+// Update implements tea.Model
+func (m *viewerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		m.scroll()
+	case tea.KeyMsg:
+		switch m.mode {
+		case modeSearch:
+			return m, m.updateSearch(msg)
+		case modeCommand:
+			return m, m.updateCommand(msg)
 		}
-	case "s", "show", "state":
-		v.displayCurrentState()
-	case "r", "reconstruct":
-		if len(parts) > 1 {
-			v.reconstructState(parts[1])
-		} else {
-			v.reconstructCurrentState()
+		return m, m.updateBrowse(msg)
+	}
+	return m, nil
+}
+
+func (m *viewerModel) updateBrowse(msg tea.KeyMsg) tea.Cmd {
+	m.status, m.message = "", ""
+	switch msg.String() {
+	case "q", "ctrl+c":
+		return tea.Quit
+	case "up", "k":
+		m.moveTo(m.cursor - 1)
+	case "down", "j":
+		m.moveTo(m.cursor + 1)
+	case "pgup":
+		m.moveTo(m.cursor - m.bodyHeight())
+	case "pgdown":
+		m.moveTo(m.cursor + m.bodyHeight())
+	case "home", "g":
+		m.moveTo(0)
+	case "end", "G":
+		m.moveTo(len(m.visible) - 1)
+	case "enter", " ":
+		if node := m.selected(); !node.IsLeaf() {
+			node.ToggleExpanded()
+			m.refresh()
 		}
-	case "i", "info":
-		v.showNavigationInfo()
-	case "l", "list":
-		if len(parts) > 1 {
-			v.listSteps(parts[1])
-		} else {
-			v.listSteps("10")
+	case "e":
+		m.root.ExpandAll()
+		m.refresh()
+	case "c":
+		m.root.CollapseAll()
+		m.root.Expanded = true
+		m.refresh()
+	case "right", "l":
+		if _, err := m.trace.StepForward(); err != nil {
+			m.status = err.Error()
+			break
 		}
-	case "h", "help":
-		v.showHelp()
-	case "q", "quit", "exit":
-		fmt.Println("Goodbye! 👋")
-		return true
-	default:
-		fmt.Printf("Unknown command: %s. Type 'help' for available commands.
-", cmd)
+		m.selectStep(m.trace.CurrentStep)
+	case "left", "h":
+		if _, err := m.trace.StepBackward(); err != nil {
+			m.status = err.Error()
+			break
+		}
+		m.selectStep(m.trace.CurrentStep)
+	case ">":
+		m.runCommand("continue")
+	case "<":
+		m.runCommand("reverse-continue")
+	case "b":
+		m.toggleBreakpoint()
+	case "B":
+		m.showBreakpoints = !m.showBreakpoints
+	case ":":
+		m.mode = modeCommand
+		m.command = ""
+	case "/":
+		m.mode = modeSearch
+		m.query = ""
+	case "n":
+		m.showMatch(m.search.NextMatch())
+	case "N":
+		m.showMatch(m.search.PreviousMatch())
+	case "esc":
+		m.query = ""
+		m.search.SetQuery("")
+	case "?":
+		m.help = !m.help
 	}
-
-	return false
+	return nil
 }
 
-// stepForward moves to the next step
-func (v *InteractiveViewer) stepForward() {
-	state, err := v.trace.StepForward()
-	if err != nil {
-		fmt.Printf("❌ %s
-", err)
-		return
+// updateSearch edits the query, searching again on every keystroke
+func (m *viewerModel) updateSearch(msg tea.KeyMsg) tea.Cmd {
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		return tea.Quit
+	case tea.KeyEnter:
+		m.mode = modeBrowse
+		return nil
+	case tea.KeyEsc:
+		m.mode = modeBrowse
+		m.query = ""
+		m.search.SetQuery("")
+		return nil
 	}
 
-	fmt.Printf("➡️  Stepped forward to step %d
-", state.Step)
-	v.displayCurrentState()
+	query, edited := editLine(m.query, msg)
+	if !edited {
+		return nil
+	}
+	m.query = query
+	m.search.SetQuery(m.query)
+	m.search.Search(m.root.FlattenAll())
+	m.showMatch(m.search.CurrentMatch())
+	return nil
 }
 
-// stepBackward moves to the previous step
-func (v *InteractiveViewer) stepBackward() {
-	state, err := v.trace.StepBackward()
-	if err != nil {
-		fmt.Printf("❌ %s
-", err)
-		return
+// updateCommand edits the command line, and runs it on Enter
+func (m *viewerModel) updateCommand(msg tea.KeyMsg) tea.Cmd {
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		return tea.Quit
+	case tea.KeyEnter:
+		m.mode = modeBrowse
+		return m.runCommand(m.command)
+	case tea.KeyEsc:
+		m.mode = modeBrowse
+		return nil
 	}
-
-	fmt.Printf("⬅️  Stepped backward to step %d
-", state.Step)
-	v.displayCurrentState()
+	m.command, _ = editLine(m.command, msg)
+	return nil
 }
 
-// jumpToStep jumps to a specific step
-func (v *InteractiveViewer) jumpToStep(stepStr string) {
-	step, err := strconv.Atoi(stepStr)
-	if err != nil {
-		fmt.Printf("❌ Invalid step number: %s
-", stepStr)
-		return
+// editLine applies a key typed into a line of text, and reports whether the
+// text changed
+func editLine(text string, msg tea.KeyMsg) (string, bool) {
+	switch msg.Type {
+	case tea.KeyBackspace:
+		if text == "" {
+			return text, false
+		}
+		runes := []rune(text)
+		return string(runes[:len(runes)-1]), true
+	case tea.KeyRunes, tea.KeySpace:
+		return text + string(msg.Runes), true
 	}
+	return text, false
+}
 
-	state, err := v.trace.JumpToStep(step)
-	if err != nil {
-		fmt.Printf("❌ %s
-", err)
-		return
+// runCommand runs a command typed after ":"
+func (m *viewerModel) runCommand(command string) tea.Cmd {
+	m.status, m.message = "", ""
+	fields := strings.Fields(command)
+	if len(fields) == 0 {
+		return nil
 	}
+	args := fields[1:]
 
-	fmt.Printf("🎯 Jumped to step %d
-", state.Step)
-	v.displayCurrentState()
+	switch fields[0] {
+	case "break", "b":
+		bp, err := ParseBreakpoint(strings.Join(args, " "))
+		if err != nil {
+			m.status = err.Error()
+			break
+		}
+		m.addBreakpoint(bp)
+	case "delete", "d":
+		if len(args) == 0 {
+			m.breakpoints.Breakpoints = m.breakpoints.Breakpoints[:0]
+			m.message = "Deleted all breakpoints"
+			m.saveBreakpoints()
+			break
+		}
+		m.eachBreakpoint(args, m.breakpoints.Remove, "Deleted")
+	case "enable":
+		m.eachBreakpoint(args, func(id int) error { return m.breakpoints.SetEnabled(id, true) }, "Enabled")
+	case "disable":
+		m.eachBreakpoint(args, func(id int) error { return m.breakpoints.SetEnabled(id, false) }, "Disabled")
+	case "breakpoints", "bl":
+		m.showBreakpoints = true
+	case "continue", "c":
+		m.continueTo(m.trace.Continue, "last")
+	case "reverse-continue", "rc":
+		m.continueTo(m.trace.ReverseContinue, "first")
+	case "quit", "q":
+		return tea.Quit
+	default:
+		m.status = fmt.Sprintf("Unknown command %q, see ? for the list", fields[0])
+	}
+	return nil
 }
 
-// displayCurrentState shows the current execution state
-func (v *InteractiveViewer) displayCurrentState() {
-	state, err := v.trace.GetCurrentState()
-	if err != nil {
-		fmt.Printf("❌ %s
-", err)
+// continueTo runs Continue or ReverseContinue and moves the cursor to the step
+// it stopped on
+func (m *viewerModel) continueTo(run func(*BreakpointSet) (*ExecutionState, *Breakpoint, error), end string) {
+	_, bp, err := run(m.breakpoints)
+	switch {
+	case err != nil:
+		m.status = err.Error()
 		return
+	case bp != nil:
+		m.message = fmt.Sprintf("Breakpoint %d hit at step %d: %s", bp.ID, m.trace.CurrentStep, bp)
+	default:
+		m.message = fmt.Sprintf("No breakpoint hit, stopped at the %s step", end)
 	}
+	m.selectStep(m.trace.CurrentStep)
+}
 
-	fmt.Println("
-📍 Current State")
-	fmt.Println("================")
-	fmt.Printf("Step: %d/%d
-", state.Step, len(v.trace.States)-1)
-	fmt.Printf("Time: %s
-", state.Timestamp.Format("15:04:05.000"))
-	fmt.Printf("Operation: %s
-", state.Operation)
-
-	if state.ContractID != "" {
-		fmt.Printf("Contract: %s
-", state.ContractID)
-	}
-	if state.Function != "" {
-		fmt.Printf("Function: %s
-", state.Function)
-	}
-	if len(state.Arguments) > 0 {
-		fmt.Printf("Arguments: %v
-", state.Arguments)
-	}
-	if state.ReturnValue != nil {
-		fmt.Printf("Return: %v
-", state.ReturnValue)
-	}
-	if state.Error != "" {
-		fmt.Printf("❌ Error: %s
-", state.Error)
-	}
-
-	// Show memory/state summary
-	if len(state.HostState) > 0 {
-		fmt.Printf("Host State: %d entries
-", len(state.HostState))
+// toggleBreakpoint adds a breakpoint on the contract and function of the
+// selected step, or deletes it if there is one already
+func (m *viewerModel) toggleBreakpoint() {
+	node := m.selected()
+	if _, ok := m.steps[node]; !ok || (node.ContractID == "" && node.Function == "") {
+		m.status = "Select a contract call to break on"
+		return
 	}
-	if len(state.Memory) > 0 {
-		fmt.Printf("Memory: %d entries
-", len(state.Memory))
+	bp := Breakpoint{ContractID: node.ContractID, Function: node.Function}
+	if existing, ok := m.breakpoints.Find(bp); ok {
+		_ = m.breakpoints.Remove(existing.ID)
+		m.message = fmt.Sprintf("Deleted breakpoint %d", existing.ID)
+		m.saveBreakpoints()
+		return
 	}
+	m.addBreakpoint(bp)
 }
 
-// reconstructCurrentState reconstructs and displays the current state
-func (v *InteractiveViewer) reconstructCurrentState() {
-	state, err := v.trace.ReconstructStateAt(v.trace.CurrentStep)
+func (m *viewerModel) addBreakpoint(bp Breakpoint) {
+	bp, err := m.breakpoints.Add(bp)
 	if err != nil {
-		fmt.Printf("❌ Failed to reconstruct state: %s
-", err)
+		m.status = err.Error()
 		return
 	}
-
-	fmt.Println("
-🔧 Reconstructed State")
-	fmt.Println("======================")
-	v.displayState(state)
+	m.message = fmt.Sprintf("Breakpoint %d: %s", bp.ID, bp)
+	m.saveBreakpoints()
 }
 
-// reconstructState reconstructs and displays state at a specific step
-func (v *InteractiveViewer) reconstructState(stepStr string) {
-	step, err := strconv.Atoi(stepStr)
-	if err != nil {
-		fmt.Printf("❌ Invalid step number: %s
-", stepStr)
+// eachBreakpoint applies change to the breakpoints whose IDs are listed
+func (m *viewerModel) eachBreakpoint(args []string, change func(id int) error, done string) {
+	if len(args) == 0 {
+		m.status = "Give the number of a breakpoint"
 		return
 	}
+	for _, arg := range args {
+		id, err := strconv.Atoi(arg)
+		if err == nil {
+			err = change(id)
+		}
+		if err != nil {
+			m.status = fmt.Sprintf("%s: %v", arg, err)
+			return
+		}
+	}
+	m.message = fmt.Sprintf("%s breakpoint %s", done, strings.Join(args, ", "))
+	m.saveBreakpoints()
+}
 
-	state, err := v.trace.ReconstructStateAt(step)
-	if err != nil {
-		fmt.Printf("❌ Failed to reconstruct state: %s
-", err)
+// saveBreakpoints persists the breakpoints after a change
+func (m *viewerModel) saveBreakpoints() {
+	if m.save == nil {
 		return
 	}
-
-	fmt.Printf("
-🔧 Reconstructed State at Step %d
-", step)
-	fmt.Println("==================================")
-	v.displayState(state)
+	if err := m.save(m.breakpoints); err != nil {
+		m.status = fmt.Sprintf("failed to save breakpoints: %v", err)
+	}
 }
 
-// displayState displays a complete state
-func (v *InteractiveViewer) displayState(state *ExecutionState) {
-	fmt.Printf("Step: %d
-", state.Step)
-	fmt.Printf("Time: %s
-", state.Timestamp.Format("15:04:05.000"))
-	fmt.Printf("Operation: %s
-", state.Operation)
-
-	if state.ContractID != "" {
-		fmt.Printf("Contract: %s
-", state.ContractID)
-	}
-	if state.Function != "" {
-		fmt.Printf("Function: %s
-", state.Function)
-	}
-
-	if len(state.HostState) > 0 {
-		fmt.Println("
-Host State:")
-		for k, v := range state.HostState {
-			fmt.Printf("  %s: %v
-", k, v)
-		}
+// refresh recomputes the visible nodes after the tree was expanded or
+// collapsed. The cursor stays on the same node, or moves to its closest
+// visible ancestor.
+func (m *viewerModel) refresh() {
+	var current *TraceNode
+	if m.cursor < len(m.visible) {
+		current = m.visible[m.cursor]
 	}
+	m.visible = m.root.Flatten()
 
-	if len(state.Memory) > 0 {
-		fmt.Println("
-Memory:")
-		for k, v := range state.Memory {
-			fmt.Printf("  %s: %v
-", k, v)
+	cursor := 0
+	for node := current; node != nil; node = node.Parent {
+		if i := m.indexOf(node); i >= 0 {
+			cursor = i
+			break
 		}
 	}
+	m.moveTo(cursor)
 }
 
-// showNavigationInfo displays navigation information
-func (v *InteractiveViewer) showNavigationInfo() {
-	info := v.trace.GetNavigationInfo()
-
-	fmt.Println("
-📊 Navigation Info")
-	fmt.Println("==================")
-	fmt.Printf("Total Steps: %d
-", info["total_steps"])
-	fmt.Printf("Current Step: %d
-", info["current_step"])
-	fmt.Printf("Can Step Back: %t
-", info["can_step_back"])
-	fmt.Printf("Can Step Forward: %t
-", info["can_step_forward"])
-	fmt.Printf("Snapshots: %d
-", info["snapshots_count"])
+// indexOf returns the position of node among the visible nodes, or -1
+func (m *viewerModel) indexOf(node *TraceNode) int {
+	for i, visible := range m.visible {
+		if visible == node {
+			return i
+		}
+	}
+	return -1
 }
 
-// listSteps shows a list of steps around the current position
-func (v *InteractiveViewer) listSteps(countStr string) {
-	count, err := strconv.Atoi(countStr)
-	if err != nil {
-		count = 10
+// moveTo puts the cursor on the i-th visible node and makes its step current
+func (m *viewerModel) moveTo(i int) {
+	m.cursor = min(max(i, 0), len(m.visible)-1)
+	m.scroll()
+	if step, ok := m.steps[m.selected()]; ok {
+		m.trace.CurrentStep = step
 	}
+}
 
-	current := v.trace.CurrentStep
-	start := max(0, current-count/2)
-	end := min(len(v.trace.States)-1, start+count-1)
+// reveal expands the ancestors of node and moves the cursor onto it
+func (m *viewerModel) reveal(node *TraceNode) {
+	for parent := node.Parent; parent != nil; parent = parent.Parent {
+		parent.Expanded = true
+	}
+	m.refresh()
+	if i := m.indexOf(node); i >= 0 {
+		m.moveTo(i)
+	}
+}
 
-	fmt.Printf("
-📋 Steps %d-%d
-", start, end)
-	fmt.Println("===============")
+func (m *viewerModel) selectStep(step int) {
+	if step >= 0 && step < len(m.nodes) {
+		m.reveal(m.nodes[step])
+	}
+}
 
-	for i := start; i <= end; i++ {
-		state := &v.trace.States[i]
-		marker := "  "
-		if i == current {
-			marker = "▶️"
-		}
+func (m *viewerModel) showMatch(match *TraceNodeMatch) {
+	if match != nil {
+		m.reveal(match.NodeData)
+	}
+}
 
-		fmt.Printf("%s %3d: %s", marker, i, state.Operation)
-		if state.Function != "" {
-			fmt.Printf(" (%s)", state.Function)
-		}
-		if state.Error != "" {
-			fmt.Printf(" ❌")
-		}
-		fmt.Println()
+// scroll keeps the cursor inside the tree pane
+func (m *viewerModel) scroll() {
+	height := m.bodyHeight()
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	}
+	if m.cursor >= m.offset+height {
+		m.offset = m.cursor - height + 1
 	}
+	m.offset = max(0, min(m.offset, len(m.visible)-height))
 }
 
-// showHelp displays available commands
-func (v *InteractiveViewer) showHelp() {
-	fmt.Println("
-📖 Available Commands")
-	fmt.Println("=====================")
-	fmt.Println("Navigation:")
-	fmt.Println("  n, next, forward     - Step forward")
-	fmt.Println("  p, prev, back        - Step backward")
-	fmt.Println("  j, jump <step>       - Jump to specific step")
-	fmt.Println()
-	fmt.Println("Display:")
-	fmt.Println("  s, show, state       - Show current state")
-	fmt.Println("  r, reconstruct [step] - Reconstruct state")
-	fmt.Println("  l, list [count]      - List steps (default: 10)")
-	fmt.Println("  i, info              - Show navigation info")
-	fmt.Println()
-	fmt.Println("Other:")
-	fmt.Println("  h, help              - Show this help")
-	fmt.Println("  q, quit, exit        - Exit viewer")
+func (m *viewerModel) selected() *TraceNode {
+	return m.visible[m.cursor]
 }
 
-// Helper functions
-func max(a, b int) int {
-	if a > b {
-		return a
-	}
-	return b
+// bodyHeight is the number of rows between the header and the status lines
+func (m *viewerModel) bodyHeight() int {
+	return max(m.height-3, 1)
 }
 
-func min(a, b int) int {
-	if a < b {
-		return a
-	}
-	return b
+// helpLines documents the key bindings, shown in the detail pane with ?
+var helpLines = []string{
+	"Navigation",
+	"  ↑/k ↓/j      move",
+	"  PgUp PgDn    scroll one page",
+	"  Home/g End/G first / last node",
+	"  Enter/Space  expand or collapse",
+	"  e / c        expand / collapse all",
+	"  ←/h →/l      previous / next step",
+	"",
+	"Breakpoints",
+	"  > / <        continue / reverse-continue",
+	"  b            break on the selected call",
+	"  B            list breakpoints",
+	"",
+	"Search",
+	"  /            search, as you type",
+	"  Enter        keep the results",
+	"  n / N        next / previous match",
+	"  Esc          clear the search",
+	"",
+	"Commands, after :",
+	"  break <conditions>   contract=<id> function=<name>",
+	"                       error host=<key> memory=<key>",
+	"  delete [n...]        delete breakpoints, or all",
+	"  enable / disable n   switch a breakpoint on / off",
+	"  continue / c         run to the next breakpoint",
+	"  reverse-continue/rc  run back to the previous one",
+	"",
+	"  ?            toggle this help",
+	"  q / Ctrl+C   quit",
 }
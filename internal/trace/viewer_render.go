@@ -0,0 +1,408 @@
+// Copyright (c) 2026 dotandev
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/dotandev/hintents/internal/decoder"
+)
+
+// Smallest screen the viewer lays its panes out on
+const (
+	minViewerWidth  = 40
+	minViewerHeight = 8
+)
+
+var (
+	titleStyle        = lipgloss.NewStyle().Bold(true)
+	dimStyle          = lipgloss.NewStyle().Faint(true)
+	cursorStyle       = lipgloss.NewStyle().Bold(true)
+	contractStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("6"))
+	functionStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("4"))
+	errorStyle        = lipgloss.NewStyle().Foreground(lipgloss.Color("1"))
+	matchStyle        = lipgloss.NewStyle().Background(lipgloss.Color("3")).Foreground(lipgloss.Color("0"))
+	currentMatchStyle = lipgloss.NewStyle().Background(lipgloss.Color("2")).Foreground(lipgloss.Color("0"))
+	addedStyle        = lipgloss.NewStyle().Foreground(lipgloss.Color("2"))
+	changedStyle      = lipgloss.NewStyle().Foreground(lipgloss.Color("3"))
+	removedStyle      = lipgloss.NewStyle().Foreground(lipgloss.Color("1"))
+	breakpointStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("1")).Bold(true)
+	plainStyle        = lipgloss.NewStyle()
+)
+
+const keyHints = "↑↓ move  ⏎ expand  ←→ step  <> continue  b break  / search  : command  ? help  q quit"
+
+// View implements tea.Model. The screen is a header, the tree and detail
+// panes side by side, a status line and the key hints.
+func (m *viewerModel) View() string {
+	if m.width < minViewerWidth || m.height < minViewerHeight {
+		return fmt.Sprintf("Terminal too small (%dx%d), the trace viewer needs %dx%d",
+			m.width, m.height, minViewerWidth, minViewerHeight)
+	}
+
+	treeWidth := (m.width - 3) * 11 / 20
+	detailWidth := m.width - 3 - treeWidth
+	height := m.bodyHeight()
+	tree := m.treeLines(treeWidth, height)
+	detail := m.detailLines()
+
+	lines := make([]string, 0, m.height)
+	lines = append(lines, fit(m.headerLine(), m.width))
+	for i := 0; i < height; i++ {
+		var left, right string
+		if i < len(tree) {
+			left = tree[i]
+		}
+		if i < len(detail) {
+			right = detail[i]
+		}
+		lines = append(lines, pad(left, treeWidth)+dimStyle.Render(" │ ")+fit(right, detailWidth))
+	}
+	lines = append(lines, fit(m.statusLine(), m.width), fit(dimStyle.Render(keyHints), m.width))
+	return strings.Join(lines, "\n")
+}
+
+func (m *viewerModel) headerLine() string {
+	position := "no steps"
+	if len(m.trace.States) > 0 {
+		position = fmt.Sprintf("step %d/%d", m.trace.CurrentStep, len(m.trace.States)-1)
+	}
+	return titleStyle.Render("erst trace") + "  " + m.trace.TransactionHash + "  " + dimStyle.Render(position)
+}
+
+func (m *viewerModel) statusLine() string {
+	switch {
+	case m.mode == modeSearch:
+		return fmt.Sprintf("/%s█  %s", m.query, dimStyle.Render(matchCount(m.search.MatchCount())))
+	case m.mode == modeCommand:
+		return ":" + m.command + "█"
+	case m.status != "":
+		return errorStyle.Render(m.status)
+	case m.message != "":
+		return m.message
+	case m.search.GetQuery() == "":
+		return ""
+	case m.search.MatchCount() == 0:
+		return fmt.Sprintf("No matches for %q", m.search.GetQuery())
+	default:
+		return fmt.Sprintf("Match %d of %d for %q", m.search.CurrentMatchNumber(), m.search.MatchCount(), m.search.GetQuery())
+	}
+}
+
+func matchCount(n int) string {
+	if n == 1 {
+		return "1 match"
+	}
+	return fmt.Sprintf("%d matches", n)
+}
+
+// -------------------- Tree pane --------------------
+
+func (m *viewerModel) treeLines(width, height int) []string {
+	end := min(len(m.visible), m.offset+height)
+	lines := make([]string, 0, end-m.offset)
+	for i := m.offset; i < end; i++ {
+		lines = append(lines, fit(m.treeLine(m.visible[i], i == m.cursor), width))
+	}
+	return lines
+}
+
+// treeLine renders a node as "> ▼ ● ✗ function(args) → return CDLZ…CYSC: error",
+// where ● marks the steps a breakpoint stops on
+func (m *viewerModel) treeLine(node *TraceNode, selected bool) string {
+	var sb strings.Builder
+	if selected {
+		sb.WriteString(cursorStyle.Render(">") + " ")
+	} else {
+		sb.WriteString("  ")
+	}
+	sb.WriteString(strings.Repeat("  ", node.Depth))
+	switch {
+	case node.IsLeaf():
+		sb.WriteString("  ")
+	case node.Expanded:
+		sb.WriteString("▼ ")
+	default:
+		sb.WriteString("▶ ")
+	}
+	if step, ok := m.steps[node]; ok {
+		if _, hit, _ := m.breakpoints.Match(m.trace, step); hit {
+			sb.WriteString(breakpointStyle.Render("●") + " ")
+		}
+	}
+	if node.Failed {
+		sb.WriteString(errorStyle.Render("✗") + " ")
+	}
+
+	switch {
+	case node.Function != "":
+		sb.WriteString(m.highlight(node, node.Function, "function", functionStyle))
+		sb.WriteString("(" + m.highlight(node, node.ArgsText(), "args", plainStyle) + ")")
+		if node.ReturnValue != "" {
+			sb.WriteString(" → " + m.highlight(node, node.ReturnValue, "return", plainStyle))
+		}
+	case node.EventData != "":
+		sb.WriteString(m.highlight(node, node.EventData, "event", plainStyle))
+	default:
+		sb.WriteString(m.highlight(node, node.Type, "type", plainStyle))
+	}
+
+	if node.ContractID != "" {
+		// The short form cannot show where the match is, so it is highlighted whole
+		style := contractStyle
+		if len(m.search.HighlightMatches(node, "contractID")) > 0 {
+			style = m.matchStyleFor(node)
+		}
+		sb.WriteString(" " + style.Render(shortContractID(node.ContractID)))
+	}
+	if node.Error != "" {
+		sb.WriteString(": " + m.highlight(node, node.Error, "error", errorStyle))
+	}
+	return sb.String()
+}
+
+// highlight renders text, one of the fields of node, with its search matches
+// picked out
+func (m *viewerModel) highlight(node *TraceNode, text, field string, base lipgloss.Style) string {
+	match := m.matchStyleFor(node)
+	var sb strings.Builder
+	pos := 0
+	for _, r := range m.search.HighlightMatches(node, field) {
+		if r.Start < pos || r.End > len(text) {
+			continue
+		}
+		sb.WriteString(render(base, text[pos:r.Start]))
+		sb.WriteString(render(match, text[r.Start:r.End]))
+		pos = r.End
+	}
+	sb.WriteString(render(base, text[pos:]))
+	return sb.String()
+}
+
+func (m *viewerModel) matchStyleFor(node *TraceNode) lipgloss.Style {
+	if current := m.search.CurrentMatch(); current != nil && current.NodeData == node {
+		return currentMatchStyle
+	}
+	return matchStyle
+}
+
+// shortContractID abbreviates a strkey to its first and last 4 characters
+func shortContractID(id string) string {
+	if len(id) <= 12 {
+		return id
+	}
+	return id[:4] + "…" + id[len(id)-4:]
+}
+
+// -------------------- Detail pane --------------------
+
+func (m *viewerModel) detailLines() []string {
+	if m.help {
+		return append([]string{titleStyle.Render("Keys")}, helpLines...)
+	}
+	if m.showBreakpoints {
+		return m.breakpointLines()
+	}
+	node := m.selected()
+	if step, ok := m.steps[node]; ok {
+		return m.stepLines(node, step)
+	}
+
+	failed := 0
+	for _, state := range m.trace.States {
+		if state.Error != "" {
+			failed++
+		}
+	}
+	lines := []string{
+		titleStyle.Render("Transaction"),
+		label("Hash", m.trace.TransactionHash),
+		label("Steps", fmt.Sprint(len(m.trace.States))),
+		label("Snapshots", fmt.Sprint(len(m.trace.Snapshots))),
+	}
+	if failed > 0 {
+		lines = append(lines, errorStyle.Render(fmt.Sprintf("%d failed step(s)", failed)))
+	}
+	return lines
+}
+
+// stepLines shows the reconstructed state at step, and how it differs from
+// the state at the previous step
+func (m *viewerModel) stepLines(node *TraceNode, step int) []string {
+	state, err := m.trace.ReconstructStateAt(step)
+	if err != nil {
+		return []string{errorStyle.Render(err.Error())}
+	}
+	before := &ExecutionState{}
+	if step > 0 {
+		if before, err = m.trace.ReconstructStateAt(step - 1); err != nil {
+			return []string{errorStyle.Render(err.Error())}
+		}
+	}
+
+	lines := []string{titleStyle.Render(fmt.Sprintf("Step %d/%d  %s", step, len(m.trace.States)-1, state.Operation))}
+	if state.ContractID != "" {
+		lines = append(lines, dimStyle.Render("Contract: ")+contractStyle.Render(state.ContractID))
+	}
+	if state.Function != "" {
+		lines = append(lines, dimStyle.Render("Function: ")+functionStyle.Render(state.Function))
+	}
+	if len(node.Args) > 0 {
+		lines = append(lines, label("Arguments", node.ArgsText()))
+	}
+	if node.ReturnValue != "" {
+		lines = append(lines, label("Return", node.ReturnValue))
+	}
+	if state.Error != "" {
+		lines = append(lines, errorStyle.Render("Error: "+state.Error))
+		if info, ok := decoder.ExplainErrorMessage(state.Error); ok {
+			lines = append(lines, label("Why", info.Explanation), label("Fix", info.Remediation))
+		}
+	}
+
+	lines = append(lines, "", titleStyle.Render("Host state"))
+	lines = append(lines, changeLines(diffStates(before.HostState, state.HostState))...)
+	lines = append(lines, "", titleStyle.Render("Memory"))
+	lines = append(lines, changeLines(diffStates(before.Memory, state.Memory))...)
+	return lines
+}
+
+// breakpointLines lists the breakpoints with the number of steps each stops on
+func (m *viewerModel) breakpointLines() []string {
+	lines := []string{titleStyle.Render("Breakpoints")}
+	if len(m.breakpoints.Breakpoints) == 0 {
+		return append(lines, dimStyle.Render("  (none, add one with b or :break)"))
+	}
+	for _, bp := range m.breakpoints.Breakpoints {
+		if bp.Disabled {
+			lines = append(lines, dimStyle.Render(fmt.Sprintf("○ %d  %s (disabled)", bp.ID, bp)))
+			continue
+		}
+		hits := 0
+		var err error
+		for step := range m.trace.States {
+			var hit bool
+			if hit, err = bp.Matches(m.trace, step); err != nil {
+				break
+			}
+			if hit {
+				hits++
+			}
+		}
+		if err != nil {
+			lines = append(lines, breakpointStyle.Render("●")+fmt.Sprintf(" %d  %s ", bp.ID, bp)+errorStyle.Render("("+err.Error()+")"))
+			continue
+		}
+		lines = append(lines, breakpointStyle.Render("●")+fmt.Sprintf(" %d  %s ", bp.ID, bp)+dimStyle.Render(fmt.Sprintf("(%d step(s))", hits)))
+	}
+	return lines
+}
+
+func label(name, value string) string {
+	return dimStyle.Render(name+": ") + value
+}
+
+// changeKind tells how a state entry changed between two steps
+type changeKind int
+
+const (
+	unchanged changeKind = iota
+	added
+	changed
+	removed
+)
+
+// stateChange is an entry of the host state or memory compared between two
+// steps
+type stateChange struct {
+	Key    string
+	Before interface{}
+	After  interface{}
+	Kind   changeKind
+}
+
+// diffStates compares two reconstructed state maps, sorted by key
+func diffStates(before, after map[string]interface{}) []stateChange {
+	keys := make([]string, 0, len(before)+len(after))
+	for key := range after {
+		keys = append(keys, key)
+	}
+	for key := range before {
+		if _, ok := after[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	changes := make([]stateChange, 0, len(keys))
+	for _, key := range keys {
+		old, hadOld := before[key]
+		value, hasValue := after[key]
+		change := stateChange{Key: key, Before: old, After: value}
+		switch {
+		case !hadOld:
+			change.Kind = added
+		case !hasValue:
+			change.Kind = removed
+		case fmt.Sprint(old) != fmt.Sprint(value):
+			change.Kind = changed
+		}
+		changes = append(changes, change)
+	}
+	return changes
+}
+
+func changeLines(changes []stateChange) []string {
+	if len(changes) == 0 {
+		return []string{dimStyle.Render("  (empty)")}
+	}
+	lines := make([]string, 0, len(changes))
+	for _, c := range changes {
+		switch c.Kind {
+		case added:
+			lines = append(lines, addedStyle.Render(fmt.Sprintf("+ %s: %v", c.Key, c.After)))
+		case changed:
+			lines = append(lines, changedStyle.Render(fmt.Sprintf("~ %s: %v → %v", c.Key, c.Before, c.After)))
+		case removed:
+			lines = append(lines, removedStyle.Render(fmt.Sprintf("- %s: %v", c.Key, c.Before)))
+		default:
+			lines = append(lines, dimStyle.Render(fmt.Sprintf("  %s: %v", c.Key, c.After)))
+		}
+	}
+	return lines
+}
+
+// -------------------- Layout helpers --------------------
+
+func render(style lipgloss.Style, text string) string {
+	if text == "" {
+		return ""
+	}
+	return style.Render(text)
+}
+
+// fit cuts a rendered line to width cells
+func fit(line string, width int) string {
+	return lipgloss.NewStyle().MaxWidth(width).Render(line)
+}
+
+// pad cuts or fills a rendered line to exactly width cells
+func pad(line string, width int) string {
+	line = fit(line, width)
+	return line + strings.Repeat(" ", max(0, width-lipgloss.Width(line)))
+}
@@ -0,0 +1,384 @@
+// Copyright (c) 2026 dotandev
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var ansiEscape = regexp.MustCompile(`\x1b\[[0-9;?]*[a-zA-Z]`)
+
+// namedKeys maps the key names used in scripts to the messages a terminal
+// would deliver
+var namedKeys = map[string]tea.KeyType{
+	"up":        tea.KeyUp,
+	"down":      tea.KeyDown,
+	"left":      tea.KeyLeft,
+	"right":     tea.KeyRight,
+	"pgup":      tea.KeyPgUp,
+	"pgdown":    tea.KeyPgDown,
+	"home":      tea.KeyHome,
+	"end":       tea.KeyEnd,
+	"enter":     tea.KeyEnter,
+	"esc":       tea.KeyEsc,
+	"backspace": tea.KeyBackspace,
+	"ctrl+c":    tea.KeyCtrlC,
+}
+
+// virtualTerminal drives the viewer like a terminal of a fixed size would,
+// one key at a time, and reads back the screen as plain text
+type virtualTerminal struct {
+	t      *testing.T
+	model  *viewerModel
+	width  int
+	height int
+	quit   bool
+}
+
+func newVirtualTerminal(t *testing.T, trace *ExecutionTrace, width, height int) *virtualTerminal {
+	vt := &virtualTerminal{t: t, model: newViewerModel(trace), width: width, height: height}
+	vt.send(tea.WindowSizeMsg{Width: width, Height: height})
+	return vt
+}
+
+func (vt *virtualTerminal) send(msg tea.Msg) {
+	_, cmd := vt.model.Update(msg)
+	if cmd != nil {
+		if _, ok := cmd().(tea.QuitMsg); ok {
+			vt.quit = true
+		}
+	}
+}
+
+// press plays a script of keys: names from namedKeys, or text typed one rune
+// at a time
+func (vt *virtualTerminal) press(keys ...string) {
+	for _, key := range keys {
+		if keyType, ok := namedKeys[key]; ok {
+			vt.send(tea.KeyMsg{Type: keyType})
+			continue
+		}
+		for _, r := range key {
+			if r == ' ' {
+				vt.send(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{r}})
+			} else {
+				vt.send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+			}
+		}
+	}
+}
+
+// screen renders the model and checks it fits the terminal
+func (vt *virtualTerminal) screen() []string {
+	vt.t.Helper()
+	lines := strings.Split(ansiEscape.ReplaceAllString(vt.model.View(), ""), "\n")
+	require.Len(vt.t, lines, vt.height, "the view should fill the terminal")
+	for i, line := range lines {
+		require.LessOrEqual(vt.t, lipgloss.Width(line), vt.width, "line %d overflows: %q", i, line)
+	}
+	return lines
+}
+
+func (vt *virtualTerminal) text() string {
+	return strings.Join(vt.screen(), "\n")
+}
+
+// cursorLine returns the tree row the cursor is on
+func (vt *virtualTerminal) cursorLine() string {
+	vt.t.Helper()
+	for _, line := range vt.screen() {
+		if strings.HasPrefix(line, "> ") {
+			return line
+		}
+	}
+	vt.t.Fatal("no cursor on screen")
+	return ""
+}
+
+func viewerTestTrace() *ExecutionTrace {
+	trace := NewExecutionTrace("tx-abc", 2)
+	for _, state := range []ExecutionState{
+		{Operation: "contract_call", ContractID: testContractID, Function: "transfer",
+			Arguments: []interface{}{"alice", 100}, HostState: map[string]interface{}{"balance": 500}},
+		{Operation: "contract_call", Depth: 1, ContractID: testContractID, Function: "get_balance",
+			ReturnValue: 500, Memory: map[string]interface{}{"account": "alice"}},
+		{Operation: "storage_write", Depth: 1, HostState: map[string]interface{}{"balance": 400, "nonce": 1}},
+		{Operation: "contract_call", ContractID: testContractID, Function: "burn",
+			Error: "insufficient balance", HostState: map[string]interface{}{"balance": 400}},
+	} {
+		trace.AddState(state)
+	}
+	return trace
+}
+
+func TestViewer_InitialScreen(t *testing.T) {
+	vt := newVirtualTerminal(t, viewerTestTrace(), 100, 20)
+	lines := vt.screen()
+
+	assert.Contains(t, lines[0], "erst trace")
+	assert.Contains(t, lines[0], "tx-abc")
+	assert.Contains(t, lines[0], "step 0/3")
+	assert.Contains(t, vt.cursorLine(), "transfer(alice, 100)")
+	assert.Contains(t, vt.text(), "get_balance() → 500")
+	assert.Contains(t, vt.text(), "✗ burn() CDLZ…CYSC: insufficient balance")
+	assert.Contains(t, vt.text(), "Step 0/3  contract_call")
+	assert.Contains(t, vt.text(), "+ balance: 500")
+}
+
+func TestViewer_NavigationFollowsSteps(t *testing.T) {
+	trace := viewerTestTrace()
+	vt := newVirtualTerminal(t, trace, 100, 20)
+
+	vt.press("down", "down")
+	assert.Equal(t, 2, trace.CurrentStep)
+	assert.Contains(t, vt.cursorLine(), "storage_write")
+	assert.Contains(t, vt.text(), "~ balance: 500 → 400")
+	assert.Contains(t, vt.text(), "+ nonce: 1")
+	assert.Contains(t, vt.text(), "  account: alice", "memory of earlier steps is carried over")
+
+	vt.press("right")
+	assert.Equal(t, 3, trace.CurrentStep)
+	assert.Contains(t, vt.cursorLine(), "burn")
+	assert.Contains(t, vt.text(), "Error: insufficient balance")
+
+	vt.press("right")
+	assert.Contains(t, vt.text(), "already at the last step")
+
+	vt.press("home")
+	assert.Contains(t, vt.cursorLine(), "Transaction: tx-abc")
+	assert.Contains(t, vt.text(), "Steps: 4")
+	assert.Contains(t, vt.text(), "1 failed step(s)")
+
+	vt.press("end", "left")
+	assert.Equal(t, 2, trace.CurrentStep)
+}
+
+func TestViewer_ExpandCollapse(t *testing.T) {
+	vt := newVirtualTerminal(t, viewerTestTrace(), 100, 20)
+
+	vt.press("enter")
+	assert.Contains(t, vt.cursorLine(), "▶ transfer")
+	assert.NotContains(t, vt.text(), "get_balance")
+
+	vt.press(" ")
+	assert.Contains(t, vt.cursorLine(), "▼ transfer")
+	assert.Contains(t, vt.text(), "get_balance")
+
+	vt.press("down", "c")
+	assert.NotContains(t, vt.text(), "get_balance")
+	assert.Contains(t, vt.cursorLine(), "transfer", "the cursor moves to the collapsed parent")
+
+	vt.press("e")
+	assert.Contains(t, vt.text(), "get_balance")
+}
+
+func TestViewer_IncrementalSearch(t *testing.T) {
+	trace := viewerTestTrace()
+	vt := newVirtualTerminal(t, trace, 100, 20)
+	vt.press("c")
+
+	vt.press("/", "bal")
+	lines := vt.screen()
+	assert.Equal(t, "/bal█  2 matches", strings.TrimSpace(lines[len(lines)-2]))
+	assert.Contains(t, vt.cursorLine(), "get_balance", "matches inside collapsed calls are revealed")
+
+	vt.press("backspace", "backspace", "backspace", "burn", "enter")
+	assert.Contains(t, vt.text(), `Match 1 of 1 for "burn"`)
+	assert.Equal(t, 3, trace.CurrentStep)
+
+	vt.press("/", "balance", "enter")
+	assert.Contains(t, vt.text(), `Match 1 of 2 for "balance"`)
+	vt.press("n")
+	assert.Contains(t, vt.text(), `Match 2 of 2 for "balance"`)
+	assert.Contains(t, vt.cursorLine(), "burn")
+	vt.press("N", "N")
+	assert.Contains(t, vt.text(), `Match 2 of 2 for "balance"`)
+
+	vt.press("esc")
+	assert.NotContains(t, vt.text(), "Match")
+
+	vt.press("/", "nothing-here")
+	assert.Contains(t, vt.text(), "0 matches")
+	vt.press("esc")
+	assert.Equal(t, modeBrowse, vt.model.mode)
+}
+
+func TestViewer_SearchHighlightsMatches(t *testing.T) {
+	defer func(match, current lipgloss.Style) {
+		matchStyle, currentMatchStyle = match, current
+	}(matchStyle, currentMatchStyle)
+	matchStyle = lipgloss.NewStyle().Transform(func(s string) string { return "[" + s + "]" })
+	currentMatchStyle = lipgloss.NewStyle().Transform(func(s string) string { return "{" + s + "}" })
+
+	vt := newVirtualTerminal(t, viewerTestTrace(), 100, 20)
+	vt.press("/", "alance", "enter")
+	assert.Contains(t, vt.cursorLine(), "get_b{alance}() → 500")
+	assert.Contains(t, vt.text(), "✗ burn() CDLZ…CYSC: insufficient b[alance]")
+
+	vt.press("/", "cdlz", "enter")
+	assert.Contains(t, vt.cursorLine(), "transfer(alice, 100) {CDLZ…CYSC}")
+	assert.Contains(t, vt.text(), "get_balance() → 500 [CDLZ…CYSC]")
+}
+
+func TestViewer_Scrolling(t *testing.T) {
+	trace := NewExecutionTrace("tx-long", 10)
+	for i := 0; i < 50; i++ {
+		trace.AddState(ExecutionState{Operation: "step", Function: "fn", Arguments: []interface{}{i}})
+	}
+	vt := newVirtualTerminal(t, trace, 80, 12)
+
+	vt.press("end")
+	assert.Contains(t, vt.cursorLine(), "fn(49)")
+	vt.press("pgup")
+	assert.Contains(t, vt.cursorLine(), "fn(40)")
+	vt.press("home")
+	assert.Contains(t, vt.cursorLine(), "Transaction")
+}
+
+func TestViewer_HelpAndQuit(t *testing.T) {
+	vt := newVirtualTerminal(t, viewerTestTrace(), 100, 24)
+
+	vt.press("?")
+	assert.Contains(t, vt.text(), "next / previous match")
+	vt.press("?")
+	assert.NotContains(t, vt.text(), "next / previous match")
+
+	vt.press("q")
+	assert.True(t, vt.quit)
+}
+
+func TestViewer_Breakpoints(t *testing.T) {
+	trace := viewerTestTrace()
+	vt := newVirtualTerminal(t, trace, 100, 24)
+	var saved []string
+	vt.model.save = func(set *BreakpointSet) error {
+		saved = append(saved, fmt.Sprint(len(set.Breakpoints)))
+		return nil
+	}
+
+	vt.press(":", "break host=balance", "enter")
+	assert.Contains(t, vt.text(), "Breakpoint 1: host=balance")
+	vt.press("down", "b")
+	assert.Contains(t, vt.text(), "Breakpoint 2: contract="+testContractID+" function=get_balance")
+	assert.Contains(t, vt.cursorLine(), "● get_balance")
+	assert.Equal(t, []string{"1", "2"}, saved)
+
+	vt.press("up", ">")
+	assert.Equal(t, 1, trace.CurrentStep, "continue starts after the current step")
+	assert.Contains(t, vt.text(), "Breakpoint 2 hit at step 1")
+	vt.press(">")
+	assert.Equal(t, 2, trace.CurrentStep)
+	assert.Contains(t, vt.cursorLine(), "● storage_write")
+	vt.press(">")
+	assert.Equal(t, 3, trace.CurrentStep)
+	assert.Contains(t, vt.text(), "No breakpoint hit, stopped at the last step")
+	vt.press(">")
+	assert.Contains(t, vt.text(), "already at the last step")
+
+	vt.press("<")
+	assert.Equal(t, 2, trace.CurrentStep)
+	vt.press(":", "rc", "enter")
+	assert.Equal(t, 1, trace.CurrentStep)
+
+	vt.press(":", "disable 2", "enter", ":", "bl", "enter")
+	assert.Contains(t, vt.text(), "● 1  host=balance (2 step(s))")
+	assert.Contains(t, vt.text(), "○ 2  contract=CDLZ")
+	vt.press(":", "continue", "enter")
+	assert.Equal(t, 2, trace.CurrentStep, "disabled breakpoints are skipped")
+
+	vt.press("up", "b")
+	assert.Contains(t, vt.text(), "Deleted breakpoint 2")
+	vt.press(":", "delete", "enter")
+	assert.Empty(t, vt.model.breakpoints.Breakpoints)
+	assert.Equal(t, []string{"1", "2", "2", "1", "0"}, saved)
+
+	vt.press(":", "break line=3", "enter")
+	assert.Contains(t, vt.text(), "unknown breakpoint condition")
+	vt.press(":", "delete 7", "enter")
+	assert.Contains(t, vt.text(), "7: no breakpoint 7")
+	vt.press(":", "frobnicate", "enter")
+	assert.Contains(t, vt.text(), `Unknown command "frobnicate"`)
+	vt.press("home", "b")
+	assert.Contains(t, vt.text(), "Select a contract call to break on")
+
+	vt.press(":", "quit", "enter")
+	assert.True(t, vt.quit)
+}
+
+func TestViewer_CommandLine(t *testing.T) {
+	vt := newVirtualTerminal(t, viewerTestTrace(), 100, 20)
+
+	vt.press(":", "brk", "backspace", "backspace", "reak err")
+	lines := vt.screen()
+	assert.Equal(t, ":break err█", strings.TrimSpace(lines[len(lines)-2]))
+
+	vt.press("esc")
+	assert.Equal(t, modeBrowse, vt.model.mode)
+	assert.Empty(t, vt.model.breakpoints.Breakpoints, "esc drops the command")
+}
+
+func TestViewer_TooSmall(t *testing.T) {
+	vt := newVirtualTerminal(t, viewerTestTrace(), 30, 5)
+	assert.Contains(t, vt.model.View(), "Terminal too small")
+}
+
+func TestViewer_EmptyTrace(t *testing.T) {
+	vt := newVirtualTerminal(t, NewExecutionTrace("tx-empty", 5), 80, 10)
+	assert.Contains(t, vt.text(), "no steps")
+	vt.press("down", "right", "/", "zzz", "enter")
+	assert.Contains(t, vt.text(), "No matches")
+}
+
+func TestBuildStepTree_Depth(t *testing.T) {
+	root, nodes := buildStepTree(viewerTestTrace())
+
+	require.Len(t, root.Children, 2)
+	assert.Equal(t, []*TraceNode{nodes[1], nodes[2]}, nodes[0].Children)
+	assert.Equal(t, []string{"alice", "100"}, nodes[0].Args)
+	assert.Equal(t, "500", nodes[1].ReturnValue)
+	assert.True(t, nodes[3].Failed)
+}
+
+func TestDiffStates(t *testing.T) {
+	changes := diffStates(
+		map[string]interface{}{"a": 1, "b": 2, "c": 3},
+		map[string]interface{}{"a": 1, "b": 5, "d": 4},
+	)
+
+	require.Len(t, changes, 4)
+	assert.Equal(t, stateChange{Key: "a", Before: 1, After: 1, Kind: unchanged}, changes[0])
+	assert.Equal(t, stateChange{Key: "b", Before: 2, After: 5, Kind: changed}, changes[1])
+	assert.Equal(t, stateChange{Key: "c", Before: 3, Kind: removed}, changes[2])
+	assert.Equal(t, stateChange{Key: "d", After: 4, Kind: added}, changes[3])
+}
+
+func TestInteractiveViewer_Start(t *testing.T) {
+	var out bytes.Buffer
+	viewer := NewInteractiveViewer(viewerTestTrace())
+	viewer.input = strings.NewReader("q")
+	viewer.output = &out
+
+	require.NoError(t, viewer.Start())
+	assert.Contains(t, out.String(), "transfer")
+}
@@ -0,0 +1,74 @@
+// Copyright (c) 2026 dotandev
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package wasm reads the sections of WebAssembly modules.
+package wasm
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/dotandev/hintents/internal/errors"
+)
+
+var wasmMagic = []byte{0x00, 'a', 's', 'm', 0x01, 0x00, 0x00, 0x00}
+
+const sectionCustom = 0
+
+// CustomSections returns the custom sections of a WASM module by name, such
+// as the DWARF sections ".debug_info", ".debug_line" and so on, or the
+// "contractspecv0" interface of a Soroban contract.
+func CustomSections(module []byte) (map[string][]byte, error) {
+	if !bytes.HasPrefix(module, wasmMagic) {
+		return nil, errors.WrapInvalidWasm("not a WASM module, or not version 1")
+	}
+
+	sections := make(map[string][]byte)
+	pos := len(wasmMagic)
+	for pos < len(module) {
+		id := module[pos]
+		size, n, err := readULEB128(module[pos+1:])
+		if err != nil {
+			return nil, errors.WrapInvalidWasm(fmt.Sprintf("section at offset %d: %v", pos, err))
+		}
+		start := pos + 1 + n
+		end := start + int(size)
+		if size > uint64(len(module)) || end > len(module) {
+			return nil, errors.WrapInvalidWasm(fmt.Sprintf("section at offset %d is truncated", pos))
+		}
+
+		if id == sectionCustom {
+			nameLen, n, err := readULEB128(module[start:end])
+			if err != nil || nameLen > uint64(end-start-n) {
+				return nil, errors.WrapInvalidWasm(fmt.Sprintf("custom section at offset %d has an invalid name", pos))
+			}
+			name := string(module[start+n : start+n+int(nameLen)])
+			sections[name] = module[start+n+int(nameLen) : end]
+		}
+		pos = end
+	}
+	return sections, nil
+}
+
+// readULEB128 decodes an unsigned LEB128 number, returning it and its length
+func readULEB128(b []byte) (uint64, int, error) {
+	var value uint64
+	for i := 0; i < len(b) && i < 10; i++ {
+		value |= uint64(b[i]&0x7f) << (7 * i)
+		if b[i]&0x80 == 0 {
+			return value, i + 1, nil
+		}
+	}
+	return 0, 0, fmt.Errorf("invalid LEB128 number")
+}
@@ -0,0 +1,47 @@
+// Copyright (c) 2026 dotandev
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wasmdebug
+
+import (
+	"fmt"
+
+	"github.com/dotandev/hintents/internal/trace"
+)
+
+// Annotate sets the Source of every node of a trace tree that has code
+// offsets, such as the error raised by a trap, to the source frames of its
+// offsets, innermost first. Offsets the debug info does not cover are kept as
+// "wasm+0x1a2b". It returns the number of nodes annotated.
+func (d *DebugInfo) Annotate(root *trace.TraceNode) int {
+	annotated := 0
+	for _, node := range root.FlattenAll() {
+		if len(node.CodeOffsets) == 0 {
+			continue
+		}
+		node.Source = node.Source[:0]
+		for _, offset := range node.CodeOffsets {
+			frames := d.Lookup(offset)
+			if len(frames) == 0 {
+				node.Source = append(node.Source, fmt.Sprintf("wasm+%#x", offset))
+				continue
+			}
+			for _, frame := range frames {
+				node.Source = append(node.Source, frame.String())
+			}
+		}
+		annotated++
+	}
+	return annotated
+}
@@ -0,0 +1,117 @@
+// Copyright (c) 2026 dotandev
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wasmdebug
+
+import (
+	"context"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/dotandev/hintents/internal/trace"
+	"github.com/stellar/go/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnnotate(t *testing.T) {
+	info, err := Parse("token.wasm", testModule(t))
+	require.NoError(t, err)
+
+	root := trace.NewTraceNode("root", "simulation")
+	call := trace.NewTraceNode("call-0", "contract_call")
+	call.Function = "transfer"
+	trap := trace.NewTraceNode("event-1", "error")
+	trap.CodeOffsets = []uint64{0x1a, 0x500}
+	root.AddChild(call)
+	call.AddChild(trap)
+
+	assert.Equal(t, 1, info.Annotate(root))
+	assert.Empty(t, call.Source)
+	assert.Equal(t, []string{
+		"token::checked_sub at src/math.rs:3 (inlined)",
+		"token::transfer at src/lib.rs:12",
+		"wasm+0x500",
+	}, trap.Source)
+}
+
+// scvalJSON is a ScVal as erst-sim writes it, base64 XDR in quotes
+func scvalJSON(t *testing.T, v xdr.ScVal) string {
+	t.Helper()
+	b64, err := xdr.MarshalBase64(v)
+	require.NoError(t, err)
+	return `"` + b64 + `"`
+}
+
+// Frames reported by erst-sim resolve to source lines: the simulator sends
+// the code offsets of the open frames on each event, the call tree keeps
+// them and Annotate looks them up in the DWARF info
+func TestAnnotate_SimulatorBacktrace(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake simulator scripts need a POSIX shell")
+	}
+	info, err := Parse("token.wasm", testModule(t))
+	require.NoError(t, err)
+
+	sym := func(s string) string {
+		return scvalJSON(t, xdr.ScVal{Type: xdr.ScValTypeScvSymbol, Sym: (*xdr.ScSymbol)(&s)})
+	}
+	id := make([]byte, 32)
+	contract := scvalJSON(t, xdr.ScVal{Type: xdr.ScValTypeScvBytes, Bytes: (*xdr.ScBytes)(&id)})
+	code := xdr.ScErrorCodeScecInvalidAction
+	trapped := scvalJSON(t, xdr.ScVal{Type: xdr.ScValTypeScvError, Error: &xdr.ScError{Type: xdr.ScErrorTypeSceWasmVm, Code: &code}})
+	void := scvalJSON(t, xdr.ScVal{Type: xdr.ScValTypeScvVoid})
+
+	// transfer starts at 0x10 and calls balance, which starts at 0x30 and traps
+	events := strings.Join([]string{
+		`{"type":"diagnostic","topics":[` + sym("fn_call") + `,` + contract + `,` + sym("transfer") + `],"data":` + void + `,"in_successful_contract_call":false,"call_depth":1,"wasm_backtrace":[16]}`,
+		`{"type":"diagnostic","topics":[` + sym("fn_call") + `,` + contract + `,` + sym("balance") + `],"data":` + void + `,"in_successful_contract_call":false,"call_depth":2,"wasm_backtrace":[48,16]}`,
+		`{"type":"diagnostic","topics":[` + sym("error") + `,` + trapped + `],"data":` + void + `,"in_successful_contract_call":false,"call_depth":2,"wasm_backtrace":[48,16]}`,
+	}, ",")
+	script := `#!/bin/sh
+if [ "$1" = "--handshake" ]; then echo '{"protocol_versions":["1.0"],"simulator_version":"test"}'; exit 0; fi
+read -r line
+id=$(printf '%s' "$line" | sed 's/.*"request_id":"\([^"]*\)".*/\1/')
+echo "{\"version\":\"1.0\",\"request_id\":\"$id\",\"success\":true,\"result\":{\"events\":[$EVENTS]}}"
+`
+	path := filepath.Join(t.TempDir(), "erst-sim")
+	require.NoError(t, os.WriteFile(path, []byte(script), 0755))
+	t.Setenv("EVENTS", events)
+	runner := &simulator.Runner{BinaryPath: path, Limits: simulator.DefaultLimits}
+
+	envelope := base64.StdEncoding.EncodeToString(make([]byte, 4))
+	resp, err := runner.RunContext(context.Background(), &simulator.SimulationRequest{EnvelopeXdr: envelope, ResultMetaXdr: envelope})
+	require.NoError(t, err)
+	require.Len(t, resp.Events, 3)
+	assert.Equal(t, []uint64{0x30, 0x10}, resp.Events[2].WasmBacktrace)
+
+	root, err := trace.ParseSimulationResponse(&trace.SimulationResponse{Status: resp.Status, Events: resp.Events})
+	require.NoError(t, err)
+	assert.Equal(t, 3, info.Annotate(root))
+
+	transfer := root.Children[0]
+	balance := transfer.Children[0]
+	trap := balance.Children[0]
+	assert.Equal(t, "error", trap.Type)
+	assert.Equal(t, []string{"token::transfer at src/lib.rs:10"}, transfer.Source)
+	assert.Equal(t, []string{
+		"token::balance at src/lib.rs:20",
+		"token::transfer at src/lib.rs:10",
+	}, trap.Source)
+}
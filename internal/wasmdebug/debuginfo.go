@@ -0,0 +1,309 @@
+// Copyright (c) 2026 dotandev
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package wasmdebug maps code offsets of a contract's WASM module back to the
+// Rust functions and source lines they were compiled from, using the DWARF
+// debug info of a locally built, unstripped module.
+package wasmdebug
+
+import (
+	"debug/dwarf"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/dotandev/hintents/internal/wasm"
+)
+
+// Frame is a source-level frame: a function, and where in it execution was
+type Frame struct {
+	Function string
+	File     string
+	Line     int
+	Column   int
+	// Inlined is true when the function was inlined into the frame after it
+	Inlined bool
+}
+
+// String renders the frame as "token::transfer at src/lib.rs:42:9"
+func (f Frame) String() string {
+	var sb strings.Builder
+	if f.Function != "" {
+		sb.WriteString(f.Function + " at ")
+	}
+	sb.WriteString(f.File)
+	if f.Line > 0 {
+		fmt.Fprintf(&sb, ":%d", f.Line)
+		if f.Column > 0 {
+			fmt.Fprintf(&sb, ":%d", f.Column)
+		}
+	}
+	if f.Inlined {
+		sb.WriteString(" (inlined)")
+	}
+	return sb.String()
+}
+
+// DebugInfo is the DWARF line table and function scopes of a WASM module.
+// Addresses are code offsets, relative to the start of the code section.
+type DebugInfo struct {
+	lines  []lineRange
+	scopes []scope
+}
+
+// lineRange is a row of the line table, covering code up to the next row
+type lineRange struct {
+	low, high uint64
+	file      string
+	line      int
+	column    int
+}
+
+// scope is the code of a function, or of a function inlined into another one
+type scope struct {
+	low, high uint64
+	// depth is the nesting of the DIE, inlined scopes being deeper than the
+	// scopes they were inlined into
+	depth    int
+	function string
+	inlined  bool
+	// origin is the DIE naming the function, when the scope has no name
+	origin dwarf.Offset
+	// callFile, callLine and callColumn are where an inlined function was
+	// called from
+	callFile   string
+	callLine   int
+	callColumn int
+}
+
+// Load reads the debug info of the WASM module at path
+func Load(path string) (*DebugInfo, error) {
+	module, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read WASM module: %w", err)
+	}
+	return Parse(path, module)
+}
+
+// Parse reads the debug info of a WASM module, name being used in errors
+func Parse(name string, module []byte) (*DebugInfo, error) {
+	sections, err := wasm.CustomSections(module)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := sections[".debug_info"]; !ok {
+		return nil, errors.WrapNoDebugInfo(name)
+	}
+
+	data, err := dwarf.New(
+		sections[".debug_abbrev"],
+		sections[".debug_aranges"],
+		sections[".debug_frame"],
+		sections[".debug_info"],
+		sections[".debug_line"],
+		sections[".debug_pubnames"],
+		sections[".debug_ranges"],
+		sections[".debug_str"],
+	)
+	if err != nil {
+		return nil, errors.WrapInvalidWasm(fmt.Sprintf("%s: %v", name, err))
+	}
+	// DWARF 5 sections
+	for _, section := range []string{".debug_addr", ".debug_line_str", ".debug_str_offsets", ".debug_rnglists"} {
+		if contents, ok := sections[section]; ok {
+			if err := data.AddSection(section, contents); err != nil {
+				return nil, errors.WrapInvalidWasm(fmt.Sprintf("%s: %v", name, err))
+			}
+		}
+	}
+
+	info := &DebugInfo{}
+	if err := info.read(data); err != nil {
+		return nil, errors.WrapInvalidWasm(fmt.Sprintf("%s: %v", name, err))
+	}
+	return info, nil
+}
+
+// read collects the line tables and function scopes of every compile unit
+func (d *DebugInfo) read(data *dwarf.Data) error {
+	names := make(map[dwarf.Offset]string)
+	// path holds the names of the namespaces and types the reader is in, by
+	// depth, to qualify function names
+	var path []string
+	var files []*dwarf.LineFile
+
+	r := data.Reader()
+	for {
+		entry, err := r.Next()
+		if err != nil {
+			return err
+		}
+		if entry == nil {
+			break
+		}
+		if entry.Tag == 0 {
+			if len(path) > 0 {
+				path = path[:len(path)-1]
+			}
+			continue
+		}
+
+		name, _ := entry.Val(dwarf.AttrName).(string)
+		switch entry.Tag {
+		case dwarf.TagCompileUnit:
+			if files, err = d.readLines(data, entry); err != nil {
+				return err
+			}
+			name = ""
+		case dwarf.TagSubprogram, dwarf.TagInlinedSubroutine:
+			if name != "" {
+				names[entry.Offset] = qualify(path, name)
+			}
+			if err := d.addScope(data, entry, len(path), names[entry.Offset], files); err != nil {
+				return err
+			}
+		case dwarf.TagNamespace, dwarf.TagStructType, dwarf.TagEnumerationType, dwarf.TagUnionType:
+		default:
+			name = ""
+		}
+		if entry.Children {
+			path = append(path, name)
+		}
+	}
+
+	// Scopes without a name take it from their declaration or abstract
+	// instance, which may come after them
+	for i := range d.scopes {
+		if s := &d.scopes[i]; s.function == "" {
+			s.function = names[s.origin]
+		}
+	}
+	sort.Slice(d.lines, func(i, j int) bool { return d.lines[i].low < d.lines[j].low })
+	return nil
+}
+
+// readLines adds the line table of a compile unit, returning its files
+func (d *DebugInfo) readLines(data *dwarf.Data, cu *dwarf.Entry) ([]*dwarf.LineFile, error) {
+	lr, err := data.LineReader(cu)
+	if err != nil || lr == nil {
+		return nil, err
+	}
+
+	var sequence []dwarf.LineEntry
+	var row dwarf.LineEntry
+	for {
+		if err := lr.Next(&row); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		sequence = append(sequence, row)
+		if !row.EndSequence {
+			continue
+		}
+		for i := 0; i+1 < len(sequence); i++ {
+			from, to := sequence[i], sequence[i+1]
+			if to.Address <= from.Address || from.File == nil {
+				continue
+			}
+			d.lines = append(d.lines, lineRange{low: from.Address, high: to.Address, file: from.File.Name, line: from.Line, column: from.Column})
+		}
+		sequence = sequence[:0]
+	}
+	return lr.Files(), nil
+}
+
+// addScope adds the code ranges of a function or inlined function
+func (d *DebugInfo) addScope(data *dwarf.Data, entry *dwarf.Entry, depth int, function string, files []*dwarf.LineFile) error {
+	ranges, err := data.Ranges(entry)
+	if err != nil {
+		return err
+	}
+	if len(ranges) == 0 {
+		return nil
+	}
+
+	s := scope{depth: depth, function: function, inlined: entry.Tag == dwarf.TagInlinedSubroutine}
+	if function == "" {
+		for _, attr := range []dwarf.Attr{dwarf.AttrAbstractOrigin, dwarf.AttrSpecification} {
+			if origin, ok := entry.Val(attr).(dwarf.Offset); ok {
+				s.origin = origin
+				break
+			}
+		}
+	}
+	if s.inlined {
+		if i, ok := entry.Val(dwarf.AttrCallFile).(int64); ok && i >= 0 && int(i) < len(files) && files[i] != nil {
+			s.callFile = files[i].Name
+		}
+		line, _ := entry.Val(dwarf.AttrCallLine).(int64)
+		column, _ := entry.Val(dwarf.AttrCallColumn).(int64)
+		s.callLine, s.callColumn = int(line), int(column)
+	}
+	for _, r := range ranges {
+		s.low, s.high = r[0], r[1]
+		d.scopes = append(d.scopes, s)
+	}
+	return nil
+}
+
+// qualify names a function after the namespaces and types it is declared in,
+// leaving out Rust's anonymous impl blocks ("{impl#0}")
+func qualify(path []string, name string) string {
+	var parts []string
+	for _, part := range path {
+		if part != "" && !strings.HasPrefix(part, "{impl") {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(append(parts, name), "::")
+}
+
+// Lookup maps a code offset to its source frames, innermost first: the
+// function the offset is in, then the functions it was inlined into. It
+// returns nil when the offset is not covered by the line table.
+func (d *DebugInfo) Lookup(offset uint64) []Frame {
+	i := sort.Search(len(d.lines), func(i int) bool { return d.lines[i].low > offset }) - 1
+	if i < 0 || offset >= d.lines[i].high {
+		return nil
+	}
+	line := d.lines[i]
+	at := Frame{File: line.file, Line: line.line, Column: line.column}
+
+	var scopes []scope
+	for _, s := range d.scopes {
+		if s.low <= offset && offset < s.high {
+			scopes = append(scopes, s)
+		}
+	}
+	if len(scopes) == 0 {
+		return []Frame{at}
+	}
+	sort.SliceStable(scopes, func(i, j int) bool { return scopes[i].depth > scopes[j].depth })
+
+	frames := make([]Frame, 0, len(scopes))
+	for _, s := range scopes {
+		frame := at
+		frame.Function, frame.Inlined = s.function, s.inlined
+		frames = append(frames, frame)
+		if !s.inlined {
+			break
+		}
+		at = Frame{File: s.callFile, Line: s.callLine, Column: s.callColumn}
+	}
+	return frames
+}
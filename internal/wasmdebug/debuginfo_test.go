@@ -0,0 +1,241 @@
+// Copyright (c) 2026 dotandev
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wasmdebug
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dotandev/hintents/internal/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// DWARF constants used by the test module
+const (
+	tagCompileUnit       = 0x11
+	tagNamespace         = 0x39
+	tagSubprogram        = 0x2e
+	tagInlinedSubroutine = 0x1d
+
+	atName           = 0x03
+	atStmtList       = 0x10
+	atLowPC          = 0x11
+	atHighPC         = 0x12
+	atInline         = 0x20
+	atAbstractOrigin = 0x31
+	atCallFile       = 0x58
+	atCallLine       = 0x59
+
+	formAddr      = 0x01
+	formData1     = 0x0b
+	formData4     = 0x06
+	formString    = 0x08
+	formRef4      = 0x13
+	formSecOffset = 0x17
+)
+
+// testModule is a WASM module with the debug info of
+//
+//	src/lib.rs
+//	10  fn transfer()        0x10..0x30
+//	12      checked_sub()    0x18..0x20, inlined from src/math.rs:3
+//	13      ...
+//	20  fn balance()         0x30..0x40
+func testModule(t *testing.T) []byte {
+	t.Helper()
+
+	abbrev := []byte{
+		1, tagCompileUnit, 1, atName, formString, atStmtList, formSecOffset, atLowPC, formAddr, atHighPC, formData4, 0, 0,
+		2, tagNamespace, 1, atName, formString, 0, 0,
+		3, tagSubprogram, 1, atName, formString, atLowPC, formAddr, atHighPC, formData4, 0, 0,
+		4, tagSubprogram, 0, atName, formString, atInline, formData1, 0, 0,
+		5, tagInlinedSubroutine, 0, atAbstractOrigin, formRef4, atLowPC, formAddr, atHighPC, formData4, atCallFile, formData1, atCallLine, formData1, 0, 0,
+		0,
+	}
+
+	var dies bytes.Buffer
+	u32 := func(v uint32) { binary.Write(&dies, binary.LittleEndian, v) }
+	str := func(s string) { dies.WriteString(s + "\x00") }
+	// Offsets in the unit count its 11 byte header
+	const header = 11
+
+	dies.WriteByte(1)
+	str("src/lib.rs")
+	u32(0)
+	u32(0x10)
+	u32(0x30)
+	dies.WriteByte(2)
+	str("token")
+	checkedSub := header + dies.Len()
+	dies.WriteByte(4)
+	str("checked_sub")
+	dies.WriteByte(3) // DW_INL_declared_inlined
+	dies.WriteByte(3)
+	str("transfer")
+	u32(0x10)
+	u32(0x20)
+	dies.WriteByte(5)
+	u32(uint32(checkedSub))
+	u32(0x18)
+	u32(0x08)
+	dies.WriteByte(1)
+	dies.WriteByte(12)
+	dies.WriteByte(0) // end of transfer
+	dies.WriteByte(3)
+	str("balance")
+	u32(0x30)
+	u32(0x10)
+	dies.WriteByte(0) // end of balance
+	dies.WriteByte(0) // end of token
+	dies.WriteByte(0) // end of the unit
+
+	var info bytes.Buffer
+	binary.Write(&info, binary.LittleEndian, uint32(7+dies.Len()))
+	binary.Write(&info, binary.LittleEndian, uint16(4))
+	binary.Write(&info, binary.LittleEndian, uint32(0))
+	info.WriteByte(4)
+	info.Write(dies.Bytes())
+
+	return wasmWithSections(map[string][]byte{
+		".debug_abbrev": abbrev,
+		".debug_info":   info.Bytes(),
+		".debug_line":   testLineProgram(),
+	})
+}
+
+// testLineProgram is a DWARF 4 line program with files 1 src/lib.rs and
+// 2 src/math.rs
+func testLineProgram() []byte {
+	var hdr bytes.Buffer
+	hdr.Write([]byte{1, 1, 1, 0xfb, 14, 13})
+	hdr.Write([]byte{0, 1, 1, 1, 1, 0, 0, 0, 1, 0, 0, 1})
+	hdr.WriteString("src\x00\x00")
+	hdr.WriteString("lib.rs\x00\x01\x00\x00")
+	hdr.WriteString("math.rs\x00\x01\x00\x00")
+	hdr.WriteByte(0)
+
+	var prog bytes.Buffer
+	setAddress := func(addr uint32) {
+		prog.Write([]byte{0, 5, 2})
+		binary.Write(&prog, binary.LittleEndian, addr)
+	}
+	line := 1
+	row := func(addr uint32, file byte, to int) {
+		setAddress(addr)
+		prog.Write([]byte{4, file})
+		// DW_LNS_advance_line takes a signed LEB128, one byte is enough here
+		prog.Write([]byte{3, byte(to-line) & 0x7f})
+		line = to
+		prog.WriteByte(1)
+	}
+	row(0x10, 1, 10)
+	row(0x18, 2, 3)
+	row(0x20, 1, 13)
+	row(0x30, 1, 20)
+	setAddress(0x40)
+	prog.Write([]byte{0, 1, 1})
+
+	var out bytes.Buffer
+	binary.Write(&out, binary.LittleEndian, uint32(2+4+hdr.Len()+prog.Len()))
+	binary.Write(&out, binary.LittleEndian, uint16(4))
+	binary.Write(&out, binary.LittleEndian, uint32(hdr.Len()))
+	out.Write(hdr.Bytes())
+	out.Write(prog.Bytes())
+	return out.Bytes()
+}
+
+// wasmWithSections builds a module with an empty code section and the given
+// custom sections
+func wasmWithSections(custom map[string][]byte) []byte {
+	module := []byte("\x00asm\x01\x00\x00\x00")
+	module = append(module, 10, 1, 0)
+	for _, name := range []string{".debug_abbrev", ".debug_info", ".debug_line"} {
+		contents, ok := custom[name]
+		if !ok {
+			continue
+		}
+		payload := append(uleb128(uint64(len(name))), name...)
+		payload = append(payload, contents...)
+		module = append(module, 0)
+		module = append(module, uleb128(uint64(len(payload)))...)
+		module = append(module, payload...)
+	}
+	return module
+}
+
+func uleb128(v uint64) []byte {
+	var out []byte
+	for {
+		b := byte(v & 0x7f)
+		v >>= 7
+		if v != 0 {
+			out = append(out, b|0x80)
+			continue
+		}
+		return append(out, b)
+	}
+}
+
+func TestLookup(t *testing.T) {
+	info, err := Parse("token.wasm", testModule(t))
+	require.NoError(t, err)
+
+	assert.Equal(t, []Frame{{Function: "token::transfer", File: "src/lib.rs", Line: 10}}, info.Lookup(0x12))
+	assert.Equal(t, []Frame{{Function: "token::balance", File: "src/lib.rs", Line: 20}}, info.Lookup(0x3f))
+
+	// An inlined function is followed by the call it was inlined at
+	assert.Equal(t, []Frame{
+		{Function: "token::checked_sub", File: "src/math.rs", Line: 3, Inlined: true},
+		{Function: "token::transfer", File: "src/lib.rs", Line: 12},
+	}, info.Lookup(0x1a))
+
+	assert.Nil(t, info.Lookup(0x08))
+	assert.Nil(t, info.Lookup(0x40))
+}
+
+func TestFrame_String(t *testing.T) {
+	assert.Equal(t, "token::transfer at src/lib.rs:12:9", Frame{Function: "token::transfer", File: "src/lib.rs", Line: 12, Column: 9}.String())
+	assert.Equal(t, "token::checked_sub at src/math.rs:3 (inlined)", Frame{Function: "token::checked_sub", File: "src/math.rs", Line: 3, Inlined: true}.String())
+	assert.Equal(t, "src/lib.rs:7", Frame{File: "src/lib.rs", Line: 7}.String())
+}
+
+func TestParse_Errors(t *testing.T) {
+	_, err := Parse("token.wasm", []byte("not wasm"))
+	assert.ErrorIs(t, err, errors.ErrInvalidWasm)
+
+	_, err = Parse("token.wasm", wasmWithSections(nil))
+	assert.ErrorIs(t, err, errors.ErrNoDebugInfo)
+	assert.ErrorContains(t, err, "token.wasm")
+
+	truncated := testModule(t)
+	_, err = Parse("token.wasm", truncated[:len(truncated)-4])
+	assert.ErrorIs(t, err, errors.ErrInvalidWasm)
+}
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "token.wasm")
+	require.NoError(t, os.WriteFile(path, testModule(t), 0644))
+
+	info, err := Load(path)
+	require.NoError(t, err)
+	assert.Len(t, info.Lookup(0x1a), 2)
+
+	_, err = Load(filepath.Join(t.TempDir(), "missing.wasm"))
+	assert.Error(t, err)
+}
@@ -26,26 +26,39 @@
 
 use jsonschema::JSONSchema;
 use serde_json::Value;
+use std::sync::OnceLock;
+
+/// $id of common.schema.json, which the other schemas reference
+const COMMON_SCHEMA_ID: &str = "https://example.com/schemas/common.schema.json";
+
+/// Compiles simulation-request.schema.json once, with common.schema.json
+/// registered so that its definitions resolve without network access
+fn request_schema() -> &'static JSONSchema {
+    static SCHEMA: OnceLock<JSONSchema> = OnceLock::new();
+    SCHEMA.get_or_init(|| {
+        // include the schemas at compile-time
+        let common: Value =
+            serde_json::from_str(include_str!("../../../docs/schema/common.schema.json")).unwrap();
+        let schema: Value = serde_json::from_str(include_str!(
+            "../../../docs/schema/simulation-request.schema.json"
+        ))
+        .unwrap();
+        JSONSchema::options()
+            .with_document(COMMON_SCHEMA_ID.to_string(), common)
+            .compile(&schema)
+            .unwrap()
+    })
+}
 
 /// Validates JSON input against the simulation-request.schema.json
 pub fn validate_request(input: &str) -> Result<Value, String> {
-    // include the schema at compile-time
-    let schema_json = include_str!("../../../docs/schema/simulation-request.schema.json");
-    let schema: Value = serde_json::from_str(schema_json).unwrap();
-    let compiled = JSONSchema::compile(&schema).unwrap();
-
     // parse the incoming JSON
     let instance: Value = serde_json::from_str(input).map_err(|e| e.to_string())?;
 
     // validate against the schema
-    compiled
+    request_schema()
         .validate(&instance)
-        .map_err(|errors| {
-            errors
-                .map(|e| e.to_string())
-                .collect::<Vec<_>>()
-                .join(", ")
-        })?;
+        .map_err(|errors| errors.map(|e| e.to_string()).collect::<Vec<_>>().join(", "))?;
 
     Ok(instance)
 }
@@ -12,37 +12,105 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
+mod ipc;
+
 use base64::Engine as _;
 use serde::{Deserialize, Serialize};
-use soroban_env_host::xdr::ReadXdr;
+use soroban_env_host::xdr::{ReadXdr, WriteXdr};
+use std::cell::RefCell;
 use std::collections::HashMap;
-use std::io::{self, Read};
+use std::io::{self, BufRead, Read, Write};
+use std::rc::Rc;
+
+/// Protocol versions spoken with erst, newest first. See docs/schema.
+const PROTOCOL_VERSIONS: &[&str] = &["1.0"];
 
 // -----------------------------------------------------------------------------
 // Data Structures
 // -----------------------------------------------------------------------------
 
+/// docs/schema/simulation-request.schema.json
 #[derive(Debug, Deserialize)]
 struct SimulationRequest {
-    envelope_xdr: String,
-    result_meta_xdr: String,
+    version: String,
+    request_id: String,
+    // Envelope XDR, absent for local WASM replay
+    xdr: Option<String>,
+    result_meta_xdr: Option<String>,
     // Key XDR -> Entry XDR
     ledger_entries: Option<HashMap<String, String>>,
+    // Meter the CPU instructions and memory bytes of every event
+    #[serde(default)]
+    profile: bool,
 }
 
+/// docs/schema/simulation-response.schema.json
 #[derive(Debug, Serialize)]
 struct SimulationResponse {
-    status: String,
-    error: Option<String>,
-    events: Vec<String>,
+    version: String,
+    request_id: String,
+    success: bool,
+    #[serde(skip_serializing_if = "Option::is_none")]
+    result: Option<SimulationResult>,
+    #[serde(skip_serializing_if = "Option::is_none")]
+    error: Option<ResponseError>,
+}
+
+#[derive(Debug, Serialize)]
+struct SimulationResult {
+    events: Vec<DiagnosticEvent>,
     logs: Vec<String>,
 }
 
+/// docs/schema/common.schema.json#/definitions/DiagnosticEvent
+#[derive(Debug, Serialize)]
+struct DiagnosticEvent {
+    #[serde(rename = "type")]
+    event_type: String,
+    #[serde(skip_serializing_if = "Option::is_none")]
+    contract_id: Option<String>,
+    // ScVal XDR of each topic
+    topics: Vec<String>,
+    // ScVal XDR
+    data: String,
+    in_successful_contract_call: bool,
+    call_depth: u32,
+    #[serde(skip_serializing_if = "Option::is_none")]
+    cpu_instructions: Option<u64>,
+    #[serde(skip_serializing_if = "Option::is_none")]
+    memory_bytes: Option<u64>,
+    // Code section offsets of the open contract frames, innermost first
+    #[serde(skip_serializing_if = "Vec::is_empty")]
+    wasm_backtrace: Vec<u64>,
+}
+
+#[derive(Debug, Serialize)]
+struct ResponseError {
+    code: String,
+    message: String,
+}
+
+/// docs/schema/handshake.schema.json
+#[derive(Debug, Serialize)]
+struct Handshake {
+    protocol_versions: Vec<String>,
+    simulator_version: String,
+}
+
 // -----------------------------------------------------------------------------
 // Main Execution
 // -----------------------------------------------------------------------------
 
 fn main() {
+    let args: Vec<String> = std::env::args().skip(1).collect();
+    if args.iter().any(|arg| arg == "--handshake") {
+        println!("{}", handshake());
+        return;
+    }
+    if args.iter().any(|arg| arg == "--serve") {
+        return serve();
+    }
+
     // Read JSON from Stdin
     let mut buffer = String::new();
     if let Err(e) = io::stdin().read_to_string(&mut buffer) {
@@ -50,16 +118,121 @@ fn main() {
         return;
     }
 
-    // Parse Request
-    let request: SimulationRequest = match serde_json::from_str(&buffer) {
-        Ok(req) => req,
-        Err(e) => {
-            return send_error(format!("Invalid JSON: {}", e));
+    let response = handle_request(&buffer);
+    println!("{}", serde_json::to_string(&response).unwrap());
+}
+
+/// The line announcing the protocol versions this simulator speaks
+fn handshake() -> String {
+    let handshake = Handshake {
+        protocol_versions: PROTOCOL_VERSIONS.iter().map(|v| v.to_string()).collect(),
+        simulator_version: env!("CARGO_PKG_VERSION").to_string(),
+    };
+    serde_json::to_string(&handshake).unwrap()
+}
+
+/// Long-lived worker mode used by the Go simulator pool.
+///
+/// Prints the handshake, then reads one JSON request per line from stdin and
+/// writes one JSON response per line to stdout. Exits when stdin is closed.
+fn serve() {
+    let stdin = io::stdin();
+    let mut stdout = io::stdout().lock();
+
+    if writeln!(stdout, "{}", handshake())
+        .and_then(|_| stdout.flush())
+        .is_err()
+    {
+        return;
+    }
+
+    for line in stdin.lock().lines() {
+        let line = match line {
+            Ok(line) => line,
+            Err(e) => {
+                eprintln!("Failed to read stdin: {}", e);
+                return;
+            }
+        };
+        if line.trim().is_empty() {
+            continue;
+        }
+
+        let response = handle_request(&line);
+        let written = writeln!(stdout, "{}", serde_json::to_string(&response).unwrap())
+            .and_then(|_| stdout.flush());
+        if written.is_err() {
+            return;
+        }
+    }
+}
+
+/// Checks a request against the request schema and the supported protocol
+/// versions, then simulates it
+fn handle_request(input: &str) -> SimulationResponse {
+    // Echo the request ID and version even when the request is rejected, so
+    // that the caller can match the error to its request
+    let raw: Option<serde_json::Value> = serde_json::from_str(input).ok();
+    let field = |name: &str| {
+        raw.as_ref()
+            .and_then(|v| v.get(name)?.as_str().map(String::from))
+            .unwrap_or_default()
+    };
+    let request_id = field("request_id");
+    let requested = field("version");
+    let supported = PROTOCOL_VERSIONS.contains(&requested.as_str());
+    let version = if supported {
+        requested.as_str()
+    } else {
+        PROTOCOL_VERSIONS[0]
+    };
+
+    if let Err(e) = ipc::validate::validate_request(input) {
+        return error_response(version, &request_id, "invalid_request", e);
+    }
+    if !supported {
+        return error_response(
+            version,
+            &request_id,
+            "unsupported_version",
+            format!(
+                "unsupported protocol version {}, this simulator speaks {}",
+                requested,
+                PROTOCOL_VERSIONS.join(", ")
+            ),
+        );
+    }
+
+    match serde_json::from_str::<SimulationRequest>(input) {
+        Ok(request) => simulate(request),
+        Err(e) => error_response(
+            version,
+            &request_id,
+            "invalid_request",
+            format!("Invalid JSON: {}", e),
+        ),
+    }
+}
+
+fn simulate(request: SimulationRequest) -> SimulationResponse {
+    let send_error = |msg: String| {
+        error_response(
+            &request.version,
+            &request.request_id,
+            "simulation_failed",
+            msg,
+        )
+    };
+
+    let envelope_xdr = match &request.xdr {
+        Some(xdr) => xdr,
+        None => {
+            return send_error("Local WASM replay is not supported by this simulator".to_string())
         }
     };
 
     // Decode Envelope XDR
-    let envelope = match base64::engine::general_purpose::STANDARD.decode(&request.envelope_xdr) {
+    let envelope = match base64::engine::general_purpose::STANDARD.decode(envelope_xdr) {
         Ok(bytes) => match soroban_env_host::xdr::TransactionEnvelope::from_xdr(
             bytes,
             &soroban_env_host::xdr::Limits::none(),
@@ -78,8 +251,14 @@ fn main() {
     let host = soroban_env_host::Host::default();
     host.set_diagnostic_level(soroban_env_host::DiagnosticLevel::Debug)
         .unwrap();
+    let meter = EventMeter::default();
+    if request.profile {
+        if let Err(e) = meter.install(&host) {
+            return send_error(format!("Failed to enable profiling: {:?}", e));
+        }
+    }
 
-    let mut loaded_entries_count = 0;
+    let mut loaded_entries = Vec::new();
 
     // Populate Host Storage
     if let Some(entries) = &request.ledger_entries {
@@ -92,16 +271,17 @@ fn main() {
                 Err(e) => return send_error(format!("Failed to decode LedgerKey Base64: {}", e)),
             };
 
-            let _entry = match base64::engine::general_purpose::STANDARD.decode(entry_xdr) {
+            let entry = match base64::engine::general_purpose::STANDARD.decode(entry_xdr) {
                 Ok(b) => match soroban_env_host::xdr::LedgerEntry::from_xdr(b, &soroban_env_host::xdr::Limits::none()) {
                     Ok(e) => e,
                     Err(e) => return send_error(format!("Failed to parse LedgerEntry XDR: {}", e)),
                 },
                 Err(e) => return send_error(format!("Failed to decode LedgerEntry Base64: {}", e)),
             };
-            loaded_entries_count += 1;
+            loaded_entries.push(entry);
         }
     }
+    let code_offsets = CodeOffsets::from_entries(&loaded_entries);
 
     let mut invocation_logs = vec![];
 
@@ -128,25 +308,253 @@ fn main() {
     }
 
     let events = match host.get_events() {
-        Ok(evs) => evs.0.iter().map(|e| format!("{:?}", e)).collect::<Vec<String>>(),
-        Err(e) => vec![format!("Failed to retrieve events: {:?}", e)],
+        Ok(evs) => match diagnostic_events(&evs, &meter.readings(), &code_offsets) {
+            Ok(events) => events,
+            Err(e) => return send_error(format!("Failed to encode events: {}", e)),
+        },
+        Err(e) => return send_error(format!("Failed to retrieve events: {:?}", e)),
     };
 
     // Final Response
-    let response = SimulationResponse {
-        status: "success".to_string(),
+    SimulationResponse {
+        version: request.version.clone(),
+        request_id: request.request_id.clone(),
+        success: true,
+        result: Some(SimulationResult {
+            events,
+            logs: {
+                let mut logs = vec![format!(
+                    "Host Initialized. Loaded {} Ledger Entries",
+                    loaded_entries.len()
+                )];
+                logs.extend(invocation_logs);
+                logs
+            },
+        }),
         error: None,
-        events,
-        logs: {
-            let mut logs = vec![
-                format!("Host Initialized. Loaded {} Ledger Entries", loaded_entries_count),
-            ];
-            logs.extend(invocation_logs);
-            logs
-        },
+    }
+}
+
+// -----------------------------------------------------------------------------
+// Events
+// -----------------------------------------------------------------------------
+
+/// Budget readings taken while the host runs: entry i holds the CPU
+/// instructions and memory bytes the host had metered when it recorded event i.
+#[derive(Clone, Default)]
+struct EventMeter(Rc<RefCell<Vec<(u64, u64)>>>);
+
+impl EventMeter {
+    /// Installs a trace hook reading the budget whenever the host has recorded
+    /// new events, checked on every context push and pop and host function
+    /// return. The host does not expose its event count, and get_events copies
+    /// the events, so the hook is only installed for profiling requests.
+    fn install(&self, host: &soroban_env_host::Host) -> Result<(), soroban_env_host::HostError> {
+        use soroban_env_host::TraceEvent;
+
+        let readings = self.0.clone();
+        host.set_trace_hook(Some(Rc::new(
+            move |host: &soroban_env_host::Host, event: TraceEvent| {
+                if !matches!(
+                    event,
+                    TraceEvent::PushCtx(..) | TraceEvent::PopCtx(..) | TraceEvent::EnvRet(..)
+                ) {
+                    return Ok(());
+                }
+                let recorded = host.get_events()?.0.len();
+                let mut readings = readings.borrow_mut();
+                if readings.len() < recorded {
+                    let budget = host.budget_cloned();
+                    let reading = (
+                        budget.get_cpu_insns_consumed()?,
+                        budget.get_mem_bytes_consumed()?,
+                    );
+                    readings.resize(recorded, reading);
+                }
+                Ok(())
+            },
+        )))
+    }
+
+    fn readings(&self) -> Vec<(u64, u64)> {
+        self.0.borrow().clone()
+    }
+}
+
+/// Code section offsets of the functions contracts export, read from the
+/// contract instances and WASM code among the ledger entries of a request.
+/// Offsets are those of the first instruction of each function, relative to
+/// the start of the code section as DWARF addresses are.
+#[derive(Default)]
+struct CodeOffsets(HashMap<[u8; 32], HashMap<String, u64>>);
+
+impl CodeOffsets {
+    fn from_entries(entries: &[soroban_env_host::xdr::LedgerEntry]) -> Self {
+        use soroban_env_host::xdr::{ContractExecutable, LedgerEntryData, ScAddress, ScVal};
+
+        let mut code = HashMap::new();
+        let mut instances = Vec::new();
+        for entry in entries {
+            match &entry.data {
+                LedgerEntryData::ContractCode(c) => {
+                    code.insert(c.hash.0, c.code.as_slice());
+                }
+                LedgerEntryData::ContractData(d) => {
+                    if let (ScAddress::Contract(id), ScVal::ContractInstance(instance)) =
+                        (&d.contract, &d.val)
+                    {
+                        if let ContractExecutable::Wasm(hash) = &instance.executable {
+                            instances.push((id.0, hash.0));
+                        }
+                    }
+                }
+                _ => {}
+            }
+        }
+
+        let mut offsets = HashMap::new();
+        for (contract, hash) in instances {
+            // A module that does not parse only loses its offsets
+            if let Some(Ok(functions)) = code.get(&hash).map(|wasm| export_offsets(wasm)) {
+                offsets.insert(contract, functions);
+            }
+        }
+        CodeOffsets(offsets)
+    }
+
+    /// The offset of the function a fn_call event calls, from its topics
+    /// ["fn_call", contract ID bytes, function name]
+    fn call(&self, topics: &[soroban_env_host::xdr::ScVal]) -> Option<u64> {
+        use soroban_env_host::xdr::ScVal;
+
+        let (Some(ScVal::Bytes(id)), Some(ScVal::Symbol(function))) =
+            (topics.get(1), topics.get(2))
+        else {
+            return None;
+        };
+        let contract: [u8; 32] = id.as_slice().try_into().ok()?;
+        self.0
+            .get(&contract)?
+            .get(function.0.to_utf8_string_lossy().as_str())
+            .copied()
+    }
+}
+
+/// Maps the functions a WASM module exports to the code section offset of
+/// their first instruction
+fn export_offsets(wasm: &[u8]) -> Result<HashMap<String, u64>, wasmparser::BinaryReaderError> {
+    use wasmparser::{ExternalKind, Parser, Payload, TypeRef};
+
+    let mut imported = 0u32;
+    let mut exports = HashMap::new();
+    let mut code_start = 0;
+    let mut bodies = Vec::new();
+    for payload in Parser::new(0).parse_all(wasm) {
+        match payload? {
+            Payload::ImportSection(imports) => {
+                for import in imports {
+                    if let TypeRef::Func(_) = import?.ty {
+                        imported += 1;
+                    }
+                }
+            }
+            Payload::ExportSection(section) => {
+                for export in section {
+                    let export = export?;
+                    if export.kind == ExternalKind::Func {
+                        exports.insert(export.index, export.name.to_string());
+                    }
+                }
+            }
+            Payload::CodeSectionStart { range, .. } => code_start = range.start,
+            Payload::CodeSectionEntry(body) => {
+                let first = body.get_operators_reader()?.original_position();
+                bodies.push((first - code_start) as u64);
+            }
+            _ => {}
+        }
+    }
+
+    Ok(exports
+        .into_iter()
+        .filter_map(|(index, name)| {
+            let defined = index.checked_sub(imported)? as usize;
+            Some((name, *bodies.get(defined)?))
+        })
+        .collect())
+}
+
+/// Converts the events recorded by the host into their wire form, tracking the
+/// call depth through the fn_call and fn_return diagnostic events. A fn_call
+/// and its fn_return share the depth of the called frame. readings holds the
+/// budget metered at each event, empty when not profiling.
+///
+/// The backtrace of an event lists the offsets of the functions of the open
+/// contract frames. The VM does not report the instruction a trap happened
+/// at, so the innermost offset of an error is the entry of the failing
+/// function.
+fn diagnostic_events(
+    events: &soroban_env_host::events::Events,
+    readings: &[(u64, u64)],
+    code: &CodeOffsets,
+) -> Result<Vec<DiagnosticEvent>, soroban_env_host::xdr::Error> {
+    use soroban_env_host::xdr::{ContractEventBody, ContractEventType, ScVal};
+
+    let encode = |val: &ScVal| -> Result<String, soroban_env_host::xdr::Error> {
+        let bytes = val.to_xdr(soroban_env_host::xdr::Limits::none())?;
+        Ok(base64::engine::general_purpose::STANDARD.encode(bytes))
     };
 
-    println!("{}", serde_json::to_string(&response).unwrap());
+    // failed_call flag and code offset of every open frame. The host emits no
+    // fn_return for a call that fails, so a failed frame is closed once its
+    // caller emits an event again.
+    let mut frames: Vec<(bool, Option<u64>)> = Vec::new();
+    let mut out = Vec::with_capacity(events.0.len());
+    for (i, host_event) in events.0.iter().enumerate() {
+        let event = &host_event.event;
+        let ContractEventBody::V0(body) = &event.body;
+
+        let event_type = match event.type_ {
+            ContractEventType::Contract => "contract",
+            ContractEventType::System => "system",
+            ContractEventType::Diagnostic => "diagnostic",
+        };
+        let name = match body.topics.first() {
+            Some(ScVal::Symbol(sym)) if event.type_ == ContractEventType::Diagnostic => {
+                sym.0.to_utf8_string_lossy()
+            }
+            _ => String::new(),
+        };
+        if !host_event.failed_call {
+            while frames.last().map_or(false, |frame| frame.0) {
+                frames.pop();
+            }
+        }
+        if name == "fn_call" {
+            frames.push((host_event.failed_call, code.call(body.topics.as_slice())));
+        }
+        let call_depth = frames.len() as u32;
+        let wasm_backtrace = frames.iter().rev().filter_map(|frame| frame.1).collect();
+        if name == "fn_return" {
+            frames.pop();
+        }
+
+        out.push(DiagnosticEvent {
+            event_type: event_type.to_string(),
+            contract_id: event
+                .contract_id
+                .as_ref()
+                .map(|id| stellar_strkey::Contract(id.0).to_string()),
+            topics: body.topics.iter().map(encode).collect::<Result<_, _>>()?,
+            data: encode(&body.data)?,
+            in_successful_contract_call: !host_event.failed_call,
+            call_depth,
+            cpu_instructions: readings.get(i).map(|r| r.0),
+            memory_bytes: readings.get(i).map(|r| r.1),
+            wasm_backtrace,
+        });
+    }
+    Ok(out)
 }
 
 // -----------------------------------------------------------------------------
@@ -190,14 +598,17 @@ fn decode_error(err_msg: &str) -> String {
     format!("Execution Error: {}", err_msg)
 }
 
-fn send_error(msg: String) {
-    let res = SimulationResponse {
-        status: "error".to_string(),
-        error: Some(msg),
-        events: vec![],
-        logs: vec![],
-    };
-    println!("{}", serde_json::to_string(&res).unwrap());
+fn error_response(version: &str, request_id: &str, code: &str, msg: String) -> SimulationResponse {
+    SimulationResponse {
+        version: version.to_string(),
+        request_id: request_id.to_string(),
+        success: false,
+        result: None,
+        error: Some(ResponseError {
+            code: code.to_string(),
+            message: msg,
+        }),
+    }
 }
 
 // -----------------------------------------------------------------------------
@@ -235,6 +646,148 @@ mod tests {
         assert!(!msg.contains("VM Trap"));
     }
 
+    #[test]
+    fn test_handshake_lists_protocol_versions() {
+        let value: serde_json::Value = serde_json::from_str(&handshake()).unwrap();
+        assert_eq!(value["protocol_versions"][0], PROTOCOL_VERSIONS[0]);
+    }
+
+    #[test]
+    fn test_rejects_unsupported_version() {
+        let res = handle_request(r#"{"version":"9.0","request_id":"r1","wasm_path":"a.wasm"}"#);
+        assert!(!res.success);
+        assert_eq!(res.request_id, "r1");
+        assert_eq!(res.error.unwrap().code, "unsupported_version");
+    }
+
+    #[test]
+    fn test_rejects_invalid_request() {
+        let res = handle_request(r#"{"version":"1.0","request_id":"r2"}"#);
+        assert!(!res.success);
+        assert_eq!(res.request_id, "r2");
+        assert_eq!(res.error.unwrap().code, "invalid_request");
+    }
+
+    #[test]
+    fn test_diagnostic_events_track_call_depth() {
+        use soroban_env_host::events::{Events, HostEvent};
+        use soroban_env_host::xdr::{
+            ContractEvent, ContractEventBody, ContractEventType, ContractEventV0, ExtensionPoint,
+            Hash, ScSymbol, ScVal,
+        };
+
+        let event = |topic: &str, failed_call: bool| HostEvent {
+            event: ContractEvent {
+                ext: ExtensionPoint::V0,
+                contract_id: Some(Hash([0; 32])),
+                type_: ContractEventType::Diagnostic,
+                body: ContractEventBody::V0(ContractEventV0 {
+                    topics: vec![ScVal::Symbol(ScSymbol(topic.try_into().unwrap()))]
+                        .try_into()
+                        .unwrap(),
+                    data: ScVal::Void,
+                }),
+            },
+            failed_call,
+        };
+        let events = Events(vec![
+            event("fn_call", false),
+            event("fn_call", false),
+            event("fn_return", false),
+            event("fn_call", true),
+            event("log", true),
+            event("error", true),
+            // the failed call returned nothing, its caller carries on
+            event("log", false),
+            event("fn_return", false),
+        ]);
+
+        let out = diagnostic_events(&events, &[], &CodeOffsets::default()).unwrap();
+        let depths: Vec<u32> = out.iter().map(|e| e.call_depth).collect();
+        assert_eq!(depths, vec![1, 2, 2, 2, 2, 2, 1, 1]);
+        assert!(!out[4].in_successful_contract_call);
+        assert_eq!(out[0].event_type, "diagnostic");
+        assert!(out[0].contract_id.as_ref().unwrap().starts_with('C'));
+        assert_eq!(out[0].cpu_instructions, None);
+
+        let metered =
+            diagnostic_events(&events, &[(100, 10), (250, 40)], &CodeOffsets::default()).unwrap();
+        assert_eq!(metered[1].cpu_instructions, Some(250));
+        assert_eq!(metered[1].memory_bytes, Some(40));
+        assert_eq!(metered[2].cpu_instructions, None);
+        let json = serde_json::to_value(&metered[0]).unwrap();
+        assert_eq!(json["cpu_instructions"], 100);
+        assert_eq!(json["memory_bytes"], 10);
+    }
+
+    // A module exporting "transfer", its second function, which declares one
+    // local: the first instruction of transfer is at offset 8 of the code section
+    const EXPORTS_WASM: &[u8] = &[
+        0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00, // header
+        0x01, 0x04, 0x01, 0x60, 0x00, 0x00, // types: () -> ()
+        0x03, 0x03, 0x02, 0x00, 0x00, // functions
+        0x07, 0x0c, 0x01, 0x08, // exports
+        b't', b'r', b'a', b'n', b's', b'f', b'e', b'r', 0x00, 0x01, // transfer: function 1
+        0x0a, 0x09, 0x02, 0x02, 0x00, 0x0b, 0x04, 0x01, 0x01, 0x7f, 0x0b, // code
+    ];
+
+    #[test]
+    fn test_export_offsets() {
+        let offsets = export_offsets(EXPORTS_WASM).unwrap();
+        assert_eq!(offsets.get("transfer"), Some(&8));
+        assert_eq!(offsets.len(), 1);
+        assert!(export_offsets(&EXPORTS_WASM[..20]).is_err());
+    }
+
+    #[test]
+    fn test_diagnostic_events_wasm_backtrace() {
+        use soroban_env_host::events::{Events, HostEvent};
+        use soroban_env_host::xdr::{
+            ContractEvent, ContractEventBody, ContractEventType, ContractEventV0, ExtensionPoint,
+            Hash, ScBytes, ScSymbol, ScVal,
+        };
+
+        let symbol = |s: &str| ScVal::Symbol(ScSymbol(s.try_into().unwrap()));
+        let event = |topics: Vec<ScVal>, failed_call: bool| HostEvent {
+            event: ContractEvent {
+                ext: ExtensionPoint::V0,
+                contract_id: Some(Hash([7; 32])),
+                type_: ContractEventType::Diagnostic,
+                body: ContractEventBody::V0(ContractEventV0 {
+                    topics: topics.try_into().unwrap(),
+                    data: ScVal::Void,
+                }),
+            },
+            failed_call,
+        };
+        let call = |function: &str| {
+            event(
+                vec![
+                    symbol("fn_call"),
+                    ScVal::Bytes(ScBytes([7; 32].to_vec().try_into().unwrap())),
+                    symbol(function),
+                ],
+                true,
+            )
+        };
+        let events = Events(vec![
+            call("transfer"),
+            call("unknown"),
+            event(vec![symbol("error")], true),
+        ]);
+
+        let code = CodeOffsets(HashMap::from([(
+            [7; 32],
+            export_offsets(EXPORTS_WASM).unwrap(),
+        )]));
+        let out = diagnostic_events(&events, &[], &code).unwrap();
+        assert_eq!(out[0].wasm_backtrace, vec![8]);
+        // Frames of functions without an offset are left out
+        assert_eq!(out[2].wasm_backtrace, vec![8]);
+        let json = serde_json::to_value(&out[2]).unwrap();
+        assert_eq!(json["wasm_backtrace"][0], 8);
+    }
+
     #[test]
     fn test_unknown_trap_fallback() {
         let msg = decode_error("Wasm Trap: something weird happened");
@@ -77,6 +77,7 @@ func main() {
 		},
 		{
 			Operation:   "balance_check",
+			Depth:       1,
 			ContractID:  "CDLZFC3SYJYDZT7K67VZ75HPJVIEUVNIXF47ZG2FB2RMQQAHHAGCN4B2",
 			Function:    "get_balance",
 			Arguments:   []interface{}{"GDQP2KPQGKIHYJGXNUIYOMHARUARCA7DJT5FO2FFOOKY3B2WSQHG4W37"},
@@ -133,26 +134,19 @@ func main() {
 	// Serialize and save
 	traceData, err := executionTrace.ToJSON()
 	if err != nil {
-		fmt.Printf("Failed to serialize trace: %v
-", err)
+		fmt.Printf("Failed to serialize trace: %v\n", err)
 		os.Exit(1)
 	}
 
 	err = os.WriteFile(filename, traceData, 0644)
 	if err != nil {
-		fmt.Printf("Failed to write trace file: %v
-", err)
+		fmt.Printf("Failed to write trace file: %v\n", err)
 		os.Exit(1)
 	}
 
-	fmt.Printf("Sample trace generated: %s
-", filename)
-	fmt.Printf("Total steps: %d
-", len(states))
-	fmt.Printf("Snapshots: %d
-", len(executionTrace.Snapshots))
-	fmt.Println("
-To view the trace:")
-	fmt.Printf("  ./erst trace %s
-", filename)
+	fmt.Printf("Sample trace generated: %s\n", filename)
+	fmt.Printf("Total steps: %d\n", len(states))
+	fmt.Printf("Snapshots: %d\n", len(executionTrace.Snapshots))
+	fmt.Println("\nTo view the trace:")
+	fmt.Printf("  ./erst trace %s\n", filename)
 }